@@ -1,12 +1,23 @@
 package main
 
 import (
+	"context"
+	"strings"
+	"time"
+
 	"github.com/EM-Stawberry/Stawberry/internal/adapter/auth"
+	"github.com/EM-Stawberry/Stawberry/internal/adapter/pgnotify"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/audit"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/category"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/emailoutbox"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/notification"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/productimage"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/reviews"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/store"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/token"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/webhook"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
 	"github.com/EM-Stawberry/Stawberry/internal/repository"
 	"github.com/EM-Stawberry/Stawberry/pkg/database"
@@ -15,6 +26,7 @@ import (
 	"github.com/EM-Stawberry/Stawberry/pkg/migrator"
 	"github.com/EM-Stawberry/Stawberry/pkg/security"
 	"github.com/EM-Stawberry/Stawberry/pkg/server"
+	"github.com/EM-Stawberry/Stawberry/pkg/storage"
 	"github.com/jmoiron/sqlx"
 	flag "github.com/spf13/pflag"
 	"go.uber.org/zap"
@@ -31,7 +43,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-const basePath = "/api/v1"
+const (
+	basePath = "/api/v1"
+	// eventBusStopTimeout сколько ждать фоновых подписчиков событий при остановке
+	eventBusStopTimeout = 10 * time.Second
+)
 
 var enableMail bool
 
@@ -52,14 +68,27 @@ func main() {
 
 	migrator.RunMigrationsWithZap(db, "migrations", log)
 
-	database.DefaultAdminAcc()
+	if err := database.DefaultAdminAcc(); err != nil {
+		// пароль из примера допустим только локально
+		if cfg.Environment != config.EnvDev {
+			log.Fatal("Refusing to start with the example admin password", zap.Error(err))
+		}
+		log.Warn("Default admin account is not created", zap.Error(err))
+	}
 
-	router, mailer, auditMiddleware := initializeApp(cfg, db, log)
+	router, mailer, eventBus, webhookDeliverer, auditMiddleware, stopStreams := initializeApp(cfg, db, log)
 
-	if err := server.StartServer(router, mailer, &cfg.Server, log); err != nil {
+	if err := server.StartServer(router, mailer, &cfg.Server, log, stopStreams); err != nil {
 		log.Fatal("Failed to start server", zap.Error(err))
 	}
 
+	// письма из фоновых подписчиков попадут в outbox и уйдут после перезапуска
+	ctx, cancel := context.WithTimeout(context.Background(), eventBusStopTimeout)
+	defer cancel()
+	eventBus.Close(ctx)
+	// неотправленные вебхуки остаются в очереди и уйдут после перезапуска
+	webhookDeliverer.Stop(ctx)
+
 	auditMiddleware.Close()
 }
 
@@ -70,61 +99,147 @@ func initializeApp(
 ) (
 	*gin.Engine,
 	email.MailerService,
-	*middleware.AuditMiddleware) {
-	mailer := email.NewMailer(log, &cfg.Email)
-	log.Info("Mailer initialized")
+	*event.Bus,
+	*webhook.Deliverer,
+	*middleware.AuditMiddleware,
+	func()) {
+	emailTemplates, err := email.NewTemplates()
+	if err != nil {
+		log.Fatal("Failed to load email templates", zap.Error(err))
+	}
+	imageStorage, err := storage.New(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize image storage", zap.Error(err))
+	}
+	log.Info("Image storage initialized", zap.String("driver", cfg.Storage.Driver))
 
 	productRepository := repository.NewProductRepository(db)
+	categoryRepository := repository.NewCategoryRepository(db)
+	storeRepository := repository.NewStoreRepository(db)
 	offerRepository := repository.NewOfferRepository(db)
 	userRepository := repository.NewUserRepository(db)
 	notificationRepository := repository.NewNotificationRepository(db)
 	tokenRepository := repository.NewTokenRepository(db)
 	productReviewsRepository := repo.NewProductReviewRepository(db, log)
 	sellerReviewsRepository := repo.NewSellerReviewRepository(db, log)
+	reviewModerationRepository := repo.NewReviewModerationRepository(db, log)
+	reviewInteractionRepository := repo.NewReviewInteractionRepository(db, log)
 	auditRepository := repository.NewAuditRepository(db)
 	guestOfferRepository := guestofferrepo.NewRepository(db)
+	emailOutboxRepository := repository.NewEmailOutboxRepository(db)
+	webhookRepository := repository.NewWebhookRepository(db)
 	log.Info("Repositories initialized")
 
+	mailTransport, err := email.NewTransport(&cfg.Email)
+	if err != nil {
+		log.Fatal("Failed to initialize email transport", zap.Error(err))
+	}
+	// письма из памяти никуда не уходят, вне разработки их бы просто потеряли
+	var mailboxHandler *handler.MailboxHandler
+	if capture, ok := mailTransport.(*email.CaptureTransport); ok {
+		switch cfg.Environment {
+		case config.EnvDev:
+			mailboxHandler = handler.NewMailboxHandler(capture)
+		case config.EnvTest:
+		default:
+			log.Fatal("Memory email transport is only allowed in dev and test environments")
+		}
+	}
+	mailer := email.NewMailer(log, &cfg.Email, emailTemplates, emailOutboxRepository, mailTransport)
+	log.Info("Mailer initialized", zap.String("transport", cfg.Email.Transport))
+
+	eventBus := event.NewBus(log)
+
 	passwordManager := security.NewArgon2idPasswordManager()
 	jwtManager := auth.NewJWTManager(cfg.Token.Secret)
 
-	productService := product.NewService(productRepository)
-	offerService := offer.NewService(offerRepository, mailer)
+	categoryService := category.NewService(categoryRepository)
+	productService := product.NewService(productRepository, categoryService, imageStorage)
+	productImageService := productimage.NewService(productRepository, imageStorage, &cfg.Storage)
+	storeService := store.NewService(storeRepository, mailer)
+	notificationHub := notification.NewHub()
+	notificationDispatcher := notification.NewDispatcher(
+		notificationRepository,
+		pgnotify.NewBroadcaster(db),
+		mailer,
+		notification.NewUnsubscribeTokens(cfg.Token.Secret),
+		strings.TrimSuffix(cfg.Server.PublicURL, "/")+basePath+"/notifications/unsubscribe",
+		log,
+	)
+	notificationService := notification.NewService(notificationRepository, notificationHub, notificationDispatcher, log)
+	notificationService.SubscribeEvents(eventBus)
+	offerService := offer.NewService(offerRepository, eventBus)
 	tokenService := token.NewService(
 		tokenRepository,
 		jwtManager,
 		cfg.Token.RefreshTokenDuration,
 		cfg.Token.AccessTokenDuration,
 	)
-	userService := user.NewService(userRepository, tokenService, passwordManager, mailer)
-	notificationService := notification.NewService(notificationRepository)
-	productReviewsService := reviews.NewProductReviewService(productReviewsRepository, log)
-	sellerReviewsService := reviews.NewSellerReviewService(sellerReviewsRepository, log)
+	userService := user.NewService(
+		userRepository, tokenService, passwordManager, mailer, eventBus, cfg.Token.PasswordResetDuration,
+		strings.TrimSuffix(cfg.Server.PublicURL, "/")+basePath+"/auth/email/verify",
+	)
+	productReviewsService := reviews.NewProductReviewService(
+		productReviewsRepository, imageStorage,
+		cfg.Storage.MaxImageSize, cfg.Storage.ThumbnailSize, cfg.Storage.MaxReviewPhotos,
+		eventBus, log,
+	)
+	sellerReviewsService := reviews.NewSellerReviewService(sellerReviewsRepository, eventBus, log)
+	reviewModerationService := reviews.NewReviewModerationService(reviewModerationRepository, log)
+	reviewInteractionService := reviews.NewReviewInteractionService(reviewInteractionRepository, log)
 	auditService := audit.NewAuditService(auditRepository)
 	guestOfferService := guestofferservice.NewService(guestOfferRepository, mailer, log)
+	emailOutboxService := emailoutbox.NewService(emailOutboxRepository)
+	webhookDeliverer := webhook.NewDeliverer(webhookRepository, &cfg.Webhook, log)
+	webhookService := webhook.NewService(webhookRepository, storeRepository, webhookDeliverer)
+	webhookService.SubscribeEvents(eventBus)
 	log.Info("Services initialized")
 
 	healthHandler := handler.NewHealthHandler()
 	productHandler := handler.NewProductHandler(productService)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	productImageHandler := handler.NewProductImageHandler(productImageService, cfg.Storage.MaxImageSize)
+	storeHandler := handler.NewStoreHandler(storeService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
 	offerHandler := handler.NewOfferHandler(offerService)
 	userHandler := handler.NewUserHandler(cfg, userService)
 	notificationHandler := handler.NewNotificationHandler(notificationService)
-	productReviewsHandler := hdlr.NewProductReviewHandler(productReviewsService, log)
+	productReviewsHandler := hdlr.NewProductReviewHandler(productReviewsService, cfg.Storage.MaxImageSize, log)
 	sellerReviewsHandler := hdlr.NewSellerReviewsHandler(sellerReviewsService, log)
+	reviewModerationHandler := hdlr.NewReviewModerationHandler(reviewModerationService, log)
+	reviewInteractionHandler := hdlr.NewReviewInteractionHandler(reviewInteractionService, log)
 	auditHandler := handler.NewAuditHandler(auditService)
 	guestOfferHandler := guesthandler.NewHandler(guestOfferService, log)
+	emailTemplateHandler := handler.NewEmailTemplateHandler(emailTemplates)
+	emailOutboxHandler := handler.NewEmailOutboxHandler(emailOutboxService)
 	log.Info("Handlers initialized")
 
 	auditMiddleware := middleware.NewAuditMiddleware(&cfg.Audit, auditService, log)
 
+	// проверка просроченных офферов работает до завершения процесса
+	go offerService.RunExpiration(context.Background(), log)
+	listenCtx, stopListen := context.WithCancel(context.Background())
+	go pgnotify.Listen(listenCtx, cfg.DB.GetDBConnString(), notificationHub, log)
+	// при остановке сервера потоки SSE закрываются, иначе Shutdown ждет их до таймаута
+	stopStreams := func() {
+		stopListen()
+		notificationHub.CloseAll()
+	}
+
 	router := handler.SetupRouter(
 		healthHandler,
 		productHandler,
+		categoryHandler,
+		productImageHandler,
+		storeHandler,
+		webhookHandler,
 		offerHandler,
 		userHandler,
 		notificationHandler,
 		productReviewsHandler,
 		sellerReviewsHandler,
+		reviewModerationHandler,
+		reviewInteractionHandler,
 		guestOfferHandler,
 		userService,
 		tokenService,
@@ -132,7 +247,16 @@ func initializeApp(
 		log,
 		auditMiddleware,
 		auditHandler,
+		emailTemplateHandler,
+		emailOutboxHandler,
+		mailboxHandler,
+		cfg.Environment,
 	)
 
-	return router, mailer, auditMiddleware
+	// локальное хранилище раздается самим приложением, S3 отдает файлы напрямую
+	if local, ok := imageStorage.(*storage.LocalStorage); ok && strings.HasPrefix(local.PublicURL(), "/") {
+		router.Static(local.PublicURL(), local.Dir())
+	}
+
+	return router, mailer, eventBus, webhookDeliverer, auditMiddleware, stopStreams
 }
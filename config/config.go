@@ -38,12 +38,16 @@ type ServerConfig struct {
 	Domain  string
 	Port    string
 	GinMode string
+	// PublicURL адрес приложения для ссылок в письмах
+	PublicURL string
 }
 
 type TokenConfig struct {
 	Secret               string
 	AccessTokenDuration  time.Duration
 	RefreshTokenDuration time.Duration
+	// PasswordResetDuration сколько действует токен сброса пароля из письма
+	PasswordResetDuration time.Duration
 }
 
 type EmailConfig struct {
@@ -53,7 +57,38 @@ type EmailConfig struct {
 	SMTPHost   string
 	SMTPPort   int
 	WorkerPool int
-	QueueSize  int
+	// BatchSize сколько писем воркер забирает из outbox за раз
+	BatchSize int
+	// MaxAttempts после стольких неудачных попыток письмо уходит в dead letters
+	MaxAttempts  int
+	PollInterval time.Duration
+	// Transport способ доставки: smtp, maildir или memory (только для разработки)
+	Transport  string
+	MaildirDir string
+}
+
+type WebhookConfig struct {
+	WorkerPool int
+	// BatchSize сколько доставок воркер забирает за раз
+	BatchSize int
+	// MaxAttempts после стольких неудачных попыток доставка считается проваленной
+	MaxAttempts  int
+	PollInterval time.Duration
+	// Timeout ожидания ответа от адреса магазина
+	Timeout time.Duration
+	// AllowPrivateNetworks разрешает адреса во внутренней сети, только для локальной разработки
+	AllowPrivateNetworks bool
+}
+
+type StorageConfig struct {
+	// Driver выбирает хранилище изображений: s3 или local
+	Driver        string
+	LocalDir      string
+	PublicURL     string
+	MaxImageSize  int64
+	ThumbnailSize int
+	// MaxReviewPhotos ограничивает число фото у одного отзыва
+	MaxReviewPhotos int
 }
 
 type AuditConfig struct {
@@ -70,11 +105,13 @@ type Config struct {
 	SigningRegion string
 	Environment   string
 
-	DB     DBConfig
-	Server ServerConfig
-	Token  TokenConfig
-	Email  EmailConfig
-	Audit  AuditConfig
+	DB      DBConfig
+	Server  ServerConfig
+	Token   TokenConfig
+	Email   EmailConfig
+	Webhook WebhookConfig
+	Audit   AuditConfig
+	Storage StorageConfig
 }
 
 func LoadConfig() *Config {
@@ -96,7 +133,25 @@ func LoadConfig() *Config {
 	viper.SetDefault("DB_MAX_OPEN_CONNS", 25)
 	viper.SetDefault("DB_MAX_IDLE_CONNS", 10)
 	viper.SetDefault("SERVER_PORT", 8080)
+	viper.SetDefault("TOKEN_PASSWORD_RESET_DURATION", "1h")
+	viper.SetDefault("SERVER_PUBLIC_URL", "http://localhost:8080")
+	viper.SetDefault("EMAIL_BATCH_SIZE", 20)
+	viper.SetDefault("EMAIL_MAX_ATTEMPTS", 8)
+	viper.SetDefault("EMAIL_POLL_INTERVAL", "2s")
+	viper.SetDefault("EMAIL_TRANSPORT", "smtp")
+	viper.SetDefault("EMAIL_MAILDIR", "maildir")
+	viper.SetDefault("WEBHOOK_WORKER_POOL", 2)
+	viper.SetDefault("WEBHOOK_BATCH_SIZE", 20)
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 10)
+	viper.SetDefault("WEBHOOK_POLL_INTERVAL", "5s")
+	viper.SetDefault("WEBHOOK_TIMEOUT", "10s")
 	viper.SetDefault("AUDIT_BATCH_SIZE", 100)
+	viper.SetDefault("STORAGE_DRIVER", "local")
+	viper.SetDefault("STORAGE_LOCAL_DIR", "uploads")
+	viper.SetDefault("STORAGE_PUBLIC_URL", "/static")
+	viper.SetDefault("IMAGE_MAX_SIZE", 5<<20)
+	viper.SetDefault("IMAGE_THUMBNAIL_SIZE", 320)
+	viper.SetDefault("REVIEW_MAX_PHOTOS", 5)
 
 	config := &Config{
 		AccessKey:     viper.GetString("ACCESS_KEY"),
@@ -119,11 +174,14 @@ func LoadConfig() *Config {
 			Domain:  viper.GetString("SERVER_DOMAIN"),
 			Port:    viper.GetString("SERVER_PORT"),
 			GinMode: viper.GetString("GIN_MODE"),
+
+			PublicURL: viper.GetString("SERVER_PUBLIC_URL"),
 		},
 		Token: TokenConfig{
-			Secret:               viper.GetString("TOKEN_SECRET"),
-			AccessTokenDuration:  viper.GetDuration("TOKEN_ACCESS_DURATION"),
-			RefreshTokenDuration: viper.GetDuration("TOKEN_REFRESH_DURATION"),
+			Secret:                viper.GetString("TOKEN_SECRET"),
+			AccessTokenDuration:   viper.GetDuration("TOKEN_ACCESS_DURATION"),
+			RefreshTokenDuration:  viper.GetDuration("TOKEN_REFRESH_DURATION"),
+			PasswordResetDuration: viper.GetDuration("TOKEN_PASSWORD_RESET_DURATION"),
 		},
 		Email: EmailConfig{
 			Enabled:    viper.GetBool("EMAIL_ENABLED") || viper.GetBool("mail"),
@@ -132,13 +190,35 @@ func LoadConfig() *Config {
 			SMTPHost:   viper.GetString("SMTP_HOST"),
 			SMTPPort:   viper.GetInt("SMTP_PORT"),
 			WorkerPool: viper.GetInt("EMAIL_WORKER_POOL"),
-			QueueSize:  viper.GetInt("EMAIL_QUEUE_SIZE"),
+
+			BatchSize:    viper.GetInt("EMAIL_BATCH_SIZE"),
+			MaxAttempts:  viper.GetInt("EMAIL_MAX_ATTEMPTS"),
+			PollInterval: viper.GetDuration("EMAIL_POLL_INTERVAL"),
+			Transport:    viper.GetString("EMAIL_TRANSPORT"),
+			MaildirDir:   viper.GetString("EMAIL_MAILDIR"),
+		},
+		Webhook: WebhookConfig{
+			WorkerPool:   viper.GetInt("WEBHOOK_WORKER_POOL"),
+			BatchSize:    viper.GetInt("WEBHOOK_BATCH_SIZE"),
+			MaxAttempts:  viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+			PollInterval: viper.GetDuration("WEBHOOK_POLL_INTERVAL"),
+			Timeout:      viper.GetDuration("WEBHOOK_TIMEOUT"),
+
+			AllowPrivateNetworks: viper.GetBool("WEBHOOK_ALLOW_PRIVATE_NETWORKS"),
 		},
 		Audit: AuditConfig{
 			WorkerPoolSize: viper.GetInt("AUDIT_WORKER_POOL"),
 			QueueSize:      viper.GetInt("AUDIT_QUEUE_SIZE"),
 			BatchSize:      viper.GetInt("AUDIT_BATCH_SIZE"),
 		},
+		Storage: StorageConfig{
+			Driver:          viper.GetString("STORAGE_DRIVER"),
+			LocalDir:        viper.GetString("STORAGE_LOCAL_DIR"),
+			PublicURL:       viper.GetString("STORAGE_PUBLIC_URL"),
+			MaxImageSize:    viper.GetInt64("IMAGE_MAX_SIZE"),
+			ThumbnailSize:   viper.GetInt("IMAGE_THUMBNAIL_SIZE"),
+			MaxReviewPhotos: viper.GetInt("REVIEW_MAX_PHOTOS"),
+		},
 	}
 
 	return config
@@ -8,13 +8,17 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"time"
 
+	"github.com/EM-Stawberry/Stawberry/config"
 	"github.com/EM-Stawberry/Stawberry/pkg/email"
 
 	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
 
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/notification"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/offer"
 	"github.com/EM-Stawberry/Stawberry/internal/handler"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
@@ -28,6 +32,7 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"go.uber.org/zap"
 )
 
 var (
@@ -151,25 +156,24 @@ func mockAuthIncorrectShopOwnerMiddleware() gin.HandlerFunc {
 	}
 }
 
-type mockMailer struct{}
-
-func newMockMailer() email.MailerService {
-	return &mockMailer{}
-}
-
-func (m *mockMailer) Registered(userName string, userMail string) {
-}
-
-func (m *mockMailer) StatusUpdate(offerID uint, status string, userMail string) {
-}
-
-func (m *mockMailer) OfferReceived(offerID uint, userMail string) {
-}
-
-func (m *mockMailer) SendGuestOfferNotification(email string, subject string, body string) {
-}
-
-func (m *mockMailer) Stop(ctx context.Context) {
+// newCaptureMailer создает настоящий mailer с outbox в тестовой базе, письма которого
+// остаются в памяти и доступны для проверок
+func newCaptureMailer(db *sqlx.DB) (email.MailerService, *email.CaptureTransport) {
+	templates, err := email.NewTemplates()
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+	capture := email.NewCaptureTransport()
+	mailer := email.NewMailer(zap.NewNop(), &config.EmailConfig{
+		Enabled:      true,
+		From:         "noreply@stawberry.test",
+		WorkerPool:   1,
+		BatchSize:    10,
+		MaxAttempts:  3,
+		PollInterval: 50 * time.Millisecond,
+		Transport:    email.TransportMemory,
+	}, templates, repository.NewEmailOutboxRepository(db), capture)
+
+	return mailer, capture
 }
 
 func setupRouter(authMiddleware gin.HandlerFunc, method, path string, handlerFunc gin.HandlerFunc) *gin.Engine {
@@ -193,6 +197,9 @@ var _ = ginkgo.Describe("offer handlers", ginkgo.Ordered, func() {
 	var (
 		dbCont    *postgres.PostgresContainer
 		db        *sqlx.DB
+		mailer    email.MailerService
+		mailbox   *email.CaptureTransport
+		eventBus  *event.Bus
 		offerRepo offer.Repository
 		offerServ *offer.Service
 		offerHand *handler.OfferHandler
@@ -207,14 +214,23 @@ var _ = ginkgo.Describe("offer handlers", ginkgo.Ordered, func() {
 			slog.Error(err.Error())
 			ginkgo.Fail("Failed to get database connection")
 		}
-		mailer := newMockMailer()
+		mailer, mailbox = newCaptureMailer(db)
 
 		offerRepo = repository.NewOfferRepository(db)
-		offerServ = offer.NewService(offerRepo, mailer)
+		notificationRepo := repository.NewNotificationRepository(db)
+		hub := notification.NewHub()
+		dispatcher := notification.NewDispatcher(notificationRepo, hub, mailer,
+			notification.NewUnsubscribeTokens("secret"), "/notifications/unsubscribe", zap.NewNop())
+		notificationServ := notification.NewService(notificationRepo, hub, dispatcher, zap.NewNop())
+		eventBus = event.NewBus(zap.NewNop())
+		notificationServ.SubscribeEvents(eventBus)
+		offerServ = offer.NewService(offerRepo, eventBus)
 		offerHand = handler.NewOfferHandler(offerServ)
 	})
 
 	ginkgo.AfterAll(func() {
+		eventBus.Close(context.Background())
+		mailer.Stop(context.Background())
 		_ = db.Close()
 		_ = dbCont.Terminate(context.Background())
 	})
@@ -247,6 +263,15 @@ var _ = ginkgo.Describe("offer handlers", ginkgo.Ordered, func() {
 			var ofr dto.PatchOfferStatusResp
 			_ = json.Unmarshal(rec.Body.Bytes(), &ofr)
 			gomega.Expect(ofr.NewStatus).To(gomega.Equal("accepted"))
+
+			// покупатель получает письмо о решении магазина
+			gomega.Eventually(func() []email.CapturedMessage {
+				return mailbox.Messages("user2email")
+			}, 5*time.Second, 50*time.Millisecond).Should(gomega.ContainElement(gomega.SatisfyAll(
+				gomega.HaveField("Subject", gomega.ContainSubstring("ID 1")),
+				gomega.HaveField("Text", gomega.ContainSubstring("accepted")),
+				gomega.HaveField("UnsubscribeURL", gomega.HavePrefix("/notifications/unsubscribe?token=")),
+			)))
 		})
 
 		ginkgo.It("fails data validation if the offerID is negative", func() {
@@ -480,7 +505,7 @@ var _ = ginkgo.Describe("offer handlers", ginkgo.Ordered, func() {
 
 		ginkgo.It("successfully creates an offer with valid data", func() {
 			reqBody := dto.PostOfferReq{
-				ProductID: 1,
+				VariantID: 1,
 				ShopID:    2,
 				Price:     100.50,
 				Currency:  "USD",
@@ -499,11 +524,25 @@ var _ = ginkgo.Describe("offer handlers", ginkgo.Ordered, func() {
 			err := json.Unmarshal(rec.Body.Bytes(), &resp)
 			gomega.Expect(err).To(gomega.BeNil())
 			gomega.Expect(resp.ID).To(gomega.BeNumerically(">", 0)) // Expect a positive ID
+
+			// владелец магазина получает одно письмо, хотя оно записывается и в транзакции, и после нее
+			subject := fmt.Sprintf("New Offer Received (ID %d)", resp.ID)
+			received := func() []email.CapturedMessage {
+				var msgs []email.CapturedMessage
+				for _, msg := range mailbox.Messages("user1email") {
+					if strings.Contains(msg.Subject, subject) {
+						msgs = append(msgs, msg)
+					}
+				}
+				return msgs
+			}
+			gomega.Eventually(received, 5*time.Second, 50*time.Millisecond).Should(gomega.HaveLen(1))
+			gomega.Consistently(received, 300*time.Millisecond, 50*time.Millisecond).Should(gomega.HaveLen(1))
 		})
 
 		ginkgo.It("fails to create an offer with a negative price", func() {
 			reqBody := dto.PostOfferReq{
-				ProductID: 2,
+				VariantID: 2,
 				ShopID:    2,
 				Price:     -10.00,
 				Currency:  "USD",
@@ -519,9 +558,9 @@ var _ = ginkgo.Describe("offer handlers", ginkgo.Ordered, func() {
 			gomega.Expect(rec.Code).To(gomega.Equal(http.StatusBadRequest))
 		})
 
-		ginkgo.It("fails to create an offer with missing required fields (e.g., ProductID)", func() {
+		ginkgo.It("fails to create an offer with missing required fields (e.g., VariantID)", func() {
 			reqBody := dto.PostOfferReq{
-				// ProductID is missing, which is required by `binding:"required"`
+				// VariantID is missing, which is required by `binding:"required"`
 				ShopID:   2,
 				Price:    100.00,
 				Currency: "USD",
@@ -546,7 +585,7 @@ var _ = ginkgo.Describe("offer handlers", ginkgo.Ordered, func() {
 
 		ginkgo.It("fails to create an offer with a shop owner account", func() {
 			reqBody := dto.PostOfferReq{
-				ProductID: 4,
+				VariantID: 4,
 				ShopID:    2,
 				Price:     100.50,
 				Currency:  "USD",
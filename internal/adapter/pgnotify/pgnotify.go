@@ -0,0 +1,116 @@
+package pgnotify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+// Channel канал Postgres, через который реплики обмениваются уведомлениями
+const Channel = "notifications"
+
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+// Broadcaster отправляет уведомления всем репликам через NOTIFY
+type Broadcaster struct {
+	db *sqlx.DB
+}
+
+func NewBroadcaster(db *sqlx.DB) *Broadcaster {
+	return &Broadcaster{db: db}
+}
+
+func (b *Broadcaster) Publish(ctx context.Context, notifications []entity.Notification) error {
+	for _, n := range notifications {
+		payload, err := json.Marshal(n)
+		if err != nil {
+			return fmt.Errorf("marshal notification: %w", err)
+		}
+		if _, err := b.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", Channel, string(payload)); err != nil {
+			return fmt.Errorf("notify: %w", err)
+		}
+	}
+	return nil
+}
+
+// Publisher принимает уведомления, полученные из Postgres
+type Publisher interface {
+	Publish(ctx context.Context, notifications []entity.Notification) error
+	// CloseAll закрывает подписки клиентов, чтобы они переподключились и дочитали пропущенное
+	CloseAll()
+}
+
+// Listen слушает канал уведомлений на отдельном соединении и передает события в publisher,
+// пока не отменен контекст. При обрыве соединения переподключается. События, пришедшие во время
+// обрыва, до клиентов не дошли, поэтому после переподключения их подписки закрываются,
+// и клиенты дочитывают пропущенное по Last-Event-ID
+func Listen(ctx context.Context, connString string, publisher Publisher, log *zap.Logger) {
+	delay := minReconnectDelay
+	reconnect := false
+	for {
+		err := listen(ctx, connString, publisher, log, func() {
+			delay = minReconnectDelay
+			if reconnect {
+				publisher.CloseAll()
+			}
+			reconnect = true
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warn("Notification listener disconnected", zap.Error(err), zap.Duration("retry_in", delay))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+func listen(
+	ctx context.Context,
+	connString string,
+	publisher Publisher,
+	log *zap.Logger,
+	connected func(),
+) error {
+	conn, err := pgx.Connect(ctx, connString)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer func() {
+		_ = conn.Close(context.Background())
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	connected()
+	log.Info("Notification listener started")
+
+	for {
+		msg, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+
+		var n entity.Notification
+		if err := json.Unmarshal([]byte(msg.Payload), &n); err != nil {
+			log.Warn("Skipping malformed notification", zap.Error(err))
+			continue
+		}
+		_ = publisher.Publish(ctx, []entity.Notification{n})
+	}
+}
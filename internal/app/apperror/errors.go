@@ -15,6 +15,7 @@ const (
 	InvalidFingerprint = "INVALID_FINGERPRINT"
 	Conflict           = "CONFLICT"
 	Forbidden          = "FORBIDDEN"
+	TooManyRequests    = "TOO_MANY_REQUESTS"
 )
 
 type AppError interface {
@@ -47,6 +48,14 @@ func New(code, message string, cause error) *Error {
 var (
 	ErrProductNotFound = New(NotFound, "product not found", nil)
 	ErrStoreNotFound   = New(NotFound, "store not found", nil)
+	ErrImageNotFound   = New(NotFound, "image not found", nil)
+	ErrVariantNotFound = New(NotFound, "product variant not found", nil)
+
+	ErrShopMemberNotFound     = New(NotFound, "shop member not found", nil)
+	ErrShopInvitationNotFound = New(NotFound, "shop invitation not found", nil)
+
+	ErrCategoryNotFound        = New(NotFound, "category not found", nil)
+	ErrAttributeSchemaNotFound = New(NotFound, "attribute schema not found", nil)
 
 	ErrOfferNotFound = New(NotFound, "offer not found", nil)
 
@@ -58,19 +67,33 @@ var (
 	ErrInvalidToken  = New(InvalidToken, "invalid token", nil)
 	ErrTokenNotFound = New(NotFound, "token not found", nil)
 
+	ErrInvalidPasswordResetToken = New(BadRequest, "password reset token is invalid or expired", nil)
+	ErrInvalidVerificationToken  = New(BadRequest, "email verification token is invalid or expired", nil)
+	ErrEmailNotVerified          = New(Forbidden, "email is not verified", nil)
+	ErrEmailAlreadyVerified      = New(Conflict, "email is already verified", nil)
+	ErrEmailUnchanged            = New(BadRequest, "new email matches the current one", nil)
+	ErrAccountOwnsShop           = New(Conflict, "transfer or delete your shops before deleting the account", nil)
+
 	ErrNotificationNotFound = New(NotFound, "notification not found", nil)
+
+	ErrEmailNotFound = New(NotFound, "email not found", nil)
+
+	ErrWebhookNotFound = New(NotFound, "webhook not found", nil)
 )
 
-// ReviewError представляет ошибку, связанную с отзывами
+// ReviewError представляет ошибку, связанную с отзывами, и реализует AppError
 type ReviewError struct {
-	Code    string
-	Message string
+	ErrCode string
+	ErrMsg  string
 }
 
 // Error реализует интерфейс error
 func (e *ReviewError) Error() string {
-	return e.Message
+	return e.ErrMsg
 }
+func (e *ReviewError) Code() string    { return e.ErrCode }
+func (e *ReviewError) Message() string { return e.ErrMsg }
+func (e *ReviewError) Unwrap() error   { return nil }
 
 // Константы для кодов ошибок отзывов
 const (
@@ -78,13 +101,18 @@ const (
 	ReviewDuplicate     = "review_duplicate"
 	ReviewDatabaseError = "review_database_error"
 	ReviewUnauthorized  = "review_unauthorized"
+	ReviewDealRequired  = "review_deal_required"
+	ReviewEditExpired   = "review_edit_expired"
+	ReviewHidden        = "review_hidden"
+	ReviewAuthorBanned  = "review_author_banned"
+	ReviewInvalid       = "review_invalid"
 )
 
 // NewReviewError создает новую ошибку отзыва
 func NewReviewError(code string, message string) *ReviewError {
 	return &ReviewError{
-		Code:    code,
-		Message: message,
+		ErrCode: code,
+		ErrMsg:  message,
 	}
 }
 
@@ -0,0 +1,28 @@
+package entity
+
+// Типы значений атрибутов, которые можно описать в схеме категории
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+	AttributeTypeEnum    = "enum"
+)
+
+type Category struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	ParentID *int   `json:"parent_id,omitempty"`
+}
+
+// AttributeSchema описывает один атрибут продуктов категории.
+// Схема наследуется подкатегориями, атрибут подкатегории перекрывает одноименный атрибут родителя.
+type AttributeSchema struct {
+	ID         int      `json:"id"`
+	CategoryID int      `json:"category_id"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Unit       string   `json:"unit,omitempty"`
+	EnumValues []string `json:"enum_values,omitempty"`
+	Required   bool     `json:"required"`
+	Filterable bool     `json:"filterable"`
+}
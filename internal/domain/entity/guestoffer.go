@@ -10,3 +10,9 @@ type GuestOfferData struct {
 	GuestEmail string
 	GuestPhone string
 }
+
+// StoreOwnerContact is where guest offer notifications for a store are sent
+type StoreOwnerContact struct {
+	Email  string `db:"email"`
+	Locale string `db:"locale"`
+}
@@ -2,9 +2,80 @@ package entity
 
 import "time"
 
+// Типы уведомлений о событиях оффера
+const (
+	NotificationOfferCreated   = "offer_created"
+	NotificationOfferAccepted  = "offer_accepted"
+	NotificationOfferDeclined  = "offer_declined"
+	NotificationOfferCancelled = "offer_cancelled"
+	NotificationOfferExpired   = "offer_expired"
+)
+
 type Notification struct {
-	ID      uint      `json:"id"`
-	UserID  uint      `json:"user_id"`
-	Message string    `json:"message"`
-	SentAt  time.Time `json:"sent_at"`
+	ID      uint       `json:"id"`
+	UserID  uint       `json:"user_id"`
+	Type    string     `json:"type"`
+	OfferID *uint      `json:"offer_id,omitempty"`
+	Message string     `json:"message"`
+	SentAt  time.Time  `json:"sent_at"`
+	ReadAt  *time.Time `json:"read_at,omitempty"`
+}
+
+// Каналы доставки уведомлений
+const (
+	ChannelInApp   = "in_app"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// Типы событий, для которых пользователь настраивает каналы доставки
+const (
+	EventOfferReceived = "offer_received"
+	EventOfferStatus   = "offer_status"
+	EventReviewPosted  = "review_posted"
+	EventMarketing     = "marketing"
+)
+
+var (
+	NotificationChannels = []string{ChannelInApp, ChannelEmail, ChannelWebhook}
+	NotificationEvents   = []string{EventOfferReceived, EventOfferStatus, EventReviewPosted, EventMarketing}
+)
+
+// NotificationPreference включен ли канал для типа событий
+type NotificationPreference struct {
+	Event   string `json:"event"`
+	Channel string `json:"channel"`
+	Enabled bool   `json:"enabled"`
+}
+
+// DefaultPreference значение настройки, которую пользователь не менял:
+// рассылки и вебхуки включаются только явно, остальное приходит в приложение и на почту
+func DefaultPreference(event, channel string) bool {
+	return event != EventMarketing && channel != ChannelWebhook
+}
+
+// NotificationEvent возвращает тип события для настроек по типу уведомления
+func NotificationEvent(notificationType string) string {
+	if notificationType == NotificationOfferCreated {
+		return EventOfferReceived
+	}
+	return EventOfferStatus
+}
+
+// NotificationRecipient получатель уведомления и его настройки для типа событий.
+// В Channels только измененные пользователем каналы
+type NotificationRecipient struct {
+	UserID   uint
+	Email    string
+	Locale   string
+	Event    string
+	Channels map[string]bool
+}
+
+// Enabled учитывает настройку пользователя, а если ее нет - значение по умолчанию
+func (r NotificationRecipient) Enabled(channel string) bool {
+	if enabled, ok := r.Channels[channel]; ok {
+		return enabled
+	}
+	return DefaultPreference(r.Event, channel)
 }
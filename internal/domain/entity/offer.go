@@ -13,4 +13,12 @@ type Offer struct {
 	ShopID    uint
 	UserID    uint
 	ProductID uint
+	VariantID uint
+}
+
+// OfferDetails товар и магазин оффера для писем
+type OfferDetails struct {
+	ProductName string `db:"product_name"`
+	VariantSKU  string `db:"variant_sku"`
+	ShopName    string `db:"shop_name"`
 }
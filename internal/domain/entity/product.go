@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type Product struct {
 	ID            int                    `json:"id"`
 	Name          string                 `json:"name"`
@@ -10,6 +12,43 @@ type Product struct {
 	AverageRating float64                `json:"average_rating"`
 	CountReviews  int                    `json:"count_reviews"`
 	Attributes    map[string]interface{} `json:"product_attributes"`
+	Images        []ProductImage         `json:"images"`
+	// Variants и VariantAxes заполняются только при получении одного продукта
+	Variants    []ProductVariant         `json:"variants,omitempty"`
+	VariantAxes map[string][]interface{} `json:"variant_axes,omitempty"`
+}
+
+// ProductVariant конкретная модификация продукта (цвет, размер и т.п.) со своим SKU.
+// Магазины продают и принимают предложения именно по вариантам.
+type ProductVariant struct {
+	ID           int                    `json:"id"`
+	ProductID    int                    `json:"product_id"`
+	SKU          string                 `json:"sku"`
+	Attributes   map[string]interface{} `json:"attributes"`
+	MinimalPrice int                    `json:"minimal_price"`
+	MaximalPrice int                    `json:"maximal_price"`
+	Inventory    []VariantInventory     `json:"inventory"`
+}
+
+// VariantInventory предложение варианта в конкретном магазине, цена в копейках
+type VariantInventory struct {
+	VariantID   int    `json:"-"`
+	ShopID      int    `json:"shop_id"`
+	Price       int    `json:"price"`
+	Currency    string `json:"currency"`
+	IsAvailable bool   `json:"is_available"`
+}
+
+// ProductImage изображение продукта. Ключи хранилища наружу не отдаются, только ссылки.
+type ProductImage struct {
+	ID           int       `json:"id"`
+	ProductID    int       `json:"-"`
+	Key          string    `json:"-"`
+	ThumbnailKey string    `json:"-"`
+	ContentType  string    `json:"-"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 type NewProduct struct {
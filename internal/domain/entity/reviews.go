@@ -3,19 +3,151 @@ package entity
 import "time"
 
 type ProductReview struct {
-	ID        int       `json:"id" db:"id"`
-	ProductID int       `json:"product_id" db:"productid"`
-	UserID    int       `json:"user_id" db:"userid"`
-	Rating    int       `json:"rating" db:"rating"`
-	Review    string    `json:"review" db:"review"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID              int           `json:"id" db:"id"`
+	ProductID       int           `json:"product_id" db:"productid"`
+	UserID          int           `json:"user_id" db:"userid"`
+	Rating          int           `json:"rating" db:"rating"`
+	Review          string        `json:"review" db:"review"`
+	Verified        bool          `json:"verified" db:"is_verified"`
+	Status          string        `json:"status" db:"status"`
+	HelpfulCount    int           `json:"helpful_count" db:"helpful_count"`
+	NotHelpfulCount int           `json:"not_helpful_count" db:"not_helpful_count"`
+	Reply           *ReviewReply  `json:"reply,omitempty" db:"-"`
+	Photos          []ReviewPhoto `json:"photos" db:"-"`
+	CreatedAt       time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt       *time.Time    `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// ReviewPhoto is an image attached to a product review. URLs are filled from the image storage.
+type ReviewPhoto struct {
+	ID           int       `json:"id" db:"id"`
+	ReviewID     int       `json:"-" db:"review_id"`
+	Key          string    `json:"-" db:"image_key"`
+	ThumbnailKey string    `json:"-" db:"thumbnail_key"`
+	ContentType  string    `json:"-" db:"content_type"`
+	URL          string    `json:"url" db:"-"`
+	ThumbnailURL string    `json:"thumbnail_url" db:"-"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// Review list orderings.
+const (
+	ReviewSortNewest      = "newest"
+	ReviewSortHighest     = "highest"
+	ReviewSortLowest      = "lowest"
+	ReviewSortMostHelpful = "most_helpful"
+)
+
+// ReviewFilter selects a page of reviews and its order.
+type ReviewFilter struct {
+	// VerifiedOnly keeps only reviews of users who have an accepted offer for the product.
+	// It applies to product reviews only.
+	VerifiedOnly bool
+	// MinRating and MaxRating bound the rating, zero means no bound.
+	MinRating int
+	MaxRating int
+	Sort      string
+	Limit     int
+	Offset    int
+}
+
+// ReviewSummary describes all published reviews of a product or shop regardless of the filter.
+type ReviewSummary struct {
+	Average float64 `json:"average"`
+	Total   int     `json:"total"`
+	// Histogram maps every rating from 1 to 5 to the number of reviews with it.
+	Histogram map[int]int `json:"histogram"`
+}
+
+// ProductReviewPage is a page of product reviews with the total number of matching reviews.
+type ProductReviewPage struct {
+	Reviews []ProductReview
+	Total   int
+	Summary ReviewSummary
+}
+
+// SellerReviewPage is a page of seller reviews with the total number of matching reviews.
+type SellerReviewPage struct {
+	Reviews []SellerReview
+	Total   int
+	Summary ReviewSummary
 }
 
 type SellerReview struct {
-	ID        int       `json:"id" db:"id"`
-	SellerID  int       `json:"seller_id" db:"seller_id"`
-	UserID    int       `json:"user_id" db:"user_id"`
-	Rating    int       `json:"rating" db:"rating"`
-	Review    string    `json:"review" db:"review"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID              int          `json:"id" db:"id"`
+	ShopID          int          `json:"shop_id" db:"shop_id"`
+	OfferID         *int         `json:"offer_id,omitempty" db:"offer_id"`
+	UserID          int          `json:"user_id" db:"user_id"`
+	Rating          int          `json:"rating" db:"rating"`
+	Review          string       `json:"review" db:"review"`
+	Status          string       `json:"status" db:"status"`
+	HelpfulCount    int          `json:"helpful_count" db:"helpful_count"`
+	NotHelpfulCount int          `json:"not_helpful_count" db:"not_helpful_count"`
+	Reply           *ReviewReply `json:"reply,omitempty" db:"-"`
+	CreatedAt       time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt       *time.Time   `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// Review types, used where product and seller reviews are handled together.
+const (
+	ReviewTypeProduct = "product"
+	ReviewTypeSeller  = "seller"
+)
+
+// Review statuses. Hidden reviews are not listed and do not count towards ratings.
+const (
+	ReviewStatusPublished = "published"
+	ReviewStatusHidden    = "hidden"
+)
+
+// Moderation actions stored in the moderation log.
+const (
+	ModerationActionHide    = "hide"
+	ModerationActionRestore = "restore"
+	ModerationActionBan     = "ban"
+	ModerationActionUnban   = "unban"
+)
+
+// ReviewReport is a complaint about a review left by another user.
+type ReviewReport struct {
+	ID         int       `json:"id"`
+	ReviewType string    `json:"review_type"`
+	ReviewID   int       `json:"review_id"`
+	ReporterID int       `json:"reporter_id"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ModerationQueueItem is a reported review waiting for a moderator decision.
+type ModerationQueueItem struct {
+	ReviewType     string    `json:"review_type"`
+	ReviewID       int       `json:"review_id"`
+	TargetID       int       `json:"target_id"`
+	AuthorID       int       `json:"author_id"`
+	Rating         int       `json:"rating"`
+	Review         string    `json:"review"`
+	Status         string    `json:"status"`
+	ReportCount    int       `json:"report_count"`
+	Reasons        []string  `json:"reasons"`
+	LastReportedAt time.Time `json:"last_reported_at"`
+}
+
+// ReviewReply is the public answer of a shop to a review.
+type ReviewReply struct {
+	ID         int        `json:"id" db:"id"`
+	ReviewType string     `json:"-" db:"review_type"`
+	ReviewID   int        `json:"review_id" db:"review_id"`
+	ShopID     int        `json:"shop_id" db:"shop_id"`
+	AuthorID   *int       `json:"author_id,omitempty" db:"author_id"`
+	Body       string     `json:"body" db:"body"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// ReviewTarget describes who wrote a review and what it is about.
+type ReviewTarget struct {
+	AuthorID int
+	// TargetID is the product ID for product reviews and the shop ID for seller reviews.
+	TargetID int
+	Status   string
 }
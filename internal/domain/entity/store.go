@@ -2,12 +2,58 @@ package entity
 
 import "time"
 
-// тут тоже надо поправить, когда store будут реализовывать
+// Store магазин продавца. В базе хранится в таблице shops.
 type Store struct {
-	ID          uint
-	Name        string
-	Description string
-	Products    []Product
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID           uint              `json:"id"`
+	UserID       uint              `json:"user_id"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	LogoURL      string            `json:"logo_url"`
+	ContactEmail string            `json:"contact_email"`
+	ContactPhone string            `json:"contact_phone"`
+	Address      string            `json:"address"`
+	WorkingHours map[string]string `json:"working_hours"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+
+	// Вычисляемые поля, заполняются при чтении
+	ProductCount int     `json:"product_count"`
+	Rating       float64 `json:"rating"`
+	ReviewCount  int     `json:"review_count"`
+}
+
+// ShopRole роль участника магазина
+type ShopRole string
+
+const (
+	ShopRoleOwner   ShopRole = "owner"
+	ShopRoleManager ShopRole = "manager"
+	ShopRoleViewer  ShopRole = "viewer"
+)
+
+// CanManage сообщает, может ли роль работать с офферами и ассортиментом магазина
+func (r ShopRole) CanManage() bool {
+	return r == ShopRoleOwner || r == ShopRoleManager
+}
+
+// ShopMember сотрудник магазина
+type ShopMember struct {
+	ShopID    uint      `json:"shop_id"`
+	UserID    uint      `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      ShopRole  `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ShopInvitation приглашение в магазин, отправляемое на email
+type ShopInvitation struct {
+	ID         uint       `json:"id"`
+	ShopID     uint       `json:"shop_id"`
+	Email      string     `json:"email"`
+	Role       ShopRole   `json:"role"`
+	InvitedBy  uint       `json:"invited_by"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
 }
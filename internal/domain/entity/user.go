@@ -1,5 +1,16 @@
 package entity
 
+import "time"
+
+// UserRole роль пользователя, определяет доступ к административным эндпойнтам
+type UserRole string
+
+const (
+	UserRoleUser  UserRole = "user"
+	UserRoleShop  UserRole = "shop"
+	UserRoleAdmin UserRole = "admin"
+)
+
 type User struct {
 	ID       uint
 	Name     string
@@ -7,4 +18,13 @@ type User struct {
 	Email    string
 	Phone    string
 	IsStore  bool
+	Role     UserRole
+	// Locale язык писем: en или ru
+	Locale string
+	// EmailVerifiedAt когда пользователь подтвердил почту, nil - еще не подтвердил
+	EmailVerifiedAt *time.Time
+}
+
+func (u User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Статусы доставки вебхука
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	// WebhookDeliveryFailed доставка не удалась за все попытки
+	WebhookDeliveryFailed = "failed"
+)
+
+// Webhook адрес магазина, на который отправляются события. Secret возвращается только при создании
+type Webhook struct {
+	ID        uint      `json:"id"`
+	ShopID    uint      `json:"shop_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery запись журнала доставок вебхука
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      uint            `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus *int            `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// PendingWebhookDelivery доставка вместе с адресом и секретом вебхука для отправки
+type PendingWebhookDelivery struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
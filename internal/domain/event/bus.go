@@ -0,0 +1,126 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+)
+
+// Handler синхронный подписчик. exec - транзакция, в которой произошло событие, или nil,
+// если событие опубликовано вне транзакции. Ошибка отменяет операцию публикующего
+type Handler func(ctx context.Context, exec email.Execer, e Event) error
+
+// AsyncHandler асинхронный подписчик, вызывается в фоне после завершения операции.
+// Ошибка только логируется
+type AsyncHandler func(ctx context.Context, e Event) error
+
+// Bus шина доменных событий внутри процесса. Сервисы публикуют события, а уведомления,
+// письма и вебхуки подписываются на них независимо друг от друга
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	async    map[string][]AsyncHandler
+	closed   bool
+	wg       sync.WaitGroup
+	log      *zap.Logger
+}
+
+func NewBus(log *zap.Logger) *Bus {
+	return &Bus{
+		handlers: make(map[string][]Handler),
+		async:    make(map[string][]AsyncHandler),
+		log:      log,
+	}
+}
+
+// Subscribe подписывает синхронный обработчик на события с именем name
+func (b *Bus) Subscribe(name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], h)
+}
+
+// SubscribeAsync подписывает асинхронный обработчик на события с именем name
+func (b *Bus) SubscribeAsync(name string, h AsyncHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.async[name] = append(b.async[name], h)
+}
+
+// Publish вызывает синхронных подписчиков в порядке подписки. Публикующий вызывает его
+// внутри своей транзакции и передает ее в exec, чтобы изменения подписчиков попали в нее же.
+// Первая ошибка прерывает публикацию
+func (b *Bus) Publish(ctx context.Context, exec email.Execer, e Event) error {
+	b.mu.RLock()
+	handlers := b.handlers[e.Name()]
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := h(ctx, exec, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PublishAsync запускает асинхронных подписчиков в фоне. Вызывается после коммита,
+// чтобы подписчики не увидели отмененных изменений. Контекст запроса не отменяет обработку
+func (b *Bus) PublishAsync(ctx context.Context, e Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		b.log.Warn("Event bus is closed, event dropped", zap.String("event", e.Name()))
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	for _, h := range b.async[e.Name()] {
+		b.wg.Add(1)
+		go b.run(ctx, h, e)
+	}
+}
+
+func (b *Bus) run(ctx context.Context, h AsyncHandler, e Event) {
+	defer b.wg.Done()
+	// паника подписчика не должна ронять процесс и мешать остальным
+	defer func() {
+		if r := recover(); r != nil {
+			b.log.Error("Event handler panicked", zap.String("event", e.Name()), zap.String("panic", fmt.Sprint(r)))
+		}
+	}()
+
+	if err := h(ctx, e); err != nil {
+		b.log.Error("Event handler failed", zap.String("event", e.Name()), zap.Error(err))
+	}
+}
+
+// Wait ждет завершения уже запущенных асинхронных подписчиков
+func (b *Bus) Wait() {
+	b.wg.Wait()
+}
+
+// Close перестает принимать события для асинхронных подписчиков и ждет запущенных,
+// пока не отменен ctx
+func (b *Bus) Close(ctx context.Context) {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		b.log.Info("Event bus stopped")
+	case <-ctx.Done():
+		b.log.Warn("Event bus forcefully stopped (timeout), some handlers did not finish")
+	}
+}
@@ -0,0 +1,159 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+)
+
+var _ = Describe("Bus", func() {
+	var (
+		bus *Bus
+		ctx context.Context
+		e   OfferCreated
+	)
+
+	BeforeEach(func() {
+		bus = NewBus(zap.NewNop())
+		ctx = context.Background()
+		e = OfferCreated{Offer: entity.Offer{ID: 7}}
+	})
+
+	Describe("Publish", func() {
+		It("should call the synchronous subscribers in order with the transaction", func() {
+			tx := &sql.Tx{}
+			var calls []string
+			bus.Subscribe(NameOfferCreated, func(_ context.Context, exec email.Execer, got Event) error {
+				Expect(exec).To(BeIdenticalTo(tx))
+				Expect(got).To(Equal(e))
+				calls = append(calls, "first")
+				return nil
+			})
+			bus.Subscribe(NameOfferCreated, func(context.Context, email.Execer, Event) error {
+				calls = append(calls, "second")
+				return nil
+			})
+			bus.Subscribe(NameUserRegistered, func(context.Context, email.Execer, Event) error {
+				calls = append(calls, "other event")
+				return nil
+			})
+
+			Expect(bus.Publish(ctx, tx, e)).To(Succeed())
+			Expect(calls).To(Equal([]string{"first", "second"}))
+		})
+
+		It("should stop at the first failed subscriber", func() {
+			failure := errors.New("outbox is unavailable")
+			called := false
+			bus.Subscribe(NameOfferCreated, func(context.Context, email.Execer, Event) error { return failure })
+			bus.Subscribe(NameOfferCreated, func(context.Context, email.Execer, Event) error {
+				called = true
+				return nil
+			})
+
+			Expect(bus.Publish(ctx, nil, e)).To(MatchError(failure))
+			Expect(called).To(BeFalse())
+		})
+
+		It("should not call the asynchronous subscribers", func() {
+			bus.SubscribeAsync(NameOfferCreated, func(context.Context, Event) error {
+				Fail("asynchronous subscriber called by Publish")
+				return nil
+			})
+
+			Expect(bus.Publish(ctx, nil, e)).To(Succeed())
+			bus.Wait()
+		})
+	})
+
+	Describe("PublishAsync", func() {
+		It("should deliver the event to every asynchronous subscriber", func() {
+			var (
+				mu  sync.Mutex
+				got []Event
+			)
+			for range 3 {
+				bus.SubscribeAsync(NameOfferCreated, func(_ context.Context, ev Event) error {
+					mu.Lock()
+					defer mu.Unlock()
+					got = append(got, ev)
+					return nil
+				})
+			}
+
+			bus.PublishAsync(ctx, e)
+			bus.Wait()
+
+			Expect(got).To(Equal([]Event{e, e, e}))
+		})
+
+		It("should keep handling the event after the request is cancelled", func() {
+			reqCtx, cancel := context.WithCancel(ctx)
+			release := make(chan struct{})
+			var handlerErr error
+			bus.SubscribeAsync(NameOfferCreated, func(ctx context.Context, _ Event) error {
+				<-release
+				handlerErr = ctx.Err()
+				return nil
+			})
+
+			bus.PublishAsync(reqCtx, e)
+			cancel()
+			close(release)
+			bus.Wait()
+
+			Expect(handlerErr).NotTo(HaveOccurred())
+		})
+
+		It("should isolate failed and panicking subscribers", func() {
+			delivered := make(chan struct{}, 1)
+			bus.SubscribeAsync(NameOfferCreated, func(context.Context, Event) error { panic("boom") })
+			bus.SubscribeAsync(NameOfferCreated, func(context.Context, Event) error { return errors.New("failed") })
+			bus.SubscribeAsync(NameOfferCreated, func(context.Context, Event) error {
+				delivered <- struct{}{}
+				return nil
+			})
+
+			bus.PublishAsync(ctx, e)
+			bus.Wait()
+
+			Expect(delivered).To(Receive())
+		})
+	})
+
+	Describe("Close", func() {
+		It("should wait for the running subscribers and drop new events", func() {
+			release := make(chan struct{})
+			calls := 0
+			bus.SubscribeAsync(NameOfferCreated, func(context.Context, Event) error {
+				<-release
+				calls++
+				return nil
+			})
+			bus.PublishAsync(ctx, e)
+
+			closed := make(chan struct{})
+			go func() {
+				bus.Close(ctx)
+				close(closed)
+			}()
+			Consistently(closed, 50*time.Millisecond).ShouldNot(BeClosed())
+
+			close(release)
+			Eventually(closed).Should(BeClosed())
+
+			bus.PublishAsync(ctx, e)
+			bus.Wait()
+			Expect(calls).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,64 @@
+package event
+
+import "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+
+// Event доменное событие. По имени события шина находит подписчиков
+type Event interface {
+	Name() string
+}
+
+// Имена доменных событий
+const (
+	NameOfferCreated       = "offer.created"
+	NameOfferStatusChanged = "offer.status_changed"
+	NameUserRegistered     = "user.registered"
+	NameEmailVerified      = "user.email_verified"
+	NameReviewAdded        = "review.added"
+)
+
+// OfferCreated покупатель сделал оффер
+type OfferCreated struct {
+	Offer entity.Offer
+}
+
+func (OfferCreated) Name() string { return NameOfferCreated }
+
+// OfferStatusChanged магазин принял или отклонил оффер, покупатель его отменил или истек срок.
+// Новый статус в Offer.Status, просроченный оффер отменяется с Expired
+type OfferStatusChanged struct {
+	Offer   entity.Offer
+	Expired bool
+}
+
+func (OfferStatusChanged) Name() string { return NameOfferStatusChanged }
+
+// UserRegistered зарегистрирован новый пользователь
+type UserRegistered struct {
+	UserID   uint
+	UserName string
+	Email    string
+	Locale   string
+}
+
+func (UserRegistered) Name() string { return NameUserRegistered }
+
+// EmailVerified пользователь подтвердил почту по ссылке из письма
+type EmailVerified struct {
+	UserID   uint
+	UserName string
+	Email    string
+	Locale   string
+}
+
+func (EmailVerified) Name() string { return NameEmailVerified }
+
+// ReviewAdded оставлен новый отзыв. TargetID - товар или магазин в зависимости от ReviewType
+type ReviewAdded struct {
+	ReviewType string
+	ReviewID   int
+	TargetID   int
+	UserID     int
+	Rating     int
+}
+
+func (ReviewAdded) Name() string { return NameReviewAdded }
@@ -0,0 +1,133 @@
+package category
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=category_mock_test.go -package=category Repository
+
+type Repository interface {
+	GetCategoryByID(ctx context.Context, id int) (entity.Category, error)
+	GetAttributeSchemas(ctx context.Context, categoryID int) ([]entity.AttributeSchema, error)
+	UpsertAttributeSchema(ctx context.Context, schema entity.AttributeSchema) (entity.AttributeSchema, error)
+	DeleteAttributeSchema(ctx context.Context, categoryID int, name string) error
+}
+
+type Service struct {
+	categoryRepository Repository
+}
+
+func NewService(categoryRepo Repository) *Service {
+	return &Service{categoryRepository: categoryRepo}
+}
+
+// GetAttributeSchema возвращает схему атрибутов категории, включая унаследованные от родительских категорий
+func (cs *Service) GetAttributeSchema(
+	ctx context.Context,
+	categoryID int,
+) ([]entity.AttributeSchema, error) {
+	if _, err := cs.categoryRepository.GetCategoryByID(ctx, categoryID); err != nil {
+		return nil, err
+	}
+
+	return cs.categoryRepository.GetAttributeSchemas(ctx, categoryID)
+}
+
+// SaveAttributeSchema добавляет атрибут в схему категории или заменяет существующий с тем же именем
+func (cs *Service) SaveAttributeSchema(
+	ctx context.Context,
+	schema entity.AttributeSchema,
+) (entity.AttributeSchema, error) {
+	switch schema.Type {
+	case entity.AttributeTypeString, entity.AttributeTypeNumber, entity.AttributeTypeBoolean:
+		schema.EnumValues = nil
+	case entity.AttributeTypeEnum:
+		if len(schema.EnumValues) == 0 {
+			return entity.AttributeSchema{}, apperror.New(apperror.BadRequest,
+				"enum attribute must have at least one value", nil)
+		}
+	default:
+		return entity.AttributeSchema{}, apperror.New(apperror.BadRequest,
+			fmt.Sprintf("unknown attribute type %q", schema.Type), nil)
+	}
+
+	if _, err := cs.categoryRepository.GetCategoryByID(ctx, schema.CategoryID); err != nil {
+		return entity.AttributeSchema{}, err
+	}
+
+	return cs.categoryRepository.UpsertAttributeSchema(ctx, schema)
+}
+
+// DeleteAttributeSchema удаляет атрибут из схемы категории.
+// Унаследованные от родителя атрибуты таким образом не удаляются.
+func (cs *Service) DeleteAttributeSchema(
+	ctx context.Context,
+	categoryID int,
+	name string,
+) error {
+	return cs.categoryRepository.DeleteAttributeSchema(ctx, categoryID, name)
+}
+
+// ValidateAttributes проверяет атрибуты продукта по схеме его категории.
+// Атрибуты, не описанные в схеме, допускаются без проверки.
+func (cs *Service) ValidateAttributes(
+	ctx context.Context,
+	categoryID int,
+	attrs map[string]interface{},
+) error {
+	schemas, err := cs.GetAttributeSchema(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+
+	for _, schema := range schemas {
+		value, ok := attrs[schema.Name]
+		if !ok || value == nil {
+			if schema.Required {
+				return apperror.New(apperror.BadRequest,
+					fmt.Sprintf("attribute %q is required", schema.Name), nil)
+			}
+			continue
+		}
+
+		if err := validateAttribute(schema, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateAttribute(schema entity.AttributeSchema, value interface{}) error {
+	var ok bool
+	switch schema.Type {
+	case entity.AttributeTypeString:
+		_, ok = value.(string)
+	case entity.AttributeTypeNumber:
+		switch value.(type) {
+		case float64, float32, int, int64, int32, uint, uint64, uint32:
+			ok = true
+		}
+	case entity.AttributeTypeBoolean:
+		_, ok = value.(bool)
+	case entity.AttributeTypeEnum:
+		str, isString := value.(string)
+		if !isString || !slices.Contains(schema.EnumValues, str) {
+			return apperror.New(apperror.BadRequest,
+				fmt.Sprintf("attribute %q must be one of %v", schema.Name, schema.EnumValues), nil)
+		}
+		ok = true
+	}
+
+	if !ok {
+		return apperror.New(apperror.BadRequest,
+			fmt.Sprintf("attribute %q must be of type %s", schema.Name, schema.Type), nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: category.go
+//
+// Generated by this command:
+//
+//	mockgen -source=category.go -destination=category_mock_test.go -package=category Repository
+//
+
+// Package category is a generated GoMock package.
+package category
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteAttributeSchema mocks base method.
+func (m *MockRepository) DeleteAttributeSchema(ctx context.Context, categoryID int, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAttributeSchema", ctx, categoryID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAttributeSchema indicates an expected call of DeleteAttributeSchema.
+func (mr *MockRepositoryMockRecorder) DeleteAttributeSchema(ctx, categoryID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAttributeSchema", reflect.TypeOf((*MockRepository)(nil).DeleteAttributeSchema), ctx, categoryID, name)
+}
+
+// GetAttributeSchemas mocks base method.
+func (m *MockRepository) GetAttributeSchemas(ctx context.Context, categoryID int) ([]entity.AttributeSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttributeSchemas", ctx, categoryID)
+	ret0, _ := ret[0].([]entity.AttributeSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttributeSchemas indicates an expected call of GetAttributeSchemas.
+func (mr *MockRepositoryMockRecorder) GetAttributeSchemas(ctx, categoryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttributeSchemas", reflect.TypeOf((*MockRepository)(nil).GetAttributeSchemas), ctx, categoryID)
+}
+
+// GetCategoryByID mocks base method.
+func (m *MockRepository) GetCategoryByID(ctx context.Context, id int) (entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryByID", ctx, id)
+	ret0, _ := ret[0].(entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryByID indicates an expected call of GetCategoryByID.
+func (mr *MockRepositoryMockRecorder) GetCategoryByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryByID", reflect.TypeOf((*MockRepository)(nil).GetCategoryByID), ctx, id)
+}
+
+// UpsertAttributeSchema mocks base method.
+func (m *MockRepository) UpsertAttributeSchema(ctx context.Context, schema entity.AttributeSchema) (entity.AttributeSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertAttributeSchema", ctx, schema)
+	ret0, _ := ret[0].(entity.AttributeSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertAttributeSchema indicates an expected call of UpsertAttributeSchema.
+func (mr *MockRepositoryMockRecorder) UpsertAttributeSchema(ctx, schema any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertAttributeSchema", reflect.TypeOf((*MockRepository)(nil).UpsertAttributeSchema), ctx, schema)
+}
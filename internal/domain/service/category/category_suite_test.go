@@ -0,0 +1,13 @@
+package category
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCategory(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Category Service Suite")
+}
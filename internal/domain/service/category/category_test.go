@@ -0,0 +1,138 @@
+package category
+
+import (
+	"context"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("CategoryService", func() {
+	var (
+		ctrl     *gomock.Controller
+		mockRepo *MockRepository
+		service  *Service
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		service = NewService(mockRepo)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("SaveAttributeSchema", func() {
+		It("should reject an unknown attribute type", func() {
+			_, err := service.SaveAttributeSchema(ctx, entity.AttributeSchema{
+				CategoryID: 3,
+				Name:       "ram_gb",
+				Type:       "integer",
+			})
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.(*apperror.Error).Code()).To(Equal(apperror.BadRequest))
+		})
+
+		It("should reject an enum attribute without values", func() {
+			_, err := service.SaveAttributeSchema(ctx, entity.AttributeSchema{
+				CategoryID: 3,
+				Name:       "color",
+				Type:       entity.AttributeTypeEnum,
+			})
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.(*apperror.Error).Code()).To(Equal(apperror.BadRequest))
+		})
+
+		It("should return not found for a missing category", func() {
+			mockRepo.EXPECT().GetCategoryByID(ctx, 999).Return(entity.Category{}, apperror.ErrCategoryNotFound)
+
+			_, err := service.SaveAttributeSchema(ctx, entity.AttributeSchema{
+				CategoryID: 999,
+				Name:       "ram_gb",
+				Type:       entity.AttributeTypeNumber,
+			})
+
+			Expect(err).To(Equal(apperror.ErrCategoryNotFound))
+		})
+
+		It("should save a valid schema", func() {
+			schema := entity.AttributeSchema{
+				CategoryID: 3,
+				Name:       "ram_gb",
+				Type:       entity.AttributeTypeNumber,
+				Unit:       "GB",
+				Required:   true,
+				Filterable: true,
+			}
+			saved := schema
+			saved.ID = 1
+
+			mockRepo.EXPECT().GetCategoryByID(ctx, 3).Return(entity.Category{ID: 3}, nil)
+			mockRepo.EXPECT().UpsertAttributeSchema(ctx, schema).Return(saved, nil)
+
+			result, err := service.SaveAttributeSchema(ctx, schema)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(saved))
+		})
+	})
+
+	Describe("ValidateAttributes", func() {
+		BeforeEach(func() {
+			mockRepo.EXPECT().GetCategoryByID(ctx, 3).Return(entity.Category{ID: 3}, nil)
+			mockRepo.EXPECT().GetAttributeSchemas(ctx, 3).Return([]entity.AttributeSchema{
+				{Name: "ram_gb", Type: entity.AttributeTypeNumber, Required: true},
+				{Name: "touchscreen", Type: entity.AttributeTypeBoolean},
+				{Name: "color", Type: entity.AttributeTypeEnum, EnumValues: []string{"black", "silver"}},
+			}, nil)
+		})
+
+		It("should accept attributes matching the schema", func() {
+			err := service.ValidateAttributes(ctx, 3, map[string]interface{}{
+				"ram_gb":      float64(16),
+				"touchscreen": false,
+				"color":       "silver",
+				"cpu":         "Intel Core i7",
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject a missing required attribute", func() {
+			err := service.ValidateAttributes(ctx, 3, map[string]interface{}{
+				"color": "black",
+			})
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`"ram_gb" is required`))
+		})
+
+		It("should reject a value of the wrong type", func() {
+			err := service.ValidateAttributes(ctx, 3, map[string]interface{}{
+				"ram_gb": "16GB",
+			})
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`"ram_gb" must be of type number`))
+		})
+
+		It("should reject a value outside of the enum", func() {
+			err := service.ValidateAttributes(ctx, 3, map[string]interface{}{
+				"ram_gb": float64(8),
+				"color":  "gold",
+			})
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`"color" must be one of`))
+		})
+	})
+})
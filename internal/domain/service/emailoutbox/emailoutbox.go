@@ -0,0 +1,54 @@
+package emailoutbox
+
+import (
+	"context"
+	"slices"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=emailoutbox_mock_test.go -package=emailoutbox
+
+type Repository interface {
+	SelectOutboxMessages(ctx context.Context, status string, offset, limit int) ([]email.OutboxMessage, int, error)
+	RetryOutboxMessage(ctx context.Context, id int64) (email.OutboxMessage, error)
+}
+
+// Service дает администратору просматривать исходящие письма и повторять неотправленные.
+// Данные писем с токенами не показываются
+type Service struct {
+	outboxRepository Repository
+}
+
+func NewService(outboxRepository Repository) *Service {
+	return &Service{outboxRepository: outboxRepository}
+}
+
+func (s *Service) GetMessages(
+	ctx context.Context,
+	status string,
+	page, limit int,
+) ([]email.OutboxMessage, int, error) {
+	if !slices.Contains(email.Statuses, status) {
+		return nil, 0, apperror.New(apperror.BadRequest, "invalid status value (must be pending, sent or dead)", nil)
+	}
+	offset := (page - 1) * limit
+	msgs, total, err := s.outboxRepository.SelectOutboxMessages(ctx, status, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range msgs {
+		msgs[i] = msgs[i].Redacted()
+	}
+	return msgs, total, nil
+}
+
+// Retry ставит письмо в очередь заново со сброшенным счетчиком попыток
+func (s *Service) Retry(ctx context.Context, id int64) (email.OutboxMessage, error) {
+	msg, err := s.outboxRepository.RetryOutboxMessage(ctx, id)
+	if err != nil {
+		return email.OutboxMessage{}, err
+	}
+	return msg.Redacted(), nil
+}
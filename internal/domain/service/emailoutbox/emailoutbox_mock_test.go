@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: emailoutbox.go
+//
+// Generated by this command:
+//
+//	mockgen -source=emailoutbox.go -destination=emailoutbox_mock_test.go -package=emailoutbox
+//
+
+// Package emailoutbox is a generated GoMock package.
+package emailoutbox
+
+import (
+	context "context"
+	reflect "reflect"
+
+	email "github.com/EM-Stawberry/Stawberry/pkg/email"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// RetryOutboxMessage mocks base method.
+func (m *MockRepository) RetryOutboxMessage(ctx context.Context, id int64) (email.OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryOutboxMessage", ctx, id)
+	ret0, _ := ret[0].(email.OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryOutboxMessage indicates an expected call of RetryOutboxMessage.
+func (mr *MockRepositoryMockRecorder) RetryOutboxMessage(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryOutboxMessage", reflect.TypeOf((*MockRepository)(nil).RetryOutboxMessage), ctx, id)
+}
+
+// SelectOutboxMessages mocks base method.
+func (m *MockRepository) SelectOutboxMessages(ctx context.Context, status string, offset, limit int) ([]email.OutboxMessage, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectOutboxMessages", ctx, status, offset, limit)
+	ret0, _ := ret[0].([]email.OutboxMessage)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectOutboxMessages indicates an expected call of SelectOutboxMessages.
+func (mr *MockRepositoryMockRecorder) SelectOutboxMessages(ctx, status, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectOutboxMessages", reflect.TypeOf((*MockRepository)(nil).SelectOutboxMessages), ctx, status, offset, limit)
+}
@@ -0,0 +1,13 @@
+package emailoutbox
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestEmailOutbox(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Email Outbox Service Suite")
+}
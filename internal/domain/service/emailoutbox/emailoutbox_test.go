@@ -0,0 +1,93 @@
+package emailoutbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("EmailOutboxService", func() {
+	var (
+		ctrl     *gomock.Controller
+		mockRepo *MockRepository
+		service  *Service
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		service = NewService(mockRepo)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("GetMessages", func() {
+		It("should return a page of messages with secret data redacted", func() {
+			mockRepo.EXPECT().SelectOutboxMessages(ctx, email.StatusDead, 20, 10).Return([]email.OutboxMessage{
+				{ID: 1, Template: email.TemplatePasswordReset, Data: json.RawMessage(`{"Token":"secret"}`)},
+				{ID: 2, Template: email.TemplateRegistered, Data: json.RawMessage(`{"UserName":"Bob"}`)},
+			}, 12, nil)
+
+			msgs, total, err := service.GetMessages(ctx, email.StatusDead, 3, 10)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(total).To(Equal(12))
+			Expect(msgs).To(HaveLen(2))
+			Expect(string(msgs[0].Data)).NotTo(ContainSubstring("secret"))
+			Expect(string(msgs[1].Data)).To(Equal(`{"UserName":"Bob"}`))
+		})
+
+		It("should reject an unknown status", func() {
+			_, _, err := service.GetMessages(ctx, "failed", 1, 10)
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+
+		It("should return the repository error", func() {
+			mockRepo.EXPECT().SelectOutboxMessages(ctx, email.StatusPending, 0, 10).
+				Return(nil, 0, errors.New("db is down"))
+
+			_, _, err := service.GetMessages(ctx, email.StatusPending, 1, 10)
+
+			Expect(err).To(MatchError("db is down"))
+		})
+	})
+
+	Describe("Retry", func() {
+		It("should return the requeued message with secret data redacted", func() {
+			mockRepo.EXPECT().RetryOutboxMessage(ctx, int64(5)).Return(email.OutboxMessage{
+				ID:       5,
+				Template: email.TemplateEmailVerification,
+				Status:   email.StatusPending,
+				Data:     json.RawMessage(`{"VerifyURL":"https://example.com/verify?token=secret"}`),
+			}, nil)
+
+			msg, err := service.Retry(ctx, 5)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Status).To(Equal(email.StatusPending))
+			Expect(string(msg.Data)).NotTo(ContainSubstring("secret"))
+		})
+
+		It("should return the conflict for an already sent message", func() {
+			conflict := apperror.New(apperror.Conflict, "email 5 has already been sent", nil)
+			mockRepo.EXPECT().RetryOutboxMessage(ctx, int64(5)).Return(email.OutboxMessage{}, conflict)
+
+			_, err := service.Retry(ctx, 5)
+
+			Expect(err).To(MatchError(conflict))
+		})
+	})
+})
@@ -8,12 +8,13 @@ import (
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	guestofferrepo "github.com/EM-Stawberry/Stawberry/internal/repository/guestoffer"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
 	"go.uber.org/zap"
 )
 
 // NotificationSender interface for sending guest offer notifications
 type NotificationSender interface {
-	SendGuestOfferNotification(email string, subject string, body string)
+	GuestOffer(offer email.GuestOfferData, userMail string, locale string)
 }
 
 // Service describes the interface for the guest offer service
@@ -43,7 +44,7 @@ func NewService(
 
 // ProcessGuestOffer handles the guest offer business logic
 func (s *GuestOfferService) ProcessGuestOffer(ctx context.Context, offerData entity.GuestOfferData) error {
-	shopOwnerEmail, err := s.storeInfoGetter.GetStoreOwnerEmailByStoreID(ctx, offerData.StoreID)
+	owner, err := s.storeInfoGetter.GetStoreOwnerContactByStoreID(ctx, offerData.StoreID)
 	if err != nil {
 		s.log.Error("Failed to get shop owner email", zap.Error(err), zap.Uint("store_id", offerData.StoreID))
 
@@ -55,25 +56,16 @@ func (s *GuestOfferService) ProcessGuestOffer(ctx context.Context, offerData ent
 		return fmt.Errorf("failed to get store owner email from repository: %w", err)
 	}
 
-	emailSubject := "New guest offer received"
-	emailBody := fmt.Sprintf(
-		"A new guest offer has been received:\n\n"+
-			"Product ID: %d\n"+
-			"Store ID: %d\n"+
-			"Proposed Price: %.2f %s\n"+
-			"Guest Name: %s\n"+
-			"Guest Email: %s\n"+
-			"Guest Phone: %s",
-		offerData.ProductID,
-		offerData.StoreID,
-		offerData.Price,
-		offerData.Currency,
-		offerData.GuestName,
-		offerData.GuestEmail,
-		offerData.GuestPhone,
-	)
-
-	s.notificationSender.SendGuestOfferNotification(shopOwnerEmail, emailSubject, emailBody)
+	// the email itself is rendered by the mailer from a template in the owner's language
+	s.notificationSender.GuestOffer(email.GuestOfferData{
+		ProductID:  offerData.ProductID,
+		StoreID:    offerData.StoreID,
+		Price:      offerData.Price,
+		Currency:   offerData.Currency,
+		GuestName:  offerData.GuestName,
+		GuestEmail: offerData.GuestEmail,
+		GuestPhone: offerData.GuestPhone,
+	}, owner.Email, owner.Locale)
 
 	return nil
 }
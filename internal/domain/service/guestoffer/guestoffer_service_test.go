@@ -3,12 +3,12 @@ package guestoffer_test
 import (
 	"context"
 	"errors"
-	"fmt"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	guestofferservice "github.com/EM-Stawberry/Stawberry/internal/domain/service/guestoffer"
 	repomocks "github.com/EM-Stawberry/Stawberry/internal/repository/guestoffer"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	gomock "go.uber.org/mock/gomock"
@@ -54,33 +54,24 @@ var _ = Describe("GuestOfferService", func() {
 	Describe("ProcessGuestOffer", func() {
 		Context("when getting store owner email is successful", func() {
 			It("should send a notification and return nil", func() {
-				expectedEmail := "owner@example.com"
-
+				owner := entity.StoreOwnerContact{Email: "owner@example.com", Locale: "ru"}
 				mockStoreInfoGetter.EXPECT().
-					GetStoreOwnerEmailByStoreID(ctx, offerData.StoreID).
-					Return(expectedEmail, nil).
+					GetStoreOwnerContactByStoreID(ctx, offerData.StoreID).
+					Return(owner, nil).
 					Times(1)
 
-				expectedSubject := "New guest offer received"
-				expectedBody := fmt.Sprintf(
-					"A new guest offer has been received:\n\n"+
-						"Product ID: %d\n"+
-						"Store ID: %d\n"+
-						"Proposed Price: %.2f %s\n"+
-						"Guest Name: %s\n"+
-						"Guest Email: %s\n"+
-						"Guest Phone: %s",
-					offerData.ProductID,
-					offerData.StoreID,
-					offerData.Price,
-					offerData.Currency,
-					offerData.GuestName,
-					offerData.GuestEmail,
-					offerData.GuestPhone,
-				)
+				expectedOffer := email.GuestOfferData{
+					ProductID:  offerData.ProductID,
+					StoreID:    offerData.StoreID,
+					Price:      offerData.Price,
+					Currency:   offerData.Currency,
+					GuestName:  offerData.GuestName,
+					GuestEmail: offerData.GuestEmail,
+					GuestPhone: offerData.GuestPhone,
+				}
 
 				mockNotificationSender.EXPECT().
-					SendGuestOfferNotification(expectedEmail, expectedSubject, expectedBody).
+					GuestOffer(expectedOffer, owner.Email, owner.Locale).
 					Times(1)
 
 				err := service.ProcessGuestOffer(ctx, offerData)
@@ -93,11 +84,11 @@ var _ = Describe("GuestOfferService", func() {
 			It("should return a GuestOfferStoreNotFound error", func() {
 				repoError := apperror.NewGuestOfferError(apperror.GuestOfferStoreNotFound, "store not found")
 				mockStoreInfoGetter.EXPECT().
-					GetStoreOwnerEmailByStoreID(ctx, offerData.StoreID).
-					Return("", repoError).
+					GetStoreOwnerContactByStoreID(ctx, offerData.StoreID).
+					Return(entity.StoreOwnerContact{}, repoError).
 					Times(1)
 
-				mockNotificationSender.EXPECT().SendGuestOfferNotification(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				mockNotificationSender.EXPECT().GuestOffer(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
 				err := service.ProcessGuestOffer(ctx, offerData)
 
@@ -113,11 +104,11 @@ var _ = Describe("GuestOfferService", func() {
 			It("should return a wrapped error", func() {
 				repoError := errors.New("some database error")
 				mockStoreInfoGetter.EXPECT().
-					GetStoreOwnerEmailByStoreID(ctx, offerData.StoreID).
-					Return("", repoError).
+					GetStoreOwnerContactByStoreID(ctx, offerData.StoreID).
+					Return(entity.StoreOwnerContact{}, repoError).
 					Times(1)
 
-				mockNotificationSender.EXPECT().SendGuestOfferNotification(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				mockNotificationSender.EXPECT().GuestOffer(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
 				err := service.ProcessGuestOffer(ctx, offerData)
 
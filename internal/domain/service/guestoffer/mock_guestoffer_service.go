@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	email "github.com/EM-Stawberry/Stawberry/pkg/email"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,16 +42,16 @@ func (m *MockNotificationSender) EXPECT() *MockNotificationSenderMockRecorder {
 	return m.recorder
 }
 
-// SendGuestOfferNotification mocks base method.
-func (m *MockNotificationSender) SendGuestOfferNotification(email, subject, body string) {
+// GuestOffer mocks base method.
+func (m *MockNotificationSender) GuestOffer(offer email.GuestOfferData, userMail, locale string) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "SendGuestOfferNotification", email, subject, body)
+	m.ctrl.Call(m, "GuestOffer", offer, userMail, locale)
 }
 
-// SendGuestOfferNotification indicates an expected call of SendGuestOfferNotification.
-func (mr *MockNotificationSenderMockRecorder) SendGuestOfferNotification(email, subject, body any) *gomock.Call {
+// GuestOffer indicates an expected call of GuestOffer.
+func (mr *MockNotificationSenderMockRecorder) GuestOffer(offer, userMail, locale any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendGuestOfferNotification", reflect.TypeOf((*MockNotificationSender)(nil).SendGuestOfferNotification), email, subject, body)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GuestOffer", reflect.TypeOf((*MockNotificationSender)(nil).GuestOffer), offer, userMail, locale)
 }
 
 // MockService is a mock of Service interface.
@@ -0,0 +1,215 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+)
+
+// Dispatcher доставляет уведомления по каналам, которые получатели не отключили.
+// Письма о событиях ставятся в outbox только через него, в каждое добавляется ссылка отписки
+type Dispatcher struct {
+	notificationRepository Repository
+	publisher              Publisher
+	mailer                 email.MailerService
+	tokens                 *UnsubscribeTokens
+	unsubscribeURL         string
+	log                    *zap.Logger
+}
+
+// NewDispatcher создает диспетчер. unsubscribeURL - адрес эндпоинта отписки, токен добавляется к нему параметром
+func NewDispatcher(
+	notificationRepository Repository,
+	publisher Publisher,
+	mailer email.MailerService,
+	tokens *UnsubscribeTokens,
+	unsubscribeURL string,
+	log *zap.Logger,
+) *Dispatcher {
+	return &Dispatcher{
+		notificationRepository: notificationRepository,
+		publisher:              publisher,
+		mailer:                 mailer,
+		tokens:                 tokens,
+		unsubscribeURL:         unsubscribeURL,
+		log:                    log,
+	}
+}
+
+// Dispatch сохраняет и публикует уведомления в приложении и ставит в очередь письма о событии оффера.
+// Настройки вебхуков только хранятся, пользовательских вебхуков пока нет
+func (d *Dispatcher) Dispatch(
+	ctx context.Context,
+	eventType string,
+	offer entity.Offer,
+	notifications []entity.Notification,
+) {
+	log := d.log.With(zap.String("event", eventType), zap.Uint("offerID", offer.ID))
+
+	recipients, err := d.recipients(ctx, eventType, notifications)
+	if err != nil {
+		log.Error("Failed to get notification recipients", zap.Error(err))
+		return
+	}
+
+	// письма могли быть уже записаны в транзакции события, повтор отбросится по ключу идемпотентности
+	msgs, err := d.emails(ctx, eventType, offer, notifications, recipients)
+	if err != nil {
+		log.Error("Failed to build emails", zap.Error(err))
+	} else if len(msgs) > 0 {
+		if err := d.mailer.Enqueue(ctx, nil, msgs...); err != nil {
+			log.Error("Failed to enqueue emails", zap.Error(err))
+		}
+	}
+
+	inApp := make([]entity.Notification, 0, len(notifications))
+	for _, n := range notifications {
+		if recipient, ok := recipients[n.UserID]; ok && recipient.Enabled(entity.ChannelInApp) {
+			inApp = append(inApp, n)
+		}
+	}
+
+	if len(inApp) == 0 {
+		return
+	}
+	saved, err := d.notificationRepository.InsertNotifications(ctx, inApp)
+	if err != nil {
+		log.Error("Failed to create notifications", zap.Error(err))
+		return
+	}
+
+	// уведомления уже сохранены, без доставки клиенты получат их при следующем запросе
+	if err := d.publisher.Publish(ctx, saved); err != nil {
+		log.Warn("Failed to publish notifications", zap.Error(err))
+	}
+}
+
+// QueueEmails записывает письма о событии оффера через exec, например в транзакции события
+func (d *Dispatcher) QueueEmails(
+	ctx context.Context,
+	exec email.Execer,
+	eventType string,
+	offer entity.Offer,
+	notifications []entity.Notification,
+) error {
+	recipients, err := d.recipients(ctx, eventType, notifications)
+	if err != nil {
+		return err
+	}
+	msgs, err := d.emails(ctx, eventType, offer, notifications, recipients)
+	if err != nil || len(msgs) == 0 {
+		return err
+	}
+	return d.mailer.Enqueue(ctx, exec, msgs...)
+}
+
+// Welcome ставит в очередь приветственное письмо новому пользователю. Пользователь
+// получает его один раз, даже если позже сменит и заново подтвердит почту
+func (d *Dispatcher) Welcome(userID uint, userName, userMail, locale string) {
+	d.mailer.Registered(userID, userName, userMail, locale)
+}
+
+// Unsubscribe отключает письма о типе событий, указанном в подписанном токене из письма
+func (d *Dispatcher) Unsubscribe(ctx context.Context, token string) error {
+	userID, event, err := d.tokens.Verify(token)
+	if err != nil {
+		return err
+	}
+	if !validEvent(event) {
+		return apperror.ErrInvalidToken
+	}
+
+	return d.notificationRepository.UpsertNotificationPreferences(ctx, userID, []entity.NotificationPreference{
+		{Event: event, Channel: entity.ChannelEmail, Enabled: false},
+	})
+}
+
+// recipients возвращает настройки и контакты получателей уведомлений по их id
+func (d *Dispatcher) recipients(
+	ctx context.Context,
+	eventType string,
+	notifications []entity.Notification,
+) (map[uint]entity.NotificationRecipient, error) {
+	userIDs := make([]uint, len(notifications))
+	for i, n := range notifications {
+		userIDs[i] = n.UserID
+	}
+	recipients, err := d.notificationRepository.SelectRecipients(ctx, userIDs, entity.NotificationEvent(eventType))
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[uint]entity.NotificationRecipient, len(recipients))
+	for _, r := range recipients {
+		byUser[r.UserID] = r
+	}
+	return byUser, nil
+}
+
+// emails собирает письма получателям, у которых включены письма о событии: магазину о новом
+// и отмененном оффере, остальным о смене статуса. Ключ идемпотентности не дает отправить
+// одно письмо о событии дважды
+func (d *Dispatcher) emails(
+	ctx context.Context,
+	eventType string,
+	offer entity.Offer,
+	notifications []entity.Notification,
+	recipients map[uint]entity.NotificationRecipient,
+) ([]email.Message, error) {
+	var to []entity.NotificationRecipient
+	for _, n := range notifications {
+		recipient, ok := recipients[n.UserID]
+		if ok && recipient.Enabled(entity.ChannelEmail) && recipient.Email != "" {
+			to = append(to, recipient)
+		}
+	}
+	if len(to) == 0 {
+		return nil, nil
+	}
+
+	// вариант могли удалить, пока оффер жив. Письмо уходит без названий товара и магазина,
+	// иначе ошибка откатила бы само событие оффера
+	details, err := d.notificationRepository.SelectOfferDetails(ctx, offer.ShopID, offer.VariantID)
+	if err != nil && !errors.Is(err, apperror.ErrVariantNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		d.log.Warn("Offer details not found, sending emails without them",
+			zap.Uint("offerID", offer.ID), zap.Uint("variantID", offer.VariantID))
+	}
+
+	msgs := make([]email.Message, 0, len(to))
+	for _, recipient := range to {
+		link := d.unsubscribeURL + "?token=" + url.QueryEscape(d.tokens.Sign(recipient.UserID, recipient.Event))
+
+		var msg email.Message
+		switch eventType {
+		case entity.NotificationOfferCreated:
+			msg = email.OfferReceivedMessage(email.OfferReceivedData{
+				OfferID: offer.ID, ProductName: details.ProductName, VariantSKU: details.VariantSKU,
+				ShopName: details.ShopName, Price: offer.Price, Currency: offer.Currency,
+			}, recipient.Email, recipient.Locale, link)
+		case entity.NotificationOfferCancelled:
+			msg = email.OfferCancelledMessage(email.OfferCancelledData{
+				OfferID: offer.ID, ProductName: details.ProductName, VariantSKU: details.VariantSKU,
+				ShopName: details.ShopName, Price: offer.Price, Currency: offer.Currency,
+			}, recipient.Email, recipient.Locale, link)
+		default:
+			msg = email.StatusUpdateMessage(email.StatusUpdateData{
+				OfferID: offer.ID, Status: strings.TrimPrefix(eventType, "offer_"),
+				ProductName: details.ProductName, ShopName: details.ShopName,
+			}, recipient.Email, recipient.Locale, link)
+		}
+		msg.IdempotencyKey = fmt.Sprintf("%s:%d:%d", eventType, offer.ID, recipient.UserID)
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
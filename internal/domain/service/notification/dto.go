@@ -1,4 +0,0 @@
-package notification
-
-// пока не нужно, но может понадобиться
-// type Notification struct{}
@@ -0,0 +1,83 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+)
+
+// EventBus шина доменных событий, на которые подписываются уведомления
+type EventBus interface {
+	Subscribe(name string, h event.Handler)
+	SubscribeAsync(name string, h event.AsyncHandler)
+}
+
+// statusNotifications сопоставляет новый статус оффера с типом уведомления
+var statusNotifications = map[string]string{
+	"accepted":  entity.NotificationOfferAccepted,
+	"declined":  entity.NotificationOfferDeclined,
+	"cancelled": entity.NotificationOfferCancelled,
+}
+
+// SubscribeEvents подписывает уведомления на доменные события. Письма о событиях оффера
+// пишутся в outbox синхронно, в транзакции события, а уведомления в приложении
+// и приветственное письмо отправляются в фоне. Приветствие уходит после подтверждения почты,
+// до него пользователь получает только ссылку подтверждения
+func (ns *Service) SubscribeEvents(bus EventBus) {
+	bus.Subscribe(event.NameOfferCreated, ns.queueOfferEventEmails)
+	bus.Subscribe(event.NameOfferStatusChanged, ns.queueOfferEventEmails)
+	bus.SubscribeAsync(event.NameOfferCreated, ns.notifyOfferEvent)
+	bus.SubscribeAsync(event.NameOfferStatusChanged, ns.notifyOfferEvent)
+	bus.SubscribeAsync(event.NameEmailVerified, ns.welcome)
+}
+
+func (ns *Service) queueOfferEventEmails(ctx context.Context, exec email.Execer, e event.Event) error {
+	eventType, offer, err := offerEvent(e)
+	if err != nil {
+		return err
+	}
+	return ns.QueueOfferEmails(ctx, exec, eventType, offer)
+}
+
+func (ns *Service) notifyOfferEvent(ctx context.Context, e event.Event) error {
+	eventType, offer, err := offerEvent(e)
+	if err != nil {
+		return err
+	}
+	ns.NotifyOfferEvent(ctx, eventType, offer)
+	return nil
+}
+
+func (ns *Service) welcome(_ context.Context, e event.Event) error {
+	verified, ok := e.(event.EmailVerified)
+	if !ok {
+		return unexpectedEvent(e)
+	}
+	ns.dispatcher.Welcome(verified.UserID, verified.UserName, verified.Email, verified.Locale)
+	return nil
+}
+
+// offerEvent возвращает тип уведомления и оффер доменного события
+func offerEvent(e event.Event) (string, entity.Offer, error) {
+	switch e := e.(type) {
+	case event.OfferCreated:
+		return entity.NotificationOfferCreated, e.Offer, nil
+	case event.OfferStatusChanged:
+		if e.Expired {
+			return entity.NotificationOfferExpired, e.Offer, nil
+		}
+		if eventType, ok := statusNotifications[e.Offer.Status]; ok {
+			return eventType, e.Offer, nil
+		}
+		return "", entity.Offer{}, apperror.New(apperror.InternalError, "unknown offer status "+e.Offer.Status, nil)
+	default:
+		return "", entity.Offer{}, unexpectedEvent(e)
+	}
+}
+
+func unexpectedEvent(e event.Event) error {
+	return apperror.New(apperror.InternalError, "unexpected event "+e.Name(), nil)
+}
@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"context"
+	"sync"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+// subscriberBuffer сколько событий может накопиться у медленного подписчика
+const subscriberBuffer = 32
+
+// Hub рассылает уведомления подписчикам внутри процесса.
+// Между репликами события приходят через Postgres (см. adapter/pgnotify), Hub об этом не знает
+type Hub struct {
+	mu   sync.Mutex
+	subs map[uint]map[chan entity.Notification]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[uint]map[chan entity.Notification]struct{})}
+}
+
+// Subscribe подписывает на уведомления пользователя. Канал закрывается после вызова
+// возвращенной функции или если подписчик не успевает читать события,
+// тогда клиент должен переподключиться и дочитать пропущенное по Last-Event-ID
+func (h *Hub) Subscribe(userID uint) (<-chan entity.Notification, func()) {
+	ch := make(chan entity.Notification, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[chan entity.Notification]struct{})
+	}
+	h.subs[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.remove(userID, ch)
+		})
+	}
+}
+
+// Publish отправляет уведомления подписчикам их получателей, не блокируясь на медленных
+func (h *Hub) Publish(_ context.Context, notifications []entity.Notification) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, n := range notifications {
+		for ch := range h.subs[n.UserID] {
+			select {
+			case ch <- n:
+			default:
+				h.remove(n.UserID, ch)
+			}
+		}
+	}
+	return nil
+}
+
+// CloseAll закрывает все подписки. Клиенты переподключаются и дочитывают пропущенное
+// по Last-Event-ID, например после обрыва соединения, через которое приходили события
+func (h *Hub) CloseAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for userID, subs := range h.subs {
+		for ch := range subs {
+			h.remove(userID, ch)
+		}
+	}
+}
+
+// remove вызывается под мьютексом, поэтому канал закрывается ровно один раз
+func (h *Hub) remove(userID uint, ch chan entity.Notification) {
+	if _, ok := h.subs[userID][ch]; !ok {
+		return
+	}
+	delete(h.subs[userID], ch)
+	if len(h.subs[userID]) == 0 {
+		delete(h.subs, userID)
+	}
+	close(ch)
+}
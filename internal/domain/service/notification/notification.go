@@ -1,19 +1,234 @@
 package notification
 
-import "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=notification_mock_test.go -package=notification Repository Publisher
+
+// maxResumeBacklog сколько пропущенных уведомлений досылается при переподключении к потоку.
+// Если пропущено больше, клиент перечитывает уведомления целиком
+const maxResumeBacklog = 100
 
 type Repository interface {
-	SelectUserNotifications(id string, offset, limit int) ([]entity.Notification, int, error)
+	InsertNotifications(ctx context.Context, notifications []entity.Notification) ([]entity.Notification, error)
+	SelectNotificationsAfter(
+		ctx context.Context, userID uint, afterID uint, limit int,
+	) ([]entity.Notification, error)
+	SelectUserNotifications(
+		ctx context.Context, userID uint, unreadOnly bool, offset, limit int,
+	) ([]entity.Notification, int, error)
+	CountUnreadNotifications(ctx context.Context, userID uint) (int, error)
+	MarkNotificationRead(ctx context.Context, userID uint, notificationID uint) error
+	MarkAllNotificationsRead(ctx context.Context, userID uint) (int, error)
+	SelectShopManagerIDs(ctx context.Context, shopID uint) ([]uint, error)
+	SelectOfferDetails(ctx context.Context, shopID, variantID uint) (entity.OfferDetails, error)
+	SelectNotificationPreferences(ctx context.Context, userID uint) ([]entity.NotificationPreference, error)
+	UpsertNotificationPreferences(ctx context.Context, userID uint, preferences []entity.NotificationPreference) error
+	SelectRecipients(ctx context.Context, userIDs []uint, event string) ([]entity.NotificationRecipient, error)
+}
+
+// Publisher доставляет созданные уведомления подписчикам всех реплик
+type Publisher interface {
+	Publish(ctx context.Context, notifications []entity.Notification) error
 }
 
 type Service struct {
 	notificationRepository Repository
+	hub                    *Hub
+	dispatcher             *Dispatcher
+	log                    *zap.Logger
+}
+
+// NewService создает сервис уведомлений. Подписки обслуживает hub этого процесса,
+// а новые уведомления доставляет dispatcher с учетом настроек получателей
+func NewService(notificationRepository Repository, hub *Hub, dispatcher *Dispatcher, log *zap.Logger) *Service {
+	return &Service{
+		notificationRepository: notificationRepository,
+		hub:                    hub,
+		dispatcher:             dispatcher,
+		log:                    log,
+	}
+}
+
+func (ns *Service) GetUserNotifications(
+	ctx context.Context,
+	userID uint,
+	unreadOnly bool,
+	page, limit int,
+) ([]entity.Notification, int, error) {
+	offset := (page - 1) * limit
+	return ns.notificationRepository.SelectUserNotifications(ctx, userID, unreadOnly, offset, limit)
+}
+
+func (ns *Service) CountUnread(ctx context.Context, userID uint) (int, error) {
+	return ns.notificationRepository.CountUnreadNotifications(ctx, userID)
+}
+
+func (ns *Service) MarkRead(ctx context.Context, userID uint, notificationID uint) error {
+	return ns.notificationRepository.MarkNotificationRead(ctx, userID, notificationID)
 }
 
-func NewService(notificationRepository Repository) *Service {
-	return &Service{notificationRepository}
+func (ns *Service) MarkAllRead(ctx context.Context, userID uint) (int, error) {
+	return ns.notificationRepository.MarkAllNotificationsRead(ctx, userID)
 }
 
-func (ns *Service) GetNotification(id string, offset int, limit int) ([]entity.Notification, int, error) {
-	return ns.notificationRepository.SelectUserNotifications(id, offset, limit)
+// NotifyOfferEvent уведомляет о событии оффера по включенным у получателей каналам.
+// О новых и отмененных офферах узнают владельцы и менеджеры магазина, о решении магазина - покупатель,
+// об истечении срока - обе стороны.
+// Ошибка только логируется: уведомление не должно откатывать уже выполненное действие с оффером
+func (ns *Service) NotifyOfferEvent(ctx context.Context, eventType string, offer entity.Offer) {
+	log := ns.log.With(zap.String("event", eventType), zap.Uint("offerID", offer.ID))
+
+	notifications, err := ns.offerNotifications(ctx, eventType, offer)
+	if err != nil {
+		log.Error("Failed to build notifications", zap.Error(err))
+		return
+	}
+	if len(notifications) == 0 {
+		return
+	}
+	ns.dispatcher.Dispatch(ctx, eventType, offer, notifications)
+}
+
+// QueueOfferEmails записывает письма о событии оффера через exec, обычно транзакцию самого события.
+// Повторная запись тех же писем из NotifyOfferEvent отбрасывается по ключу идемпотентности
+func (ns *Service) QueueOfferEmails(
+	ctx context.Context,
+	exec email.Execer,
+	eventType string,
+	offer entity.Offer,
+) error {
+	notifications, err := ns.offerNotifications(ctx, eventType, offer)
+	if err != nil {
+		return err
+	}
+	return ns.dispatcher.QueueEmails(ctx, exec, eventType, offer, notifications)
+}
+
+// offerNotifications определяет получателей и текст уведомлений о событии оффера
+func (ns *Service) offerNotifications(
+	ctx context.Context,
+	eventType string,
+	offer entity.Offer,
+) ([]entity.Notification, error) {
+	var (
+		toBuyer string
+		toShop  string
+	)
+	switch eventType {
+	case entity.NotificationOfferCreated:
+		toShop = fmt.Sprintf("New offer #%d: %.2f %s", offer.ID, offer.Price, offer.Currency)
+	case entity.NotificationOfferAccepted:
+		toBuyer = fmt.Sprintf("Your offer #%d was accepted", offer.ID)
+	case entity.NotificationOfferDeclined:
+		toBuyer = fmt.Sprintf("Your offer #%d was declined", offer.ID)
+	case entity.NotificationOfferCancelled:
+		toShop = fmt.Sprintf("Offer #%d was cancelled by the buyer", offer.ID)
+	case entity.NotificationOfferExpired:
+		toBuyer = fmt.Sprintf("Your offer #%d has expired", offer.ID)
+		toShop = fmt.Sprintf("Offer #%d has expired", offer.ID)
+	default:
+		return nil, apperror.New(apperror.InternalError, "unknown offer event "+eventType, nil)
+	}
+
+	offerID := offer.ID
+	var notifications []entity.Notification
+	if toBuyer != "" {
+		notifications = append(notifications, entity.Notification{
+			UserID: offer.UserID, Type: eventType, OfferID: &offerID, Message: toBuyer,
+		})
+	}
+	if toShop != "" {
+		managers, err := ns.notificationRepository.SelectShopManagerIDs(ctx, offer.ShopID)
+		if err != nil {
+			return nil, err
+		}
+		for _, userID := range managers {
+			notifications = append(notifications, entity.Notification{
+				UserID: userID, Type: eventType, OfferID: &offerID, Message: toShop,
+			})
+		}
+	}
+	return notifications, nil
+}
+
+// Stream возвращает канал новых уведомлений пользователя. Если передан lastEventID,
+// сначала досылаются уведомления, созданные после него. Если их больше maxResumeBacklog,
+// они не досылаются, а resync сообщает, что клиенту нужно перечитать уведомления целиком.
+// Канал закрывается при отмене контекста или если клиент не успевает читать,
+// тогда ему нужно переподключиться
+func (ns *Service) Stream(
+	ctx context.Context,
+	userID uint,
+	lastEventID uint,
+) (<-chan entity.Notification, bool, error) {
+	// подписываемся до чтения пропущенного, чтобы не потерять события между запросом и подпиской
+	live, unsubscribe := ns.hub.Subscribe(userID)
+
+	var (
+		backlog []entity.Notification
+		resync  bool
+	)
+	if lastEventID > 0 {
+		var err error
+		backlog, err = ns.notificationRepository.SelectNotificationsAfter(ctx, userID, lastEventID,
+			maxResumeBacklog+1)
+		if err != nil {
+			unsubscribe()
+			return nil, false, err
+		}
+		if len(backlog) > maxResumeBacklog {
+			backlog, resync = nil, true
+		}
+	}
+
+	out := make(chan entity.Notification)
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+
+		lastID := lastEventID
+		send := func(n entity.Notification) bool {
+			select {
+			case out <- n:
+				lastID = n.ID
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for _, n := range backlog {
+			if !send(n) {
+				return
+			}
+		}
+		for {
+			select {
+			case n, ok := <-live:
+				if !ok {
+					return
+				}
+				// событие могло уже прийти вместе с пропущенными
+				if n.ID <= lastID {
+					continue
+				}
+				if !send(n) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, resync, nil
 }
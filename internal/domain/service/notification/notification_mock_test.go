@@ -0,0 +1,244 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: notification.go
+//
+// Generated by this command:
+//
+//	mockgen -source=notification.go -destination=notification_mock_test.go -package=notification Repository Publisher
+//
+
+// Package notification is a generated GoMock package.
+package notification
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockRepository) CountUnreadNotifications(ctx context.Context, userID uint) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockRepositoryMockRecorder) CountUnreadNotifications(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockRepository)(nil).CountUnreadNotifications), ctx, userID)
+}
+
+// InsertNotifications mocks base method.
+func (m *MockRepository) InsertNotifications(ctx context.Context, notifications []entity.Notification) ([]entity.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertNotifications", ctx, notifications)
+	ret0, _ := ret[0].([]entity.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertNotifications indicates an expected call of InsertNotifications.
+func (mr *MockRepositoryMockRecorder) InsertNotifications(ctx, notifications any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertNotifications", reflect.TypeOf((*MockRepository)(nil).InsertNotifications), ctx, notifications)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockRepository) MarkAllNotificationsRead(ctx context.Context, userID uint) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockRepositoryMockRecorder) MarkAllNotificationsRead(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockRepository)(nil).MarkAllNotificationsRead), ctx, userID)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockRepository) MarkNotificationRead(ctx context.Context, userID, notificationID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", ctx, userID, notificationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockRepositoryMockRecorder) MarkNotificationRead(ctx, userID, notificationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockRepository)(nil).MarkNotificationRead), ctx, userID, notificationID)
+}
+
+// SelectNotificationPreferences mocks base method.
+func (m *MockRepository) SelectNotificationPreferences(ctx context.Context, userID uint) ([]entity.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectNotificationPreferences", ctx, userID)
+	ret0, _ := ret[0].([]entity.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectNotificationPreferences indicates an expected call of SelectNotificationPreferences.
+func (mr *MockRepositoryMockRecorder) SelectNotificationPreferences(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectNotificationPreferences", reflect.TypeOf((*MockRepository)(nil).SelectNotificationPreferences), ctx, userID)
+}
+
+// SelectNotificationsAfter mocks base method.
+func (m *MockRepository) SelectNotificationsAfter(ctx context.Context, userID, afterID uint, limit int) ([]entity.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectNotificationsAfter", ctx, userID, afterID, limit)
+	ret0, _ := ret[0].([]entity.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectNotificationsAfter indicates an expected call of SelectNotificationsAfter.
+func (mr *MockRepositoryMockRecorder) SelectNotificationsAfter(ctx, userID, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectNotificationsAfter", reflect.TypeOf((*MockRepository)(nil).SelectNotificationsAfter), ctx, userID, afterID, limit)
+}
+
+// SelectOfferDetails mocks base method.
+func (m *MockRepository) SelectOfferDetails(ctx context.Context, shopID, variantID uint) (entity.OfferDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectOfferDetails", ctx, shopID, variantID)
+	ret0, _ := ret[0].(entity.OfferDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectOfferDetails indicates an expected call of SelectOfferDetails.
+func (mr *MockRepositoryMockRecorder) SelectOfferDetails(ctx, shopID, variantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectOfferDetails", reflect.TypeOf((*MockRepository)(nil).SelectOfferDetails), ctx, shopID, variantID)
+}
+
+// SelectRecipients mocks base method.
+func (m *MockRepository) SelectRecipients(ctx context.Context, userIDs []uint, event string) ([]entity.NotificationRecipient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectRecipients", ctx, userIDs, event)
+	ret0, _ := ret[0].([]entity.NotificationRecipient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectRecipients indicates an expected call of SelectRecipients.
+func (mr *MockRepositoryMockRecorder) SelectRecipients(ctx, userIDs, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectRecipients", reflect.TypeOf((*MockRepository)(nil).SelectRecipients), ctx, userIDs, event)
+}
+
+// SelectShopManagerIDs mocks base method.
+func (m *MockRepository) SelectShopManagerIDs(ctx context.Context, shopID uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectShopManagerIDs", ctx, shopID)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectShopManagerIDs indicates an expected call of SelectShopManagerIDs.
+func (mr *MockRepositoryMockRecorder) SelectShopManagerIDs(ctx, shopID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectShopManagerIDs", reflect.TypeOf((*MockRepository)(nil).SelectShopManagerIDs), ctx, shopID)
+}
+
+// SelectUserNotifications mocks base method.
+func (m *MockRepository) SelectUserNotifications(ctx context.Context, userID uint, unreadOnly bool, offset, limit int) ([]entity.Notification, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectUserNotifications", ctx, userID, unreadOnly, offset, limit)
+	ret0, _ := ret[0].([]entity.Notification)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectUserNotifications indicates an expected call of SelectUserNotifications.
+func (mr *MockRepositoryMockRecorder) SelectUserNotifications(ctx, userID, unreadOnly, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectUserNotifications", reflect.TypeOf((*MockRepository)(nil).SelectUserNotifications), ctx, userID, unreadOnly, offset, limit)
+}
+
+// UpsertNotificationPreferences mocks base method.
+func (m *MockRepository) UpsertNotificationPreferences(ctx context.Context, userID uint, preferences []entity.NotificationPreference) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotificationPreferences", ctx, userID, preferences)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertNotificationPreferences indicates an expected call of UpsertNotificationPreferences.
+func (mr *MockRepositoryMockRecorder) UpsertNotificationPreferences(ctx, userID, preferences any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationPreferences", reflect.TypeOf((*MockRepository)(nil).UpsertNotificationPreferences), ctx, userID, preferences)
+}
+
+// MockPublisher is a mock of Publisher interface.
+type MockPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockPublisherMockRecorder is the mock recorder for MockPublisher.
+type MockPublisherMockRecorder struct {
+	mock *MockPublisher
+}
+
+// NewMockPublisher creates a new mock instance.
+func NewMockPublisher(ctrl *gomock.Controller) *MockPublisher {
+	mock := &MockPublisher{ctrl: ctrl}
+	mock.recorder = &MockPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublisher) EXPECT() *MockPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockPublisher) Publish(ctx context.Context, notifications []entity.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, notifications)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockPublisherMockRecorder) Publish(ctx, notifications any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockPublisher)(nil).Publish), ctx, notifications)
+}
@@ -0,0 +1,527 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+	"github.com/EM-Stawberry/Stawberry/pkg/email/mock_email"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("NotificationService", func() {
+	var (
+		ctrl          *gomock.Controller
+		mockRepo      *MockRepository
+		mockPublisher *MockPublisher
+		mockMailer    *mock_email.MockMailerService
+		tokens        *UnsubscribeTokens
+		hub           *Hub
+		service       *Service
+		ctx           context.Context
+		offer         entity.Offer
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockPublisher = NewMockPublisher(ctrl)
+		mockMailer = mock_email.NewMockMailerService(ctrl)
+		tokens = NewUnsubscribeTokens("secret")
+		hub = NewHub()
+		dispatcher := NewDispatcher(mockRepo, mockPublisher, mockMailer, tokens,
+			"https://example.com/unsubscribe", zap.NewNop())
+		service = NewService(mockRepo, hub, dispatcher, zap.NewNop())
+		ctx = context.Background()
+		offer = entity.Offer{ID: 7, UserID: 3, ShopID: 2, VariantID: 4, Price: 99.5, Currency: "USD"}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	recipients := func(notifications []entity.Notification) []uint {
+		ids := make([]uint, len(notifications))
+		for i, n := range notifications {
+			ids[i] = n.UserID
+			Expect(*n.OfferID).To(Equal(uint(7)))
+		}
+		return ids
+	}
+
+	details := entity.OfferDetails{ProductName: "Strawberry jam", VariantSKU: "JAM-250", ShopName: "Berry Shop"}
+
+	// recipient с пустыми Channels получает уведомления по каналам по умолчанию
+	recipient := func(userID uint, event string, channels map[string]bool) entity.NotificationRecipient {
+		return entity.NotificationRecipient{
+			UserID: userID, Email: fmt.Sprintf("user%d@example.com", userID), Locale: "ru",
+			Event: event, Channels: channels,
+		}
+	}
+
+	Describe("NotifyOfferEvent", func() {
+		It("should notify shop managers about a new offer", func() {
+			mockRepo.EXPECT().SelectShopManagerIDs(ctx, uint(2)).Return([]uint{1, 5}, nil)
+			mockRepo.EXPECT().SelectRecipients(ctx, []uint{1, 5}, entity.EventOfferReceived).
+				Return([]entity.NotificationRecipient{
+					recipient(1, entity.EventOfferReceived, nil),
+					recipient(5, entity.EventOfferReceived, map[string]bool{entity.ChannelEmail: false}),
+				}, nil)
+			mockRepo.EXPECT().SelectOfferDetails(ctx, uint(2), uint(4)).Return(details, nil)
+			mockMailer.EXPECT().Enqueue(ctx, gomock.Nil(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ email.Execer, msgs ...email.Message) error {
+					Expect(msgs).To(HaveLen(1))
+					Expect(msgs[0].To).To(Equal("user1@example.com"))
+					Expect(msgs[0].Template).To(Equal(email.TemplateOfferReceived))
+					Expect(msgs[0].Data).To(Equal(email.OfferReceivedData{
+						OfferID: 7, ProductName: "Strawberry jam", VariantSKU: "JAM-250", ShopName: "Berry Shop",
+						Price: 99.5, Currency: "USD",
+					}))
+					Expect(msgs[0].Locale).To(Equal("ru"))
+					Expect(msgs[0].IdempotencyKey).To(Equal("offer_created:7:1"))
+					return nil
+				})
+			mockRepo.EXPECT().InsertNotifications(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, batch []entity.Notification) ([]entity.Notification, error) {
+					Expect(recipients(batch)).To(Equal([]uint{1, 5}))
+					Expect(batch[0].Type).To(Equal(entity.NotificationOfferCreated))
+					Expect(batch[0].Message).To(ContainSubstring("99.50 USD"))
+					return batch, nil
+				})
+			mockPublisher.EXPECT().Publish(ctx, gomock.Len(2)).Return(nil)
+
+			service.NotifyOfferEvent(ctx, entity.NotificationOfferCreated, offer)
+		})
+
+		It("should notify only the buyer about the shop decision", func() {
+			mockRepo.EXPECT().SelectRecipients(ctx, []uint{3}, entity.EventOfferStatus).
+				Return([]entity.NotificationRecipient{recipient(3, entity.EventOfferStatus, nil)}, nil)
+			mockRepo.EXPECT().SelectOfferDetails(ctx, uint(2), uint(4)).Return(details, nil)
+			mockMailer.EXPECT().Enqueue(ctx, gomock.Nil(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ email.Execer, msgs ...email.Message) error {
+					Expect(msgs).To(HaveLen(1))
+					Expect(msgs[0].Data).To(Equal(email.StatusUpdateData{
+						OfferID: 7, Status: "accepted", ProductName: "Strawberry jam", ShopName: "Berry Shop",
+					}))
+					return errors.New("db is down")
+				})
+			mockRepo.EXPECT().InsertNotifications(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, batch []entity.Notification) ([]entity.Notification, error) {
+					Expect(recipients(batch)).To(Equal([]uint{3}))
+					Expect(batch[0].Type).To(Equal(entity.NotificationOfferAccepted))
+					return batch, nil
+				})
+			mockPublisher.EXPECT().Publish(ctx, gomock.Len(1)).Return(errors.New("publish failed"))
+
+			service.NotifyOfferEvent(ctx, entity.NotificationOfferAccepted, offer)
+		})
+
+		It("should notify both sides about an expired offer", func() {
+			mockRepo.EXPECT().SelectShopManagerIDs(ctx, uint(2)).Return([]uint{1}, nil)
+			mockRepo.EXPECT().SelectRecipients(ctx, []uint{3, 1}, entity.EventOfferStatus).
+				Return([]entity.NotificationRecipient{
+					recipient(1, entity.EventOfferStatus, map[string]bool{entity.ChannelEmail: false}),
+					recipient(3, entity.EventOfferStatus, map[string]bool{entity.ChannelEmail: false}),
+				}, nil)
+			mockRepo.EXPECT().InsertNotifications(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, batch []entity.Notification) ([]entity.Notification, error) {
+					Expect(recipients(batch)).To(Equal([]uint{3, 1}))
+					return batch, nil
+				})
+			mockPublisher.EXPECT().Publish(ctx, gomock.Len(2)).Return(nil)
+
+			service.NotifyOfferEvent(ctx, entity.NotificationOfferExpired, offer)
+		})
+
+		It("should send the email with a link that unsubscribes from the event", func() {
+			mockRepo.EXPECT().SelectRecipients(ctx, []uint{3}, entity.EventOfferStatus).
+				Return([]entity.NotificationRecipient{
+					recipient(3, entity.EventOfferStatus, map[string]bool{entity.ChannelInApp: false}),
+				}, nil)
+			mockRepo.EXPECT().SelectOfferDetails(ctx, uint(2), uint(4)).Return(details, nil)
+			mockMailer.EXPECT().Enqueue(ctx, gomock.Nil(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ email.Execer, msgs ...email.Message) error {
+					Expect(msgs).To(HaveLen(1))
+					link := msgs[0].UnsubscribeURL
+					Expect(link).To(HavePrefix("https://example.com/unsubscribe?token="))
+					parsed, err := url.Parse(link)
+					Expect(err).NotTo(HaveOccurred())
+
+					userID, event, err := tokens.Verify(parsed.Query().Get("token"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(userID).To(Equal(uint(3)))
+					Expect(event).To(Equal(entity.EventOfferStatus))
+					return nil
+				})
+
+			service.NotifyOfferEvent(ctx, entity.NotificationOfferDeclined, offer)
+		})
+
+		It("should send the shop a cancellation email with the product details", func() {
+			mockRepo.EXPECT().SelectShopManagerIDs(ctx, uint(2)).Return([]uint{1}, nil)
+			mockRepo.EXPECT().SelectRecipients(ctx, []uint{1}, entity.EventOfferStatus).
+				Return([]entity.NotificationRecipient{
+					recipient(1, entity.EventOfferStatus, map[string]bool{entity.ChannelInApp: false}),
+				}, nil)
+			mockRepo.EXPECT().SelectOfferDetails(ctx, uint(2), uint(4)).Return(details, nil)
+			mockMailer.EXPECT().Enqueue(ctx, gomock.Nil(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ email.Execer, msgs ...email.Message) error {
+					Expect(msgs).To(HaveLen(1))
+					Expect(msgs[0].To).To(Equal("user1@example.com"))
+					Expect(msgs[0].Template).To(Equal(email.TemplateOfferCancelled))
+					Expect(msgs[0].Data).To(HaveField("ProductName", "Strawberry jam"))
+					Expect(msgs[0].IdempotencyKey).To(Equal("offer_cancelled:7:1"))
+					return nil
+				})
+
+			service.NotifyOfferEvent(ctx, entity.NotificationOfferCancelled, offer)
+		})
+
+		It("should still notify in the app when the offer details cannot be loaded", func() {
+			mockRepo.EXPECT().SelectRecipients(ctx, []uint{3}, entity.EventOfferStatus).
+				Return([]entity.NotificationRecipient{recipient(3, entity.EventOfferStatus, nil)}, nil)
+			mockRepo.EXPECT().SelectOfferDetails(ctx, uint(2), uint(4)).
+				Return(entity.OfferDetails{}, errors.New("db is down"))
+			mockRepo.EXPECT().InsertNotifications(ctx, gomock.Len(1)).Return([]entity.Notification{{ID: 1}}, nil)
+			mockPublisher.EXPECT().Publish(ctx, gomock.Len(1)).Return(nil)
+
+			service.NotifyOfferEvent(ctx, entity.NotificationOfferDeclined, offer)
+		})
+
+		It("should not save anything when the recipients cannot be loaded", func() {
+			mockRepo.EXPECT().SelectRecipients(ctx, []uint{3}, entity.EventOfferStatus).
+				Return(nil, errors.New("db is down"))
+
+			service.NotifyOfferEvent(ctx, entity.NotificationOfferAccepted, offer)
+		})
+
+		It("should not save anything when the shop managers cannot be loaded", func() {
+			mockRepo.EXPECT().SelectShopManagerIDs(ctx, uint(2)).Return(nil, errors.New("db is down"))
+
+			service.NotifyOfferEvent(ctx, entity.NotificationOfferCancelled, offer)
+		})
+
+		It("should ignore unknown events", func() {
+			service.NotifyOfferEvent(ctx, "", offer)
+		})
+	})
+
+	Describe("QueueOfferEmails", func() {
+		It("should write the emails through the given transaction", func() {
+			tx := &sql.Tx{}
+			mockRepo.EXPECT().SelectShopManagerIDs(ctx, uint(2)).Return([]uint{1}, nil)
+			mockRepo.EXPECT().SelectRecipients(ctx, []uint{1}, entity.EventOfferReceived).
+				Return([]entity.NotificationRecipient{recipient(1, entity.EventOfferReceived, nil)}, nil)
+			mockRepo.EXPECT().SelectOfferDetails(ctx, uint(2), uint(4)).Return(details, nil)
+			mockMailer.EXPECT().Enqueue(ctx, tx, gomock.Any()).Return(nil)
+
+			Expect(service.QueueOfferEmails(ctx, tx, entity.NotificationOfferCreated, offer)).To(Succeed())
+		})
+
+		It("should return the error so the transaction is rolled back", func() {
+			mockRepo.EXPECT().SelectShopManagerIDs(ctx, uint(2)).Return(nil, errors.New("db is down"))
+
+			Expect(service.QueueOfferEmails(ctx, &sql.Tx{}, entity.NotificationOfferCreated, offer)).
+				To(MatchError("db is down"))
+		})
+
+		It("should send the emails without details when the variant was deleted", func() {
+			tx := &sql.Tx{}
+			mockRepo.EXPECT().SelectRecipients(ctx, []uint{3}, entity.EventOfferStatus).
+				Return([]entity.NotificationRecipient{recipient(3, entity.EventOfferStatus, nil)}, nil)
+			mockRepo.EXPECT().SelectOfferDetails(ctx, uint(2), uint(4)).
+				Return(entity.OfferDetails{}, apperror.ErrVariantNotFound)
+			mockMailer.EXPECT().Enqueue(ctx, tx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ email.Execer, msgs ...email.Message) error {
+					Expect(msgs).To(HaveLen(1))
+					Expect(msgs[0].Data).To(HaveField("ProductName", ""))
+					return nil
+				})
+
+			Expect(service.QueueOfferEmails(ctx, tx, entity.NotificationOfferAccepted, offer)).To(Succeed())
+		})
+
+		It("should skip recipients who disabled emails", func() {
+			mockRepo.EXPECT().SelectRecipients(ctx, []uint{3}, entity.EventOfferStatus).
+				Return([]entity.NotificationRecipient{
+					recipient(3, entity.EventOfferStatus, map[string]bool{entity.ChannelEmail: false}),
+				}, nil)
+
+			Expect(service.QueueOfferEmails(ctx, &sql.Tx{}, entity.NotificationOfferAccepted, offer)).To(Succeed())
+		})
+	})
+
+	Describe("SubscribeEvents", func() {
+		var bus *event.Bus
+
+		BeforeEach(func() {
+			bus = event.NewBus(zap.NewNop())
+			service.SubscribeEvents(bus)
+		})
+
+		It("should write the emails in the transaction of the event", func() {
+			tx := &sql.Tx{}
+			offer.Status = "declined"
+			mockRepo.EXPECT().SelectRecipients(ctx, []uint{3}, entity.EventOfferStatus).
+				Return([]entity.NotificationRecipient{recipient(3, entity.EventOfferStatus, nil)}, nil)
+			mockRepo.EXPECT().SelectOfferDetails(ctx, uint(2), uint(4)).Return(details, nil)
+			mockMailer.EXPECT().Enqueue(ctx, tx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ email.Execer, msgs ...email.Message) error {
+					Expect(msgs).To(HaveLen(1))
+					Expect(msgs[0].IdempotencyKey).To(Equal("offer_declined:7:3"))
+					return nil
+				})
+
+			Expect(bus.Publish(ctx, tx, event.OfferStatusChanged{Offer: offer})).To(Succeed())
+		})
+
+		It("should fail the event when the emails cannot be written", func() {
+			mockRepo.EXPECT().SelectShopManagerIDs(ctx, uint(2)).Return(nil, errors.New("db is down"))
+
+			Expect(bus.Publish(ctx, nil, event.OfferCreated{Offer: offer})).NotTo(Succeed())
+		})
+
+		It("should notify both sides about an expired offer in the background", func() {
+			offer.Status = "cancelled"
+			mockRepo.EXPECT().SelectShopManagerIDs(gomock.Any(), uint(2)).Return([]uint{1}, nil)
+			mockRepo.EXPECT().SelectRecipients(gomock.Any(), []uint{3, 1}, entity.EventOfferStatus).
+				Return([]entity.NotificationRecipient{
+					recipient(3, entity.EventOfferStatus, map[string]bool{entity.ChannelEmail: false}),
+					recipient(1, entity.EventOfferStatus, map[string]bool{entity.ChannelEmail: false}),
+				}, nil)
+			mockRepo.EXPECT().InsertNotifications(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, saved []entity.Notification) ([]entity.Notification, error) {
+					Expect(saved).To(HaveEach(HaveField("Type", entity.NotificationOfferExpired)))
+					return saved, nil
+				})
+			mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Len(2)).Return(nil)
+
+			bus.PublishAsync(ctx, event.OfferStatusChanged{Offer: offer, Expired: true})
+			bus.Wait()
+		})
+
+		It("should send the welcome email once the user verifies the email", func() {
+			mockMailer.EXPECT().Registered(uint(9), "Alice", "alice@example.com", "en")
+
+			bus.PublishAsync(ctx, event.EmailVerified{UserID: 9, UserName: "Alice", Email: "alice@example.com",
+				Locale: "en"})
+			bus.Wait()
+		})
+
+		It("should not welcome the user before the email is verified", func() {
+			bus.PublishAsync(ctx, event.UserRegistered{UserID: 9, UserName: "Alice", Email: "alice@example.com",
+				Locale: "en"})
+			bus.Wait()
+		})
+	})
+
+	Describe("Preferences", func() {
+		It("should fill in defaults for preferences the user has not changed", func() {
+			mockRepo.EXPECT().SelectNotificationPreferences(ctx, uint(3)).
+				Return([]entity.NotificationPreference{
+					{Event: entity.EventOfferStatus, Channel: entity.ChannelEmail, Enabled: false},
+					{Event: entity.EventMarketing, Channel: entity.ChannelEmail, Enabled: true},
+				}, nil)
+
+			preferences, err := service.GetPreferences(ctx, 3)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(preferences).To(HaveLen(len(entity.NotificationEvents) * len(entity.NotificationChannels)))
+			Expect(preferences).To(ContainElements(
+				entity.NotificationPreference{Event: entity.EventOfferStatus, Channel: entity.ChannelEmail},
+				entity.NotificationPreference{
+					Event: entity.EventMarketing, Channel: entity.ChannelEmail, Enabled: true,
+				},
+				entity.NotificationPreference{
+					Event: entity.EventOfferStatus, Channel: entity.ChannelInApp, Enabled: true,
+				},
+				entity.NotificationPreference{Event: entity.EventMarketing, Channel: entity.ChannelInApp},
+				entity.NotificationPreference{Event: entity.EventOfferReceived, Channel: entity.ChannelWebhook},
+			))
+		})
+
+		It("should keep the last value when the same channel is set twice", func() {
+			mockRepo.EXPECT().UpsertNotificationPreferences(ctx, uint(3), []entity.NotificationPreference{
+				{Event: entity.EventOfferStatus, Channel: entity.ChannelEmail, Enabled: true},
+			}).Return(nil)
+			mockRepo.EXPECT().SelectNotificationPreferences(ctx, uint(3)).Return(nil, nil)
+
+			_, err := service.UpdatePreferences(ctx, 3, []entity.NotificationPreference{
+				{Event: entity.EventOfferStatus, Channel: entity.ChannelEmail, Enabled: false},
+				{Event: entity.EventOfferStatus, Channel: entity.ChannelEmail, Enabled: true},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject unknown events and channels", func() {
+			_, err := service.UpdatePreferences(ctx, 3, []entity.NotificationPreference{
+				{Event: "unknown", Channel: entity.ChannelEmail},
+			})
+			Expect(err).To(HaveOccurred())
+
+			_, err = service.UpdatePreferences(ctx, 3, []entity.NotificationPreference{
+				{Event: entity.EventMarketing, Channel: "sms"},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Unsubscribe", func() {
+		It("should disable emails for the event from the token", func() {
+			mockRepo.EXPECT().UpsertNotificationPreferences(ctx, uint(3), []entity.NotificationPreference{
+				{Event: entity.EventOfferReceived, Channel: entity.ChannelEmail, Enabled: false},
+			}).Return(nil)
+
+			Expect(service.Unsubscribe(ctx, tokens.Sign(3, entity.EventOfferReceived))).To(Succeed())
+		})
+
+		It("should reject a tampered token", func() {
+			token := tokens.Sign(3, entity.EventOfferReceived)
+
+			Expect(service.Unsubscribe(ctx, strings.Replace(token, "3.", "4.", 1))).To(HaveOccurred())
+			Expect(service.Unsubscribe(ctx, NewUnsubscribeTokens("other").Sign(3, entity.EventOfferReceived))).
+				To(HaveOccurred())
+			Expect(service.Unsubscribe(ctx, "garbage")).To(HaveOccurred())
+		})
+	})
+
+	Describe("GetUserNotifications", func() {
+		It("should translate the page into an offset", func() {
+			mockRepo.EXPECT().SelectUserNotifications(ctx, uint(3), true, 20, 10).
+				Return([]entity.Notification{{ID: 1}}, 21, nil)
+
+			notifications, total, err := service.GetUserNotifications(ctx, 3, true, 3, 10)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(notifications).To(HaveLen(1))
+			Expect(total).To(Equal(21))
+		})
+	})
+
+	Describe("Stream", func() {
+		var (
+			streamCtx context.Context
+			cancel    context.CancelFunc
+		)
+
+		BeforeEach(func() {
+			streamCtx, cancel = context.WithCancel(ctx)
+		})
+
+		AfterEach(func() {
+			cancel()
+		})
+
+		It("should resend missed notifications and skip duplicates from the hub", func() {
+			mockRepo.EXPECT().SelectNotificationsAfter(streamCtx, uint(3), uint(10), maxResumeBacklog+1).
+				Return([]entity.Notification{{ID: 11, UserID: 3}, {ID: 12, UserID: 3}}, nil)
+
+			events, resync, err := service.Stream(streamCtx, 3, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resync).To(BeFalse())
+
+			Expect(hub.Publish(ctx, []entity.Notification{
+				{ID: 12, UserID: 3}, {ID: 13, UserID: 4}, {ID: 14, UserID: 3},
+			})).To(Succeed())
+
+			Expect((<-events).ID).To(Equal(uint(11)))
+			Expect((<-events).ID).To(Equal(uint(12)))
+			Expect((<-events).ID).To(Equal(uint(14)))
+		})
+
+		It("should ask for a resync instead of a truncated backlog", func() {
+			backlog := make([]entity.Notification, maxResumeBacklog+1)
+			for i := range backlog {
+				backlog[i] = entity.Notification{ID: uint(11 + i), UserID: 3}
+			}
+			mockRepo.EXPECT().SelectNotificationsAfter(streamCtx, uint(3), uint(10), maxResumeBacklog+1).
+				Return(backlog, nil)
+
+			events, resync, err := service.Stream(streamCtx, 3, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resync).To(BeTrue())
+
+			// пропущенные не досылаются, новые приходят как обычно
+			Expect(hub.Publish(ctx, []entity.Notification{{ID: 500, UserID: 3}})).To(Succeed())
+			Expect((<-events).ID).To(Equal(uint(500)))
+		})
+
+		It("should not load the backlog without Last-Event-ID", func() {
+			events, _, err := service.Stream(streamCtx, 3, 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(hub.Publish(ctx, []entity.Notification{{ID: 1, UserID: 3}})).To(Succeed())
+			Expect((<-events).ID).To(Equal(uint(1)))
+		})
+
+		It("should close the stream when the context is cancelled", func() {
+			events, _, err := service.Stream(streamCtx, 3, 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			cancel()
+			Eventually(events).Should(BeClosed())
+		})
+
+		It("should return the repository error", func() {
+			mockRepo.EXPECT().SelectNotificationsAfter(streamCtx, uint(3), uint(5), maxResumeBacklog+1).
+				Return(nil, errors.New("db is down"))
+
+			_, _, err := service.Stream(streamCtx, 3, 5)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Hub", func() {
+	It("should drop a subscriber that does not keep up", func() {
+		hub := NewHub()
+		events, unsubscribe := hub.Subscribe(1)
+		defer unsubscribe()
+
+		backlog := make([]entity.Notification, subscriberBuffer+1)
+		for i := range backlog {
+			backlog[i] = entity.Notification{ID: uint(i + 1), UserID: 1}
+		}
+		Expect(hub.Publish(context.Background(), backlog)).To(Succeed())
+
+		received := 0
+		for range events {
+			received++
+		}
+		Expect(received).To(Equal(subscriberBuffer))
+	})
+
+	It("should close every subscription on CloseAll", func() {
+		hub := NewHub()
+		first, unsubscribeFirst := hub.Subscribe(1)
+		defer unsubscribeFirst()
+		second, unsubscribeSecond := hub.Subscribe(2)
+		defer unsubscribeSecond()
+
+		hub.CloseAll()
+
+		Eventually(first).Should(BeClosed())
+		Eventually(second).Should(BeClosed())
+	})
+
+	It("should stop delivering after unsubscribe", func() {
+		hub := NewHub()
+		events, unsubscribe := hub.Subscribe(1)
+		unsubscribe()
+		unsubscribe()
+
+		Expect(hub.Publish(context.Background(), []entity.Notification{{ID: 1, UserID: 1}})).To(Succeed())
+		Expect(events).To(BeClosed())
+	})
+})
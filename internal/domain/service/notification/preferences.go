@@ -0,0 +1,78 @@
+package notification
+
+import (
+	"context"
+	"slices"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+// GetPreferences возвращает настройки пользователя для всех типов событий и каналов,
+// включая значения по умолчанию
+func (ns *Service) GetPreferences(ctx context.Context, userID uint) ([]entity.NotificationPreference, error) {
+	stored, err := ns.notificationRepository.SelectNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[[2]string]bool, len(stored))
+	for _, p := range stored {
+		changed[[2]string{p.Event, p.Channel}] = p.Enabled
+	}
+
+	total := len(entity.NotificationEvents) * len(entity.NotificationChannels)
+	preferences := make([]entity.NotificationPreference, 0, total)
+	for _, event := range entity.NotificationEvents {
+		for _, channel := range entity.NotificationChannels {
+			enabled, ok := changed[[2]string{event, channel}]
+			if !ok {
+				enabled = entity.DefaultPreference(event, channel)
+			}
+			preferences = append(preferences, entity.NotificationPreference{
+				Event: event, Channel: channel, Enabled: enabled,
+			})
+		}
+	}
+	return preferences, nil
+}
+
+// UpdatePreferences сохраняет переданные настройки и возвращает все настройки пользователя
+func (ns *Service) UpdatePreferences(
+	ctx context.Context,
+	userID uint,
+	preferences []entity.NotificationPreference,
+) ([]entity.NotificationPreference, error) {
+	// повторная настройка того же канала в одном запросе перекрывает предыдущую
+	unique := make([]entity.NotificationPreference, 0, len(preferences))
+	seen := make(map[[2]string]int, len(preferences))
+	for _, p := range preferences {
+		if !validEvent(p.Event) {
+			return nil, apperror.New(apperror.BadRequest, "unknown notification event: "+p.Event, nil)
+		}
+		if !slices.Contains(entity.NotificationChannels, p.Channel) {
+			return nil, apperror.New(apperror.BadRequest, "unknown notification channel: "+p.Channel, nil)
+		}
+		key := [2]string{p.Event, p.Channel}
+		if i, ok := seen[key]; ok {
+			unique[i] = p
+			continue
+		}
+		seen[key] = len(unique)
+		unique = append(unique, p)
+	}
+
+	if err := ns.notificationRepository.UpsertNotificationPreferences(ctx, userID, unique); err != nil {
+		return nil, err
+	}
+	return ns.GetPreferences(ctx, userID)
+}
+
+// Unsubscribe отключает письма по ссылке из письма
+func (ns *Service) Unsubscribe(ctx context.Context, token string) error {
+	return ns.dispatcher.Unsubscribe(ctx, token)
+}
+
+func validEvent(event string) bool {
+	return slices.Contains(entity.NotificationEvents, event)
+}
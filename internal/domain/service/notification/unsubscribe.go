@@ -0,0 +1,57 @@
+package notification
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+)
+
+// UnsubscribeTokens подписывает ссылки отписки из писем. Токен не истекает:
+// ссылка из старого письма должна работать, а отписка повторно ничего не меняет
+type UnsubscribeTokens struct {
+	secret []byte
+}
+
+func NewUnsubscribeTokens(secret string) *UnsubscribeTokens {
+	return &UnsubscribeTokens{secret: []byte(secret)}
+}
+
+// Sign возвращает токен вида <userID>.<event>.<подпись>
+func (t *UnsubscribeTokens) Sign(userID uint, event string) string {
+	payload := fmt.Sprintf("%d.%s", userID, event)
+	return payload + "." + t.signature(payload)
+}
+
+// Verify проверяет подпись и возвращает пользователя и тип событий, от которых он отписывается
+func (t *UnsubscribeTokens) Verify(token string) (uint, string, error) {
+	idx := strings.LastIndexByte(token, '.')
+	if idx < 0 {
+		return 0, "", apperror.ErrInvalidToken
+	}
+	payload, signature := token[:idx], token[idx+1:]
+	if !hmac.Equal([]byte(signature), []byte(t.signature(payload))) {
+		return 0, "", apperror.ErrInvalidToken
+	}
+
+	rawID, event, ok := strings.Cut(payload, ".")
+	if !ok {
+		return 0, "", apperror.ErrInvalidToken
+	}
+	userID, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil {
+		return 0, "", apperror.ErrInvalidToken
+	}
+	return uint(userID), event, nil
+}
+
+func (t *UnsubscribeTokens) signature(payload string) string {
+	// префикс отделяет эти подписи от других, сделанных тем же секретом
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte("unsubscribe:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
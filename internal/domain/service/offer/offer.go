@@ -2,19 +2,37 @@ package offer
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"go.uber.org/zap"
+
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
 	"github.com/EM-Stawberry/Stawberry/pkg/email"
 )
 
 type Repository interface {
-	InsertOffer(ctx context.Context, offer entity.Offer) (uint, error)
+	InsertOffer(
+		ctx context.Context, offer entity.Offer, onInsert func(tx email.Execer, offerID uint) error,
+	) (uint, error)
 	GetOfferByID(ctx context.Context, offerID uint) (entity.Offer, error)
 	SelectUserOffers(ctx context.Context, userID uint, limit, offset int) ([]entity.Offer, int, error)
-	UpdateOfferStatus(ctx context.Context, offer entity.Offer, userID uint, isStore bool) (entity.Offer, error)
+	UpdateOfferStatus(
+		ctx context.Context, offer entity.Offer, userID uint, isStore bool,
+		onUpdate func(tx email.Execer, offer entity.Offer) error,
+	) (entity.Offer, error)
 	DeleteOffer(ctx context.Context, offerID uint) (entity.Offer, error)
+	ExpireUserOffers(ctx context.Context, userID uint) ([]entity.Offer, error)
+	ExpireOffers(ctx context.Context) ([]entity.Offer, error)
+}
+
+// EventBus публикует события оффера. Синхронные подписчики вызываются в транзакции события,
+// асинхронные - после ее коммита
+type EventBus interface {
+	Publish(ctx context.Context, exec email.Execer, e event.Event) error
+	PublishAsync(ctx context.Context, e event.Event)
 }
 
 const (
@@ -24,21 +42,23 @@ const (
 	statusPending   = "pending"
 
 	offerLifetime = 7 * 24 * time.Hour
+
+	// expirationInterval период фоновой проверки просроченных офферов
+	expirationInterval = time.Minute
 )
 
 type Service struct {
 	offerRepository Repository
-	mailer          email.MailerService
+	events          EventBus
 }
 
-func NewService(offerRepository Repository, mailer email.MailerService) *Service {
-	return &Service{offerRepository: offerRepository, mailer: mailer}
+func NewService(offerRepository Repository, events EventBus) *Service {
+	return &Service{offerRepository: offerRepository, events: events}
 }
 
 func (os *Service) CreateOffer(
 	ctx context.Context,
 	offer entity.Offer,
-	user entity.User,
 ) (uint, error) {
 
 	t := time.Now()
@@ -47,12 +67,18 @@ func (os *Service) CreateOffer(
 	offer.UpdatedAt = t
 	offer.ExpiresAt = t.Add(offerLifetime)
 
-	offerID, err := os.offerRepository.InsertOffer(ctx, offer)
+	// синхронные подписчики, например письмо магазину в outbox, выполняются вместе с записью оффера
+	offerID, err := os.offerRepository.InsertOffer(ctx, offer, func(tx email.Execer, offerID uint) error {
+		created := offer
+		created.ID = offerID
+		return os.events.Publish(ctx, tx, event.OfferCreated{Offer: created})
+	})
 	if err != nil {
 		return 0, err
 	}
 
-	os.mailer.Registered(user.Name, user.Email)
+	offer.ID = offerID
+	os.events.PublishAsync(ctx, event.OfferCreated{Offer: offer})
 
 	return offerID, nil
 }
@@ -72,6 +98,15 @@ func (os *Service) GetUserOffers(
 ) ([]entity.Offer, int, error) {
 	offset := (page - 1) * limit
 
+	// lazy update, фоновая проверка может еще не дойти до просроченных офферов
+	expired, err := os.offerRepository.ExpireUserOffers(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := os.notifyExpired(ctx, expired); err != nil {
+		return nil, 0, err
+	}
+
 	offers, total, err := os.offerRepository.SelectUserOffers(ctx, userID, limit, offset)
 
 	return offers, total, err
@@ -102,9 +137,67 @@ func (os *Service) UpdateOfferStatus(
 		}
 	}
 
-	offerResp, err := os.offerRepository.UpdateOfferStatus(ctx, offer, userID, isStore)
+	// синхронные подписчики, например письмо второй стороне, выполняются вместе со сменой статуса
+	offerResp, err := os.offerRepository.UpdateOfferStatus(ctx, offer, userID, isStore,
+		func(tx email.Execer, updated entity.Offer) error {
+			return os.events.Publish(ctx, tx, event.OfferStatusChanged{Offer: updated})
+		})
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
+	os.events.PublishAsync(ctx, event.OfferStatusChanged{Offer: offerResp})
+
+	return offerResp, nil
+}
 
-	return offerResp, err
+// ExpireOffers отменяет все просроченные офферы и уведомляет об этом стороны.
+// Возвращает число отмененных офферов
+func (os *Service) ExpireOffers(ctx context.Context) (int, error) {
+	expired, err := os.offerRepository.ExpireOffers(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.notifyExpired(ctx, expired); err != nil {
+		return len(expired), err
+	}
+	return len(expired), nil
+}
+
+// RunExpiration периодически отменяет просроченные офферы, пока не отменен контекст
+func (os *Service) RunExpiration(ctx context.Context, log *zap.Logger) {
+	ticker := time.NewTicker(expirationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := os.ExpireOffers(ctx)
+			if err != nil {
+				log.Error("Failed to expire offers", zap.Error(err))
+				continue
+			}
+			if count > 0 {
+				log.Info("Offers expired", zap.Int("count", count))
+			}
+		}
+	}
+}
+
+// notifyExpired публикует события об уже отмененных офферах. Отмена к этому моменту сохранена,
+// поэтому ошибка синхронного подписчика не прерывает публикацию остальных
+func (os *Service) notifyExpired(ctx context.Context, offers []entity.Offer) error {
+	var errs []error
+	for _, offer := range offers {
+		e := event.OfferStatusChanged{Offer: offer, Expired: true}
+		if err := os.events.Publish(ctx, nil, e); err != nil {
+			errs = append(errs, err)
+		}
+		os.events.PublishAsync(ctx, e)
+	}
+	return errors.Join(errs...)
 }
 
 func (os *Service) DeleteOffer(
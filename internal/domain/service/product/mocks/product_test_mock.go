@@ -97,6 +97,21 @@ func (mr *MockRepositoryMockRecorder) GetFilteredProductsCount(ctx, filter inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilteredProductsCount", reflect.TypeOf((*MockRepository)(nil).GetFilteredProductsCount), ctx, filter)
 }
 
+// GetImagesByProductID mocks base method.
+func (m *MockRepository) GetImagesByProductID(ctx context.Context, productID int) ([]entity.ProductImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImagesByProductID", ctx, productID)
+	ret0, _ := ret[0].([]entity.ProductImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImagesByProductID indicates an expected call of GetImagesByProductID.
+func (mr *MockRepositoryMockRecorder) GetImagesByProductID(ctx, productID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImagesByProductID", reflect.TypeOf((*MockRepository)(nil).GetImagesByProductID), ctx, productID)
+}
+
 // GetPriceRangeByProductID mocks base method.
 func (m *MockRepository) GetPriceRangeByProductID(ctx context.Context, productID int) (int, int, error) {
 	m.ctrl.T.Helper()
@@ -127,3 +142,165 @@ func (mr *MockRepositoryMockRecorder) GetProductByID(ctx, id interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductByID", reflect.TypeOf((*MockRepository)(nil).GetProductByID), ctx, id)
 }
+
+// GetVariantInventoryByProductID mocks base method.
+func (m *MockRepository) GetVariantInventoryByProductID(ctx context.Context, productID int) ([]entity.VariantInventory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariantInventoryByProductID", ctx, productID)
+	ret0, _ := ret[0].([]entity.VariantInventory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVariantInventoryByProductID indicates an expected call of GetVariantInventoryByProductID.
+func (mr *MockRepositoryMockRecorder) GetVariantInventoryByProductID(ctx, productID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariantInventoryByProductID", reflect.TypeOf((*MockRepository)(nil).GetVariantInventoryByProductID), ctx, productID)
+}
+
+// GetVariantsByProductID mocks base method.
+func (m *MockRepository) GetVariantsByProductID(ctx context.Context, productID int) ([]entity.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariantsByProductID", ctx, productID)
+	ret0, _ := ret[0].([]entity.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVariantsByProductID indicates an expected call of GetVariantsByProductID.
+func (mr *MockRepositoryMockRecorder) GetVariantsByProductID(ctx, productID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariantsByProductID", reflect.TypeOf((*MockRepository)(nil).GetVariantsByProductID), ctx, productID)
+}
+
+// InsertProduct mocks base method.
+func (m *MockRepository) InsertProduct(ctx context.Context, product entity.Product) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertProduct", ctx, product)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertProduct indicates an expected call of InsertProduct.
+func (mr *MockRepositoryMockRecorder) InsertProduct(ctx, product interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProduct", reflect.TypeOf((*MockRepository)(nil).InsertProduct), ctx, product)
+}
+
+// InsertVariant mocks base method.
+func (m *MockRepository) InsertVariant(ctx context.Context, variant entity.ProductVariant) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertVariant", ctx, variant)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertVariant indicates an expected call of InsertVariant.
+func (mr *MockRepositoryMockRecorder) InsertVariant(ctx, variant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertVariant", reflect.TypeOf((*MockRepository)(nil).InsertVariant), ctx, variant)
+}
+
+// UpdateProduct mocks base method.
+func (m *MockRepository) UpdateProduct(ctx context.Context, product entity.Product) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProduct", ctx, product)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProduct indicates an expected call of UpdateProduct.
+func (mr *MockRepositoryMockRecorder) UpdateProduct(ctx, product interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProduct", reflect.TypeOf((*MockRepository)(nil).UpdateProduct), ctx, product)
+}
+
+// UpdateVariant mocks base method.
+func (m *MockRepository) UpdateVariant(ctx context.Context, variant entity.ProductVariant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVariant", ctx, variant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVariant indicates an expected call of UpdateVariant.
+func (mr *MockRepositoryMockRecorder) UpdateVariant(ctx, variant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVariant", reflect.TypeOf((*MockRepository)(nil).UpdateVariant), ctx, variant)
+}
+
+// MockAttributeValidator is a mock of AttributeValidator interface.
+type MockAttributeValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttributeValidatorMockRecorder
+}
+
+// MockAttributeValidatorMockRecorder is the mock recorder for MockAttributeValidator.
+type MockAttributeValidatorMockRecorder struct {
+	mock *MockAttributeValidator
+}
+
+// NewMockAttributeValidator creates a new mock instance.
+func NewMockAttributeValidator(ctrl *gomock.Controller) *MockAttributeValidator {
+	mock := &MockAttributeValidator{ctrl: ctrl}
+	mock.recorder = &MockAttributeValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttributeValidator) EXPECT() *MockAttributeValidatorMockRecorder {
+	return m.recorder
+}
+
+// ValidateAttributes mocks base method.
+func (m *MockAttributeValidator) ValidateAttributes(ctx context.Context, categoryID int, attrs map[string]interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateAttributes", ctx, categoryID, attrs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateAttributes indicates an expected call of ValidateAttributes.
+func (mr *MockAttributeValidatorMockRecorder) ValidateAttributes(ctx, categoryID, attrs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateAttributes", reflect.TypeOf((*MockAttributeValidator)(nil).ValidateAttributes), ctx, categoryID, attrs)
+}
+
+// MockImageURLBuilder is a mock of ImageURLBuilder interface.
+type MockImageURLBuilder struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageURLBuilderMockRecorder
+}
+
+// MockImageURLBuilderMockRecorder is the mock recorder for MockImageURLBuilder.
+type MockImageURLBuilderMockRecorder struct {
+	mock *MockImageURLBuilder
+}
+
+// NewMockImageURLBuilder creates a new mock instance.
+func NewMockImageURLBuilder(ctrl *gomock.Controller) *MockImageURLBuilder {
+	mock := &MockImageURLBuilder{ctrl: ctrl}
+	mock.recorder = &MockImageURLBuilderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageURLBuilder) EXPECT() *MockImageURLBuilderMockRecorder {
+	return m.recorder
+}
+
+// URL mocks base method.
+func (m *MockImageURLBuilder) URL(key string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "URL", key)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// URL indicates an expected call of URL.
+func (mr *MockImageURLBuilderMockRecorder) URL(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "URL", reflect.TypeOf((*MockImageURLBuilder)(nil).URL), key)
+}
@@ -3,6 +3,8 @@ package product
 import (
 	"context"
 	"fmt"
+	"maps"
+	"strconv"
 
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 
@@ -16,14 +18,65 @@ type Repository interface {
 	GetAttributesByID(ctx context.Context, productID string) (map[string]interface{}, error)
 	GetPriceRangeByProductID(ctx context.Context, productID int) (int, int, error)
 	GetAverageRatingByProductID(ctx context.Context, productID int) (float64, int, error)
+	InsertProduct(ctx context.Context, product entity.Product) (int, error)
+	UpdateProduct(ctx context.Context, product entity.Product) error
+	GetImagesByProductID(ctx context.Context, productID int) ([]entity.ProductImage, error)
+	GetVariantsByProductID(ctx context.Context, productID int) ([]entity.ProductVariant, error)
+	GetVariantInventoryByProductID(ctx context.Context, productID int) ([]entity.VariantInventory, error)
+	InsertVariant(ctx context.Context, variant entity.ProductVariant) (int, error)
+	UpdateVariant(ctx context.Context, variant entity.ProductVariant) error
+}
+
+// AttributeValidator проверяет атрибуты продукта по схеме его категории
+type AttributeValidator interface {
+	ValidateAttributes(ctx context.Context, categoryID int, attrs map[string]interface{}) error
+}
+
+// ImageURLBuilder строит публичные ссылки на файлы в хранилище изображений
+type ImageURLBuilder interface {
+	URL(key string) string
 }
 
 type Service struct {
-	ProductRepository Repository
+	ProductRepository  Repository
+	AttributeValidator AttributeValidator
+	ImageURLBuilder    ImageURLBuilder
 }
 
-func NewService(productRepo Repository) *Service {
-	return &Service{ProductRepository: productRepo}
+func NewService(
+	productRepo Repository,
+	attributeValidator AttributeValidator,
+	imageURLBuilder ImageURLBuilder,
+) *Service {
+	return &Service{
+		ProductRepository:  productRepo,
+		AttributeValidator: attributeValidator,
+		ImageURLBuilder:    imageURLBuilder,
+	}
+}
+
+// CreateProduct создает продукт, предварительно проверив его атрибуты по схеме категории
+func (ps *Service) CreateProduct(
+	ctx context.Context,
+	product entity.Product,
+) (int, error) {
+	if err := ps.AttributeValidator.ValidateAttributes(ctx, product.CategoryID, product.Attributes); err != nil {
+		return 0, err
+	}
+
+	return ps.ProductRepository.InsertProduct(ctx, product)
+}
+
+// UpdateProduct обновляет продукт, предварительно проверив его атрибуты по схеме категории
+func (ps *Service) UpdateProduct(
+	ctx context.Context,
+	product entity.Product,
+) error {
+	if err := ps.AttributeValidator.ValidateAttributes(ctx, product.CategoryID, product.Attributes); err != nil {
+		return err
+	}
+
+	return ps.ProductRepository.UpdateProduct(ctx, product)
 }
 
 // GetProductByID получает продукт по его ID
@@ -46,9 +99,63 @@ func (ps *Service) GetProductByID(
 		return entity.Product{}, err
 	}
 
+	variants, err := ps.ProductRepository.GetVariantsByProductID(ctx, product.ID)
+	if err != nil {
+		return entity.Product{}, err
+	}
+	inventory, err := ps.ProductRepository.GetVariantInventoryByProductID(ctx, product.ID)
+	if err != nil {
+		return entity.Product{}, err
+	}
+	enrichedProduct.Variants, enrichedProduct.VariantAxes = buildVariantMatrix(variants, inventory)
+
 	return enrichedProduct, nil
 }
 
+// CreateVariant добавляет вариант продукту. Атрибуты варианта дополняют атрибуты продукта
+// и вместе с ними проверяются по схеме категории.
+func (ps *Service) CreateVariant(
+	ctx context.Context,
+	variant entity.ProductVariant,
+) (int, error) {
+	if err := ps.validateVariant(ctx, variant); err != nil {
+		return 0, err
+	}
+
+	return ps.ProductRepository.InsertVariant(ctx, variant)
+}
+
+// UpdateVariant заменяет SKU и атрибуты варианта
+func (ps *Service) UpdateVariant(
+	ctx context.Context,
+	variant entity.ProductVariant,
+) error {
+	if err := ps.validateVariant(ctx, variant); err != nil {
+		return err
+	}
+
+	return ps.ProductRepository.UpdateVariant(ctx, variant)
+}
+
+func (ps *Service) validateVariant(ctx context.Context, variant entity.ProductVariant) error {
+	productID := strconv.Itoa(variant.ProductID)
+
+	product, err := ps.ProductRepository.GetProductByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	attrs, err := ps.ProductRepository.GetAttributesByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	merged := make(map[string]interface{}, len(attrs)+len(variant.Attributes))
+	maps.Copy(merged, attrs)
+	maps.Copy(merged, variant.Attributes)
+
+	return ps.AttributeValidator.ValidateAttributes(ctx, product.CategoryID, merged)
+}
+
 func (ps *Service) GetFilteredProducts(ctx context.Context,
 	filter model.ProductFilter,
 	limit, offset int) ([]entity.Product, int, error) {
@@ -74,7 +181,8 @@ func (ps *Service) GetFilteredProducts(ctx context.Context,
 	return products, count, nil
 }
 
-// EnrichProducts выполняет обогащение продукта информацией о диапазоне цены, средней оценке и количестве отзывов
+// EnrichProducts выполняет обогащение продукта информацией о диапазоне цены, средней оценке,
+// количестве отзывов и ссылками на изображения. Цены и отзывы агрегируются по всем вариантам продукта.
 func (ps *Service) enrichProducts(
 	ctx context.Context,
 	product entity.Product,
@@ -90,10 +198,22 @@ func (ps *Service) enrichProducts(
 		return entity.Product{}, err
 	}
 
+	images, err := ps.ProductRepository.GetImagesByProductID(ctx, product.ID)
+	if err != nil {
+		return entity.Product{}, err
+	}
+	for i := range images {
+		images[i].URL = ps.ImageURLBuilder.URL(images[i].Key)
+		if images[i].ThumbnailKey != "" {
+			images[i].ThumbnailURL = ps.ImageURLBuilder.URL(images[i].ThumbnailKey)
+		}
+	}
+
 	product.MinimalPrice = minPrice
 	product.MaximalPrice = maxPrice
 	product.AverageRating = avgRating
 	product.CountReviews = countReviews
+	product.Images = images
 
 	return product, nil
 }
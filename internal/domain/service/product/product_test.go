@@ -16,6 +16,7 @@ var _ = Describe("EnrichProducts", func() {
 	var (
 		mockCtrl *gomock.Controller
 		mockRepo *mocks.MockRepository
+		mockURLs *mocks.MockImageURLBuilder
 		svc      *Service
 		ctx      context.Context
 		product  entity.Product
@@ -24,7 +25,8 @@ var _ = Describe("EnrichProducts", func() {
 	BeforeEach(func() {
 		mockCtrl = gomock.NewController(GinkgoT())
 		mockRepo = mocks.NewMockRepository(mockCtrl)
-		svc = &Service{ProductRepository: mockRepo}
+		mockURLs = mocks.NewMockImageURLBuilder(mockCtrl)
+		svc = &Service{ProductRepository: mockRepo, ImageURLBuilder: mockURLs}
 		ctx = context.Background()
 
 		product = entity.Product{ID: 1, Name: "Product A"}
@@ -38,6 +40,11 @@ var _ = Describe("EnrichProducts", func() {
 	It("successfully enriches products", func() {
 		mockRepo.EXPECT().GetPriceRangeByProductID(ctx, 1).Return(1000, 2000, nil)
 		mockRepo.EXPECT().GetAverageRatingByProductID(ctx, 1).Return(4.5, 10, nil)
+		mockRepo.EXPECT().GetImagesByProductID(ctx, 1).Return([]entity.ProductImage{
+			{ID: 3, Key: "products/1/a.jpg", ThumbnailKey: "products/1/a_thumb.jpg"},
+		}, nil)
+		mockURLs.EXPECT().URL("products/1/a.jpg").Return("https://cdn/products/1/a.jpg")
+		mockURLs.EXPECT().URL("products/1/a_thumb.jpg").Return("https://cdn/products/1/a_thumb.jpg")
 
 		result, err := svc.enrichProducts(ctx, product)
 		Expect(err).ToNot(HaveOccurred())
@@ -45,6 +52,9 @@ var _ = Describe("EnrichProducts", func() {
 		Expect(result.MaximalPrice).To(Equal(2000))
 		Expect(result.AverageRating).To(Equal(4.5))
 		Expect(result.CountReviews).To(Equal(10))
+		Expect(result.Images).To(HaveLen(1))
+		Expect(result.Images[0].URL).To(Equal("https://cdn/products/1/a.jpg"))
+		Expect(result.Images[0].ThumbnailURL).To(Equal("https://cdn/products/1/a_thumb.jpg"))
 	})
 
 	It("returns error if GetPriceRangeByProductID fails", func() {
@@ -62,3 +72,136 @@ var _ = Describe("EnrichProducts", func() {
 		Expect(err).To(MatchError("rating error"))
 	})
 })
+
+var _ = Describe("CreateProduct", func() {
+	var (
+		mockCtrl      *gomock.Controller
+		mockRepo      *mocks.MockRepository
+		mockValidator *mocks.MockAttributeValidator
+		svc           *Service
+		ctx           context.Context
+		product       entity.Product
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepository(mockCtrl)
+		mockValidator = mocks.NewMockAttributeValidator(mockCtrl)
+		svc = NewService(mockRepo, mockValidator, nil)
+		ctx = context.Background()
+
+		product = entity.Product{
+			Name:       "SuperFast Laptop",
+			CategoryID: 3,
+			Attributes: map[string]interface{}{"ram_gb": float64(16)},
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("creates a product with valid attributes", func() {
+		mockValidator.EXPECT().ValidateAttributes(ctx, 3, product.Attributes).Return(nil)
+		mockRepo.EXPECT().InsertProduct(ctx, product).Return(11, nil)
+
+		id, err := svc.CreateProduct(ctx, product)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(id).To(Equal(11))
+	})
+
+	It("does not insert a product with invalid attributes", func() {
+		mockValidator.EXPECT().ValidateAttributes(ctx, 3, product.Attributes).Return(errors.New("invalid attributes"))
+
+		_, err := svc.CreateProduct(ctx, product)
+		Expect(err).To(MatchError("invalid attributes"))
+	})
+})
+
+var _ = Describe("CreateVariant", func() {
+	var (
+		mockCtrl      *gomock.Controller
+		mockRepo      *mocks.MockRepository
+		mockValidator *mocks.MockAttributeValidator
+		svc           *Service
+		ctx           context.Context
+		variant       entity.ProductVariant
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepository(mockCtrl)
+		mockValidator = mocks.NewMockAttributeValidator(mockCtrl)
+		svc = NewService(mockRepo, mockValidator, nil)
+		ctx = context.Background()
+
+		variant = entity.ProductVariant{
+			ProductID:  5,
+			SKU:        "P000005-512-SLV",
+			Attributes: map[string]interface{}{"color": "Silver"},
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("validates variant attributes merged over product attributes", func() {
+		mockRepo.EXPECT().GetProductByID(ctx, "5").Return(entity.Product{ID: 5, CategoryID: 8}, nil)
+		mockRepo.EXPECT().GetAttributesByID(ctx, "5").
+			Return(map[string]interface{}{"color": "Space Gray", "storage": "256GB"}, nil)
+		mockValidator.EXPECT().ValidateAttributes(ctx, 8, map[string]interface{}{
+			"color": "Silver", "storage": "256GB",
+		}).Return(nil)
+		mockRepo.EXPECT().InsertVariant(ctx, variant).Return(12, nil)
+
+		id, err := svc.CreateVariant(ctx, variant)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(id).To(Equal(12))
+	})
+
+	It("does not save a variant with invalid attributes", func() {
+		mockRepo.EXPECT().GetProductByID(ctx, "5").Return(entity.Product{ID: 5, CategoryID: 8}, nil)
+		mockRepo.EXPECT().GetAttributesByID(ctx, "5").Return(nil, nil)
+		mockValidator.EXPECT().ValidateAttributes(ctx, 8, gomock.Any()).Return(errors.New("invalid color"))
+
+		_, err := svc.CreateVariant(ctx, variant)
+		Expect(err).To(MatchError("invalid color"))
+	})
+})
+
+var _ = Describe("buildVariantMatrix", func() {
+	It("groups inventory by variant and collects differing attributes", func() {
+		variants := []entity.ProductVariant{
+			{ID: 1, Attributes: map[string]interface{}{"color": "Black", "storage": "256GB"}},
+			{ID: 2, Attributes: map[string]interface{}{"color": "Silver", "storage": "256GB"}},
+			{ID: 3, Attributes: map[string]interface{}{"color": "Black", "storage": "512GB"}},
+		}
+		inventory := []entity.VariantInventory{
+			{VariantID: 1, ShopID: 1, Price: 99900},
+			{VariantID: 1, ShopID: 2, Price: 95000},
+			{VariantID: 3, ShopID: 1, Price: 119900},
+		}
+
+		result, axes := buildVariantMatrix(variants, inventory)
+
+		Expect(result[0].Inventory).To(HaveLen(2))
+		Expect(result[0].MinimalPrice).To(Equal(95000))
+		Expect(result[0].MaximalPrice).To(Equal(99900))
+		Expect(result[1].Inventory).To(BeEmpty())
+		Expect(result[1].MinimalPrice).To(Equal(0))
+		Expect(result[2].MinimalPrice).To(Equal(119900))
+		Expect(axes).To(Equal(map[string][]interface{}{
+			"color":   {"Black", "Silver"},
+			"storage": {"256GB", "512GB"},
+		}))
+	})
+
+	It("returns no axes for a product with a single variant", func() {
+		_, axes := buildVariantMatrix([]entity.ProductVariant{
+			{ID: 1, Attributes: map[string]interface{}{"color": "Black"}},
+		}, nil)
+
+		Expect(axes).To(BeNil())
+	})
+})
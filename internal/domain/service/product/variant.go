@@ -0,0 +1,71 @@
+package product
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+// buildVariantMatrix раскладывает предложения магазинов по вариантам, считает диапазон цен
+// каждого варианта и собирает оси матрицы — атрибуты, по которым варианты различаются
+func buildVariantMatrix(
+	variants []entity.ProductVariant,
+	inventory []entity.VariantInventory,
+) ([]entity.ProductVariant, map[string][]interface{}) {
+	byVariant := make(map[int][]entity.VariantInventory, len(variants))
+	for _, item := range inventory {
+		byVariant[item.VariantID] = append(byVariant[item.VariantID], item)
+	}
+
+	for i := range variants {
+		items := byVariant[variants[i].ID]
+		if items == nil {
+			items = []entity.VariantInventory{}
+		}
+		variants[i].Inventory = items
+
+		for j, item := range items {
+			if j == 0 || item.Price < variants[i].MinimalPrice {
+				variants[i].MinimalPrice = item.Price
+			}
+			if item.Price > variants[i].MaximalPrice {
+				variants[i].MaximalPrice = item.Price
+			}
+		}
+	}
+
+	return variants, variantAxes(variants)
+}
+
+// variantAxes возвращает значения атрибутов, которые встречаются у вариантов,
+// если вариантов больше одного и значения у них различаются
+func variantAxes(variants []entity.ProductVariant) map[string][]interface{} {
+	if len(variants) < 2 {
+		return nil
+	}
+
+	axes := make(map[string][]interface{})
+	seen := make(map[string][]string)
+	for _, variant := range variants {
+		for name, value := range variant.Attributes {
+			key := fmt.Sprint(value)
+			if slices.Contains(seen[name], key) {
+				continue
+			}
+			seen[name] = append(seen[name], key)
+			axes[name] = append(axes[name], value)
+		}
+	}
+
+	for name, values := range axes {
+		if len(values) < 2 {
+			delete(axes, name)
+		}
+	}
+	if len(axes) == 0 {
+		return nil
+	}
+
+	return axes
+}
@@ -0,0 +1,150 @@
+package productimage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/imaging"
+	"github.com/google/uuid"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=productimage_mock_test.go -package=productimage Repository Storage
+
+type Repository interface {
+	IsProductInUserShop(ctx context.Context, productID int, userID uint) (bool, error)
+	InsertImage(ctx context.Context, image entity.ProductImage) (entity.ProductImage, error)
+	GetImageByID(ctx context.Context, imageID int) (entity.ProductImage, error)
+	DeleteImage(ctx context.Context, imageID int) error
+}
+
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+type Service struct {
+	imageRepository Repository
+	storage         Storage
+	maxSize         int64
+	thumbnailSize   int
+}
+
+func NewService(imageRepo Repository, storage Storage, cfg *config.StorageConfig) *Service {
+	return &Service{
+		imageRepository: imageRepo,
+		storage:         storage,
+		maxSize:         cfg.MaxImageSize,
+		thumbnailSize:   cfg.ThumbnailSize,
+	}
+}
+
+// UploadProductImage загружает изображение продукта вместе с превью.
+// Загружать изображения может только владелец магазина, в котором продается продукт.
+func (s *Service) UploadProductImage(
+	ctx context.Context,
+	userID uint,
+	productID int,
+	data []byte,
+) (entity.ProductImage, error) {
+	if s.maxSize > 0 && int64(len(data)) > s.maxSize {
+		return entity.ProductImage{}, apperror.New(apperror.BadRequest,
+			fmt.Sprintf("image is larger than %d bytes", s.maxSize), nil)
+	}
+
+	if err := s.checkOwner(ctx, productID, userID); err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	format, err := imaging.Sniff(data)
+	if err != nil {
+		return entity.ProductImage{}, apperror.New(apperror.BadRequest,
+			"only jpeg, png and gif images are allowed", err)
+	}
+
+	thumbnail, thumbnailFormat, err := imaging.Thumbnail(data, format, s.thumbnailSize)
+	if err != nil {
+		return entity.ProductImage{}, apperror.New(apperror.BadRequest, "failed to process image", err)
+	}
+
+	name := uuid.NewString()
+	image := entity.ProductImage{
+		ProductID:    productID,
+		Key:          fmt.Sprintf("products/%d/%s.%s", productID, name, format.Extension),
+		ThumbnailKey: fmt.Sprintf("products/%d/%s_thumb.%s", productID, name, thumbnailFormat.Extension),
+		ContentType:  format.ContentType,
+	}
+
+	if err := s.storage.Put(ctx, image.Key, data, format.ContentType); err != nil {
+		return entity.ProductImage{}, apperror.New(apperror.InternalError, "failed to store image", err)
+	}
+	if err := s.storage.Put(ctx, image.ThumbnailKey, thumbnail, thumbnailFormat.ContentType); err != nil {
+		s.removeObjects(ctx, image.Key)
+		return entity.ProductImage{}, apperror.New(apperror.InternalError, "failed to store thumbnail", err)
+	}
+
+	saved, err := s.imageRepository.InsertImage(ctx, image)
+	if err != nil {
+		s.removeObjects(ctx, image.Key, image.ThumbnailKey)
+		return entity.ProductImage{}, err
+	}
+	image = saved
+
+	image.URL = s.storage.URL(image.Key)
+	image.ThumbnailURL = s.storage.URL(image.ThumbnailKey)
+
+	return image, nil
+}
+
+// DeleteProductImage удаляет изображение продукта из базы и хранилища
+func (s *Service) DeleteProductImage(
+	ctx context.Context,
+	userID uint,
+	productID int,
+	imageID int,
+) error {
+	image, err := s.imageRepository.GetImageByID(ctx, imageID)
+	if err != nil {
+		return err
+	}
+	if image.ProductID != productID {
+		return apperror.ErrImageNotFound
+	}
+
+	if err := s.checkOwner(ctx, productID, userID); err != nil {
+		return err
+	}
+
+	if err := s.imageRepository.DeleteImage(ctx, imageID); err != nil {
+		return err
+	}
+
+	// запись уже удалена, поэтому оставшиеся в хранилище файлы ни на что не влияют
+	s.removeObjects(ctx, image.Key, image.ThumbnailKey)
+
+	return nil
+}
+
+func (s *Service) checkOwner(ctx context.Context, productID int, userID uint) error {
+	ok, err := s.imageRepository.IsProductInUserShop(ctx, productID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperror.New(apperror.Forbidden, "product is not sold in your shop", nil)
+	}
+	return nil
+}
+
+// removeObjects удаляет файлы из хранилища по возможности,
+// ошибка удаления не должна ломать основной сценарий
+func (s *Service) removeObjects(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if key != "" {
+			_ = s.storage.Delete(ctx, key)
+		}
+	}
+}
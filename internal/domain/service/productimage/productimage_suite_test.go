@@ -0,0 +1,13 @@
+package productimage
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestProductImage(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Product Image Service Suite")
+}
@@ -0,0 +1,160 @@
+package productimage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+func testPNG(w, h int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	Expect(png.Encode(&buf, img)).To(Succeed())
+	return buf.Bytes()
+}
+
+var _ = Describe("ProductImageService", func() {
+	var (
+		ctrl        *gomock.Controller
+		mockRepo    *MockRepository
+		mockStorage *MockStorage
+		service     *Service
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockStorage = NewMockStorage(ctrl)
+		service = NewService(mockRepo, mockStorage, &config.StorageConfig{
+			MaxImageSize:  1 << 20,
+			ThumbnailSize: 32,
+		})
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("UploadProductImage", func() {
+		It("should store the image with a thumbnail and return urls", func() {
+			data := testPNG(200, 100)
+			var thumbnail []byte
+
+			mockRepo.EXPECT().IsProductInUserShop(ctx, 5, uint(7)).Return(true, nil)
+			mockStorage.EXPECT().
+				Put(ctx, gomock.Any(), data, "image/png").
+				DoAndReturn(func(_ context.Context, key string, _ []byte, _ string) error {
+					Expect(key).To(HavePrefix("products/5/"))
+					Expect(key).To(HaveSuffix(".png"))
+					return nil
+				})
+			mockStorage.EXPECT().
+				Put(ctx, gomock.Any(), gomock.Any(), "image/png").
+				DoAndReturn(func(_ context.Context, key string, data []byte, _ string) error {
+					Expect(key).To(HaveSuffix("_thumb.png"))
+					thumbnail = data
+					return nil
+				})
+			mockRepo.EXPECT().InsertImage(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, img entity.ProductImage) (entity.ProductImage, error) {
+					img.ID = 1
+					return img, nil
+				})
+			mockStorage.EXPECT().URL(gomock.Any()).DoAndReturn(func(key string) string {
+				return "https://cdn.example.com/" + key
+			}).Times(2)
+
+			img, err := service.UploadProductImage(ctx, 7, 5, data)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(img.ID).To(Equal(1))
+			Expect(img.ContentType).To(Equal("image/png"))
+			Expect(img.URL).To(HavePrefix("https://cdn.example.com/products/5/"))
+			Expect(img.ThumbnailURL).To(HaveSuffix("_thumb.png"))
+
+			cfg, _, err := image.DecodeConfig(bytes.NewReader(thumbnail))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Width).To(Equal(32))
+			Expect(cfg.Height).To(Equal(16))
+		})
+
+		It("should reject files that are not images regardless of name", func() {
+			mockRepo.EXPECT().IsProductInUserShop(ctx, 5, uint(7)).Return(true, nil)
+
+			_, err := service.UploadProductImage(ctx, 7, 5, []byte("<html><body>hi</body></html>"))
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+
+		It("should reject images over the size limit", func() {
+			_, err := service.UploadProductImage(ctx, 7, 5, bytes.Repeat([]byte{0}, 1<<20+1))
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+
+		It("should forbid uploads for products outside the user's shops", func() {
+			mockRepo.EXPECT().IsProductInUserShop(ctx, 5, uint(7)).Return(false, nil)
+
+			_, err := service.UploadProductImage(ctx, 7, 5, testPNG(10, 10))
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.Forbidden))
+		})
+
+		It("should remove stored files when saving the record fails", func() {
+			mockRepo.EXPECT().IsProductInUserShop(ctx, 5, uint(7)).Return(true, nil)
+			mockStorage.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			mockRepo.EXPECT().InsertImage(ctx, gomock.Any()).
+				Return(entity.ProductImage{}, apperror.New(apperror.DatabaseError, "boom", nil))
+			mockStorage.EXPECT().Delete(ctx, gomock.Any()).Return(nil).Times(2)
+
+			_, err := service.UploadProductImage(ctx, 7, 5, testPNG(10, 10))
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("DeleteProductImage", func() {
+		It("should delete the record and both files", func() {
+			mockRepo.EXPECT().GetImageByID(ctx, 3).Return(entity.ProductImage{
+				ID: 3, ProductID: 5, Key: "products/5/a.png", ThumbnailKey: "products/5/a_thumb.png",
+			}, nil)
+			mockRepo.EXPECT().IsProductInUserShop(ctx, 5, uint(7)).Return(true, nil)
+			mockRepo.EXPECT().DeleteImage(ctx, 3).Return(nil)
+			mockStorage.EXPECT().Delete(ctx, "products/5/a.png").Return(nil)
+			mockStorage.EXPECT().Delete(ctx, "products/5/a_thumb.png").Return(nil)
+
+			Expect(service.DeleteProductImage(ctx, 7, 5, 3)).To(Succeed())
+		})
+
+		It("should not delete an image of another product", func() {
+			mockRepo.EXPECT().GetImageByID(ctx, 3).Return(entity.ProductImage{ID: 3, ProductID: 6}, nil)
+
+			err := service.DeleteProductImage(ctx, 7, 5, 3)
+
+			Expect(err).To(MatchError(apperror.ErrImageNotFound))
+		})
+	})
+})
@@ -0,0 +1,26 @@
+package reviews
+
+import (
+	"context"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+)
+
+// EventBus publishes review events to their subscribers.
+type EventBus interface {
+	Publish(ctx context.Context, exec email.Execer, e event.Event) error
+	PublishAsync(ctx context.Context, e event.Event)
+}
+
+// reviewAddedHook returns a repository hook that runs the synchronous subscribers
+// of a new review in the transaction that saves it. The asynchronous ones are
+// started by the caller after the commit.
+func reviewAddedHook(
+	ctx context.Context, events EventBus, e event.ReviewAdded,
+) func(tx email.Execer, reviewID int) error {
+	return func(tx email.Execer, reviewID int) error {
+		e.ReviewID = reviewID
+		return events.Publish(ctx, tx, e)
+	}
+}
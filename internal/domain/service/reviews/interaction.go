@@ -0,0 +1,232 @@
+package reviews
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"go.uber.org/zap"
+)
+
+// maxReplyLength limits the length of a shop reply in characters.
+const maxReplyLength = 2000
+
+type ReviewInteractionRepository interface {
+	GetReviewTarget(ctx context.Context, reviewType string, reviewID int) (entity.ReviewTarget, error)
+	GetShopRole(ctx context.Context, shopID int, userID int) (entity.ShopRole, error)
+	ShopSellsProduct(ctx context.Context, shopID int, productID int) (bool, error)
+	GetReply(ctx context.Context, reviewType string, reviewID int) (entity.ReviewReply, error)
+	UpsertReply(ctx context.Context, reply entity.ReviewReply) (entity.ReviewReply, bool, error)
+	DeleteReply(ctx context.Context, reviewType string, reviewID int) error
+	SetVote(ctx context.Context, reviewType string, reviewID int, userID int, helpful bool) error
+	DeleteVote(ctx context.Context, reviewType string, reviewID int, userID int) error
+}
+
+// ReviewInteractionService defines the interface for shop replies and helpfulness votes business logic.
+type ReviewInteractionService interface {
+	ReplyToReview(
+		ctx context.Context, reviewType string, targetID int, reviewID int, shopID int, userID int, body string,
+	) (entity.ReviewReply, bool, error)
+	DeleteReply(ctx context.Context, reviewType string, targetID int, reviewID int, userID int) error
+	VoteReview(
+		ctx context.Context, reviewType string, targetID int, reviewID int, userID int, helpful bool,
+	) error
+	RetractVote(ctx context.Context, reviewType string, targetID int, reviewID int, userID int) error
+}
+
+type InteractionService struct {
+	rir    ReviewInteractionRepository
+	logger *zap.Logger
+}
+
+func NewReviewInteractionService(rir ReviewInteractionRepository, l *zap.Logger) ReviewInteractionService {
+	return &InteractionService{
+		rir:    rir,
+		logger: l,
+	}
+}
+
+// ReplyToReview publishes or edits the reply of a shop to a review. Seller reviews can be answered
+// only by the reviewed shop, product reviews by any shop that sells the product. The user must be
+// an owner or a manager of the shop, and a review can have only one reply.
+// It returns the reply and whether it was created.
+func (s *InteractionService) ReplyToReview(
+	ctx context.Context, reviewType string, targetID int, reviewID int, shopID int, userID int, body string,
+) (entity.ReviewReply, bool, error) {
+	const op = "interactionService.ReplyToReview()"
+	log := s.logger.With(zap.String("op", op))
+
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return entity.ReviewReply{}, false, apperror.NewReviewError(apperror.ReviewInvalid, "reply is required")
+	}
+	if len([]rune(body)) > maxReplyLength {
+		return entity.ReviewReply{}, false, apperror.NewReviewError(apperror.ReviewInvalid, "reply is too long")
+	}
+
+	target, err := s.getPublishedReview(ctx, reviewType, targetID, reviewID)
+	if err != nil {
+		return entity.ReviewReply{}, false, err
+	}
+
+	if reviewType == entity.ReviewTypeSeller {
+		shopID = target.TargetID
+	} else {
+		sells, err := s.rir.ShopSellsProduct(ctx, shopID, target.TargetID)
+		if err != nil {
+			log.Error("Failed to check shop inventory", zap.Error(err))
+			return entity.ReviewReply{}, false, fmt.Errorf("op: %s, err: %w", op, err)
+		}
+		if !sells {
+			return entity.ReviewReply{}, false, apperror.NewReviewError(apperror.ReviewUnauthorized,
+				"only shops selling the product can reply to its reviews")
+		}
+	}
+	if err := s.checkCanReply(ctx, shopID, userID); err != nil {
+		return entity.ReviewReply{}, false, err
+	}
+
+	log.Info("Saving a reply", zap.Int("reviewID", reviewID), zap.Int("shopID", shopID))
+	reply, created, err := s.rir.UpsertReply(ctx, entity.ReviewReply{
+		ReviewType: reviewType,
+		ReviewID:   reviewID,
+		ShopID:     shopID,
+		AuthorID:   &userID,
+		Body:       body,
+	})
+	if err != nil {
+		log.Warn("Failed to save reply", zap.Error(err))
+		return entity.ReviewReply{}, false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	log.Info("Reply saved successfully")
+	return reply, created, nil
+}
+
+// DeleteReply removes the reply of a shop. Only owners and managers of the replying shop can do it.
+func (s *InteractionService) DeleteReply(
+	ctx context.Context, reviewType string, targetID int, reviewID int, userID int,
+) error {
+	const op = "interactionService.DeleteReply()"
+	log := s.logger.With(zap.String("op", op))
+
+	if err := s.checkReviewTarget(ctx, reviewType, targetID, reviewID); err != nil {
+		return err
+	}
+
+	reply, err := s.rir.GetReply(ctx, reviewType, reviewID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkCanReply(ctx, reply.ShopID, userID); err != nil {
+		return err
+	}
+
+	log.Info("Deleting a reply", zap.Int("reviewID", reviewID))
+	if err := s.rir.DeleteReply(ctx, reviewType, reviewID); err != nil {
+		log.Warn("Failed to delete reply", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	log.Info("Reply deleted successfully")
+	return nil
+}
+
+// VoteReview marks the review as helpful or not helpful. Each user has one vote per review,
+// voting again changes it. Authors cannot vote for their own reviews.
+func (s *InteractionService) VoteReview(
+	ctx context.Context, reviewType string, targetID int, reviewID int, userID int, helpful bool,
+) error {
+	const op = "interactionService.VoteReview()"
+	log := s.logger.With(zap.String("op", op))
+
+	target, err := s.getPublishedReview(ctx, reviewType, targetID, reviewID)
+	if err != nil {
+		return err
+	}
+	if target.AuthorID == userID {
+		return apperror.NewReviewError(apperror.ReviewInvalid, "you cannot vote for your own review")
+	}
+
+	log.Info("Saving a vote", zap.Int("reviewID", reviewID), zap.Bool("helpful", helpful))
+	if err := s.rir.SetVote(ctx, reviewType, reviewID, userID, helpful); err != nil {
+		log.Warn("Failed to save vote", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	log.Info("Vote saved successfully")
+	return nil
+}
+
+func (s *InteractionService) RetractVote(
+	ctx context.Context, reviewType string, targetID int, reviewID int, userID int,
+) error {
+	const op = "interactionService.RetractVote()"
+	log := s.logger.With(zap.String("op", op))
+
+	if err := s.checkReviewTarget(ctx, reviewType, targetID, reviewID); err != nil {
+		return err
+	}
+
+	log.Info("Retracting a vote", zap.Int("reviewID", reviewID))
+	if err := s.rir.DeleteVote(ctx, reviewType, reviewID, userID); err != nil {
+		log.Warn("Failed to retract vote", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	log.Info("Vote retracted successfully")
+	return nil
+}
+
+// getPublishedReview returns the review of the given product or shop unless it was hidden.
+func (s *InteractionService) getPublishedReview(
+	ctx context.Context, reviewType string, targetID int, reviewID int,
+) (entity.ReviewTarget, error) {
+	if reviewType != entity.ReviewTypeProduct && reviewType != entity.ReviewTypeSeller {
+		return entity.ReviewTarget{}, apperror.NewReviewError(apperror.ReviewInvalid, "unknown review type")
+	}
+
+	target, err := s.rir.GetReviewTarget(ctx, reviewType, reviewID)
+	if err != nil {
+		return entity.ReviewTarget{}, err
+	}
+	if target.TargetID != targetID {
+		return entity.ReviewTarget{}, apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+	if target.Status == entity.ReviewStatusHidden {
+		return entity.ReviewTarget{}, apperror.NewReviewError(apperror.ReviewHidden,
+			"the review was hidden by a moderator")
+	}
+	return target, nil
+}
+
+func (s *InteractionService) checkReviewTarget(
+	ctx context.Context, reviewType string, targetID int, reviewID int,
+) error {
+	if reviewType != entity.ReviewTypeProduct && reviewType != entity.ReviewTypeSeller {
+		return apperror.NewReviewError(apperror.ReviewInvalid, "unknown review type")
+	}
+
+	target, err := s.rir.GetReviewTarget(ctx, reviewType, reviewID)
+	if err != nil {
+		return err
+	}
+	if target.TargetID != targetID {
+		return apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+	return nil
+}
+
+func (s *InteractionService) checkCanReply(ctx context.Context, shopID int, userID int) error {
+	role, err := s.rir.GetShopRole(ctx, shopID, userID)
+	if err != nil {
+		return err
+	}
+	if !role.CanManage() {
+		return apperror.NewReviewError(apperror.ReviewUnauthorized,
+			"only shop owners and managers can reply to reviews")
+	}
+	return nil
+}
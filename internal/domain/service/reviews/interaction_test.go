@@ -0,0 +1,247 @@
+package reviews_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/reviews"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+type voteKey struct {
+	reviewID int
+	userID   int
+}
+
+type mockReviewInteractionRepository struct {
+	targets   map[string]map[int]entity.ReviewTarget
+	roles     map[[2]int]entity.ShopRole
+	inventory map[[2]int]bool
+	replies   map[string]map[int]entity.ReviewReply
+	votes     map[voteKey]bool
+}
+
+func newMockReviewInteractionRepository() *mockReviewInteractionRepository {
+	return &mockReviewInteractionRepository{
+		targets: map[string]map[int]entity.ReviewTarget{
+			entity.ReviewTypeProduct: {},
+			entity.ReviewTypeSeller:  {},
+		},
+		roles:     make(map[[2]int]entity.ShopRole),
+		inventory: make(map[[2]int]bool),
+		replies: map[string]map[int]entity.ReviewReply{
+			entity.ReviewTypeProduct: {},
+			entity.ReviewTypeSeller:  {},
+		},
+		votes: make(map[voteKey]bool),
+	}
+}
+
+func (m *mockReviewInteractionRepository) GetReviewTarget(
+	_ context.Context, reviewType string, reviewID int,
+) (entity.ReviewTarget, error) {
+	target, ok := m.targets[reviewType][reviewID]
+	if !ok {
+		return entity.ReviewTarget{}, apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+	return target, nil
+}
+
+func (m *mockReviewInteractionRepository) GetShopRole(
+	_ context.Context, shopID int, userID int,
+) (entity.ShopRole, error) {
+	return m.roles[[2]int{shopID, userID}], nil
+}
+
+func (m *mockReviewInteractionRepository) ShopSellsProduct(
+	_ context.Context, shopID int, productID int,
+) (bool, error) {
+	return m.inventory[[2]int{shopID, productID}], nil
+}
+
+func (m *mockReviewInteractionRepository) GetReply(
+	_ context.Context, reviewType string, reviewID int,
+) (entity.ReviewReply, error) {
+	reply, ok := m.replies[reviewType][reviewID]
+	if !ok {
+		return entity.ReviewReply{}, apperror.NewReviewError(apperror.NotFound, "reply not found")
+	}
+	return reply, nil
+}
+
+func (m *mockReviewInteractionRepository) UpsertReply(
+	_ context.Context, reply entity.ReviewReply,
+) (entity.ReviewReply, bool, error) {
+	existing, ok := m.replies[reply.ReviewType][reply.ReviewID]
+	if ok && existing.ShopID != reply.ShopID {
+		return entity.ReviewReply{}, false, apperror.NewReviewError(apperror.ReviewDuplicate,
+			"the review already has a reply from another shop")
+	}
+	m.replies[reply.ReviewType][reply.ReviewID] = reply
+	return reply, !ok, nil
+}
+
+func (m *mockReviewInteractionRepository) DeleteReply(_ context.Context, reviewType string, reviewID int) error {
+	delete(m.replies[reviewType], reviewID)
+	return nil
+}
+
+func (m *mockReviewInteractionRepository) SetVote(
+	_ context.Context, _ string, reviewID int, userID int, helpful bool,
+) error {
+	m.votes[voteKey{reviewID, userID}] = helpful
+	return nil
+}
+
+func (m *mockReviewInteractionRepository) DeleteVote(
+	_ context.Context, _ string, reviewID int, userID int,
+) error {
+	if _, ok := m.votes[voteKey{reviewID, userID}]; !ok {
+		return apperror.NewReviewError(apperror.NotFound, "vote not found")
+	}
+	delete(m.votes, voteKey{reviewID, userID})
+	return nil
+}
+
+var _ = Describe("ReviewInteractionService", func() {
+	var (
+		service reviews.ReviewInteractionService
+		repo    *mockReviewInteractionRepository
+		ctx     context.Context
+	)
+
+	expectReviewErrorCode := func(err error, code string) {
+		var reviewErr *apperror.ReviewError
+		ExpectWithOffset(1, errors.As(err, &reviewErr)).To(BeTrue())
+		ExpectWithOffset(1, reviewErr.Code()).To(Equal(code))
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		repo = newMockReviewInteractionRepository()
+		service = reviews.NewReviewInteractionService(repo, zap.NewNop())
+
+		// A review of product 10 and a review of shop 20, both by user 2
+		repo.targets[entity.ReviewTypeProduct][1] = entity.ReviewTarget{
+			AuthorID: 2, TargetID: 10, Status: entity.ReviewStatusPublished,
+		}
+		repo.targets[entity.ReviewTypeSeller][1] = entity.ReviewTarget{
+			AuthorID: 2, TargetID: 20, Status: entity.ReviewStatusPublished,
+		}
+		repo.roles[[2]int{20, 5}] = entity.ShopRoleManager
+		repo.roles[[2]int{20, 6}] = entity.ShopRoleViewer
+		repo.roles[[2]int{30, 7}] = entity.ShopRoleOwner
+		repo.inventory[[2]int{20, 10}] = true
+		repo.inventory[[2]int{30, 10}] = true
+	})
+
+	Context("ReplyToReview", func() {
+		It("should let a shop manager reply to a review of the shop", func() {
+			reply, created, err := service.ReplyToReview(ctx, entity.ReviewTypeSeller, 20, 1, 0, 5, "  Thanks!  ")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(BeTrue())
+			Expect(reply.ShopID).To(Equal(20))
+			Expect(reply.Body).To(Equal("Thanks!"))
+			Expect(*reply.AuthorID).To(Equal(5))
+		})
+
+		It("should edit the existing reply of the same shop", func() {
+			_, _, err := service.ReplyToReview(ctx, entity.ReviewTypeProduct, 10, 1, 20, 5, "Thanks!")
+			Expect(err).NotTo(HaveOccurred())
+
+			reply, created, err := service.ReplyToReview(ctx, entity.ReviewTypeProduct, 10, 1, 20, 5, "Thank you!")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(BeFalse())
+			Expect(reply.Body).To(Equal("Thank you!"))
+		})
+
+		It("should allow only one shop to reply", func() {
+			_, _, err := service.ReplyToReview(ctx, entity.ReviewTypeProduct, 10, 1, 20, 5, "Thanks!")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = service.ReplyToReview(ctx, entity.ReviewTypeProduct, 10, 1, 30, 7, "We sell it too!")
+
+			expectReviewErrorCode(err, apperror.ReviewDuplicate)
+		})
+
+		It("should reject viewers of the shop", func() {
+			_, _, err := service.ReplyToReview(ctx, entity.ReviewTypeSeller, 20, 1, 0, 6, "Thanks!")
+
+			expectReviewErrorCode(err, apperror.ReviewUnauthorized)
+		})
+
+		It("should reject shops that do not sell the product", func() {
+			repo.roles[[2]int{40, 5}] = entity.ShopRoleOwner
+
+			_, _, err := service.ReplyToReview(ctx, entity.ReviewTypeProduct, 10, 1, 40, 5, "Thanks!")
+
+			expectReviewErrorCode(err, apperror.ReviewUnauthorized)
+		})
+
+		It("should reject an empty reply", func() {
+			_, _, err := service.ReplyToReview(ctx, entity.ReviewTypeSeller, 20, 1, 0, 5, "   ")
+
+			expectReviewErrorCode(err, apperror.ReviewInvalid)
+		})
+
+		It("should not reply to a hidden review", func() {
+			repo.targets[entity.ReviewTypeSeller][1] = entity.ReviewTarget{
+				AuthorID: 2, TargetID: 20, Status: entity.ReviewStatusHidden,
+			}
+
+			_, _, err := service.ReplyToReview(ctx, entity.ReviewTypeSeller, 20, 1, 0, 5, "Thanks!")
+
+			expectReviewErrorCode(err, apperror.ReviewHidden)
+		})
+	})
+
+	Context("DeleteReply", func() {
+		It("should let only managers of the replying shop delete the reply", func() {
+			_, _, err := service.ReplyToReview(ctx, entity.ReviewTypeProduct, 10, 1, 20, 5, "Thanks!")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = service.DeleteReply(ctx, entity.ReviewTypeProduct, 10, 1, 7)
+			expectReviewErrorCode(err, apperror.ReviewUnauthorized)
+
+			Expect(service.DeleteReply(ctx, entity.ReviewTypeProduct, 10, 1, 5)).To(Succeed())
+			Expect(repo.replies[entity.ReviewTypeProduct]).To(BeEmpty())
+		})
+	})
+
+	Context("VoteReview and RetractVote", func() {
+		It("should keep one vote per user", func() {
+			Expect(service.VoteReview(ctx, entity.ReviewTypeProduct, 10, 1, 3, true)).To(Succeed())
+			Expect(service.VoteReview(ctx, entity.ReviewTypeProduct, 10, 1, 3, false)).To(Succeed())
+
+			Expect(repo.votes).To(HaveLen(1))
+			Expect(repo.votes).To(HaveKeyWithValue(voteKey{1, 3}, false))
+
+			Expect(service.RetractVote(ctx, entity.ReviewTypeProduct, 10, 1, 3)).To(Succeed())
+			Expect(repo.votes).To(BeEmpty())
+		})
+
+		It("should reject a vote for the own review", func() {
+			err := service.VoteReview(ctx, entity.ReviewTypeProduct, 10, 1, 2, true)
+
+			expectReviewErrorCode(err, apperror.ReviewInvalid)
+		})
+
+		It("should not find a review of another product", func() {
+			err := service.VoteReview(ctx, entity.ReviewTypeProduct, 11, 1, 3, true)
+
+			expectReviewErrorCode(err, apperror.NotFound)
+		})
+
+		It("should return NotFound when there is no vote to retract", func() {
+			err := service.RetractVote(ctx, entity.ReviewTypeSeller, 20, 1, 3)
+
+			expectReviewErrorCode(err, apperror.NotFound)
+		})
+	})
+})
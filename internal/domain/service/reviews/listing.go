@@ -0,0 +1,38 @@
+package reviews
+
+import (
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+const (
+	defaultReviewPageSize = 20
+	maxReviewPageSize     = 100
+)
+
+// normalizeReviewFilter validates the filter and fills in the default sort and page size.
+func normalizeReviewFilter(filter entity.ReviewFilter) (entity.ReviewFilter, error) {
+	switch filter.Sort {
+	case "":
+		filter.Sort = entity.ReviewSortNewest
+	case entity.ReviewSortNewest, entity.ReviewSortHighest, entity.ReviewSortLowest, entity.ReviewSortMostHelpful:
+	default:
+		return filter, apperror.NewReviewError(apperror.ReviewInvalid, "unknown sort order")
+	}
+
+	if filter.MinRating < 0 || filter.MinRating > 5 || filter.MaxRating < 0 || filter.MaxRating > 5 {
+		return filter, apperror.NewReviewError(apperror.ReviewInvalid, "rating must be between 1 and 5")
+	}
+	if filter.MaxRating > 0 && filter.MinRating > filter.MaxRating {
+		return filter, apperror.NewReviewError(apperror.ReviewInvalid, "min_rating is greater than max_rating")
+	}
+
+	if filter.Limit < 0 || filter.Limit > maxReviewPageSize || filter.Offset < 0 {
+		return filter, apperror.NewReviewError(apperror.ReviewInvalid, "invalid pagination")
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultReviewPageSize
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,232 @@
+package reviews
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"go.uber.org/zap"
+)
+
+// reviewEditWindow is how long after posting the author can still change or delete a review.
+const reviewEditWindow = 48 * time.Hour
+
+type ReviewModerationRepository interface {
+	GetReviewTarget(ctx context.Context, reviewType string, reviewID int) (int, int, error)
+	AddReport(ctx context.Context, report entity.ReviewReport) (int, error)
+	GetModerationQueue(ctx context.Context, limit, offset int) ([]entity.ModerationQueueItem, int, error)
+	SetReviewStatus(
+		ctx context.Context, reviewType string, reviewID int, status, action, reason string, moderatorID int,
+	) error
+	BanReviewer(ctx context.Context, userID int, reason string, moderatorID int) error
+	UnbanReviewer(ctx context.Context, userID int, reason string, moderatorID int) error
+}
+
+// ReviewModerationService defines the interface for review reports and moderation business logic.
+type ReviewModerationService interface {
+	ReportReview(
+		ctx context.Context, reviewType string, targetID int, reviewID int, reporterID int, reason string,
+	) (int, error)
+	GetModerationQueue(ctx context.Context, limit, offset int) ([]entity.ModerationQueueItem, int, error)
+	HideReview(ctx context.Context, reviewType string, reviewID int, reason string, moderatorID int) error
+	RestoreReview(ctx context.Context, reviewType string, reviewID int, reason string, moderatorID int) error
+	BanReviewer(ctx context.Context, userID int, reason string, moderatorID int) error
+	UnbanReviewer(ctx context.Context, userID int, reason string, moderatorID int) error
+}
+
+type ModerationService struct {
+	rmr    ReviewModerationRepository
+	logger *zap.Logger
+}
+
+func NewReviewModerationService(rmr ReviewModerationRepository, l *zap.Logger) ReviewModerationService {
+	return &ModerationService{
+		rmr:    rmr,
+		logger: l,
+	}
+}
+
+// ReportReview files a complaint about a review of the given product or shop.
+// Users cannot report their own reviews, and each user can report a review only once.
+func (s *ModerationService) ReportReview(
+	ctx context.Context, reviewType string, targetID int, reviewID int, reporterID int, reason string,
+) (int, error) {
+	const op = "moderationService.ReportReview()"
+	log := s.logger.With(zap.String("op", op))
+
+	reason, err := validateModerationInput(reviewType, reason)
+	if err != nil {
+		return 0, err
+	}
+
+	authorID, reviewTargetID, err := s.rmr.GetReviewTarget(ctx, reviewType, reviewID)
+	if err != nil {
+		return 0, err
+	}
+	if reviewTargetID != targetID {
+		return 0, apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+	if authorID == reporterID {
+		return 0, apperror.NewReviewError(apperror.ReviewInvalid, "you cannot report your own review")
+	}
+
+	log.Info("Adding a report")
+	id, err := s.rmr.AddReport(ctx, entity.ReviewReport{
+		ReviewType: reviewType,
+		ReviewID:   reviewID,
+		ReporterID: reporterID,
+		Reason:     reason,
+	})
+	if err != nil {
+		log.Warn("Failed to add report", zap.Error(err))
+		return 0, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	log.Info("Report added successfully")
+	return id, nil
+}
+
+func (s *ModerationService) GetModerationQueue(
+	ctx context.Context, limit, offset int,
+) ([]entity.ModerationQueueItem, int, error) {
+	const op = "moderationService.GetModerationQueue()"
+	log := s.logger.With(zap.String("op", op))
+
+	items, total, err := s.rmr.GetModerationQueue(ctx, limit, offset)
+	if err != nil {
+		log.Error("Failed to get moderation queue", zap.Error(err))
+		return nil, 0, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return items, total, nil
+}
+
+// HideReview removes the review from listings and ratings and resolves its reports.
+func (s *ModerationService) HideReview(
+	ctx context.Context, reviewType string, reviewID int, reason string, moderatorID int,
+) error {
+	return s.setReviewStatus(ctx, reviewType, reviewID, entity.ReviewStatusHidden,
+		entity.ModerationActionHide, reason, moderatorID)
+}
+
+// RestoreReview publishes a previously hidden review again.
+func (s *ModerationService) RestoreReview(
+	ctx context.Context, reviewType string, reviewID int, reason string, moderatorID int,
+) error {
+	return s.setReviewStatus(ctx, reviewType, reviewID, entity.ReviewStatusPublished,
+		entity.ModerationActionRestore, reason, moderatorID)
+}
+
+// BanReviewer forbids the user to leave reviews and hides all reviews they have already left.
+func (s *ModerationService) BanReviewer(ctx context.Context, userID int, reason string, moderatorID int) error {
+	const op = "moderationService.BanReviewer()"
+	log := s.logger.With(zap.String("op", op))
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return apperror.NewReviewError(apperror.ReviewInvalid, "reason is required")
+	}
+	if userID == moderatorID {
+		return apperror.NewReviewError(apperror.ReviewInvalid, "you cannot ban yourself")
+	}
+
+	log.Info("Banning a reviewer", zap.Int("userID", userID))
+	if err := s.rmr.BanReviewer(ctx, userID, reason, moderatorID); err != nil {
+		log.Warn("Failed to ban reviewer", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	log.Info("Reviewer banned successfully")
+	return nil
+}
+
+// UnbanReviewer lets a banned user leave reviews again. Their hidden reviews are not restored.
+func (s *ModerationService) UnbanReviewer(ctx context.Context, userID int, reason string, moderatorID int) error {
+	const op = "moderationService.UnbanReviewer()"
+	log := s.logger.With(zap.String("op", op))
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return apperror.NewReviewError(apperror.ReviewInvalid, "reason is required")
+	}
+
+	log.Info("Unbanning a reviewer", zap.Int("userID", userID))
+	if err := s.rmr.UnbanReviewer(ctx, userID, reason, moderatorID); err != nil {
+		log.Warn("Failed to unban reviewer", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	log.Info("Reviewer unbanned successfully")
+	return nil
+}
+
+func (s *ModerationService) setReviewStatus(
+	ctx context.Context, reviewType string, reviewID int, status, action, reason string, moderatorID int,
+) error {
+	const op = "moderationService.setReviewStatus()"
+	log := s.logger.With(zap.String("op", op))
+
+	reason, err := validateModerationInput(reviewType, reason)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Changing review status", zap.String("status", status), zap.Int("reviewID", reviewID))
+	if err := s.rmr.SetReviewStatus(ctx, reviewType, reviewID, status, action, reason, moderatorID); err != nil {
+		log.Warn("Failed to change review status", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	log.Info("Review status changed successfully")
+	return nil
+}
+
+func validateModerationInput(reviewType, reason string) (string, error) {
+	if reviewType != entity.ReviewTypeProduct && reviewType != entity.ReviewTypeSeller {
+		return "", apperror.NewReviewError(apperror.ReviewInvalid, "unknown review type")
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", apperror.NewReviewError(apperror.ReviewInvalid, "reason is required")
+	}
+	return reason, nil
+}
+
+// reviewerBanChecker is implemented by both review repositories.
+type reviewerBanChecker interface {
+	IsReviewerBanned(ctx context.Context, userID int) (bool, error)
+}
+
+func checkReviewerNotBanned(ctx context.Context, repo reviewerBanChecker, userID int) error {
+	banned, err := repo.IsReviewerBanned(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if banned {
+		return apperror.NewReviewError(apperror.ReviewAuthorBanned, "you are not allowed to leave reviews")
+	}
+	return nil
+}
+
+// checkAuthorCanEdit allows changes only to the author of a published review within the edit window.
+func checkAuthorCanEdit(authorID, userID int, createdAt time.Time, status string) error {
+	if authorID != userID {
+		return apperror.NewReviewError(apperror.ReviewUnauthorized, "only the author can change this review")
+	}
+	if status == entity.ReviewStatusHidden {
+		return apperror.NewReviewError(apperror.ReviewHidden, "the review was hidden by a moderator")
+	}
+	if time.Since(createdAt) > reviewEditWindow {
+		return apperror.NewReviewError(apperror.ReviewEditExpired, "the review can no longer be changed")
+	}
+	return nil
+}
+
+func isReviewNotFound(err error) bool {
+	var reviewErr *apperror.ReviewError
+	return errors.As(err, &reviewErr) && reviewErr.Code() == apperror.NotFound
+}
@@ -0,0 +1,202 @@
+package reviews_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/reviews"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+type moderatedReview struct {
+	authorID int
+	targetID int
+	status   string
+}
+
+type mockReviewModerationRepository struct {
+	reviews map[string]map[int]*moderatedReview
+	reports []entity.ReviewReport
+	banned  map[int]string
+}
+
+func newMockReviewModerationRepository() *mockReviewModerationRepository {
+	return &mockReviewModerationRepository{
+		reviews: map[string]map[int]*moderatedReview{
+			entity.ReviewTypeProduct: {},
+			entity.ReviewTypeSeller:  {},
+		},
+		banned: make(map[int]string),
+	}
+}
+
+func (m *mockReviewModerationRepository) GetReviewTarget(
+	_ context.Context, reviewType string, reviewID int,
+) (int, int, error) {
+	review, ok := m.reviews[reviewType][reviewID]
+	if !ok {
+		return 0, 0, apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+	return review.authorID, review.targetID, nil
+}
+
+func (m *mockReviewModerationRepository) AddReport(_ context.Context, report entity.ReviewReport) (int, error) {
+	for _, existing := range m.reports {
+		if existing.ReviewType == report.ReviewType && existing.ReviewID == report.ReviewID &&
+			existing.ReporterID == report.ReporterID {
+			return 0, apperror.NewReviewError(apperror.ReviewDuplicate, "you have already reported this review")
+		}
+	}
+	report.ID = len(m.reports) + 1
+	m.reports = append(m.reports, report)
+	return report.ID, nil
+}
+
+func (m *mockReviewModerationRepository) GetModerationQueue(
+	_ context.Context, _, _ int,
+) ([]entity.ModerationQueueItem, int, error) {
+	return nil, 0, nil
+}
+
+func (m *mockReviewModerationRepository) SetReviewStatus(
+	_ context.Context, reviewType string, reviewID int, status, _, _ string, _ int,
+) error {
+	review, ok := m.reviews[reviewType][reviewID]
+	if !ok {
+		return apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+	review.status = status
+	return nil
+}
+
+func (m *mockReviewModerationRepository) BanReviewer(_ context.Context, userID int, reason string, _ int) error {
+	m.banned[userID] = reason
+	return nil
+}
+
+func (m *mockReviewModerationRepository) UnbanReviewer(_ context.Context, userID int, _ string, _ int) error {
+	if _, ok := m.banned[userID]; !ok {
+		return apperror.NewReviewError(apperror.ReviewInvalid, "user is not banned from reviews")
+	}
+	delete(m.banned, userID)
+	return nil
+}
+
+var _ = Describe("ReviewModerationService", func() {
+	var (
+		service reviews.ReviewModerationService
+		repo    *mockReviewModerationRepository
+		ctx     context.Context
+	)
+
+	expectReviewErrorCode := func(err error, code string) {
+		var reviewErr *apperror.ReviewError
+		ExpectWithOffset(1, errors.As(err, &reviewErr)).To(BeTrue())
+		ExpectWithOffset(1, reviewErr.Code()).To(Equal(code))
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		repo = newMockReviewModerationRepository()
+		service = reviews.NewReviewModerationService(repo, zap.NewNop())
+
+		repo.reviews[entity.ReviewTypeProduct][1] = &moderatedReview{
+			authorID: 2, targetID: 10, status: entity.ReviewStatusPublished,
+		}
+	})
+
+	Context("ReportReview", func() {
+		It("should store the report", func() {
+			id, err := service.ReportReview(ctx, entity.ReviewTypeProduct, 10, 1, 3, "  spam  ")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal(1))
+			Expect(repo.reports).To(HaveLen(1))
+			Expect(repo.reports[0].Reason).To(Equal("spam"))
+		})
+
+		It("should reject a report on the own review", func() {
+			_, err := service.ReportReview(ctx, entity.ReviewTypeProduct, 10, 1, 2, "spam")
+
+			expectReviewErrorCode(err, apperror.ReviewInvalid)
+		})
+
+		It("should not find a review of another product", func() {
+			_, err := service.ReportReview(ctx, entity.ReviewTypeProduct, 11, 1, 3, "spam")
+
+			expectReviewErrorCode(err, apperror.NotFound)
+		})
+
+		It("should accept only one report per user", func() {
+			_, err := service.ReportReview(ctx, entity.ReviewTypeProduct, 10, 1, 3, "spam")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = service.ReportReview(ctx, entity.ReviewTypeProduct, 10, 1, 3, "spam again")
+
+			expectReviewErrorCode(err, apperror.ReviewDuplicate)
+		})
+	})
+
+	Context("HideReview and RestoreReview", func() {
+		It("should hide and restore the review", func() {
+			Expect(service.HideReview(ctx, entity.ReviewTypeProduct, 1, "insults", 99)).To(Succeed())
+			Expect(repo.reviews[entity.ReviewTypeProduct][1].status).To(Equal(entity.ReviewStatusHidden))
+
+			Expect(service.RestoreReview(ctx, entity.ReviewTypeProduct, 1, "appeal accepted", 99)).To(Succeed())
+			Expect(repo.reviews[entity.ReviewTypeProduct][1].status).To(Equal(entity.ReviewStatusPublished))
+		})
+
+		It("should require a reason", func() {
+			err := service.HideReview(ctx, entity.ReviewTypeProduct, 1, " ", 99)
+
+			expectReviewErrorCode(err, apperror.ReviewInvalid)
+		})
+
+		It("should reject an unknown review type", func() {
+			err := service.HideReview(ctx, "order", 1, "insults", 99)
+
+			expectReviewErrorCode(err, apperror.ReviewInvalid)
+		})
+	})
+
+	Context("BanReviewer", func() {
+		It("should ban the reviewer with the reason", func() {
+			Expect(service.BanReviewer(ctx, 2, "repeated spam", 99)).To(Succeed())
+			Expect(repo.banned).To(HaveKeyWithValue(2, "repeated spam"))
+		})
+
+		It("should not let a moderator ban themselves", func() {
+			err := service.BanReviewer(ctx, 99, "oops", 99)
+
+			expectReviewErrorCode(err, apperror.ReviewInvalid)
+		})
+	})
+
+	Context("UnbanReviewer", func() {
+		It("should lift the ban", func() {
+			repo.banned[2] = "repeated spam"
+
+			Expect(service.UnbanReviewer(ctx, 2, "appeal accepted", 99)).To(Succeed())
+			Expect(repo.banned).NotTo(HaveKey(2))
+		})
+
+		It("should require a reason", func() {
+			repo.banned[2] = "repeated spam"
+
+			err := service.UnbanReviewer(ctx, 2, "  ", 99)
+
+			expectReviewErrorCode(err, apperror.ReviewInvalid)
+			Expect(repo.banned).To(HaveKey(2))
+		})
+
+		It("should reject a user who is not banned", func() {
+			err := service.UnbanReviewer(ctx, 2, "appeal accepted", 99)
+
+			expectReviewErrorCode(err, apperror.ReviewInvalid)
+		})
+	})
+})
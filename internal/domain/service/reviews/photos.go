@@ -0,0 +1,130 @@
+package reviews
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/imaging"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// PhotoStorage keeps review photo files. It is the same image storage that product images use.
+type PhotoStorage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// AddPhoto attaches an image to the review together with its thumbnail. Only the author can do it,
+// within the edit window and up to the configured number of photos per review.
+// The format is detected from the content, only jpeg, png and gif images are accepted.
+func (s *ProductReviewService) AddPhoto(
+	ctx context.Context, productID int, reviewID int, userID int, data []byte,
+) (entity.ReviewPhoto, error) {
+	const op = "productReviewService.AddPhoto()"
+	log := s.logger.With(zap.String("op", op))
+
+	if s.maxPhotoSize > 0 && int64(len(data)) > s.maxPhotoSize {
+		return entity.ReviewPhoto{}, apperror.NewReviewError(apperror.ReviewInvalid,
+			fmt.Sprintf("photo is larger than %d bytes", s.maxPhotoSize))
+	}
+
+	if _, err := s.getEditableReview(ctx, productID, reviewID, userID); err != nil {
+		return entity.ReviewPhoto{}, err
+	}
+
+	format, err := imaging.Sniff(data)
+	if err != nil {
+		return entity.ReviewPhoto{}, apperror.NewReviewError(apperror.ReviewInvalid,
+			"only jpeg, png and gif images are allowed")
+	}
+
+	thumbnail, thumbnailFormat, err := imaging.Thumbnail(data, format, s.thumbnailSize)
+	if err != nil {
+		return entity.ReviewPhoto{}, apperror.NewReviewError(apperror.ReviewInvalid, "failed to process photo")
+	}
+
+	name := uuid.NewString()
+	photo := entity.ReviewPhoto{
+		ReviewID:     reviewID,
+		Key:          fmt.Sprintf("reviews/%d/%s.%s", reviewID, name, format.Extension),
+		ThumbnailKey: fmt.Sprintf("reviews/%d/%s_thumb.%s", reviewID, name, thumbnailFormat.Extension),
+		ContentType:  format.ContentType,
+	}
+
+	log.Info("Storing a photo", zap.Int("reviewID", reviewID))
+	if err := s.storage.Put(ctx, photo.Key, data, format.ContentType); err != nil {
+		log.Error("Failed to store photo", zap.Error(err))
+		return entity.ReviewPhoto{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	if err := s.storage.Put(ctx, photo.ThumbnailKey, thumbnail, thumbnailFormat.ContentType); err != nil {
+		s.removeObjects(ctx, photo.Key)
+		log.Error("Failed to store thumbnail", zap.Error(err))
+		return entity.ReviewPhoto{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	saved, err := s.prr.InsertReviewPhoto(ctx, photo, s.maxPhotos)
+	if err != nil {
+		s.removeObjects(ctx, photo.Key, photo.ThumbnailKey)
+		log.Warn("Failed to save photo", zap.Error(err))
+		return entity.ReviewPhoto{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	saved.URL = s.storage.URL(saved.Key)
+	saved.ThumbnailURL = s.storage.URL(saved.ThumbnailKey)
+
+	log.Info("Photo added successfully")
+	return saved, nil
+}
+
+// DeletePhoto removes the photo from the review and its files from the storage.
+// Only the author can do it, within the edit window.
+func (s *ProductReviewService) DeletePhoto(
+	ctx context.Context, productID int, reviewID int, photoID int, userID int,
+) error {
+	const op = "productReviewService.DeletePhoto()"
+	log := s.logger.With(zap.String("op", op))
+
+	if _, err := s.getEditableReview(ctx, productID, reviewID, userID); err != nil {
+		return err
+	}
+
+	photo, err := s.prr.GetReviewPhotoByID(ctx, photoID)
+	if err != nil {
+		return err
+	}
+	if photo.ReviewID != reviewID {
+		return apperror.NewReviewError(apperror.NotFound, "photo not found")
+	}
+
+	log.Info("Deleting a photo", zap.Int("photoID", photoID))
+	if err := s.prr.DeleteReviewPhoto(ctx, photoID); err != nil {
+		log.Warn("Failed to delete photo", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	// the row is already gone, so leftover files do not affect anything
+	s.removeObjects(ctx, photo.Key, photo.ThumbnailKey)
+
+	log.Info("Photo deleted successfully")
+	return nil
+}
+
+func (s *ProductReviewService) fillPhotoURLs(photos []entity.ReviewPhoto) {
+	for i := range photos {
+		photos[i].URL = s.storage.URL(photos[i].Key)
+		photos[i].ThumbnailURL = s.storage.URL(photos[i].ThumbnailKey)
+	}
+}
+
+// removeObjects deletes files on a best-effort basis, a failed cleanup must not fail the request.
+func (s *ProductReviewService) removeObjects(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if key != "" {
+			_ = s.storage.Delete(ctx, key)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package reviews_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/reviews"
+	"github.com/EM-Stawberry/Stawberry/pkg/storage"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+// newTestStorage returns a local storage in a temporary directory removed after the spec.
+func newTestStorage() *storage.LocalStorage {
+	local, err := storage.NewLocalStorage(GinkgoT().TempDir(), "/static")
+	Expect(err).NotTo(HaveOccurred())
+	return local
+}
+
+// newTestService creates the service with a 1 MiB photo limit, 16px thumbnails and two photos per review.
+func newTestService(
+	repo reviews.ProductReviewRepository, local reviews.PhotoStorage, bus reviews.EventBus,
+) reviews.ProductReviewsService {
+	return reviews.NewProductReviewService(repo, local, 1<<20, 16, 2, bus, zap.NewNop())
+}
+
+func testPNG(w, h int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	Expect(png.Encode(&buf, img)).To(Succeed())
+	return buf.Bytes()
+}
+
+var _ = Describe("ProductReviewService photos", func() {
+	var (
+		service reviews.ProductReviewsService
+		repo    *mockProductReviewRepository
+		local   *storage.LocalStorage
+		ctx     context.Context
+	)
+
+	expectReviewErrorCode := func(err error, code string) {
+		var reviewErr *apperror.ReviewError
+		ExpectWithOffset(1, errors.As(err, &reviewErr)).To(BeTrue())
+		ExpectWithOffset(1, reviewErr.Code()).To(Equal(code))
+	}
+
+	exists := func(key string) bool {
+		_, err := os.Stat(filepath.Join(local.Dir(), filepath.FromSlash(key)))
+		return err == nil
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		repo = newMockProductReviewRepository()
+		local = newTestStorage()
+		service = newTestService(repo, local, &recordingBus{})
+
+		repo.products[1] = entity.Product{ID: 1}
+		repo.reviews[1] = []entity.ProductReview{{
+			ID: 1, ProductID: 1, UserID: 2, Rating: 5, Review: "Great product!",
+			Status: entity.ReviewStatusPublished, CreatedAt: time.Now().Add(-time.Hour),
+		}}
+	})
+
+	Context("AddPhoto", func() {
+		It("should store the photo with a thumbnail and return their URLs", func() {
+			photo, err := service.AddPhoto(ctx, 1, 1, 2, testPNG(64, 32))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(photo.ContentType).To(Equal("image/png"))
+			Expect(photo.URL).To(Equal("/static/" + photo.Key))
+			Expect(photo.ThumbnailURL).To(Equal("/static/" + photo.ThumbnailKey))
+			Expect(photo.Key).To(HavePrefix("reviews/1/"))
+			Expect(exists(photo.Key)).To(BeTrue())
+			Expect(exists(photo.ThumbnailKey)).To(BeTrue())
+		})
+
+		It("should reject content that is not an image", func() {
+			_, err := service.AddPhoto(ctx, 1, 1, 2, []byte("<html>not an image</html>"))
+
+			expectReviewErrorCode(err, apperror.ReviewInvalid)
+			Expect(repo.photos).To(BeEmpty())
+		})
+
+		It("should reject photos over the limit and remove their files", func() {
+			for range 2 {
+				_, err := service.AddPhoto(ctx, 1, 1, 2, testPNG(8, 8))
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			_, err := service.AddPhoto(ctx, 1, 1, 2, testPNG(8, 8))
+
+			expectReviewErrorCode(err, apperror.ReviewInvalid)
+			files, _ := os.ReadDir(filepath.Join(local.Dir(), "reviews", "1"))
+			Expect(files).To(HaveLen(4))
+		})
+
+		It("should let only the author add photos", func() {
+			_, err := service.AddPhoto(ctx, 1, 1, 3, testPNG(8, 8))
+
+			expectReviewErrorCode(err, apperror.ReviewUnauthorized)
+		})
+	})
+
+	Context("GetReviewsByProductID", func() {
+		It("should return photo URLs with the reviews", func() {
+			photo, err := service.AddPhoto(ctx, 1, 1, 2, testPNG(8, 8))
+			Expect(err).NotTo(HaveOccurred())
+			repo.reviews[1][0].Photos = []entity.ReviewPhoto{{ID: photo.ID, Key: photo.Key}}
+
+			page, err := service.GetReviewsByProductID(ctx, 1, entity.ReviewFilter{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Reviews[0].Photos[0].URL).To(Equal(photo.URL))
+		})
+	})
+
+	Context("DeletePhoto and DeleteReview", func() {
+		It("should remove the photo files", func() {
+			photo, err := service.AddPhoto(ctx, 1, 1, 2, testPNG(8, 8))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(service.DeletePhoto(ctx, 1, 1, photo.ID, 2)).To(Succeed())
+
+			Expect(repo.photos).To(BeEmpty())
+			Expect(exists(photo.Key)).To(BeFalse())
+			Expect(exists(photo.ThumbnailKey)).To(BeFalse())
+		})
+
+		It("should not delete a photo of another review", func() {
+			repo.photos[7] = entity.ReviewPhoto{ID: 7, ReviewID: 5}
+
+			err := service.DeletePhoto(ctx, 1, 1, 7, 2)
+
+			expectReviewErrorCode(err, apperror.NotFound)
+		})
+
+		It("should remove the photo files with the review", func() {
+			photo, err := service.AddPhoto(ctx, 1, 1, 2, testPNG(8, 8))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(service.DeleteReview(ctx, 1, 1, 2)).To(Succeed())
+
+			Expect(exists(photo.Key)).To(BeFalse())
+		})
+	})
+})
@@ -5,7 +5,10 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
 	"go.uber.org/zap"
 )
 
@@ -14,33 +17,80 @@ var (
 )
 
 type ProductReviewRepository interface {
-	AddReview(ctx context.Context, productID int, userID int, rating int, review string) error
-	GetReviewsByProductID(ctx context.Context, productID int) ([]entity.ProductReview, error)
+	UpsertReview(
+		ctx context.Context, productID int, userID int, rating int, review string, verified bool,
+		onCreate func(tx email.Execer, reviewID int) error,
+	) (int, bool, error)
+	HasAcceptedOffer(ctx context.Context, userID int, productID int) (bool, error)
+	GetReviewsByProductID(
+		ctx context.Context, productID int, filter entity.ReviewFilter,
+	) ([]entity.ProductReview, int, error)
+	GetReviewSummary(ctx context.Context, productID int) (entity.ReviewSummary, error)
 	GetProductByID(ctx context.Context, productID int) (entity.Product, error)
+	GetReviewByID(ctx context.Context, reviewID int) (entity.ProductReview, error)
+	GetUserReview(ctx context.Context, productID int, userID int) (entity.ProductReview, error)
+	UpdateReview(ctx context.Context, reviewID int, rating int, review string) error
+	DeleteReview(ctx context.Context, reviewID int) error
+	IsReviewerBanned(ctx context.Context, userID int) (bool, error)
+	IsShopStaffProduct(ctx context.Context, productID int, userID int) (bool, error)
+	InsertReviewPhoto(ctx context.Context, photo entity.ReviewPhoto, maxPhotos int) (entity.ReviewPhoto, error)
+	GetReviewPhotoByID(ctx context.Context, photoID int) (entity.ReviewPhoto, error)
+	GetReviewPhotos(ctx context.Context, reviewID int) ([]entity.ReviewPhoto, error)
+	DeleteReviewPhoto(ctx context.Context, photoID int) error
 }
 
 // ProductReviewsService defines the interface for product review business logic.
 type ProductReviewsService interface {
-	AddReview(ctx context.Context, productID int, userID int, rating int, review string) (int, error)
-	GetReviewsByProductID(ctx context.Context, productID int) ([]entity.ProductReview, error)
+	AddReview(ctx context.Context, productID int, userID int, rating int, review string) (int, bool, error)
+	GetReviewsByProductID(
+		ctx context.Context, productID int, filter entity.ReviewFilter,
+	) (entity.ProductReviewPage, error)
+	UpdateReview(ctx context.Context, productID int, reviewID int, userID int, rating int, review string) error
+	DeleteReview(ctx context.Context, productID int, reviewID int, userID int) error
+	AddPhoto(ctx context.Context, productID int, reviewID int, userID int, data []byte) (entity.ReviewPhoto, error)
+	DeletePhoto(ctx context.Context, productID int, reviewID int, photoID int, userID int) error
 }
 
 type ProductReviewService struct {
-	prr    ProductReviewRepository
-	logger *zap.Logger
+	prr           ProductReviewRepository
+	storage       PhotoStorage
+	maxPhotoSize  int64
+	maxPhotos     int
+	thumbnailSize int
+	events        EventBus
+	logger        *zap.Logger
 }
 
-func NewProductReviewService(prr ProductReviewRepository, l *zap.Logger) ProductReviewsService {
+// NewProductReviewService creates the service. Photos larger than maxPhotoSize bytes are rejected,
+// thumbnails fit into thumbnailSize pixels and a review can have at most maxPhotos photos.
+func NewProductReviewService(
+	prr ProductReviewRepository,
+	storage PhotoStorage,
+	maxPhotoSize int64,
+	thumbnailSize int,
+	maxPhotos int,
+	events EventBus,
+	l *zap.Logger,
+) ProductReviewsService {
 	return &ProductReviewService{
-		prr:    prr,
-		logger: l,
+		prr:           prr,
+		storage:       storage,
+		maxPhotoSize:  maxPhotoSize,
+		maxPhotos:     maxPhotos,
+		thumbnailSize: thumbnailSize,
+		events:        events,
+		logger:        l,
 	}
 }
 
+// AddReview creates the user's review of the product. A user has at most one review per product,
+// so posting again replaces the previous one while it is still editable. The review is marked
+// as verified when the user has an accepted offer for the product. Staff of shops selling
+// the product cannot review it. It returns the review ID and whether it was created.
 func (s *ProductReviewService) AddReview(
 	ctx context.Context, productID int, userID int, rating int, review string,
 ) (
-	int, error,
+	int, bool, error,
 ) {
 	const op = "productReviewService.AddReviews()"
 	log := s.logger.With(zap.String("op", op))
@@ -49,42 +99,167 @@ func (s *ProductReviewService) AddReview(
 	_, err := s.prr.GetProductByID(ctx, productID)
 	if err != nil {
 		log.Warn("Product not found", zap.Int("productID", productID), zap.Error(err))
-		return 0, fmt.Errorf("op: %s, err: %w", op, err)
+		return 0, false, fmt.Errorf("op: %s, err: %w", op, err)
 	}
 
-	log.Info("Adding a review")
-	err = s.prr.AddReview(ctx, productID, userID, rating, review)
+	log.Info("Reviewer check")
+	if err := checkReviewerNotBanned(ctx, s.prr, userID); err != nil {
+		return 0, false, err
+	}
+	ownProduct, err := s.prr.IsShopStaffProduct(ctx, productID, userID)
+	if err != nil {
+		log.Warn("Failed to check shop membership", zap.Error(err))
+		return 0, false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	if ownProduct {
+		return 0, false, apperror.NewReviewError(apperror.ReviewUnauthorized,
+			"you cannot review products of your own shop")
+	}
+
+	existing, err := s.prr.GetUserReview(ctx, productID, userID)
+	switch {
+	case err == nil:
+		if err := checkAuthorCanEdit(existing.UserID, userID, existing.CreatedAt, existing.Status); err != nil {
+			return 0, false, err
+		}
+	case !isReviewNotFound(err):
+		log.Warn("Failed to get existing review", zap.Error(err))
+		return 0, false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	log.Info("Verified purchase check")
+	verified, err := s.prr.HasAcceptedOffer(ctx, userID, productID)
+	if err != nil {
+		log.Warn("Failed to check purchase", zap.Error(err))
+		return 0, false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	log.Info("Saving a review")
+	added := event.ReviewAdded{
+		ReviewType: entity.ReviewTypeProduct, TargetID: productID, UserID: userID, Rating: rating,
+	}
+	id, created, err := s.prr.UpsertReview(ctx, productID, userID, rating, review, verified,
+		reviewAddedHook(ctx, s.events, added))
 	if err != nil {
 		log.Warn("Failed to add review", zap.Error(err))
-		return 0, fmt.Errorf("op: %s, err: %w", op, err)
+		return 0, false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	if created {
+		added.ReviewID = id
+		s.events.PublishAsync(ctx, added)
 	}
 
-	log.Info("Review added successfully")
-	return productID, nil
+	log.Info("Review saved successfully", zap.Bool("created", created))
+	return id, created, nil
 }
 
+// GetReviewsByProductID returns a page of published reviews of the product
+// together with the rating summary of all its reviews.
 func (s *ProductReviewService) GetReviewsByProductID(
-	ctx context.Context, productID int,
+	ctx context.Context, productID int, filter entity.ReviewFilter,
 ) (
-	[]entity.ProductReview, error,
+	entity.ProductReviewPage, error,
 ) {
 	const op = "productReviewService.GetReviewsByProductID()"
 	log := s.logger.With(zap.String("op", op))
 
+	filter, err := normalizeReviewFilter(filter)
+	if err != nil {
+		return entity.ProductReviewPage{}, err
+	}
+
 	log.Info("Existence check")
-	_, err := s.prr.GetProductByID(ctx, productID)
+	_, err = s.prr.GetProductByID(ctx, productID)
 	if err != nil {
 		log.Warn("Product not found", zap.Int("productID", productID), zap.Error(err))
-		return nil, err
+		return entity.ProductReviewPage{}, err
 	}
 
 	log.Info("Receiving reviews")
-	reviews, err := s.prr.GetReviewsByProductID(ctx, productID)
+	reviews, total, err := s.prr.GetReviewsByProductID(ctx, productID, filter)
 	if err != nil {
 		log.Warn("Failed to get reviews", zap.Error(err))
-		return nil, fmt.Errorf("op: %s, err: %w", op, err)
+		return entity.ProductReviewPage{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	for i := range reviews {
+		s.fillPhotoURLs(reviews[i].Photos)
+	}
+
+	summary, err := s.prr.GetReviewSummary(ctx, productID)
+	if err != nil {
+		log.Warn("Failed to get review summary", zap.Error(err))
+		return entity.ProductReviewPage{}, fmt.Errorf("op: %s, err: %w", op, err)
 	}
 
 	log.Info("Reviews received successfully")
-	return reviews, nil
+	return entity.ProductReviewPage{Reviews: reviews, Total: total, Summary: summary}, nil
+}
+
+// UpdateReview changes the rating and text of the review. Only the author can do it,
+// and only within the edit window.
+func (s *ProductReviewService) UpdateReview(
+	ctx context.Context, productID int, reviewID int, userID int, rating int, review string,
+) error {
+	const op = "productReviewService.UpdateReview()"
+	log := s.logger.With(zap.String("op", op))
+
+	if _, err := s.getEditableReview(ctx, productID, reviewID, userID); err != nil {
+		return err
+	}
+
+	log.Info("Updating a review")
+	if err := s.prr.UpdateReview(ctx, reviewID, rating, review); err != nil {
+		log.Warn("Failed to update review", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	log.Info("Review updated successfully")
+	return nil
+}
+
+// DeleteReview removes the review. Only the author can do it, and only within the edit window.
+func (s *ProductReviewService) DeleteReview(ctx context.Context, productID int, reviewID int, userID int) error {
+	const op = "productReviewService.DeleteReview()"
+	log := s.logger.With(zap.String("op", op))
+
+	if _, err := s.getEditableReview(ctx, productID, reviewID, userID); err != nil {
+		return err
+	}
+
+	photos, err := s.prr.GetReviewPhotos(ctx, reviewID)
+	if err != nil {
+		log.Warn("Failed to get review photos", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	log.Info("Deleting a review")
+	if err := s.prr.DeleteReview(ctx, reviewID); err != nil {
+		log.Warn("Failed to delete review", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	// the photo rows are removed with the review, only the files are left
+	for _, photo := range photos {
+		s.removeObjects(ctx, photo.Key, photo.ThumbnailKey)
+	}
+
+	log.Info("Review deleted successfully")
+	return nil
+}
+
+func (s *ProductReviewService) getEditableReview(
+	ctx context.Context, productID int, reviewID int, userID int,
+) (entity.ProductReview, error) {
+	review, err := s.prr.GetReviewByID(ctx, reviewID)
+	if err != nil {
+		return entity.ProductReview{}, err
+	}
+	if review.ProductID != productID {
+		return entity.ProductReview{}, apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+	if err := checkAuthorCanEdit(review.UserID, userID, review.CreatedAt, review.Status); err != nil {
+		return entity.ProductReview{}, err
+	}
+	return review, nil
 }
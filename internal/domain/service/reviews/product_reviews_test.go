@@ -2,43 +2,83 @@ package reviews_test
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/reviews"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"go.uber.org/zap"
 )
 
 type mockProductReviewRepository struct {
 	products map[int]entity.Product
 	reviews  map[int][]entity.ProductReview
+	// purchases maps user ID to the products they have accepted offers for
+	purchases map[int][]int
+	banned    map[int]bool
+	// staff maps user ID to the products their shops sell
+	staff map[int][]int
+	// lastFilter is the filter of the last listing request
+	lastFilter  entity.ReviewFilter
+	photos      map[int]entity.ReviewPhoto
+	nextPhotoID int
 }
 
 func newMockProductReviewRepository() *mockProductReviewRepository {
 	return &mockProductReviewRepository{
-		products: make(map[int]entity.Product),
-		reviews:  make(map[int][]entity.ProductReview),
+		products:  make(map[int]entity.Product),
+		reviews:   make(map[int][]entity.ProductReview),
+		purchases: make(map[int][]int),
+		banned:    make(map[int]bool),
+		staff:     make(map[int][]int),
+		photos:    make(map[int]entity.ReviewPhoto),
 	}
 }
 
-func (m *mockProductReviewRepository) AddReview(
-	_ context.Context, productID int, userID int, rating int, review string,
-) error {
+func (m *mockProductReviewRepository) UpsertReview(
+	_ context.Context, productID int, userID int, rating int, review string, verified bool,
+	onCreate func(tx email.Execer, reviewID int) error,
+) (int, bool, error) {
 	if _, exists := m.products[productID]; !exists {
-		return apperror.NewReviewError(apperror.NotFound, "product not found")
+		return 0, false, apperror.NewReviewError(apperror.NotFound, "product not found")
+	}
+
+	for i, existing := range m.reviews[productID] {
+		if existing.UserID == userID {
+			m.reviews[productID][i].Rating = rating
+			m.reviews[productID][i].Review = review
+			m.reviews[productID][i].Verified = verified
+			return existing.ID, false, nil
+		}
 	}
 
 	reviewEntity := entity.ProductReview{
+		ID:        len(m.reviews[productID]) + 1,
 		ProductID: productID,
 		UserID:    userID,
 		Rating:    rating,
 		Review:    review,
+		Verified:  verified,
+		Status:    entity.ReviewStatusPublished,
+		CreatedAt: time.Now(),
+	}
+	if onCreate != nil {
+		if err := onCreate(nil, reviewEntity.ID); err != nil {
+			return 0, false, err
+		}
 	}
 	m.reviews[productID] = append(m.reviews[productID], reviewEntity)
-	return nil
+	return reviewEntity.ID, true, nil
+}
+
+func (m *mockProductReviewRepository) HasAcceptedOffer(_ context.Context, userID int, productID int) (bool, error) {
+	return slices.Contains(m.purchases[userID], productID), nil
 }
 
 func (m *mockProductReviewRepository) GetProductByID(
@@ -52,22 +92,164 @@ func (m *mockProductReviewRepository) GetProductByID(
 }
 
 func (m *mockProductReviewRepository) GetReviewsByProductID(
+	_ context.Context, productID int, filter entity.ReviewFilter,
+) ([]entity.ProductReview, int, error) {
+	m.lastFilter = filter
+	var result []entity.ProductReview
+	for _, review := range m.reviews[productID] {
+		if filter.VerifiedOnly && !review.Verified {
+			continue
+		}
+		if review.Rating < filter.MinRating || (filter.MaxRating > 0 && review.Rating > filter.MaxRating) {
+			continue
+		}
+		result = append(result, review)
+	}
+	return result, len(result), nil
+}
+
+func (m *mockProductReviewRepository) GetReviewSummary(
 	_ context.Context, productID int,
-) ([]entity.ProductReview, error) {
-	return m.reviews[productID], nil
+) (entity.ReviewSummary, error) {
+	summary := entity.ReviewSummary{Histogram: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}}
+	for _, review := range m.reviews[productID] {
+		summary.Histogram[review.Rating]++
+		summary.Total++
+	}
+	return summary, nil
+}
+
+func (m *mockProductReviewRepository) findReview(match func(entity.ProductReview) bool) (int, int, bool) {
+	for productID, productReviews := range m.reviews {
+		for i, review := range productReviews {
+			if match(review) {
+				return productID, i, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+func (m *mockProductReviewRepository) GetReviewByID(_ context.Context, reviewID int) (entity.ProductReview, error) {
+	productID, i, ok := m.findReview(func(r entity.ProductReview) bool { return r.ID == reviewID })
+	if !ok {
+		return entity.ProductReview{}, apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+	return m.reviews[productID][i], nil
+}
+
+func (m *mockProductReviewRepository) GetUserReview(
+	_ context.Context, productID int, userID int,
+) (entity.ProductReview, error) {
+	for _, review := range m.reviews[productID] {
+		if review.UserID == userID {
+			return review, nil
+		}
+	}
+	return entity.ProductReview{}, apperror.NewReviewError(apperror.NotFound, "review not found")
+}
+
+func (m *mockProductReviewRepository) UpdateReview(_ context.Context, reviewID int, rating int, review string) error {
+	productID, i, ok := m.findReview(func(r entity.ProductReview) bool { return r.ID == reviewID })
+	if !ok {
+		return apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+	m.reviews[productID][i].Rating = rating
+	m.reviews[productID][i].Review = review
+	return nil
+}
+
+func (m *mockProductReviewRepository) DeleteReview(_ context.Context, reviewID int) error {
+	productID, i, ok := m.findReview(func(r entity.ProductReview) bool { return r.ID == reviewID })
+	if !ok {
+		return apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+	m.reviews[productID] = slices.Delete(m.reviews[productID], i, i+1)
+	for id, photo := range m.photos {
+		if photo.ReviewID == reviewID {
+			delete(m.photos, id)
+		}
+	}
+	return nil
+}
+
+func (m *mockProductReviewRepository) IsReviewerBanned(_ context.Context, userID int) (bool, error) {
+	return m.banned[userID], nil
+}
+
+func (m *mockProductReviewRepository) IsShopStaffProduct(_ context.Context, productID int, userID int) (bool, error) {
+	return slices.Contains(m.staff[userID], productID), nil
+}
+
+func (m *mockProductReviewRepository) InsertReviewPhoto(
+	_ context.Context, photo entity.ReviewPhoto, maxPhotos int,
+) (entity.ReviewPhoto, error) {
+	photos, _ := m.GetReviewPhotos(context.Background(), photo.ReviewID)
+	if len(photos) >= maxPhotos {
+		return entity.ReviewPhoto{}, apperror.NewReviewError(apperror.ReviewInvalid, "too many photos")
+	}
+	m.nextPhotoID++
+	photo.ID = m.nextPhotoID
+	m.photos[photo.ID] = photo
+	return photo, nil
+}
+
+func (m *mockProductReviewRepository) GetReviewPhotoByID(_ context.Context, photoID int) (entity.ReviewPhoto, error) {
+	photo, ok := m.photos[photoID]
+	if !ok {
+		return entity.ReviewPhoto{}, apperror.NewReviewError(apperror.NotFound, "photo not found")
+	}
+	return photo, nil
+}
+
+func (m *mockProductReviewRepository) GetReviewPhotos(_ context.Context, reviewID int) ([]entity.ReviewPhoto, error) {
+	var photos []entity.ReviewPhoto
+	for _, photo := range m.photos {
+		if photo.ReviewID == reviewID {
+			photos = append(photos, photo)
+		}
+	}
+	return photos, nil
+}
+
+func (m *mockProductReviewRepository) DeleteReviewPhoto(_ context.Context, photoID int) error {
+	delete(m.photos, photoID)
+	return nil
+}
+
+// recordingBus remembers the events published to the synchronous subscribers
+// and fails the publishing with err when it is set.
+type recordingBus struct {
+	published []event.Event
+	err       error
+}
+
+func (b *recordingBus) Publish(_ context.Context, _ email.Execer, e event.Event) error {
+	b.published = append(b.published, e)
+	return b.err
 }
 
+func (b *recordingBus) PublishAsync(context.Context, event.Event) {}
+
 var _ = Describe("ProductReviewService", func() {
 	var (
 		service reviews.ProductReviewsService
 		repo    *mockProductReviewRepository
+		bus     *recordingBus
 		ctx     context.Context
 	)
 
+	expectReviewErrorCode := func(err error, code string) {
+		var reviewErr *apperror.ReviewError
+		ExpectWithOffset(1, errors.As(err, &reviewErr)).To(BeTrue())
+		ExpectWithOffset(1, reviewErr.Code()).To(Equal(code))
+	}
+
 	BeforeEach(func() {
 		ctx = context.Background()
 		repo = newMockProductReviewRepository()
-		service = reviews.NewProductReviewService(repo, zap.NewNop())
+		bus = &recordingBus{}
+		service = newTestService(repo, newTestStorage(), bus)
 	})
 
 	Context("AddReview", func() {
@@ -87,23 +269,104 @@ var _ = Describe("ProductReviewService", func() {
 			}
 
 			// Act
-			id, err := service.AddReview(ctx, productID, userID, rating, review)
+			id, created, err := service.AddReview(ctx, productID, userID, rating, review)
 
 			// Assert
 			Expect(err).NotTo(HaveOccurred())
-			Expect(id).To(Equal(productID))
-			reviews, err := repo.GetReviewsByProductID(ctx, productID)
-			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal(1))
+			Expect(created).To(BeTrue())
+			reviews := repo.reviews[productID]
 			Expect(reviews).To(HaveLen(1))
 			Expect(reviews[0].ProductID).To(Equal(productID))
 			Expect(reviews[0].UserID).To(Equal(userID))
 			Expect(reviews[0].Rating).To(Equal(rating))
 			Expect(reviews[0].Review).To(Equal(review))
+			Expect(reviews[0].Verified).To(BeFalse())
+			Expect(bus.published).To(Equal([]event.Event{event.ReviewAdded{
+				ReviewType: entity.ReviewTypeProduct, ReviewID: 1, TargetID: productID, UserID: userID, Rating: rating,
+			}}))
+		})
+
+		It("should replace the previous review of the same user", func() {
+			// Arrange
+			repo.products[1] = entity.Product{ID: 1}
+			_, _, err := service.AddReview(ctx, 1, 2, 5, "Great product!")
+			Expect(err).NotTo(HaveOccurred())
+
+			// Act
+			id, created, err := service.AddReview(ctx, 1, 2, 2, "Broke after a week")
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal(1))
+			Expect(created).To(BeFalse())
+			reviews := repo.reviews[1]
+			Expect(reviews).To(HaveLen(1))
+			Expect(reviews[0].Rating).To(Equal(2))
+			Expect(reviews[0].Review).To(Equal("Broke after a week"))
+			// replacing the review is not a new one
+			Expect(bus.published).To(HaveLen(1))
+		})
+
+		It("should not save the review when a synchronous subscriber fails", func() {
+			repo.products[1] = entity.Product{ID: 1}
+			bus.err = errors.New("outbox error")
+
+			_, _, err := service.AddReview(ctx, 1, 2, 5, "Great product!")
+
+			Expect(err).To(MatchError(bus.err))
+			Expect(repo.reviews[1]).To(BeEmpty())
+		})
+
+		It("should mark the review as verified when the user bought the product", func() {
+			// Arrange
+			repo.products[1] = entity.Product{ID: 1}
+			repo.purchases[2] = []int{1}
+
+			// Act
+			_, _, err := service.AddReview(ctx, 1, 2, 5, "Great product!")
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo.reviews[1][0].Verified).To(BeTrue())
+		})
+
+		It("should reject a reviewer banned by a moderator", func() {
+			repo.products[1] = entity.Product{ID: 1}
+			repo.banned[2] = true
+
+			_, _, err := service.AddReview(ctx, 1, 2, 5, "Great product!")
+
+			expectReviewErrorCode(err, apperror.ReviewAuthorBanned)
+			Expect(repo.reviews[1]).To(BeEmpty())
+		})
+
+		It("should reject a review from staff of a shop selling the product", func() {
+			repo.products[1] = entity.Product{ID: 1}
+			repo.staff[2] = []int{1}
+
+			_, _, err := service.AddReview(ctx, 1, 2, 5, "Best product ever!")
+
+			expectReviewErrorCode(err, apperror.ReviewUnauthorized)
+			Expect(repo.reviews[1]).To(BeEmpty())
+		})
+
+		It("should not replace a review after the edit window", func() {
+			repo.products[1] = entity.Product{ID: 1}
+			repo.reviews[1] = []entity.ProductReview{{
+				ID: 1, ProductID: 1, UserID: 2, Rating: 5, Status: entity.ReviewStatusPublished,
+				CreatedAt: time.Now().Add(-72 * time.Hour),
+			}}
+
+			_, _, err := service.AddReview(ctx, 1, 2, 1, "Changed my mind")
+
+			expectReviewErrorCode(err, apperror.ReviewEditExpired)
+			Expect(repo.reviews[1][0].Rating).To(Equal(5))
 		})
 
 		It("should return error for non-existent product", func() {
 			// Act
-			_, err := service.AddReview(ctx, 999, 1, 5, "Review")
+			_, _, err := service.AddReview(ctx, 999, 1, 5, "Review")
 
 			// Assert
 			Expect(err).To(HaveOccurred())
@@ -135,26 +398,142 @@ var _ = Describe("ProductReviewService", func() {
 			}
 
 			// Act
-			reviews, err := service.GetReviewsByProductID(ctx, productID)
+			page, err := service.GetReviewsByProductID(ctx, productID, entity.ReviewFilter{})
 
 			// Assert
 			Expect(err).NotTo(HaveOccurred())
+			reviews := page.Reviews
 			Expect(reviews).To(HaveLen(1))
 			Expect(reviews[0].ProductID).To(Equal(productID))
 			Expect(reviews[0].UserID).To(Equal(userID))
 			Expect(reviews[0].Rating).To(Equal(rating))
 			Expect(reviews[0].Review).To(Equal(review))
+			Expect(page.Total).To(Equal(1))
+			Expect(page.Summary.Histogram).To(HaveKeyWithValue(5, 1))
+		})
+
+		It("should apply the default sort and page size", func() {
+			repo.products[1] = entity.Product{ID: 1}
+
+			_, err := service.GetReviewsByProductID(ctx, 1, entity.ReviewFilter{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo.lastFilter.Sort).To(Equal(entity.ReviewSortNewest))
+			Expect(repo.lastFilter.Limit).To(Equal(20))
+		})
+
+		It("should filter by rating and keep the summary of all reviews", func() {
+			repo.products[1] = entity.Product{ID: 1}
+			repo.reviews[1] = []entity.ProductReview{
+				{ID: 1, ProductID: 1, UserID: 2, Rating: 5},
+				{ID: 2, ProductID: 1, UserID: 3, Rating: 1},
+				{ID: 3, ProductID: 1, UserID: 4, Rating: 2},
+			}
+
+			page, err := service.GetReviewsByProductID(ctx, 1, entity.ReviewFilter{MaxRating: 2})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Reviews).To(HaveLen(2))
+			Expect(page.Total).To(Equal(2))
+			Expect(page.Summary.Total).To(Equal(3))
+		})
+
+		It("should reject an unknown sort order", func() {
+			repo.products[1] = entity.Product{ID: 1}
+
+			_, err := service.GetReviewsByProductID(ctx, 1, entity.ReviewFilter{Sort: "random"})
+
+			expectReviewErrorCode(err, apperror.ReviewInvalid)
+		})
+
+		It("should reject inverted rating bounds", func() {
+			repo.products[1] = entity.Product{ID: 1}
+
+			_, err := service.GetReviewsByProductID(ctx, 1, entity.ReviewFilter{MinRating: 4, MaxRating: 2})
+
+			expectReviewErrorCode(err, apperror.ReviewInvalid)
+		})
+
+		It("should return only verified reviews when asked", func() {
+			// Arrange
+			repo.products[1] = entity.Product{ID: 1}
+			repo.reviews[1] = []entity.ProductReview{
+				{ID: 1, ProductID: 1, UserID: 2, Rating: 5, Verified: true},
+				{ID: 2, ProductID: 1, UserID: 3, Rating: 1},
+			}
+
+			// Act
+			page, err := service.GetReviewsByProductID(ctx, 1, entity.ReviewFilter{VerifiedOnly: true})
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Reviews).To(HaveLen(1))
+			Expect(page.Reviews[0].ID).To(Equal(1))
 		})
 
 		It("should return error for non-existent product", func() {
 			// Act
-			_, err := service.GetReviewsByProductID(ctx, 999)
+			_, err := service.GetReviewsByProductID(ctx, 999, entity.ReviewFilter{})
 
 			// Assert
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("product not found"))
 		})
 	})
+
+	Context("UpdateReview and DeleteReview", func() {
+		BeforeEach(func() {
+			repo.products[1] = entity.Product{ID: 1}
+			repo.reviews[1] = []entity.ProductReview{{
+				ID: 1, ProductID: 1, UserID: 2, Rating: 5, Review: "Great product!",
+				Status: entity.ReviewStatusPublished, CreatedAt: time.Now().Add(-time.Hour),
+			}}
+		})
+
+		It("should let the author update the review", func() {
+			err := service.UpdateReview(ctx, 1, 1, 2, 3, "It is fine")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo.reviews[1][0].Rating).To(Equal(3))
+			Expect(repo.reviews[1][0].Review).To(Equal("It is fine"))
+		})
+
+		It("should let the author delete the review", func() {
+			err := service.DeleteReview(ctx, 1, 1, 2)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo.reviews[1]).To(BeEmpty())
+		})
+
+		It("should reject changes from another user", func() {
+			err := service.UpdateReview(ctx, 1, 1, 3, 1, "Bad")
+
+			expectReviewErrorCode(err, apperror.ReviewUnauthorized)
+		})
+
+		It("should reject changes after the edit window", func() {
+			repo.reviews[1][0].CreatedAt = time.Now().Add(-72 * time.Hour)
+
+			err := service.DeleteReview(ctx, 1, 1, 2)
+
+			expectReviewErrorCode(err, apperror.ReviewEditExpired)
+			Expect(repo.reviews[1]).To(HaveLen(1))
+		})
+
+		It("should reject changes to a hidden review", func() {
+			repo.reviews[1][0].Status = entity.ReviewStatusHidden
+
+			err := service.UpdateReview(ctx, 1, 1, 2, 3, "It is fine")
+
+			expectReviewErrorCode(err, apperror.ReviewHidden)
+		})
+
+		It("should not find a review of another product", func() {
+			err := service.DeleteReview(ctx, 2, 1, 2)
+
+			expectReviewErrorCode(err, apperror.NotFound)
+		})
+	})
 })
 
 func TestProductReviewService(t *testing.T) {
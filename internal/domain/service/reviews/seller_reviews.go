@@ -2,41 +2,60 @@ package reviews
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
 	"go.uber.org/zap"
 )
 
+// offerStatusAccepted is the only offer status that counts as a completed deal.
+const offerStatusAccepted = "accepted"
+
 type SellerReviewRepository interface {
-	AddReview(ctx context.Context, sellerID int, userID int, rating int, review string) (int, error)
-	GetReviewsBySellerID(ctx context.Context, sellerID int) ([]entity.SellerReview, error)
-	GetSellerByID(ctx context.Context, sellerID int) (entity.SellerReview, error)
+	AddReview(
+		ctx context.Context, shopID int, userID int, offerID int, rating int, review string,
+		onInsert func(tx email.Execer, reviewID int) error,
+	) (int, error)
+	GetReviewsByShopID(ctx context.Context, shopID int, filter entity.ReviewFilter) ([]entity.SellerReview, int, error)
+	GetReviewSummary(ctx context.Context, shopID int) (entity.ReviewSummary, error)
+	ShopExists(ctx context.Context, shopID int) (bool, error)
+	GetOfferByID(ctx context.Context, offerID int) (entity.Offer, error)
+	GetReviewByID(ctx context.Context, reviewID int) (entity.SellerReview, error)
+	UpdateReview(ctx context.Context, reviewID int, rating int, review string) error
+	DeleteReview(ctx context.Context, reviewID int) error
+	IsReviewerBanned(ctx context.Context, userID int) (bool, error)
+	IsShopMember(ctx context.Context, shopID int, userID int) (bool, error)
 }
 
 // SellerReviewsService defines the interface for seller review business logic.
 type SellerReviewsService interface {
-	AddReview(ctx context.Context, sellerID int, userID int, rating int, review string) (int, error)
-	GetReviewsByID(ctx context.Context, sellerID int) ([]entity.SellerReview, error)
+	AddReview(ctx context.Context, shopID int, userID int, offerID int, rating int, review string) (int, error)
+	GetReviewsByID(ctx context.Context, shopID int, filter entity.ReviewFilter) (entity.SellerReviewPage, error)
+	UpdateReview(ctx context.Context, shopID int, reviewID int, userID int, rating int, review string) error
+	DeleteReview(ctx context.Context, shopID int, reviewID int, userID int) error
 }
 
 type SellerReviewService struct {
 	srs    SellerReviewRepository
+	events EventBus
 	logger *zap.Logger
 }
 
-func NewSellerReviewService(srr SellerReviewRepository, l *zap.Logger) SellerReviewsService {
+func NewSellerReviewService(srr SellerReviewRepository, events EventBus, l *zap.Logger) SellerReviewsService {
 	return &SellerReviewService{
 		srs:    srr,
+		events: events,
 		logger: l,
 	}
 }
 
+// AddReview adds a review about a shop. The reviewer must be the buyer of the given offer,
+// the offer must be accepted by this shop, and every deal can be reviewed only once.
 func (s *SellerReviewService) AddReview(
-	ctx context.Context, sellerID int, userID int, rating int, review string,
+	ctx context.Context, shopID int, userID int, offerID int, rating int, review string,
 ) (
 	int, error,
 ) {
@@ -44,52 +63,150 @@ func (s *SellerReviewService) AddReview(
 	log := s.logger.With(zap.String("op", op))
 
 	log.Info("Existence check")
-	_, err := s.srs.GetSellerByID(ctx, sellerID)
+	if err := s.checkShopExists(ctx, shopID); err != nil {
+		return 0, err
+	}
+
+	log.Info("Reviewer check")
+	if err := checkReviewerNotBanned(ctx, s.srs, userID); err != nil {
+		return 0, err
+	}
+	member, err := s.srs.IsShopMember(ctx, shopID, userID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return 0, apperror.NewReviewError(apperror.NotFound, "seller not found")
-		}
-		log.Warn("Seller not found", zap.Int("sellerID", sellerID), zap.Error(err))
+		log.Warn("Failed to check shop membership", zap.Error(err))
 		return 0, fmt.Errorf("op: %s, err: %w", op, err)
 	}
+	if member {
+		return 0, apperror.NewReviewError(apperror.ReviewUnauthorized, "you cannot review your own shop")
+	}
+
+	log.Info("Deal check")
+	offer, err := s.srs.GetOfferByID(ctx, offerID)
+	if err != nil {
+		return 0, err
+	}
+	if int(offer.UserID) != userID {
+		return 0, apperror.NewReviewError(apperror.ReviewUnauthorized, "only the buyer can review this deal")
+	}
+	if int(offer.ShopID) != shopID {
+		return 0, apperror.NewReviewError(apperror.ReviewDealRequired, "the offer was made to another seller")
+	}
+	if offer.Status != offerStatusAccepted {
+		return 0, apperror.NewReviewError(apperror.ReviewDealRequired, "only accepted offers can be reviewed")
+	}
 
 	log.Info("Adding a review")
-	sellerID, err = s.srs.AddReview(ctx, sellerID, userID, rating, review)
+	added := event.ReviewAdded{ReviewType: entity.ReviewTypeSeller, TargetID: shopID, UserID: userID, Rating: rating}
+	id, err := s.srs.AddReview(ctx, shopID, userID, offerID, rating, review, reviewAddedHook(ctx, s.events, added))
 	if err != nil {
 		log.Warn("Failed to add review", zap.Error(err))
 		return 0, fmt.Errorf("op: %s, err: %w", op, err)
 	}
 
+	added.ReviewID = id
+	s.events.PublishAsync(ctx, added)
+
 	log.Info("Review added successfully")
-	return sellerID, nil
+	return id, nil
 }
 
+// GetReviewsByID returns a page of published reviews of the shop
+// together with the rating summary of all its reviews.
 func (s *SellerReviewService) GetReviewsByID(
-	ctx context.Context, sellerID int,
+	ctx context.Context, shopID int, filter entity.ReviewFilter,
 ) (
-	[]entity.SellerReview, error,
+	entity.SellerReviewPage, error,
 ) {
-	const op = "sellerReviewService.GetReviewsBySellerID()"
+	const op = "sellerReviewService.GetReviewsByShopID()"
 	log := s.logger.With(zap.String("op", op))
 
-	log.Info("Existence check")
-	_, err := s.srs.GetSellerByID(ctx, sellerID)
+	filter, err := normalizeReviewFilter(filter)
 	if err != nil {
-		var reviewErr *apperror.ReviewError
-		if errors.As(err, &reviewErr) {
-			return nil, err
-		}
-		log.Error("Failed to execute query", zap.Error(err))
-		return nil, fmt.Errorf("op: %s, err: %w", op, err)
+		return entity.SellerReviewPage{}, err
+	}
+
+	log.Info("Existence check")
+	if err := s.checkShopExists(ctx, shopID); err != nil {
+		return entity.SellerReviewPage{}, err
 	}
 
 	log.Info("Getting reviews")
-	reviews, err := s.srs.GetReviewsBySellerID(ctx, sellerID)
+	reviews, total, err := s.srs.GetReviewsByShopID(ctx, shopID, filter)
 	if err != nil {
 		log.Error("Failed to fetch reviews", zap.Error(err))
-		return nil, fmt.Errorf("op: %s, err: %w", op, err)
+		return entity.SellerReviewPage{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	summary, err := s.srs.GetReviewSummary(ctx, shopID)
+	if err != nil {
+		log.Error("Failed to fetch review summary", zap.Error(err))
+		return entity.SellerReviewPage{}, fmt.Errorf("op: %s, err: %w", op, err)
 	}
 
 	log.Info("Reviews gets successfully")
-	return reviews, nil
+	return entity.SellerReviewPage{Reviews: reviews, Total: total, Summary: summary}, nil
+}
+
+// UpdateReview changes the rating and text of the review. Only the author can do it,
+// and only within the edit window.
+func (s *SellerReviewService) UpdateReview(
+	ctx context.Context, shopID int, reviewID int, userID int, rating int, review string,
+) error {
+	const op = "sellerReviewService.UpdateReview()"
+	log := s.logger.With(zap.String("op", op))
+
+	if err := s.checkEditableReview(ctx, shopID, reviewID, userID); err != nil {
+		return err
+	}
+
+	log.Info("Updating a review")
+	if err := s.srs.UpdateReview(ctx, reviewID, rating, review); err != nil {
+		log.Warn("Failed to update review", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	log.Info("Review updated successfully")
+	return nil
+}
+
+// DeleteReview removes the review. Only the author can do it, and only within the edit window.
+func (s *SellerReviewService) DeleteReview(ctx context.Context, shopID int, reviewID int, userID int) error {
+	const op = "sellerReviewService.DeleteReview()"
+	log := s.logger.With(zap.String("op", op))
+
+	if err := s.checkEditableReview(ctx, shopID, reviewID, userID); err != nil {
+		return err
+	}
+
+	log.Info("Deleting a review")
+	if err := s.srs.DeleteReview(ctx, reviewID); err != nil {
+		log.Warn("Failed to delete review", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	log.Info("Review deleted successfully")
+	return nil
+}
+
+func (s *SellerReviewService) checkEditableReview(ctx context.Context, shopID int, reviewID int, userID int) error {
+	review, err := s.srs.GetReviewByID(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if review.ShopID != shopID {
+		return apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+	return checkAuthorCanEdit(review.UserID, userID, review.CreatedAt, review.Status)
+}
+
+func (s *SellerReviewService) checkShopExists(ctx context.Context, shopID int) error {
+	exists, err := s.srs.ShopExists(ctx, shopID)
+	if err != nil {
+		s.logger.Error("Failed to check seller existence", zap.Int("shopID", shopID), zap.Error(err))
+		return err
+	}
+	if !exists {
+		return apperror.NewReviewError(apperror.NotFound, "seller not found")
+	}
+	return nil
 }
@@ -2,156 +2,332 @@ package reviews_test
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"time"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/reviews"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/zap"
 )
 
 type mockSellerReviewRepository struct {
-	sellers map[int]entity.SellerReview
+	shops   map[int]bool
+	offers  map[int]entity.Offer
 	reviews map[int][]entity.SellerReview
+	banned  map[int]bool
+	// members maps shop ID to its staff
+	members map[int][]int
 }
 
 func newMockSellerReviewRepository() *mockSellerReviewRepository {
 	return &mockSellerReviewRepository{
-		sellers: make(map[int]entity.SellerReview),
+		shops:   make(map[int]bool),
+		offers:  make(map[int]entity.Offer),
 		reviews: make(map[int][]entity.SellerReview),
+		banned:  make(map[int]bool),
+		members: make(map[int][]int),
 	}
 }
 
 func (m *mockSellerReviewRepository) AddReview(
-	_ context.Context, sellerID int, userID int, rating int, review string,
+	_ context.Context, shopID int, userID int, offerID int, rating int, review string,
+	onInsert func(tx email.Execer, reviewID int) error,
 ) (int, error) {
-	if _, exists := m.sellers[sellerID]; !exists {
-		return 0, apperror.NewReviewError(apperror.NotFound, "seller not found")
+	for _, existing := range m.reviews[shopID] {
+		if existing.OfferID != nil && *existing.OfferID == offerID {
+			return 0, apperror.NewReviewError(apperror.ReviewDuplicate, "this deal has already been reviewed")
+		}
 	}
 
 	reviewEntity := entity.SellerReview{
-		SellerID: sellerID,
-		UserID:   userID,
-		Rating:   rating,
-		Review:   review,
+		ID:        len(m.reviews[shopID]) + 1,
+		ShopID:    shopID,
+		OfferID:   &offerID,
+		UserID:    userID,
+		Rating:    rating,
+		Review:    review,
+		Status:    entity.ReviewStatusPublished,
+		CreatedAt: time.Now(),
 	}
-	m.reviews[sellerID] = append(m.reviews[sellerID], reviewEntity)
-	return sellerID, nil
+	if onInsert != nil {
+		if err := onInsert(nil, reviewEntity.ID); err != nil {
+			return 0, err
+		}
+	}
+	m.reviews[shopID] = append(m.reviews[shopID], reviewEntity)
+	return reviewEntity.ID, nil
+}
+
+func (m *mockSellerReviewRepository) ShopExists(_ context.Context, shopID int) (bool, error) {
+	return m.shops[shopID], nil
 }
 
-func (m *mockSellerReviewRepository) GetSellerByID(
-	_ context.Context, sellerID int,
-) (entity.SellerReview, error) {
-	seller, exists := m.sellers[sellerID]
+func (m *mockSellerReviewRepository) GetOfferByID(_ context.Context, offerID int) (entity.Offer, error) {
+	offer, exists := m.offers[offerID]
 	if !exists {
-		return entity.SellerReview{}, apperror.NewReviewError(apperror.NotFound, "seller not found")
+		return entity.Offer{}, apperror.NewReviewError(apperror.NotFound, "offer not found")
+	}
+	return offer, nil
+}
+
+func (m *mockSellerReviewRepository) GetReviewsByShopID(
+	_ context.Context, shopID int, _ entity.ReviewFilter,
+) ([]entity.SellerReview, int, error) {
+	return m.reviews[shopID], len(m.reviews[shopID]), nil
+}
+
+func (m *mockSellerReviewRepository) GetReviewSummary(_ context.Context, shopID int) (entity.ReviewSummary, error) {
+	summary := entity.ReviewSummary{Histogram: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}}
+	sum := 0
+	for _, review := range m.reviews[shopID] {
+		summary.Histogram[review.Rating]++
+		summary.Total++
+		sum += review.Rating
+	}
+	if summary.Total > 0 {
+		summary.Average = float64(sum) / float64(summary.Total)
+	}
+	return summary, nil
+}
+
+func (m *mockSellerReviewRepository) findReview(reviewID int) (int, int, bool) {
+	for shopID, shopReviews := range m.reviews {
+		for i, review := range shopReviews {
+			if review.ID == reviewID {
+				return shopID, i, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+func (m *mockSellerReviewRepository) GetReviewByID(_ context.Context, reviewID int) (entity.SellerReview, error) {
+	shopID, i, ok := m.findReview(reviewID)
+	if !ok {
+		return entity.SellerReview{}, apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+	return m.reviews[shopID][i], nil
+}
+
+func (m *mockSellerReviewRepository) UpdateReview(_ context.Context, reviewID int, rating int, review string) error {
+	shopID, i, ok := m.findReview(reviewID)
+	if !ok {
+		return apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+	m.reviews[shopID][i].Rating = rating
+	m.reviews[shopID][i].Review = review
+	return nil
+}
+
+func (m *mockSellerReviewRepository) DeleteReview(_ context.Context, reviewID int) error {
+	shopID, i, ok := m.findReview(reviewID)
+	if !ok {
+		return apperror.NewReviewError(apperror.NotFound, "review not found")
 	}
-	return seller, nil
+	m.reviews[shopID] = slices.Delete(m.reviews[shopID], i, i+1)
+	return nil
 }
 
-func (m *mockSellerReviewRepository) GetReviewsBySellerID(
-	_ context.Context, sellerID int,
-) ([]entity.SellerReview, error) {
-	return m.reviews[sellerID], nil
+func (m *mockSellerReviewRepository) IsReviewerBanned(_ context.Context, userID int) (bool, error) {
+	return m.banned[userID], nil
+}
+
+func (m *mockSellerReviewRepository) IsShopMember(_ context.Context, shopID int, userID int) (bool, error) {
+	return slices.Contains(m.members[shopID], userID), nil
 }
 
 var _ = Describe("SellerReviewService", func() {
 	var (
 		service reviews.SellerReviewsService
 		repo    *mockSellerReviewRepository
+		bus     *recordingBus
 		ctx     context.Context
 	)
 
+	expectReviewErrorCode := func(err error, code string) {
+		var reviewErr *apperror.ReviewError
+		ExpectWithOffset(1, errors.As(err, &reviewErr)).To(BeTrue())
+		ExpectWithOffset(1, reviewErr.Code()).To(Equal(code))
+	}
+
 	BeforeEach(func() {
 		ctx = context.Background()
 		repo = newMockSellerReviewRepository()
-		service = reviews.NewSellerReviewService(repo, zap.NewNop())
+		bus = &recordingBus{}
+		service = reviews.NewSellerReviewService(repo, bus, zap.NewNop())
+
+		repo.shops[1] = true
+		repo.shops[2] = true
+		repo.offers[10] = entity.Offer{ID: 10, ShopID: 1, UserID: 2, Status: "accepted"}
 	})
 
 	Context("AddReview", func() {
-		It("should add a new review successfully", func() {
-			// Arrange
-			sellerID := 1
-			userID := 2
-			rating := 5
-			review := "Great seller!"
-
-			// Добавляем тестового продавца
-			repo.sellers[sellerID] = entity.SellerReview{
-				SellerID: sellerID,
-				UserID:   userID,
-				Rating:   rating,
-				Review:   review,
-			}
-
+		It("should add a review for an accepted deal", func() {
 			// Act
-			id, err := service.AddReview(ctx, sellerID, userID, rating, review)
+			id, err := service.AddReview(ctx, 1, 2, 10, 5, "Great seller!")
 
 			// Assert
 			Expect(err).NotTo(HaveOccurred())
-			Expect(id).To(Equal(sellerID))
-			reviews, err := repo.GetReviewsBySellerID(ctx, sellerID)
-			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal(1))
+			reviews := repo.reviews[1]
 			Expect(reviews).To(HaveLen(1))
-			Expect(reviews[0].SellerID).To(Equal(sellerID))
-			Expect(reviews[0].UserID).To(Equal(userID))
-			Expect(reviews[0].Rating).To(Equal(rating))
-			Expect(reviews[0].Review).To(Equal(review))
+			Expect(reviews[0].ShopID).To(Equal(1))
+			Expect(*reviews[0].OfferID).To(Equal(10))
+			Expect(reviews[0].UserID).To(Equal(2))
+			Expect(reviews[0].Rating).To(Equal(5))
+			Expect(bus.published).To(Equal([]event.Event{event.ReviewAdded{
+				ReviewType: entity.ReviewTypeSeller, ReviewID: 1, TargetID: 1, UserID: 2, Rating: 5,
+			}}))
+		})
+
+		It("should allow only one review per deal", func() {
+			_, err := service.AddReview(ctx, 1, 2, 10, 5, "Great seller!")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = service.AddReview(ctx, 1, 2, 10, 1, "Changed my mind")
+
+			expectReviewErrorCode(err, apperror.ReviewDuplicate)
 		})
 
 		It("should return error for non-existent seller", func() {
-			// Act
-			_, err := service.AddReview(ctx, 999, 1, 5, "Review")
+			_, err := service.AddReview(ctx, 999, 2, 10, 5, "Review")
 
-			// Assert
-			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("seller not found"))
+			expectReviewErrorCode(err, apperror.NotFound)
+		})
+
+		It("should return error for non-existent offer", func() {
+			_, err := service.AddReview(ctx, 1, 2, 999, 5, "Review")
+
+			expectReviewErrorCode(err, apperror.NotFound)
+		})
+
+		It("should reject a review of the own shop", func() {
+			repo.members[1] = []int{2}
+
+			_, err := service.AddReview(ctx, 1, 2, 10, 5, "Best seller ever!")
+
+			expectReviewErrorCode(err, apperror.ReviewUnauthorized)
+			Expect(repo.reviews[1]).To(BeEmpty())
+		})
+
+		It("should reject a review from someone who is not the buyer", func() {
+			_, err := service.AddReview(ctx, 1, 3, 10, 5, "Review")
+
+			expectReviewErrorCode(err, apperror.ReviewUnauthorized)
+		})
+
+		It("should reject a deal made with another shop", func() {
+			_, err := service.AddReview(ctx, 2, 2, 10, 5, "Review")
+
+			expectReviewErrorCode(err, apperror.ReviewDealRequired)
+		})
+
+		It("should reject a reviewer banned by a moderator", func() {
+			repo.banned[2] = true
+
+			_, err := service.AddReview(ctx, 1, 2, 10, 5, "Great seller!")
+
+			expectReviewErrorCode(err, apperror.ReviewAuthorBanned)
+		})
+
+		It("should reject an offer that was not accepted", func() {
+			repo.offers[11] = entity.Offer{ID: 11, ShopID: 1, UserID: 2, Status: "pending"}
+
+			_, err := service.AddReview(ctx, 1, 2, 11, 5, "Review")
+
+			expectReviewErrorCode(err, apperror.ReviewDealRequired)
 		})
 	})
 
 	Context("GetReviewsByID", func() {
 		It("should return reviews for existing seller", func() {
 			// Arrange
-			sellerID := 1
-			userID := 2
-			rating := 5
-			review := "Great seller!"
-
-			repo.sellers[sellerID] = entity.SellerReview{
-				SellerID: sellerID,
-				UserID:   userID,
-				Rating:   rating,
-				Review:   review,
-			}
-			repo.reviews[sellerID] = []entity.SellerReview{
+			offerID := 10
+			repo.reviews[1] = []entity.SellerReview{
 				{
-					SellerID: sellerID,
-					UserID:   userID,
-					Rating:   rating,
-					Review:   review,
+					ShopID:  1,
+					OfferID: &offerID,
+					UserID:  2,
+					Rating:  5,
+					Review:  "Great seller!",
 				},
 			}
 
 			// Act
-			reviews, err := service.GetReviewsByID(ctx, sellerID)
+			page, err := service.GetReviewsByID(ctx, 1, entity.ReviewFilter{Sort: entity.ReviewSortHighest})
 
 			// Assert
 			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Total).To(Equal(1))
+			Expect(page.Summary.Average).To(Equal(5.0))
+			reviews := page.Reviews
 			Expect(reviews).To(HaveLen(1))
-			Expect(reviews[0].SellerID).To(Equal(sellerID))
-			Expect(reviews[0].UserID).To(Equal(userID))
-			Expect(reviews[0].Rating).To(Equal(rating))
-			Expect(reviews[0].Review).To(Equal(review))
+			Expect(reviews[0].ShopID).To(Equal(1))
+			Expect(reviews[0].UserID).To(Equal(2))
+			Expect(reviews[0].Rating).To(Equal(5))
+			Expect(reviews[0].Review).To(Equal("Great seller!"))
 		})
 
 		It("should return error for non-existent seller", func() {
 			// Act
-			_, err := service.GetReviewsByID(ctx, 999)
+			_, err := service.GetReviewsByID(ctx, 999, entity.ReviewFilter{})
 
 			// Assert
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("seller not found"))
 		})
+
+		It("should reject a page that is too large", func() {
+			_, err := service.GetReviewsByID(ctx, 1, entity.ReviewFilter{Limit: 500})
+
+			expectReviewErrorCode(err, apperror.ReviewInvalid)
+		})
+	})
+
+	Context("UpdateReview and DeleteReview", func() {
+		BeforeEach(func() {
+			_, err := service.AddReview(ctx, 1, 2, 10, 5, "Great seller!")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should let the author update the review", func() {
+			err := service.UpdateReview(ctx, 1, 1, 2, 4, "Good seller")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo.reviews[1][0].Rating).To(Equal(4))
+		})
+
+		It("should let the author delete the review", func() {
+			err := service.DeleteReview(ctx, 1, 1, 2)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo.reviews[1]).To(BeEmpty())
+		})
+
+		It("should reject changes from another user", func() {
+			err := service.DeleteReview(ctx, 1, 1, 3)
+
+			expectReviewErrorCode(err, apperror.ReviewUnauthorized)
+		})
+
+		It("should reject changes after the edit window", func() {
+			repo.reviews[1][0].CreatedAt = time.Now().Add(-72 * time.Hour)
+
+			err := service.UpdateReview(ctx, 1, 1, 2, 1, "Bad seller")
+
+			expectReviewErrorCode(err, apperror.ReviewEditExpired)
+		})
+
+		It("should not find a review of another shop", func() {
+			err := service.UpdateReview(ctx, 2, 1, 2, 1, "Bad seller")
+
+			expectReviewErrorCode(err, apperror.NotFound)
+		})
 	})
 })
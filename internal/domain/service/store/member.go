@@ -0,0 +1,203 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+const (
+	invitationTTL         = 7 * 24 * time.Hour
+	invitationTokenLength = 32
+)
+
+// GetMembers получает сотрудников магазина. Список доступен любому участнику магазина.
+func (ss *Service) GetMembers(
+	ctx context.Context,
+	shopID, userID uint,
+) ([]entity.ShopMember, error) {
+	if _, err := ss.requireRole(ctx, shopID, userID,
+		entity.ShopRoleOwner, entity.ShopRoleManager, entity.ShopRoleViewer); err != nil {
+		return nil, err
+	}
+
+	return ss.storeRepository.GetMembers(ctx, shopID)
+}
+
+// InviteMember создает приглашение в магазин и отправляет токен на указанный email.
+// Приглашать сотрудников может только владелец.
+func (ss *Service) InviteMember(
+	ctx context.Context,
+	shopID, userID uint,
+	email string,
+	role entity.ShopRole,
+) (entity.ShopInvitation, error) {
+	if err := validateStaffRole(role); err != nil {
+		return entity.ShopInvitation{}, err
+	}
+
+	store, err := ss.requireRole(ctx, shopID, userID, entity.ShopRoleOwner)
+	if err != nil {
+		return entity.ShopInvitation{}, err
+	}
+
+	token, tokenHash, err := newInvitationToken()
+	if err != nil {
+		return entity.ShopInvitation{}, apperror.New(apperror.InternalError, "failed to generate invitation token", err)
+	}
+
+	invitation := entity.ShopInvitation{
+		ShopID:    shopID,
+		Email:     strings.ToLower(strings.TrimSpace(email)),
+		Role:      role,
+		InvitedBy: userID,
+		ExpiresAt: time.Now().Add(invitationTTL),
+	}
+
+	invitation.ID, err = ss.storeRepository.InsertInvitation(ctx, invitation, tokenHash)
+	if err != nil {
+		return entity.ShopInvitation{}, err
+	}
+
+	ss.mailer.ShopInvitation(store.Name, string(role), token, invitation.Email)
+
+	return invitation, nil
+}
+
+// AcceptInvitation добавляет пользователя в магазин по токену из письма.
+// Принять приглашение может только владелец email, на который оно было отправлено.
+func (ss *Service) AcceptInvitation(
+	ctx context.Context,
+	token string,
+	userID uint,
+	userEmail string,
+) (entity.ShopInvitation, error) {
+	invitation, err := ss.storeRepository.GetInvitationByTokenHash(ctx, hashInvitationToken(token))
+	if err != nil {
+		return entity.ShopInvitation{}, err
+	}
+
+	if !strings.EqualFold(invitation.Email, userEmail) {
+		return entity.ShopInvitation{}, apperror.New(apperror.Forbidden,
+			"invitation was sent to another email", nil)
+	}
+	if invitation.AcceptedAt != nil {
+		return entity.ShopInvitation{}, apperror.New(apperror.Conflict, "invitation has already been accepted", nil)
+	}
+	if time.Now().After(invitation.ExpiresAt) {
+		return entity.ShopInvitation{}, apperror.New(apperror.BadRequest, "invitation has expired", nil)
+	}
+
+	if err := ss.storeRepository.AcceptInvitation(ctx, invitation, userID); err != nil {
+		return entity.ShopInvitation{}, err
+	}
+
+	return invitation, nil
+}
+
+// UpdateMemberRole меняет роль сотрудника. Доступно только владельцу, роль владельца не меняется.
+func (ss *Service) UpdateMemberRole(
+	ctx context.Context,
+	shopID, userID, memberID uint,
+	role entity.ShopRole,
+) error {
+	if err := validateStaffRole(role); err != nil {
+		return err
+	}
+
+	if _, err := ss.requireRole(ctx, shopID, userID, entity.ShopRoleOwner); err != nil {
+		return err
+	}
+
+	memberRole, err := ss.storeRepository.GetMemberRole(ctx, shopID, memberID)
+	if err != nil {
+		return err
+	}
+	if memberRole == entity.ShopRoleOwner {
+		return apperror.New(apperror.Conflict, "the role of the shop owner cannot be changed", nil)
+	}
+
+	return ss.storeRepository.UpdateMemberRole(ctx, shopID, memberID, role)
+}
+
+// RemoveMember удаляет сотрудника из магазина. Владелец может удалить любого сотрудника,
+// остальные участники могут только покинуть магазин сами.
+func (ss *Service) RemoveMember(
+	ctx context.Context,
+	shopID, userID, memberID uint,
+) error {
+	requiredRoles := []entity.ShopRole{entity.ShopRoleOwner}
+	if userID == memberID {
+		requiredRoles = append(requiredRoles, entity.ShopRoleManager, entity.ShopRoleViewer)
+	}
+
+	if _, err := ss.requireRole(ctx, shopID, userID, requiredRoles...); err != nil {
+		return err
+	}
+
+	memberRole, err := ss.storeRepository.GetMemberRole(ctx, shopID, memberID)
+	if err != nil {
+		return err
+	}
+	if memberRole == entity.ShopRoleOwner {
+		return apperror.New(apperror.Conflict, "the shop owner cannot be removed from the shop", nil)
+	}
+
+	return ss.storeRepository.DeleteMember(ctx, shopID, memberID)
+}
+
+// requireRole проверяет, что магазин существует и пользователь состоит в нем с одной из ролей
+func (ss *Service) requireRole(
+	ctx context.Context,
+	shopID, userID uint,
+	roles ...entity.ShopRole,
+) (entity.Store, error) {
+	store, err := ss.storeRepository.GetStoreByID(ctx, shopID)
+	if err != nil {
+		return entity.Store{}, err
+	}
+
+	role, err := ss.storeRepository.GetMemberRole(ctx, shopID, userID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrShopMemberNotFound) {
+			return entity.Store{}, apperror.New(apperror.Forbidden, "you are not a member of this shop", nil)
+		}
+		return entity.Store{}, err
+	}
+
+	if !slices.Contains(roles, role) {
+		return entity.Store{}, apperror.New(apperror.Forbidden, "your shop role does not allow this action", nil)
+	}
+
+	return store, nil
+}
+
+func validateStaffRole(role entity.ShopRole) error {
+	if role != entity.ShopRoleManager && role != entity.ShopRoleViewer {
+		return apperror.New(apperror.BadRequest, "role must be manager or viewer", nil)
+	}
+	return nil
+}
+
+// newInvitationToken генерирует токен приглашения. В базе хранится только его хэш.
+func newInvitationToken() (string, string, error) {
+	b := make([]byte, invitationTokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(b)
+	return token, hashInvitationToken(token), nil
+}
+
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
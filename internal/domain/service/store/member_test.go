@@ -0,0 +1,209 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/email/mock_email"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("StoreService members", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockRepo   *MockRepository
+		mockMailer *mock_email.MockMailerService
+		service    *Service
+		ctx        context.Context
+		shop       entity.Store
+	)
+
+	expectCode := func(err error, code string) {
+		var appErr apperror.AppError
+		ExpectWithOffset(1, errors.As(err, &appErr)).To(BeTrue())
+		ExpectWithOffset(1, appErr.Code()).To(Equal(code))
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockMailer = mock_email.NewMockMailerService(ctrl)
+		service = NewService(mockRepo, mockMailer)
+		ctx = context.Background()
+		shop = entity.Store{ID: 3, UserID: 1, Name: "shop"}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("GetMembers", func() {
+		It("should list members to a viewer", func() {
+			members := []entity.ShopMember{{ShopID: 3, UserID: 1, Role: entity.ShopRoleOwner}}
+			mockRepo.EXPECT().GetStoreByID(ctx, uint(3)).Return(shop, nil)
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(3), uint(5)).Return(entity.ShopRoleViewer, nil)
+			mockRepo.EXPECT().GetMembers(ctx, uint(3)).Return(members, nil)
+
+			result, err := service.GetMembers(ctx, 3, 5)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(members))
+		})
+
+		It("should forbid outsiders", func() {
+			mockRepo.EXPECT().GetStoreByID(ctx, uint(3)).Return(shop, nil)
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(3), uint(5)).
+				Return(entity.ShopRole(""), apperror.ErrShopMemberNotFound)
+
+			_, err := service.GetMembers(ctx, 3, 5)
+
+			expectCode(err, apperror.Forbidden)
+		})
+	})
+
+	Describe("InviteMember", func() {
+		It("should store a hashed token and email the plain one", func() {
+			var storedHash, sentToken string
+			mockRepo.EXPECT().GetStoreByID(ctx, uint(3)).Return(shop, nil)
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(3), uint(1)).Return(entity.ShopRoleOwner, nil)
+			mockRepo.EXPECT().InsertInvitation(ctx, gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, inv entity.ShopInvitation, hash string) (uint, error) {
+					Expect(inv.Email).To(Equal("staff@example.com"))
+					Expect(inv.Role).To(Equal(entity.ShopRoleManager))
+					Expect(inv.InvitedBy).To(Equal(uint(1)))
+					Expect(inv.ExpiresAt).To(BeTemporally("~", time.Now().Add(invitationTTL), time.Minute))
+					storedHash = hash
+					return 7, nil
+				})
+			mockMailer.EXPECT().ShopInvitation("shop", "manager", gomock.Any(), "staff@example.com").
+				Do(func(_, _, token, _ string) { sentToken = token })
+
+			invitation, err := service.InviteMember(ctx, 3, 1, " Staff@Example.com ", entity.ShopRoleManager)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(invitation.ID).To(Equal(uint(7)))
+			Expect(sentToken).NotTo(BeEmpty())
+			Expect(storedHash).To(Equal(hashInvitationToken(sentToken)))
+			Expect(storedHash).NotTo(Equal(sentToken))
+		})
+
+		It("should forbid managers to invite", func() {
+			mockRepo.EXPECT().GetStoreByID(ctx, uint(3)).Return(shop, nil)
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(3), uint(2)).Return(entity.ShopRoleManager, nil)
+
+			_, err := service.InviteMember(ctx, 3, 2, "staff@example.com", entity.ShopRoleViewer)
+
+			expectCode(err, apperror.Forbidden)
+		})
+
+		It("should not invite new owners", func() {
+			_, err := service.InviteMember(ctx, 3, 1, "staff@example.com", entity.ShopRoleOwner)
+
+			expectCode(err, apperror.BadRequest)
+		})
+	})
+
+	Describe("AcceptInvitation", func() {
+		var invitation entity.ShopInvitation
+
+		BeforeEach(func() {
+			invitation = entity.ShopInvitation{
+				ID:        7,
+				ShopID:    3,
+				Email:     "staff@example.com",
+				Role:      entity.ShopRoleViewer,
+				ExpiresAt: time.Now().Add(time.Hour),
+			}
+		})
+
+		It("should add the user to the shop", func() {
+			mockRepo.EXPECT().GetInvitationByTokenHash(ctx, hashInvitationToken("token")).Return(invitation, nil)
+			mockRepo.EXPECT().AcceptInvitation(ctx, invitation, uint(5)).Return(nil)
+
+			result, err := service.AcceptInvitation(ctx, "token", 5, "STAFF@example.com")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.ShopID).To(Equal(uint(3)))
+		})
+
+		It("should reject an invitation sent to another email", func() {
+			mockRepo.EXPECT().GetInvitationByTokenHash(ctx, gomock.Any()).Return(invitation, nil)
+
+			_, err := service.AcceptInvitation(ctx, "token", 5, "other@example.com")
+
+			expectCode(err, apperror.Forbidden)
+		})
+
+		It("should reject an expired invitation", func() {
+			invitation.ExpiresAt = time.Now().Add(-time.Minute)
+			mockRepo.EXPECT().GetInvitationByTokenHash(ctx, gomock.Any()).Return(invitation, nil)
+
+			_, err := service.AcceptInvitation(ctx, "token", 5, "staff@example.com")
+
+			expectCode(err, apperror.BadRequest)
+		})
+
+		It("should reject an already accepted invitation", func() {
+			acceptedAt := time.Now()
+			invitation.AcceptedAt = &acceptedAt
+			mockRepo.EXPECT().GetInvitationByTokenHash(ctx, gomock.Any()).Return(invitation, nil)
+
+			_, err := service.AcceptInvitation(ctx, "token", 5, "staff@example.com")
+
+			expectCode(err, apperror.Conflict)
+		})
+	})
+
+	Describe("UpdateMemberRole", func() {
+		It("should change the role of a manager", func() {
+			mockRepo.EXPECT().GetStoreByID(ctx, uint(3)).Return(shop, nil)
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(3), uint(1)).Return(entity.ShopRoleOwner, nil)
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(3), uint(5)).Return(entity.ShopRoleManager, nil)
+			mockRepo.EXPECT().UpdateMemberRole(ctx, uint(3), uint(5), entity.ShopRoleViewer).Return(nil)
+
+			Expect(service.UpdateMemberRole(ctx, 3, 1, 5, entity.ShopRoleViewer)).To(Succeed())
+		})
+
+		It("should not demote the owner", func() {
+			mockRepo.EXPECT().GetStoreByID(ctx, uint(3)).Return(shop, nil)
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(3), uint(1)).Return(entity.ShopRoleOwner, nil).Times(2)
+
+			err := service.UpdateMemberRole(ctx, 3, 1, 1, entity.ShopRoleViewer)
+
+			expectCode(err, apperror.Conflict)
+		})
+	})
+
+	Describe("RemoveMember", func() {
+		It("should let a member leave the shop", func() {
+			mockRepo.EXPECT().GetStoreByID(ctx, uint(3)).Return(shop, nil)
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(3), uint(5)).Return(entity.ShopRoleViewer, nil).Times(2)
+			mockRepo.EXPECT().DeleteMember(ctx, uint(3), uint(5)).Return(nil)
+
+			Expect(service.RemoveMember(ctx, 3, 5, 5)).To(Succeed())
+		})
+
+		It("should forbid managers to remove other members", func() {
+			mockRepo.EXPECT().GetStoreByID(ctx, uint(3)).Return(shop, nil)
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(3), uint(5)).Return(entity.ShopRoleManager, nil)
+
+			err := service.RemoveMember(ctx, 3, 5, 6)
+
+			expectCode(err, apperror.Forbidden)
+		})
+
+		It("should not remove the owner", func() {
+			mockRepo.EXPECT().GetStoreByID(ctx, uint(3)).Return(shop, nil)
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(3), uint(1)).Return(entity.ShopRoleOwner, nil).Times(2)
+
+			err := service.RemoveMember(ctx, 3, 1, 1)
+
+			expectCode(err, apperror.Conflict)
+		})
+	})
+})
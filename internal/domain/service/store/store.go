@@ -1 +1,113 @@
 package store
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=store_mock_test.go -package=store Repository
+
+type Repository interface {
+	GetStoreByID(ctx context.Context, id uint) (entity.Store, error)
+	GetStores(ctx context.Context, filter model.StoreFilter, limit, offset int) ([]entity.Store, int, error)
+	InsertStore(ctx context.Context, store entity.Store) (uint, error)
+	UpdateStore(ctx context.Context, store entity.Store) error
+
+	GetMemberRole(ctx context.Context, shopID, userID uint) (entity.ShopRole, error)
+	GetMembers(ctx context.Context, shopID uint) ([]entity.ShopMember, error)
+	UpdateMemberRole(ctx context.Context, shopID, userID uint, role entity.ShopRole) error
+	DeleteMember(ctx context.Context, shopID, userID uint) error
+	InsertInvitation(ctx context.Context, invitation entity.ShopInvitation, tokenHash string) (uint, error)
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (entity.ShopInvitation, error)
+	AcceptInvitation(ctx context.Context, invitation entity.ShopInvitation, userID uint) error
+}
+
+const hoursClosed = "closed"
+
+var (
+	weekDays     = map[string]struct{}{"mon": {}, "tue": {}, "wed": {}, "thu": {}, "fri": {}, "sat": {}, "sun": {}}
+	hoursPattern = regexp.MustCompile(`^([01]\d|2[0-3]):[0-5]\d-(([01]\d|2[0-3]):[0-5]\d|24:00)$`)
+)
+
+type Service struct {
+	storeRepository Repository
+	mailer          email.MailerService
+}
+
+func NewService(storeRepo Repository, mailer email.MailerService) *Service {
+	return &Service{
+		storeRepository: storeRepo,
+		mailer:          mailer,
+	}
+}
+
+// CreateStore создает магазин, владельцем становится store.UserID
+func (ss *Service) CreateStore(
+	ctx context.Context,
+	store entity.Store,
+) (uint, error) {
+	if err := validateWorkingHours(store.WorkingHours); err != nil {
+		return 0, err
+	}
+
+	return ss.storeRepository.InsertStore(ctx, store)
+}
+
+// UpdateStore обновляет профиль магазина. Менять профиль может только владелец.
+func (ss *Service) UpdateStore(
+	ctx context.Context,
+	store entity.Store,
+	userID uint,
+) error {
+	if _, err := ss.requireRole(ctx, store.ID, userID, entity.ShopRoleOwner); err != nil {
+		return err
+	}
+
+	if err := validateWorkingHours(store.WorkingHours); err != nil {
+		return err
+	}
+
+	return ss.storeRepository.UpdateStore(ctx, store)
+}
+
+// GetStore получает публичный профиль магазина с количеством товаров и рейтингом продавца
+func (ss *Service) GetStore(
+	ctx context.Context,
+	id uint,
+) (entity.Store, error) {
+	return ss.storeRepository.GetStoreByID(ctx, id)
+}
+
+// GetStores получает список магазинов с поиском по названию и описанию
+func (ss *Service) GetStores(
+	ctx context.Context,
+	filter model.StoreFilter,
+	limit, offset int,
+) ([]entity.Store, int, error) {
+	return ss.storeRepository.GetStores(ctx, filter, limit, offset)
+}
+
+// validateWorkingHours проверяет часы работы вида {"mon": "09:00-18:00", "sun": "closed"}
+func validateWorkingHours(hours map[string]string) error {
+	for day, value := range hours {
+		if _, ok := weekDays[day]; !ok {
+			return apperror.New(apperror.BadRequest,
+				fmt.Sprintf("unknown day %q in working hours", day), nil)
+		}
+		if value == hoursClosed {
+			continue
+		}
+		if !hoursPattern.MatchString(value) || value[:5] >= value[6:] {
+			return apperror.New(apperror.BadRequest,
+				fmt.Sprintf("working hours for %s must look like 09:00-18:00 or %q", day, hoursClosed), nil)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: store.go
+//
+// Generated by this command:
+//
+//	mockgen -source=store.go -destination=store_mock_test.go -package=store Repository
+//
+
+// Package store is a generated GoMock package.
+package store
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	model "github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AcceptInvitation mocks base method.
+func (m *MockRepository) AcceptInvitation(ctx context.Context, invitation entity.ShopInvitation, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptInvitation", ctx, invitation, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptInvitation indicates an expected call of AcceptInvitation.
+func (mr *MockRepositoryMockRecorder) AcceptInvitation(ctx, invitation, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptInvitation", reflect.TypeOf((*MockRepository)(nil).AcceptInvitation), ctx, invitation, userID)
+}
+
+// DeleteMember mocks base method.
+func (m *MockRepository) DeleteMember(ctx context.Context, shopID, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMember", ctx, shopID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMember indicates an expected call of DeleteMember.
+func (mr *MockRepositoryMockRecorder) DeleteMember(ctx, shopID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMember", reflect.TypeOf((*MockRepository)(nil).DeleteMember), ctx, shopID, userID)
+}
+
+// GetInvitationByTokenHash mocks base method.
+func (m *MockRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (entity.ShopInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvitationByTokenHash", ctx, tokenHash)
+	ret0, _ := ret[0].(entity.ShopInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvitationByTokenHash indicates an expected call of GetInvitationByTokenHash.
+func (mr *MockRepositoryMockRecorder) GetInvitationByTokenHash(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvitationByTokenHash", reflect.TypeOf((*MockRepository)(nil).GetInvitationByTokenHash), ctx, tokenHash)
+}
+
+// GetMemberRole mocks base method.
+func (m *MockRepository) GetMemberRole(ctx context.Context, shopID, userID uint) (entity.ShopRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberRole", ctx, shopID, userID)
+	ret0, _ := ret[0].(entity.ShopRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberRole indicates an expected call of GetMemberRole.
+func (mr *MockRepositoryMockRecorder) GetMemberRole(ctx, shopID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberRole", reflect.TypeOf((*MockRepository)(nil).GetMemberRole), ctx, shopID, userID)
+}
+
+// GetMembers mocks base method.
+func (m *MockRepository) GetMembers(ctx context.Context, shopID uint) ([]entity.ShopMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembers", ctx, shopID)
+	ret0, _ := ret[0].([]entity.ShopMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembers indicates an expected call of GetMembers.
+func (mr *MockRepositoryMockRecorder) GetMembers(ctx, shopID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembers", reflect.TypeOf((*MockRepository)(nil).GetMembers), ctx, shopID)
+}
+
+// GetStoreByID mocks base method.
+func (m *MockRepository) GetStoreByID(ctx context.Context, id uint) (entity.Store, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStoreByID", ctx, id)
+	ret0, _ := ret[0].(entity.Store)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStoreByID indicates an expected call of GetStoreByID.
+func (mr *MockRepositoryMockRecorder) GetStoreByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStoreByID", reflect.TypeOf((*MockRepository)(nil).GetStoreByID), ctx, id)
+}
+
+// GetStores mocks base method.
+func (m *MockRepository) GetStores(ctx context.Context, filter model.StoreFilter, limit, offset int) ([]entity.Store, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStores", ctx, filter, limit, offset)
+	ret0, _ := ret[0].([]entity.Store)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetStores indicates an expected call of GetStores.
+func (mr *MockRepositoryMockRecorder) GetStores(ctx, filter, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStores", reflect.TypeOf((*MockRepository)(nil).GetStores), ctx, filter, limit, offset)
+}
+
+// InsertInvitation mocks base method.
+func (m *MockRepository) InsertInvitation(ctx context.Context, invitation entity.ShopInvitation, tokenHash string) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertInvitation", ctx, invitation, tokenHash)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertInvitation indicates an expected call of InsertInvitation.
+func (mr *MockRepositoryMockRecorder) InsertInvitation(ctx, invitation, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertInvitation", reflect.TypeOf((*MockRepository)(nil).InsertInvitation), ctx, invitation, tokenHash)
+}
+
+// InsertStore mocks base method.
+func (m *MockRepository) InsertStore(ctx context.Context, store entity.Store) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertStore", ctx, store)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertStore indicates an expected call of InsertStore.
+func (mr *MockRepositoryMockRecorder) InsertStore(ctx, store any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertStore", reflect.TypeOf((*MockRepository)(nil).InsertStore), ctx, store)
+}
+
+// UpdateMemberRole mocks base method.
+func (m *MockRepository) UpdateMemberRole(ctx context.Context, shopID, userID uint, role entity.ShopRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMemberRole", ctx, shopID, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMemberRole indicates an expected call of UpdateMemberRole.
+func (mr *MockRepositoryMockRecorder) UpdateMemberRole(ctx, shopID, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRole", reflect.TypeOf((*MockRepository)(nil).UpdateMemberRole), ctx, shopID, userID, role)
+}
+
+// UpdateStore mocks base method.
+func (m *MockRepository) UpdateStore(ctx context.Context, store entity.Store) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStore", ctx, store)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStore indicates an expected call of UpdateStore.
+func (mr *MockRepositoryMockRecorder) UpdateStore(ctx, store any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStore", reflect.TypeOf((*MockRepository)(nil).UpdateStore), ctx, store)
+}
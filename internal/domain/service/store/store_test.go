@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/email/mock_email"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("StoreService", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockRepo   *MockRepository
+		mockMailer *mock_email.MockMailerService
+		service    *Service
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockMailer = mock_email.NewMockMailerService(ctrl)
+		service = NewService(mockRepo, mockMailer)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("CreateStore", func() {
+		It("should create a shop with valid working hours", func() {
+			store := entity.Store{
+				UserID:       1,
+				Name:         "shop",
+				WorkingHours: map[string]string{"mon": "09:00-18:00", "sun": "closed"},
+			}
+			mockRepo.EXPECT().InsertStore(ctx, store).Return(uint(3), nil)
+
+			id, err := service.CreateStore(ctx, store)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal(uint(3)))
+		})
+
+		It("should accept closing at midnight", func() {
+			store := entity.Store{UserID: 1, Name: "shop", WorkingHours: map[string]string{"fri": "10:00-24:00"}}
+			mockRepo.EXPECT().InsertStore(ctx, store).Return(uint(4), nil)
+
+			_, err := service.CreateStore(ctx, store)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		DescribeTable("should reject invalid working hours",
+			func(hours map[string]string) {
+				_, err := service.CreateStore(ctx, entity.Store{UserID: 1, Name: "shop", WorkingHours: hours})
+
+				var appErr apperror.AppError
+				Expect(errors.As(err, &appErr)).To(BeTrue())
+				Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+			},
+			Entry("unknown day", map[string]string{"monday": "09:00-18:00"}),
+			Entry("bad format", map[string]string{"mon": "9-18"}),
+			Entry("closing before opening", map[string]string{"mon": "18:00-09:00"}),
+			Entry("closing after midnight", map[string]string{"mon": "09:00-24:59"}),
+		)
+	})
+
+	Describe("UpdateStore", func() {
+		It("should update the shop of its owner", func() {
+			store := entity.Store{ID: 3, Name: "new name"}
+			mockRepo.EXPECT().GetStoreByID(ctx, uint(3)).Return(entity.Store{ID: 3, UserID: 1}, nil)
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(3), uint(1)).Return(entity.ShopRoleOwner, nil)
+			mockRepo.EXPECT().UpdateStore(ctx, store).Return(nil)
+
+			Expect(service.UpdateStore(ctx, store, 1)).To(Succeed())
+		})
+
+		It("should forbid editing someone else's shop", func() {
+			mockRepo.EXPECT().GetStoreByID(ctx, uint(3)).Return(entity.Store{ID: 3, UserID: 2}, nil)
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(3), uint(1)).
+				Return(entity.ShopRole(""), apperror.ErrShopMemberNotFound)
+
+			err := service.UpdateStore(ctx, entity.Store{ID: 3, Name: "new name"}, 1)
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.Forbidden))
+		})
+
+		It("should forbid managers to edit the shop profile", func() {
+			mockRepo.EXPECT().GetStoreByID(ctx, uint(3)).Return(entity.Store{ID: 3, UserID: 2}, nil)
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(3), uint(1)).Return(entity.ShopRoleManager, nil)
+
+			err := service.UpdateStore(ctx, entity.Store{ID: 3, Name: "new name"}, 1)
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.Forbidden))
+		})
+
+		It("should return not found for a missing shop", func() {
+			mockRepo.EXPECT().GetStoreByID(ctx, uint(3)).Return(entity.Store{}, apperror.ErrStoreNotFound)
+
+			err := service.UpdateStore(ctx, entity.Store{ID: 3}, 1)
+
+			Expect(err).To(MatchError(apperror.ErrStoreNotFound))
+		})
+	})
+})
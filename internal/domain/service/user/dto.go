@@ -1,11 +1,19 @@
 package user
 
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
 type User struct {
 	Name     string
 	Password string
 	Email    string
 	Phone    string
 	IsStore  bool
+	Role     entity.UserRole
+	Locale   string
 }
 
 type UpdateUser struct {
@@ -13,3 +21,15 @@ type UpdateUser struct {
 	Password string
 	Email    string
 }
+
+// UpdateProfile изменяемые поля профиля, nil поле не меняется
+type UpdateProfile struct {
+	Name  *string
+	Phone *string
+}
+
+// VerificationToken хэш токена подтверждения почты и срок его действия
+type VerificationToken struct {
+	TokenHash string
+	ExpiresAt time.Time
+}
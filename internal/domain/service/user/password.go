@@ -0,0 +1,92 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+)
+
+const (
+	tokenLength = 32
+
+	// письма с токенами уходят пользователю не чаще раза в минуту и не больше пяти раз в час
+	tokenMailInterval  = time.Minute
+	tokenMailWindow    = time.Hour
+	tokenMailPerWindow = 5
+)
+
+// ForgotPassword отправляет на почту одноразовый токен сброса пароля.
+// Для неизвестной почты и сверх лимита писем ничего не отправляется, но и ошибки нет,
+// чтобы по ответу нельзя было узнать, зарегистрирован ли адрес
+func (us *Service) ForgotPassword(ctx context.Context, userMail string) error {
+	user, err := us.userRepository.GetUser(ctx, userMail)
+	if err != nil {
+		if errors.Is(err, apperror.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	sent, lastAt, err := us.userRepository.CountPasswordResetTokens(ctx, user.ID, time.Now().Add(-tokenMailWindow))
+	if err != nil {
+		return err
+	}
+	if tokenMailLimited(sent, lastAt) {
+		return nil
+	}
+
+	token, tokenHash, err := newToken()
+	if err != nil {
+		return apperror.New(apperror.InternalError, "failed to generate password reset token", err)
+	}
+
+	expiresAt := time.Now().Add(us.passwordResetTTL)
+	if err := us.userRepository.InsertPasswordResetToken(ctx, user.ID, tokenHash, expiresAt); err != nil {
+		return err
+	}
+
+	us.mailer.PasswordReset(user.Name, token, us.passwordResetTTL, user.Email, user.Locale)
+
+	return nil
+}
+
+// ResetPassword задает новый пароль по токену из письма и отзывает все refresh токены,
+// чтобы сбросить сессии, открытые со старым паролем
+func (us *Service) ResetPassword(ctx context.Context, token, password string) error {
+	hash, err := us.passwordManager.Hash(password)
+	if err != nil {
+		return apperror.New(apperror.InternalError, "failed to generate password", err)
+	}
+
+	userID, err := us.userRepository.ResetPassword(ctx, hashToken(token), hash)
+	if err != nil {
+		return err
+	}
+
+	return us.tokenService.RevokeActivesByUserID(ctx, userID)
+}
+
+// tokenMailLimited проверяет, исчерпан ли лимит писем с токенами за последний час
+func tokenMailLimited(sent int, lastAt *time.Time) bool {
+	return sent >= tokenMailPerWindow || (lastAt != nil && time.Since(*lastAt) < tokenMailInterval)
+}
+
+// newToken генерирует одноразовый токен для ссылки из письма. В базе хранится только его хэш
+func newToken() (string, string, error) {
+	b := make([]byte, tokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,110 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/email/mock_email"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Password reset", func() {
+	var (
+		ctrl                *gomock.Controller
+		mockRepo            *MockRepository
+		mockTokenService    *MockTokenService
+		mockPasswordManager *MockPasswordManager
+		mockMailer          *mock_email.MockMailerService
+		userService         *Service
+		ctx                 context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockTokenService = NewMockTokenService(ctrl)
+		mockPasswordManager = NewMockPasswordManager(ctrl)
+		mockMailer = mock_email.NewMockMailerService(ctrl)
+		userService = NewService(mockRepo, mockTokenService, mockPasswordManager, mockMailer,
+			NewMockEventBus(ctrl), time.Hour, "https://example.com/api/v1/auth/email/verify")
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("ForgotPassword", func() {
+		It("should store the token hash and mail the token", func() {
+			user := entity.User{ID: 5, Name: "Anna", Email: "anna@example.com", Locale: "ru"}
+			mockRepo.EXPECT().GetUser(ctx, "anna@example.com").Return(user, nil)
+			mockRepo.EXPECT().CountPasswordResetTokens(ctx, uint(5), gomock.Any()).Return(0, nil, nil)
+
+			var storedHash, sentToken string
+			mockRepo.EXPECT().InsertPasswordResetToken(ctx, uint(5), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ uint, tokenHash string, expiresAt time.Time) error {
+					storedHash = tokenHash
+					Expect(expiresAt).To(BeTemporally("~", time.Now().Add(time.Hour), time.Second))
+					return nil
+				})
+			mockMailer.EXPECT().PasswordReset("Anna", gomock.Any(), time.Hour, "anna@example.com", "ru").
+				Do(func(_, token string, _ time.Duration, _, _ string) { sentToken = token })
+
+			Expect(userService.ForgotPassword(ctx, "anna@example.com")).To(Succeed())
+
+			Expect(sentToken).To(HaveLen(2 * tokenLength))
+			Expect(storedHash).To(Equal(hashToken(sentToken)))
+			Expect(storedHash).NotTo(Equal(sentToken))
+		})
+
+		It("should not reveal that the email is unknown", func() {
+			mockRepo.EXPECT().GetUser(ctx, "ghost@example.com").Return(entity.User{}, apperror.ErrUserNotFound)
+
+			Expect(userService.ForgotPassword(ctx, "ghost@example.com")).To(Succeed())
+		})
+
+		DescribeTable("should silently skip the email over the limit",
+			func(sent int, lastAgo time.Duration) {
+				lastAt := time.Now().Add(-lastAgo)
+				mockRepo.EXPECT().GetUser(ctx, "anna@example.com").Return(entity.User{ID: 5}, nil)
+				mockRepo.EXPECT().CountPasswordResetTokens(ctx, uint(5), gomock.Any()).Return(sent, &lastAt, nil)
+
+				Expect(userService.ForgotPassword(ctx, "anna@example.com")).To(Succeed())
+			},
+			Entry("sent less than a minute ago", 1, 10*time.Second),
+			Entry("five emails in the last hour", 5, 10*time.Minute),
+		)
+
+		It("should return database errors", func() {
+			mockRepo.EXPECT().GetUser(ctx, "anna@example.com").
+				Return(entity.User{}, apperror.New(apperror.DatabaseError, "db error", nil))
+
+			Expect(userService.ForgotPassword(ctx, "anna@example.com")).NotTo(Succeed())
+		})
+	})
+
+	Describe("ResetPassword", func() {
+		It("should set the new hash and revoke all refresh tokens", func() {
+			mockPasswordManager.EXPECT().Hash("new-password").Return("new-hash", nil)
+			mockRepo.EXPECT().ResetPassword(ctx, hashToken("token"), "new-hash").Return(uint(5), nil)
+			mockTokenService.EXPECT().RevokeActivesByUserID(ctx, uint(5)).Return(nil)
+
+			Expect(userService.ResetPassword(ctx, "token", "new-password")).To(Succeed())
+		})
+
+		It("should not revoke sessions when the token is invalid", func() {
+			mockPasswordManager.EXPECT().Hash("new-password").Return("new-hash", nil)
+			mockRepo.EXPECT().ResetPassword(ctx, hashToken("token"), "new-hash").
+				Return(uint(0), apperror.ErrInvalidPasswordResetToken)
+
+			err := userService.ResetPassword(ctx, "token", "new-password")
+
+			Expect(errors.Is(err, apperror.ErrInvalidPasswordResetToken)).To(BeTrue())
+		})
+	})
+})
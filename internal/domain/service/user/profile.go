@@ -0,0 +1,107 @@
+package user
+
+import (
+	"context"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+)
+
+// UpdateProfile меняет имя и телефон пользователя
+func (us *Service) UpdateProfile(ctx context.Context, userID uint, update UpdateProfile) (entity.User, error) {
+	return us.userRepository.UpdateProfile(ctx, userID, update)
+}
+
+// ChangeEmail меняет почту после проверки пароля. Новая почта считается неподтвержденной,
+// пока пользователь не перейдет по ссылке, отправленной на нее. Прежний адрес получает
+// уведомление о смене, чтобы владелец заметил захват аккаунта
+func (us *Service) ChangeEmail(ctx context.Context, userID uint, newEmail, password string) (entity.User, error) {
+	user, err := us.checkPassword(ctx, userID, password)
+	if err != nil {
+		return entity.User{}, err
+	}
+	if user.Email == newEmail {
+		return entity.User{}, apperror.ErrEmailUnchanged
+	}
+	oldEmail := user.Email
+
+	token, verification, err := us.newVerificationToken()
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	user, err = us.userRepository.ChangeEmail(ctx, userID, newEmail, verification)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	us.sendVerification(user.Name, user.Email, user.Locale, token)
+	us.mailer.EmailChanged(user.Name, user.Email, oldEmail, user.Locale)
+
+	return user, nil
+}
+
+// ChangePassword меняет пароль после проверки старого и отзывает все refresh токены
+func (us *Service) ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string) error {
+	if _, err := us.checkPassword(ctx, userID, oldPassword); err != nil {
+		return err
+	}
+
+	hash, err := us.passwordManager.Hash(newPassword)
+	if err != nil {
+		return apperror.New(apperror.InternalError, "failed to generate password", err)
+	}
+
+	if err := us.userRepository.UpdatePassword(ctx, userID, hash); err != nil {
+		return err
+	}
+
+	return us.tokenService.RevokeActivesByUserID(ctx, userID)
+}
+
+// DeleteAccount удаляет аккаунт после проверки пароля. Магазины узнают об отмене
+// ожидающих офферов удаленного пользователя так же, как об отмене покупателем.
+// Синхронные подписчики выполняются в транзакции удаления, их ошибка отменяет его
+func (us *Service) DeleteAccount(ctx context.Context, userID uint, password string) error {
+	if _, err := us.checkPassword(ctx, userID, password); err != nil {
+		return err
+	}
+
+	cancelled, err := us.userRepository.DeleteUser(ctx, userID, func(tx email.Execer, offers []entity.Offer) error {
+		for _, offer := range offers {
+			if err := us.events.Publish(ctx, tx, event.OfferStatusChanged{Offer: offer}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, offer := range cancelled {
+		us.events.PublishAsync(ctx, event.OfferStatusChanged{Offer: offer})
+	}
+
+	return nil
+}
+
+// checkPassword подтверждает действие с аккаунтом текущим паролем
+func (us *Service) checkPassword(ctx context.Context, userID uint, password string) (entity.User, error) {
+	user, err := us.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	compared, err := us.passwordManager.Compare(password, user.Password)
+	if err != nil {
+		return entity.User{}, err
+	}
+	if !compared {
+		return entity.User{}, apperror.ErrIncorrectPassword
+	}
+
+	return user, nil
+}
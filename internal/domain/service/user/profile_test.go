@@ -0,0 +1,169 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+	"github.com/EM-Stawberry/Stawberry/pkg/email/mock_email"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Account management", func() {
+	var (
+		ctrl                *gomock.Controller
+		mockRepo            *MockRepository
+		mockTokenService    *MockTokenService
+		mockPasswordManager *MockPasswordManager
+		mockEventBus        *MockEventBus
+		mockMailer          *mock_email.MockMailerService
+		userService         *Service
+		ctx                 context.Context
+		account             entity.User
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockTokenService = NewMockTokenService(ctrl)
+		mockPasswordManager = NewMockPasswordManager(ctrl)
+		mockEventBus = NewMockEventBus(ctrl)
+		mockMailer = mock_email.NewMockMailerService(ctrl)
+		userService = NewService(mockRepo, mockTokenService, mockPasswordManager, mockMailer, mockEventBus,
+			time.Hour, "https://example.com/api/v1/auth/email/verify")
+		ctx = context.Background()
+		account = entity.User{ID: 5, Name: "Anna", Email: "anna@example.com", Password: "hash", Locale: "ru"}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("UpdateProfile", func() {
+		It("should pass only the changed fields to the repository", func() {
+			name := "Anne"
+			update := UpdateProfile{Name: &name}
+			mockRepo.EXPECT().UpdateProfile(ctx, uint(5), update).Return(entity.User{ID: 5, Name: name}, nil)
+
+			user, err := userService.UpdateProfile(ctx, 5, update)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(user.Name).To(Equal("Anne"))
+		})
+	})
+
+	Describe("ChangeEmail", func() {
+		It("should send the verification link to the new address and notify the old one", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(5)).Return(account, nil)
+			mockPasswordManager.EXPECT().Compare("secret", "hash").Return(true, nil)
+			mockRepo.EXPECT().ChangeEmail(ctx, uint(5), "new@example.com", gomock.Any()).
+				Return(entity.User{ID: 5, Name: "Anna", Email: "new@example.com", Locale: "ru"}, nil)
+			mockMailer.EXPECT().EmailVerification("Anna", gomock.Any(), 24*time.Hour, "new@example.com", "ru")
+			mockMailer.EXPECT().EmailChanged("Anna", "new@example.com", "anna@example.com", "ru")
+
+			user, err := userService.ChangeEmail(ctx, 5, "new@example.com", "secret")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(user.EmailVerified()).To(BeFalse())
+		})
+
+		It("should refuse with a wrong password", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(5)).Return(account, nil)
+			mockPasswordManager.EXPECT().Compare("wrong", "hash").Return(false, nil)
+
+			_, err := userService.ChangeEmail(ctx, 5, "new@example.com", "wrong")
+
+			Expect(err).To(MatchError(apperror.ErrIncorrectPassword))
+		})
+
+		It("should refuse the current email", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(5)).Return(account, nil)
+			mockPasswordManager.EXPECT().Compare("secret", "hash").Return(true, nil)
+
+			_, err := userService.ChangeEmail(ctx, 5, "anna@example.com", "secret")
+
+			Expect(err).To(MatchError(apperror.ErrEmailUnchanged))
+		})
+	})
+
+	Describe("ChangePassword", func() {
+		It("should store the new hash and end all sessions", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(5)).Return(account, nil)
+			mockPasswordManager.EXPECT().Compare("old-password", "hash").Return(true, nil)
+			mockPasswordManager.EXPECT().Hash("new-password").Return("new-hash", nil)
+			mockRepo.EXPECT().UpdatePassword(ctx, uint(5), "new-hash").Return(nil)
+			mockTokenService.EXPECT().RevokeActivesByUserID(ctx, uint(5)).Return(nil)
+
+			Expect(userService.ChangePassword(ctx, 5, "old-password", "new-password")).To(Succeed())
+		})
+
+		It("should refuse when the old password is wrong", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(5)).Return(account, nil)
+			mockPasswordManager.EXPECT().Compare("wrong", "hash").Return(false, nil)
+
+			err := userService.ChangePassword(ctx, 5, "wrong", "new-password")
+
+			Expect(err).To(MatchError(apperror.ErrIncorrectPassword))
+		})
+	})
+
+	Describe("DeleteAccount", func() {
+		It("should publish the cancellation of each pending offer", func() {
+			offer := entity.Offer{ID: 7, UserID: 5, ShopID: 2, Status: "cancelled"}
+			mockRepo.EXPECT().GetUserByID(ctx, uint(5)).Return(account, nil)
+			mockPasswordManager.EXPECT().Compare("secret", "hash").Return(true, nil)
+			mockRepo.EXPECT().DeleteUser(ctx, uint(5), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ uint,
+					onCancel func(email.Execer, []entity.Offer) error) ([]entity.Offer, error) {
+					return []entity.Offer{offer}, onCancel(nil, []entity.Offer{offer})
+				})
+			changed := event.OfferStatusChanged{Offer: offer}
+			mockEventBus.EXPECT().Publish(ctx, nil, changed).Return(nil)
+			mockEventBus.EXPECT().PublishAsync(ctx, changed)
+
+			Expect(userService.DeleteAccount(ctx, 5, "secret")).To(Succeed())
+		})
+
+		It("should keep the account when a synchronous subscriber fails", func() {
+			offer := entity.Offer{ID: 7, UserID: 5, ShopID: 2, Status: "cancelled"}
+			publishErr := errors.New("outbox error")
+			mockRepo.EXPECT().GetUserByID(ctx, uint(5)).Return(account, nil)
+			mockPasswordManager.EXPECT().Compare("secret", "hash").Return(true, nil)
+			mockRepo.EXPECT().DeleteUser(ctx, uint(5), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ uint,
+					onCancel func(email.Execer, []entity.Offer) error) ([]entity.Offer, error) {
+					// репозиторий откатывает удаление при ошибке onCancel
+					if err := onCancel(nil, []entity.Offer{offer}); err != nil {
+						return nil, err
+					}
+					return []entity.Offer{offer}, nil
+				})
+			mockEventBus.EXPECT().Publish(ctx, nil, gomock.Any()).Return(publishErr)
+
+			Expect(userService.DeleteAccount(ctx, 5, "secret")).To(MatchError(publishErr))
+		})
+
+		It("should refuse for a shop owner", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(5)).Return(account, nil)
+			mockPasswordManager.EXPECT().Compare("secret", "hash").Return(true, nil)
+			mockRepo.EXPECT().DeleteUser(ctx, uint(5), gomock.Any()).Return(nil, apperror.ErrAccountOwnsShop)
+
+			err := userService.DeleteAccount(ctx, 5, "secret")
+
+			Expect(err).To(MatchError(apperror.ErrAccountOwnsShop))
+		})
+
+		It("should not delete the account with a wrong password", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(5)).Return(account, nil)
+			mockPasswordManager.EXPECT().Compare("wrong", "hash").Return(false, errors.New("mismatch"))
+
+			Expect(userService.DeleteAccount(ctx, 5, "wrong")).ToNot(Succeed())
+		})
+	})
+})
@@ -9,14 +9,32 @@ import (
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
 )
 
-//go:generate mockgen -source=$GOFILE -destination=user_mock_test.go -package=user Repository, TokenService
+//go:generate mockgen -source=$GOFILE -destination=user_mock_test.go -package=user Repository, TokenService, EventBus
 
 type Repository interface {
-	InsertUser(ctx context.Context, user User) (uint, error)
+	InsertUser(
+		ctx context.Context,
+		user User,
+		verification VerificationToken,
+		onInsert func(tx email.Execer, userID uint) error,
+	) (uint, error)
 	GetUser(ctx context.Context, email string) (entity.User, error)
 	GetUserByID(ctx context.Context, id uint) (entity.User, error)
+	InsertPasswordResetToken(ctx context.Context, userID uint, tokenHash string, expiresAt time.Time) error
+	CountPasswordResetTokens(ctx context.Context, userID uint, since time.Time) (int, *time.Time, error)
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string) (uint, error)
+	InsertVerificationToken(ctx context.Context, userID uint, verification VerificationToken) error
+	CountVerificationTokens(ctx context.Context, userID uint, since time.Time) (int, *time.Time, error)
+	VerifyEmail(ctx context.Context, tokenHash string) (entity.User, error)
+	UpdateProfile(ctx context.Context, userID uint, update UpdateProfile) (entity.User, error)
+	UpdatePassword(ctx context.Context, userID uint, passwordHash string) error
+	ChangeEmail(ctx context.Context, userID uint, email string, verification VerificationToken) (entity.User, error)
+	DeleteUser(
+		ctx context.Context, userID uint, onCancel func(tx email.Execer, offers []entity.Offer) error,
+	) ([]entity.Offer, error)
 }
 
 // PasswordManager выполняет операции с паролями, такие как хеширование и проверка
@@ -34,28 +52,43 @@ type TokenService interface {
 	CleanUpExpiredByUserID(ctx context.Context, userID uint) error
 }
 
+// EventBus публикует события пользователей
+type EventBus interface {
+	Publish(ctx context.Context, exec email.Execer, e event.Event) error
+	PublishAsync(ctx context.Context, e event.Event)
+}
+
 type Service struct {
-	userRepository  Repository
-	tokenService    TokenService
-	passwordManager PasswordManager
-	mailer          email.MailerService
+	userRepository   Repository
+	tokenService     TokenService
+	passwordManager  PasswordManager
+	mailer           email.MailerService
+	events           EventBus
+	passwordResetTTL time.Duration
+	verifyURL        string
 }
 
 func NewService(userRepo Repository,
 	tokenService TokenService,
 	passwordManager PasswordManager,
 	mailer email.MailerService,
+	events EventBus,
+	passwordResetTTL time.Duration,
+	verifyURL string,
 ) *Service {
 	return &Service{
-		userRepository:  userRepo,
-		tokenService:    tokenService,
-		passwordManager: passwordManager,
-		mailer:          mailer,
+		userRepository:   userRepo,
+		tokenService:     tokenService,
+		passwordManager:  passwordManager,
+		mailer:           mailer,
+		events:           events,
+		passwordResetTTL: passwordResetTTL,
+		verifyURL:        verifyURL,
 	}
 }
 
 // CreateUser создает пользователя, хэшируя его пароль, используя HashArgon2id
-// генерирует access токен и uuid refresh uuid.
+// генерирует access токен и uuid refresh uuid. На почту уходит ссылка подтверждения.
 func (us *Service) CreateUser(
 	ctx context.Context,
 	user User,
@@ -68,11 +101,31 @@ func (us *Service) CreateUser(
 		return "", "", err
 	}
 	user.Password = hash
+	if user.Locale == "" {
+		user.Locale = email.DefaultLocale
+	}
+	// администратора нельзя зарегистрировать, роль выдается только в базе
+	user.Role = entity.UserRoleUser
+	if user.IsStore {
+		user.Role = entity.UserRoleShop
+	}
+
+	token, verification, err := us.newVerificationToken()
+	if err != nil {
+		return "", "", err
+	}
 
-	id, err := us.userRepository.InsertUser(ctx, user)
+	// синхронные подписчики выполняются в транзакции регистрации, их ошибка отменяет ее
+	registered := event.UserRegistered{UserName: user.Name, Email: user.Email, Locale: user.Locale}
+	id, err := us.userRepository.InsertUser(ctx, user, verification, func(tx email.Execer, userID uint) error {
+		registered.UserID = userID
+		return us.events.Publish(ctx, tx, registered)
+	})
 	if err != nil {
 		return "", "", err
 	}
+	registered.UserID = id
+	us.events.PublishAsync(ctx, registered)
 
 	accessToken, refreshToken, err := us.tokenService.GenerateTokens(ctx, fingerprint, id)
 	if err != nil {
@@ -83,7 +136,7 @@ func (us *Service) CreateUser(
 		return "", "", err
 	}
 
-	us.mailer.Registered(user.Name, user.Email)
+	us.sendVerification(user.Name, user.Email, user.Locale, token)
 
 	return accessToken, refreshToken.UUID.String(), nil
 }
@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=user.go -destination=user_mock_test.go -package=user Repository, TokenService
+//	mockgen -source=user.go -destination=user_mock_test.go -package=user Repository,TokenService,EventBus
 //
 
 // Package user is a generated GoMock package.
@@ -12,8 +12,11 @@ package user
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	event "github.com/EM-Stawberry/Stawberry/internal/domain/event"
+	email "github.com/EM-Stawberry/Stawberry/pkg/email"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,19 +44,81 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ChangeEmail mocks base method.
+func (m *MockRepository) ChangeEmail(ctx context.Context, userID uint, arg2 string, verification VerificationToken) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeEmail", ctx, userID, arg2, verification)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeEmail indicates an expected call of ChangeEmail.
+func (mr *MockRepositoryMockRecorder) ChangeEmail(ctx, userID, arg2, verification any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeEmail", reflect.TypeOf((*MockRepository)(nil).ChangeEmail), ctx, userID, arg2, verification)
+}
+
+// CountPasswordResetTokens mocks base method.
+func (m *MockRepository) CountPasswordResetTokens(ctx context.Context, userID uint, since time.Time) (int, *time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPasswordResetTokens", ctx, userID, since)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(*time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CountPasswordResetTokens indicates an expected call of CountPasswordResetTokens.
+func (mr *MockRepositoryMockRecorder) CountPasswordResetTokens(ctx, userID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPasswordResetTokens", reflect.TypeOf((*MockRepository)(nil).CountPasswordResetTokens), ctx, userID, since)
+}
+
+// CountVerificationTokens mocks base method.
+func (m *MockRepository) CountVerificationTokens(ctx context.Context, userID uint, since time.Time) (int, *time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountVerificationTokens", ctx, userID, since)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(*time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CountVerificationTokens indicates an expected call of CountVerificationTokens.
+func (mr *MockRepositoryMockRecorder) CountVerificationTokens(ctx, userID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountVerificationTokens", reflect.TypeOf((*MockRepository)(nil).CountVerificationTokens), ctx, userID, since)
+}
+
+// DeleteUser mocks base method.
+func (m *MockRepository) DeleteUser(ctx context.Context, userID uint, onCancel func(email.Execer, []entity.Offer) error) ([]entity.Offer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, userID, onCancel)
+	ret0, _ := ret[0].([]entity.Offer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockRepositoryMockRecorder) DeleteUser(ctx, userID, onCancel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockRepository)(nil).DeleteUser), ctx, userID, onCancel)
+}
+
 // GetUser mocks base method.
-func (m *MockRepository) GetUser(ctx context.Context, email string) (entity.User, error) {
+func (m *MockRepository) GetUser(ctx context.Context, arg1 string) (entity.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", ctx, email)
+	ret := m.ctrl.Call(m, "GetUser", ctx, arg1)
 	ret0, _ := ret[0].(entity.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUser indicates an expected call of GetUser.
-func (mr *MockRepositoryMockRecorder) GetUser(ctx, email any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetUser(ctx, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockRepository)(nil).GetUser), ctx, email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockRepository)(nil).GetUser), ctx, arg1)
 }
 
 // GetUserByID mocks base method.
@@ -71,19 +136,106 @@ func (mr *MockRepositoryMockRecorder) GetUserByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockRepository)(nil).GetUserByID), ctx, id)
 }
 
+// InsertPasswordResetToken mocks base method.
+func (m *MockRepository) InsertPasswordResetToken(ctx context.Context, userID uint, tokenHash string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertPasswordResetToken", ctx, userID, tokenHash, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertPasswordResetToken indicates an expected call of InsertPasswordResetToken.
+func (mr *MockRepositoryMockRecorder) InsertPasswordResetToken(ctx, userID, tokenHash, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPasswordResetToken", reflect.TypeOf((*MockRepository)(nil).InsertPasswordResetToken), ctx, userID, tokenHash, expiresAt)
+}
+
 // InsertUser mocks base method.
-func (m *MockRepository) InsertUser(ctx context.Context, user User) (uint, error) {
+func (m *MockRepository) InsertUser(ctx context.Context, user User, verification VerificationToken, onInsert func(email.Execer, uint) error) (uint, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertUser", ctx, user)
+	ret := m.ctrl.Call(m, "InsertUser", ctx, user, verification, onInsert)
 	ret0, _ := ret[0].(uint)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // InsertUser indicates an expected call of InsertUser.
-func (mr *MockRepositoryMockRecorder) InsertUser(ctx, user any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) InsertUser(ctx, user, verification, onInsert any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUser", reflect.TypeOf((*MockRepository)(nil).InsertUser), ctx, user, verification, onInsert)
+}
+
+// InsertVerificationToken mocks base method.
+func (m *MockRepository) InsertVerificationToken(ctx context.Context, userID uint, verification VerificationToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertVerificationToken", ctx, userID, verification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertVerificationToken indicates an expected call of InsertVerificationToken.
+func (mr *MockRepositoryMockRecorder) InsertVerificationToken(ctx, userID, verification any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUser", reflect.TypeOf((*MockRepository)(nil).InsertUser), ctx, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertVerificationToken", reflect.TypeOf((*MockRepository)(nil).InsertVerificationToken), ctx, userID, verification)
+}
+
+// ResetPassword mocks base method.
+func (m *MockRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, tokenHash, passwordHash)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockRepositoryMockRecorder) ResetPassword(ctx, tokenHash, passwordHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockRepository)(nil).ResetPassword), ctx, tokenHash, passwordHash)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockRepository) UpdatePassword(ctx context.Context, userID uint, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, userID, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockRepositoryMockRecorder) UpdatePassword(ctx, userID, passwordHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockRepository)(nil).UpdatePassword), ctx, userID, passwordHash)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockRepository) UpdateProfile(ctx context.Context, userID uint, update UpdateProfile) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, userID, update)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockRepositoryMockRecorder) UpdateProfile(ctx, userID, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockRepository)(nil).UpdateProfile), ctx, userID, update)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockRepository) VerifyEmail(ctx context.Context, tokenHash string) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, tokenHash)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockRepositoryMockRecorder) VerifyEmail(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockRepository)(nil).VerifyEmail), ctx, tokenHash)
 }
 
 // MockPasswordManager is a mock of PasswordManager interface.
@@ -251,3 +403,53 @@ func (mr *MockTokenServiceMockRecorder) Update(ctx, refresh any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTokenService)(nil).Update), ctx, refresh)
 }
+
+// MockEventBus is a mock of EventBus interface.
+type MockEventBus struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventBusMockRecorder
+	isgomock struct{}
+}
+
+// MockEventBusMockRecorder is the mock recorder for MockEventBus.
+type MockEventBusMockRecorder struct {
+	mock *MockEventBus
+}
+
+// NewMockEventBus creates a new mock instance.
+func NewMockEventBus(ctrl *gomock.Controller) *MockEventBus {
+	mock := &MockEventBus{ctrl: ctrl}
+	mock.recorder = &MockEventBusMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventBus) EXPECT() *MockEventBusMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockEventBus) Publish(ctx context.Context, exec email.Execer, e event.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, exec, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockEventBusMockRecorder) Publish(ctx, exec, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockEventBus)(nil).Publish), ctx, exec, e)
+}
+
+// PublishAsync mocks base method.
+func (m *MockEventBus) PublishAsync(ctx context.Context, e event.Event) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PublishAsync", ctx, e)
+}
+
+// PublishAsync indicates an expected call of PublishAsync.
+func (mr *MockEventBusMockRecorder) PublishAsync(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishAsync", reflect.TypeOf((*MockEventBus)(nil).PublishAsync), ctx, e)
+}
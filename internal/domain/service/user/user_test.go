@@ -3,12 +3,15 @@ package user
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
 	"github.com/EM-Stawberry/Stawberry/pkg/email/mock_email"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -21,7 +24,8 @@ var _ = Describe("UserService", func() {
 		mockRepo            *MockRepository
 		mockTokenService    *MockTokenService
 		mockPasswordManager *MockPasswordManager
-		mockEmailService    *mock_email.MockMailerService
+		mockEventBus        *MockEventBus
+		mockMailer          *mock_email.MockMailerService
 		userService         *Service
 		ctx                 context.Context
 	)
@@ -31,8 +35,10 @@ var _ = Describe("UserService", func() {
 		mockRepo = NewMockRepository(ctrl)
 		mockTokenService = NewMockTokenService(ctrl)
 		mockPasswordManager = NewMockPasswordManager(ctrl)
-		mockEmailService = mock_email.NewMockMailerService(ctrl)
-		userService = NewService(mockRepo, mockTokenService, mockPasswordManager, mockEmailService)
+		mockEventBus = NewMockEventBus(ctrl)
+		mockMailer = mock_email.NewMockMailerService(ctrl)
+		userService = NewService(mockRepo, mockTokenService, mockPasswordManager, mockMailer, mockEventBus, time.Hour,
+			"https://example.com/api/v1/auth/email/verify")
 		ctx = context.Background()
 	})
 
@@ -60,12 +66,22 @@ var _ = Describe("UserService", func() {
 		Context("when user creation is successful", func() {
 			It("should create user and return tokens", func() {
 				mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
-				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(1), nil)
+				registered := event.UserRegistered{
+					UserID: 1, UserName: testUser.Name, Email: testUser.Email, Locale: email.DefaultLocale,
+				}
+				mockRepo.EXPECT().InsertUser(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ User, _ VerificationToken,
+						onInsert func(email.Execer, uint) error) (uint, error) {
+						return uint(1), onInsert(nil, 1)
+					})
+				mockEventBus.EXPECT().Publish(ctx, nil, registered).Return(nil)
+				mockEventBus.EXPECT().PublishAsync(ctx, registered)
 				mockTokenService.EXPECT().
 					GenerateTokens(ctx, fingerprint, uint(1)).
 					Return("access-token", entity.RefreshToken{UUID: uuid.New()}, nil)
 				mockTokenService.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
-				mockEmailService.EXPECT().Registered(testUser.Name, testUser.Email)
+				mockMailer.EXPECT().EmailVerification(testUser.Name, gomock.Any(), 24*time.Hour, testUser.Email,
+					email.DefaultLocale)
 
 				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, fingerprint)
 
@@ -73,6 +89,56 @@ var _ = Describe("UserService", func() {
 				Expect(accessToken).ToNot(BeEmpty())
 				Expect(refreshToken).ToNot(BeEmpty())
 			})
+
+			It("should store only the hash of the token sent in the verification link", func() {
+				var stored VerificationToken
+				var link string
+				mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
+				mockRepo.EXPECT().InsertUser(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ User, v VerificationToken,
+						onInsert func(email.Execer, uint) error) (uint, error) {
+						stored = v
+						return uint(1), onInsert(nil, 1)
+					})
+				mockTokenService.EXPECT().
+					GenerateTokens(ctx, fingerprint, uint(1)).
+					Return("access-token", entity.RefreshToken{UUID: uuid.New()}, nil)
+				mockTokenService.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
+				mockEventBus.EXPECT().Publish(ctx, nil, gomock.Any()).Return(nil)
+				mockEventBus.EXPECT().PublishAsync(ctx, gomock.Any())
+				mockMailer.EXPECT().EmailVerification(testUser.Name, gomock.Any(), 24*time.Hour, testUser.Email,
+					email.DefaultLocale).
+					Do(func(_, verifyURL string, _ time.Duration, _, _ string) { link = verifyURL })
+
+				_, _, err := userService.CreateUser(ctx, testUser, fingerprint)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(link).To(HavePrefix("https://example.com/api/v1/auth/email/verify?token="))
+				token := strings.TrimPrefix(link, "https://example.com/api/v1/auth/email/verify?token=")
+				Expect(stored.TokenHash).To(Equal(hashToken(token)))
+				Expect(stored.ExpiresAt).To(BeTemporally("~", time.Now().Add(24*time.Hour), time.Minute))
+			})
+		})
+
+		Context("when assigning the role", func() {
+			DescribeTable("should derive it from the account type, never admin",
+				func(isStore bool, role entity.UserRole) {
+					testUser.IsStore = isStore
+					mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
+					mockRepo.EXPECT().InsertUser(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+						DoAndReturn(func(_ context.Context, u User, _ VerificationToken,
+							_ func(email.Execer, uint) error) (uint, error) {
+							Expect(u.Role).To(Equal(role))
+							return 0, errors.New("db error")
+						})
+
+					_, _, err := userService.CreateUser(ctx, testUser, fingerprint)
+
+					Expect(err).To(HaveOccurred())
+				},
+				Entry("buyer", false, entity.UserRoleUser),
+				Entry("store", true, entity.UserRoleShop),
+			)
 		})
 
 		Context("when password hashing fails", func() {
@@ -90,7 +156,8 @@ var _ = Describe("UserService", func() {
 		Context("when user insertion fails", func() {
 			It("should return error", func() {
 				mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
-				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(0), errors.New("db error"))
+				mockRepo.EXPECT().InsertUser(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(uint(0), errors.New("db error"))
 
 				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, fingerprint)
 
@@ -100,10 +167,32 @@ var _ = Describe("UserService", func() {
 			})
 		})
 
+		Context("when a synchronous subscriber fails", func() {
+			It("should cancel the registration", func() {
+				publishErr := errors.New("outbox error")
+				mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
+				mockRepo.EXPECT().InsertUser(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ User, _ VerificationToken,
+						onInsert func(email.Execer, uint) error) (uint, error) {
+						// репозиторий откатывает транзакцию при ошибке onInsert
+						if err := onInsert(nil, 1); err != nil {
+							return 0, err
+						}
+						return 1, nil
+					})
+				mockEventBus.EXPECT().Publish(ctx, nil, gomock.Any()).Return(publishErr)
+
+				_, _, err := userService.CreateUser(ctx, testUser, fingerprint)
+
+				Expect(err).To(MatchError(publishErr))
+			})
+		})
+
 		Context("when token generation fails", func() {
 			It("should return error", func() {
 				mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
-				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(1), nil)
+				mockRepo.EXPECT().InsertUser(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(uint(1), nil)
+				mockEventBus.EXPECT().PublishAsync(ctx, gomock.Any())
 				mockTokenService.EXPECT().
 					GenerateTokens(ctx, fingerprint, uint(1)).
 					Return("", entity.RefreshToken{}, errors.New("token generation error"))
@@ -119,7 +208,8 @@ var _ = Describe("UserService", func() {
 		Context("when token insertion fails", func() {
 			It("should return error", func() {
 				mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
-				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(1), nil)
+				mockRepo.EXPECT().InsertUser(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(uint(1), nil)
+				mockEventBus.EXPECT().PublishAsync(ctx, gomock.Any())
 				mockTokenService.EXPECT().
 					GenerateTokens(ctx, fingerprint, uint(1)).
 					Return("access-token", entity.RefreshToken{}, nil)
@@ -0,0 +1,76 @@
+package user
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
+)
+
+const verificationTTL = 24 * time.Hour
+
+// ResendVerification отправляет новую ссылку подтверждения почты.
+// Старые ссылки продолжают действовать до истечения срока
+func (us *Service) ResendVerification(ctx context.Context, userID uint) error {
+	user, err := us.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified() {
+		return apperror.ErrEmailAlreadyVerified
+	}
+
+	sent, lastAt, err := us.userRepository.CountVerificationTokens(ctx, userID, time.Now().Add(-tokenMailWindow))
+	if err != nil {
+		return err
+	}
+	if tokenMailLimited(sent, lastAt) {
+		return apperror.New(apperror.TooManyRequests, "verification email was sent recently, try again later", nil)
+	}
+
+	token, verification, err := us.newVerificationToken()
+	if err != nil {
+		return err
+	}
+	if err := us.userRepository.InsertVerificationToken(ctx, userID, verification); err != nil {
+		return err
+	}
+
+	us.sendVerification(user.Name, user.Email, user.Locale, token)
+
+	return nil
+}
+
+// VerifyEmail подтверждает почту по токену из письма. Вместе с токеном гасятся
+// все остальные ссылки пользователя, поэтому событие публикуется один раз
+func (us *Service) VerifyEmail(ctx context.Context, token string) (entity.User, error) {
+	user, err := us.userRepository.VerifyEmail(ctx, hashToken(token))
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	verified := event.EmailVerified{UserID: user.ID, UserName: user.Name, Email: user.Email, Locale: user.Locale}
+	if err := us.events.Publish(ctx, nil, verified); err != nil {
+		return entity.User{}, err
+	}
+	us.events.PublishAsync(ctx, verified)
+
+	return user, nil
+}
+
+func (us *Service) newVerificationToken() (string, VerificationToken, error) {
+	token, tokenHash, err := newToken()
+	if err != nil {
+		return "", VerificationToken{}, apperror.New(apperror.InternalError,
+			"failed to generate email verification token", err)
+	}
+	return token, VerificationToken{TokenHash: tokenHash, ExpiresAt: time.Now().Add(verificationTTL)}, nil
+}
+
+func (us *Service) sendVerification(userName, userMail, locale, token string) {
+	verifyURL := us.verifyURL + "?token=" + url.QueryEscape(token)
+	us.mailer.EmailVerification(userName, verifyURL, verificationTTL, userMail, locale)
+}
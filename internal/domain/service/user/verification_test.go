@@ -0,0 +1,113 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
+	"github.com/EM-Stawberry/Stawberry/pkg/email/mock_email"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Email verification", func() {
+	var (
+		ctrl         *gomock.Controller
+		mockRepo     *MockRepository
+		mockEventBus *MockEventBus
+		mockMailer   *mock_email.MockMailerService
+		userService  *Service
+		ctx          context.Context
+		unverified   entity.User
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockEventBus = NewMockEventBus(ctrl)
+		mockMailer = mock_email.NewMockMailerService(ctrl)
+		userService = NewService(mockRepo, NewMockTokenService(ctrl), NewMockPasswordManager(ctrl), mockMailer,
+			mockEventBus, time.Hour, "https://example.com/api/v1/auth/email/verify")
+		ctx = context.Background()
+		unverified = entity.User{ID: 5, Name: "Anna", Email: "anna@example.com", Locale: "ru"}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("ResendVerification", func() {
+		It("should send a new link", func() {
+			lastAt := time.Now().Add(-2 * time.Minute)
+			mockRepo.EXPECT().GetUserByID(ctx, uint(5)).Return(unverified, nil)
+			mockRepo.EXPECT().CountVerificationTokens(ctx, uint(5), gomock.Any()).Return(1, &lastAt, nil)
+			mockRepo.EXPECT().InsertVerificationToken(ctx, uint(5), gomock.Any()).Return(nil)
+			mockMailer.EXPECT().EmailVerification("Anna", gomock.Any(), 24*time.Hour, "anna@example.com", "ru")
+
+			Expect(userService.ResendVerification(ctx, 5)).To(Succeed())
+		})
+
+		It("should refuse when the email is already verified", func() {
+			verifiedAt := time.Now()
+			unverified.EmailVerifiedAt = &verifiedAt
+			mockRepo.EXPECT().GetUserByID(ctx, uint(5)).Return(unverified, nil)
+
+			err := userService.ResendVerification(ctx, 5)
+
+			Expect(err).To(MatchError(apperror.ErrEmailAlreadyVerified))
+		})
+
+		DescribeTable("should be rate limited",
+			func(sent int, lastAgo time.Duration) {
+				lastAt := time.Now().Add(-lastAgo)
+				mockRepo.EXPECT().GetUserByID(ctx, uint(5)).Return(unverified, nil)
+				mockRepo.EXPECT().CountVerificationTokens(ctx, uint(5), gomock.Any()).Return(sent, &lastAt, nil)
+
+				err := userService.ResendVerification(ctx, 5)
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.(*apperror.Error).Code()).To(Equal(apperror.TooManyRequests))
+			},
+			Entry("within a minute of the last email", 1, 10*time.Second),
+			Entry("after five emails in an hour", 5, 10*time.Minute),
+		)
+	})
+
+	Describe("VerifyEmail", func() {
+		It("should verify the email by the token hash and publish the event", func() {
+			verifiedAt := time.Now()
+			unverified.EmailVerifiedAt = &verifiedAt
+			mockRepo.EXPECT().VerifyEmail(ctx, hashToken("token")).Return(unverified, nil)
+			verified := event.EmailVerified{UserID: 5, UserName: "Anna", Email: "anna@example.com", Locale: "ru"}
+			mockEventBus.EXPECT().Publish(ctx, nil, verified).Return(nil)
+			mockEventBus.EXPECT().PublishAsync(ctx, verified)
+
+			user, err := userService.VerifyEmail(ctx, "token")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(user.EmailVerified()).To(BeTrue())
+		})
+
+		It("should not publish the event for an invalid token", func() {
+			mockRepo.EXPECT().VerifyEmail(ctx, hashToken("token")).
+				Return(entity.User{}, apperror.ErrInvalidVerificationToken)
+
+			_, err := userService.VerifyEmail(ctx, "token")
+
+			Expect(err).To(MatchError(apperror.ErrInvalidVerificationToken))
+		})
+
+		It("should return the publish error", func() {
+			mockRepo.EXPECT().VerifyEmail(ctx, hashToken("token")).Return(unverified, nil)
+			mockEventBus.EXPECT().Publish(ctx, nil, gomock.Any()).Return(errors.New("outbox error"))
+
+			_, err := userService.VerifyEmail(ctx, "token")
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+)
+
+// errAddressNotAllowed адрес вебхука ведет во внутреннюю сеть
+var errAddressNotAllowed = errors.New("webhook address is not allowed")
+
+// blockedPrefixes публичные по классификации net/netip диапазоны, которые все равно
+// не должны быть доступны магазинам: "this network", CGNAT (там же метаданные некоторых облаков),
+// служебные IETF и сети для тестов производительности
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+}
+
+// publicAddr отсекает loopback, частные, link-local (в том числе 169.254.169.254
+// с метаданными облака), multicast и прочие непубличные адреса
+func publicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// dialControl проверяет адрес непосредственно перед соединением,
+// поэтому подмена DNS после проверки при создании вебхука не помогает
+func dialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errAddressNotAllowed
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil || !publicAddr(ip) {
+		return errAddressNotAllowed
+	}
+	return nil
+}
+
+// checkURL проверяет, что все адреса хоста вебхука публичные
+func (d *Deliverer) checkURL(ctx context.Context, rawURL string) error {
+	if d.allowPrivate {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return apperror.New(apperror.BadRequest, "webhook url must be an absolute http or https url", err)
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return apperror.New(apperror.BadRequest, "webhook host cannot be resolved", err)
+	}
+	for _, addr := range addrs {
+		if !publicAddr(addr) {
+			return apperror.New(apperror.BadRequest, "webhook url must point to a public address", nil)
+		}
+	}
+	return nil
+}
+
+// requestError заменяет ошибку соединения общим описанием. Текст ошибки виден магазину
+// в журнале доставок, и подробности вроде "connection refused" позволили бы сканировать сеть
+func requestError(err error) error {
+	if errors.Is(err, errAddressNotAllowed) {
+		return errAddressNotAllowed
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return errors.New("webhook request timed out")
+	}
+	return errors.New("webhook request failed")
+}
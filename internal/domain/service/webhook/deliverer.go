@@ -0,0 +1,252 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/backoff"
+)
+
+// Заголовки запроса вебхука
+const (
+	HeaderEvent     = "X-Stawberry-Event"
+	HeaderDelivery  = "X-Stawberry-Delivery"
+	HeaderSignature = "X-Stawberry-Signature"
+)
+
+const (
+	// sendLease на сколько доставка откладывается при выборке, чтобы ее не взял другой воркер
+	sendLease = 5 * time.Minute
+	// resultTimeout сколько ждать записи результата доставки, в том числе при остановке
+	resultTimeout = 5 * time.Second
+
+	// задержка перед повторной доставкой: 30s, 1m, 2m... не больше часа
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = time.Hour
+)
+
+// Deliverer отправляет доставки из очереди на адреса магазинов. Неотправленные доставки
+// остаются в базе и уходят после перезапуска
+type Deliverer struct {
+	ctx          context.Context
+	ctxCanc      context.CancelFunc
+	store        DeliveryStore
+	client       *http.Client
+	wake         chan struct{}
+	wg           sync.WaitGroup
+	batchSize    int
+	maxAttempts  int
+	pollInterval time.Duration
+	allowPrivate bool
+	log          *zap.Logger
+}
+
+// NewDeliverer создает отправителя и запускает WorkerPool воркеров
+func NewDeliverer(store DeliveryStore, webhookCfg *config.WebhookConfig, log *zap.Logger) *Deliverer {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// прокси не используется: через него соединение ушло бы мимо проверки адреса
+	dialer := &net.Dialer{Timeout: webhookCfg.Timeout}
+	if !webhookCfg.AllowPrivateNetworks {
+		dialer.Control = dialControl
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	d := &Deliverer{
+		ctx:     ctx,
+		ctxCanc: cancel,
+		store:   store,
+		client: &http.Client{
+			Timeout:   webhookCfg.Timeout,
+			Transport: transport,
+			// редирект мог бы увести подписанный запрос на другой адрес
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		wake:         make(chan struct{}, 1),
+		batchSize:    webhookCfg.BatchSize,
+		maxAttempts:  webhookCfg.MaxAttempts,
+		pollInterval: webhookCfg.PollInterval,
+		allowPrivate: webhookCfg.AllowPrivateNetworks,
+		log:          log,
+	}
+
+	d.wg.Add(webhookCfg.WorkerPool)
+	for range webhookCfg.WorkerPool {
+		go d.worker()
+	}
+	return d
+}
+
+// Wake будит воркер, чтобы новые доставки ушли без ожидания следующего опроса
+func (d *Deliverer) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Deliverer) Stop(ctx context.Context) {
+	d.ctxCanc()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		d.log.Info("Webhook workers forcefully stopped (timeout), undelivered events stay in queue")
+	case <-done:
+		d.log.Info("Webhook workers stopped, undelivered events stay in queue")
+	}
+}
+
+func (d *Deliverer) worker() {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.drain()
+
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// drain отправляет доставки, пока в очереди есть готовые
+func (d *Deliverer) drain() {
+	for d.ctx.Err() == nil {
+		deliveries, err := d.store.ClaimDeliveries(d.ctx, d.batchSize, sendLease)
+		if err != nil {
+			if d.ctx.Err() == nil {
+				d.log.Error("Failed to claim webhook deliveries", zap.Error(err))
+			}
+			return
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+		for _, delivery := range deliveries {
+			d.deliver(d.ctx, delivery, d.maxAttempts)
+		}
+	}
+}
+
+// deliver отправляет доставку и записывает результат. После ошибки следующая попытка назначается
+// с экспоненциальной задержкой, после maxAttempts доставка проваливается.
+// Возвращает доставку с результатом попытки
+func (d *Deliverer) deliver(
+	ctx context.Context,
+	delivery entity.PendingWebhookDelivery,
+	maxAttempts int,
+) entity.WebhookDelivery {
+	log := d.log.With(zap.Int64("id", delivery.ID), zap.String("event", delivery.Event),
+		zap.Int("attempt", delivery.Attempts))
+
+	status, sendErr := d.send(ctx, delivery)
+
+	// результат записывается и при остановке, иначе доставка уйдет повторно
+	resultCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), resultTimeout)
+	defer cancel()
+
+	result := delivery.WebhookDelivery
+	if status != 0 {
+		result.ResponseStatus = &status
+	}
+
+	if sendErr == nil {
+		now := time.Now()
+		result.Status = entity.WebhookDeliveryDelivered
+		result.LastError = ""
+		result.DeliveredAt = &now
+		if err := d.store.MarkDelivered(resultCtx, delivery.ID, status); err != nil {
+			log.Error("Failed to mark webhook delivery as delivered", zap.Error(err))
+		}
+		return result
+	}
+
+	var retryAt *time.Time
+	if delivery.Attempts < maxAttempts {
+		next := time.Now().Add(backoff.Exponential(delivery.Attempts, retryBaseDelay, retryMaxDelay))
+		retryAt = &next
+		result.NextAttemptAt = next
+		log.Warn("Failed to deliver webhook, will retry", zap.Time("retry_at", next), zap.Error(sendErr))
+	} else {
+		result.Status = entity.WebhookDeliveryFailed
+		log.Warn("Failed to deliver webhook, giving up", zap.Error(sendErr))
+	}
+	result.LastError = sendErr.Error()
+
+	if err := d.store.MarkFailed(resultCtx, delivery.ID, status, sendErr.Error(), retryAt); err != nil {
+		log.Error("Failed to save webhook delivery error", zap.Error(err))
+	}
+	return result
+}
+
+// send выполняет одну попытку доставки и возвращает код ответа, 0 - ответа не было.
+// Успешным считается только ответ 2xx
+func (d *Deliverer) send(ctx context.Context, delivery entity.PendingWebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Stawberry-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderSignature, SignatureHeader(delivery.Secret, time.Now().Unix(), delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		d.log.Warn("Webhook request failed", zap.Int64("id", delivery.ID), zap.Error(err))
+		return 0, requestError(err)
+	}
+	defer resp.Body.Close()
+	// тело ответа не нужно, но его дочитывание позволяет переиспользовать соединение
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign возвращает HMAC-SHA256 от "<timestamp>.<body>" в hex. Метка времени в подписи
+// позволяет получателю отбрасывать повторно отправленные старые запросы
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureHeader значение заголовка подписи: t=<timestamp>,v1=<подпись>
+func SignatureHeader(secret string, timestamp int64, body []byte) string {
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, Sign(secret, timestamp, body))
+}
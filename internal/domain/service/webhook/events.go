@@ -0,0 +1,136 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+)
+
+// EventBus шина доменных событий, на которые подписываются вебхуки
+type EventBus interface {
+	Subscribe(name string, h event.Handler)
+	SubscribeAsync(name string, h event.AsyncHandler)
+}
+
+// Payload тело запроса вебхука
+type Payload struct {
+	Event      string    `json:"event"`
+	ShopID     uint      `json:"shop_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// OfferData оффер в событиях offer.created и offer.status_changed
+type OfferData struct {
+	ID        uint      `json:"id"`
+	Status    string    `json:"status"`
+	Price     float64   `json:"price"`
+	Currency  string    `json:"currency"`
+	ProductID uint      `json:"product_id"`
+	VariantID uint      `json:"variant_id"`
+	BuyerID   uint      `json:"buyer_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Expired оффер отменен по истечении срока
+	Expired bool `json:"expired,omitempty"`
+}
+
+// ReviewData отзыв в событии review.added. ProductID заполнен только у отзыва о товаре
+type ReviewData struct {
+	ID        int    `json:"id"`
+	Type      string `json:"type"`
+	ProductID int    `json:"product_id,omitempty"`
+	AuthorID  int    `json:"author_id"`
+	Rating    int    `json:"rating"`
+}
+
+// PingData данные проверочного события
+type PingData struct {
+	WebhookID uint `json:"webhook_id"`
+}
+
+// SubscribeEvents подписывает вебхуки на доменные события. Доставки пишутся синхронно,
+// в транзакции события, а после коммита воркеры будятся, чтобы не ждать следующего опроса
+func (s *Service) SubscribeEvents(bus EventBus) {
+	for _, name := range Events {
+		bus.Subscribe(name, s.queueDeliveries)
+		bus.SubscribeAsync(name, s.wakeDeliverer)
+	}
+}
+
+// queueDeliveries записывает доставку события во все подписанные на него вебхуки магазинов
+func (s *Service) queueDeliveries(ctx context.Context, exec email.Execer, e event.Event) error {
+	shopIDs, data, err := s.eventData(ctx, e)
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := s.webhookRepository.SelectSubscribedWebhooks(ctx, shopIDs, e.Name())
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	deliveries := make([]entity.WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		payload, err := newPayload(e.Name(), webhook.ShopID, data)
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, entity.WebhookDelivery{
+			WebhookID: webhook.ID,
+			Event:     e.Name(),
+			Payload:   payload,
+		})
+	}
+	return s.webhookRepository.InsertDeliveries(ctx, exec, deliveries)
+}
+
+func (s *Service) wakeDeliverer(context.Context, event.Event) error {
+	s.deliverer.Wake()
+	return nil
+}
+
+// eventData возвращает магазины, которых касается событие, и данные для тела запроса.
+// Об отзыве на товар узнают все магазины, которые его продают
+func (s *Service) eventData(ctx context.Context, e event.Event) ([]uint, any, error) {
+	switch e := e.(type) {
+	case event.OfferCreated:
+		return []uint{e.Offer.ShopID}, newOfferData(e.Offer, false), nil
+	case event.OfferStatusChanged:
+		return []uint{e.Offer.ShopID}, newOfferData(e.Offer, e.Expired), nil
+	case event.ReviewAdded:
+		data := ReviewData{ID: e.ReviewID, Type: e.ReviewType, AuthorID: e.UserID, Rating: e.Rating}
+		if e.ReviewType == entity.ReviewTypeSeller {
+			return []uint{uint(e.TargetID)}, data, nil
+		}
+		data.ProductID = e.TargetID
+		shopIDs, err := s.webhookRepository.SelectProductShopIDs(ctx, uint(e.TargetID))
+		if err != nil {
+			return nil, nil, err
+		}
+		return shopIDs, data, nil
+	default:
+		return nil, nil, apperror.New(apperror.InternalError, "unexpected event "+e.Name(), nil)
+	}
+}
+
+func newOfferData(offer entity.Offer, expired bool) OfferData {
+	return OfferData{
+		ID:        offer.ID,
+		Status:    offer.Status,
+		Price:     offer.Price,
+		Currency:  offer.Currency,
+		ProductID: offer.ProductID,
+		VariantID: offer.VariantID,
+		BuyerID:   offer.UserID,
+		CreatedAt: offer.CreatedAt,
+		UpdatedAt: offer.UpdatedAt,
+		ExpiresAt: offer.ExpiresAt,
+		Expired:   expired,
+	}
+}
@@ -0,0 +1,253 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=webhook_mock_test.go -package=webhook
+
+// DeliveryStore очередь доставок, которую разбирают воркеры
+type DeliveryStore interface {
+	// ClaimDeliveries выбирает готовые к отправке доставки и откладывает их на lease,
+	// чтобы их не взял другой воркер. Счетчик попыток увеличивается
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.PendingWebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int64, responseStatus int) error
+	// MarkFailed сохраняет ошибку и назначает следующую попытку, без retryAt доставка проваливается
+	MarkFailed(ctx context.Context, id int64, responseStatus int, lastError string, retryAt *time.Time) error
+}
+
+type Repository interface {
+	DeliveryStore
+	InsertWebhook(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error)
+	SelectShopWebhooks(ctx context.Context, shopID uint) ([]entity.Webhook, error)
+	GetWebhook(ctx context.Context, shopID, webhookID uint) (entity.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error)
+	DeleteWebhook(ctx context.Context, shopID, webhookID uint) error
+	SelectSubscribedWebhooks(ctx context.Context, shopIDs []uint, eventName string) ([]entity.Webhook, error)
+	SelectProductShopIDs(ctx context.Context, productID uint) ([]uint, error)
+	InsertDeliveries(ctx context.Context, exec email.Execer, deliveries []entity.WebhookDelivery) error
+	CreateClaimedDelivery(
+		ctx context.Context, delivery entity.WebhookDelivery, lease time.Duration,
+	) (entity.WebhookDelivery, error)
+	SelectDeliveries(ctx context.Context, webhookID uint, offset, limit int) ([]entity.WebhookDelivery, int, error)
+}
+
+// ShopRepository проверяет, что пользователь состоит в магазине
+type ShopRepository interface {
+	GetStoreByID(ctx context.Context, id uint) (entity.Store, error)
+	GetMemberRole(ctx context.Context, shopID, userID uint) (entity.ShopRole, error)
+}
+
+// EventPing проверочное событие, отправляется только по запросу магазина
+const EventPing = "ping"
+
+// secretLength длина секрета подписи в байтах
+const secretLength = 32
+
+// Events события, на которые можно подписать вебхук
+var Events = []string{event.NameOfferCreated, event.NameOfferStatusChanged, event.NameReviewAdded}
+
+// Service управляет вебхуками магазинов и ставит в очередь доставки доменных событий
+type Service struct {
+	webhookRepository Repository
+	shopRepository    ShopRepository
+	deliverer         *Deliverer
+}
+
+func NewService(webhookRepository Repository, shopRepository ShopRepository, deliverer *Deliverer) *Service {
+	return &Service{
+		webhookRepository: webhookRepository,
+		shopRepository:    shopRepository,
+		deliverer:         deliverer,
+	}
+}
+
+// CreateWebhook регистрирует адрес магазина для событий. Секрет подписи возвращается только здесь
+func (s *Service) CreateWebhook(
+	ctx context.Context,
+	shopID, userID uint,
+	rawURL string,
+	events []string,
+) (entity.Webhook, error) {
+	if err := s.requireManager(ctx, shopID, userID); err != nil {
+		return entity.Webhook{}, err
+	}
+
+	events, err := validateWebhook(rawURL, events)
+	if err != nil {
+		return entity.Webhook{}, err
+	}
+	if err := s.deliverer.checkURL(ctx, rawURL); err != nil {
+		return entity.Webhook{}, err
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return entity.Webhook{}, apperror.New(apperror.InternalError, "failed to generate webhook secret", err)
+	}
+
+	return s.webhookRepository.InsertWebhook(ctx, entity.Webhook{
+		ShopID: shopID,
+		URL:    rawURL,
+		Secret: secret,
+		Events: events,
+		Active: true,
+	})
+}
+
+func (s *Service) GetWebhooks(ctx context.Context, shopID, userID uint) ([]entity.Webhook, error) {
+	if err := s.requireManager(ctx, shopID, userID); err != nil {
+		return nil, err
+	}
+	return s.webhookRepository.SelectShopWebhooks(ctx, shopID)
+}
+
+// UpdateWebhook меняет адрес, события и активность вебхука
+func (s *Service) UpdateWebhook(ctx context.Context, userID uint, webhook entity.Webhook) (entity.Webhook, error) {
+	if err := s.requireManager(ctx, webhook.ShopID, userID); err != nil {
+		return entity.Webhook{}, err
+	}
+
+	events, err := validateWebhook(webhook.URL, webhook.Events)
+	if err != nil {
+		return entity.Webhook{}, err
+	}
+	if err := s.deliverer.checkURL(ctx, webhook.URL); err != nil {
+		return entity.Webhook{}, err
+	}
+	webhook.Events = events
+
+	return s.webhookRepository.UpdateWebhook(ctx, webhook)
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, shopID, userID, webhookID uint) error {
+	if err := s.requireManager(ctx, shopID, userID); err != nil {
+		return err
+	}
+	return s.webhookRepository.DeleteWebhook(ctx, shopID, webhookID)
+}
+
+// GetDeliveries возвращает журнал доставок вебхука, новые первыми
+func (s *Service) GetDeliveries(
+	ctx context.Context,
+	shopID, userID, webhookID uint,
+	page, limit int,
+) ([]entity.WebhookDelivery, int, error) {
+	if err := s.requireManager(ctx, shopID, userID); err != nil {
+		return nil, 0, err
+	}
+	if _, err := s.webhookRepository.GetWebhook(ctx, shopID, webhookID); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	return s.webhookRepository.SelectDeliveries(ctx, webhookID, offset, limit)
+}
+
+// Ping сразу отправляет на адрес вебхука проверочное событие и возвращает результат доставки.
+// Неудачная проверка не повторяется, но остается в журнале
+func (s *Service) Ping(ctx context.Context, shopID, userID, webhookID uint) (entity.WebhookDelivery, error) {
+	if err := s.requireManager(ctx, shopID, userID); err != nil {
+		return entity.WebhookDelivery{}, err
+	}
+
+	webhook, err := s.webhookRepository.GetWebhook(ctx, shopID, webhookID)
+	if err != nil {
+		return entity.WebhookDelivery{}, err
+	}
+
+	payload, err := newPayload(EventPing, shopID, PingData{WebhookID: webhook.ID})
+	if err != nil {
+		return entity.WebhookDelivery{}, err
+	}
+
+	delivery, err := s.webhookRepository.CreateClaimedDelivery(ctx, entity.WebhookDelivery{
+		WebhookID: webhook.ID,
+		Event:     EventPing,
+		Payload:   payload,
+	}, sendLease)
+	if err != nil {
+		return entity.WebhookDelivery{}, err
+	}
+
+	return s.deliverer.deliver(ctx, entity.PendingWebhookDelivery{
+		WebhookDelivery: delivery,
+		URL:             webhook.URL,
+		Secret:          webhook.Secret,
+	}, 1), nil
+}
+
+// requireManager проверяет, что магазин существует и пользователь управляет им
+func (s *Service) requireManager(ctx context.Context, shopID, userID uint) error {
+	if _, err := s.shopRepository.GetStoreByID(ctx, shopID); err != nil {
+		return err
+	}
+
+	role, err := s.shopRepository.GetMemberRole(ctx, shopID, userID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrShopMemberNotFound) {
+			return apperror.New(apperror.Forbidden, "you are not a member of this shop", nil)
+		}
+		return err
+	}
+	if !role.CanManage() {
+		return apperror.New(apperror.Forbidden, "only the shop owner and managers can manage webhooks", nil)
+	}
+	return nil
+}
+
+// validateWebhook проверяет адрес и события вебхука и возвращает события без повторов
+func validateWebhook(rawURL string, events []string) ([]string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, apperror.New(apperror.BadRequest, "webhook url must be an absolute http or https url", err)
+	}
+
+	if len(events) == 0 {
+		return nil, apperror.New(apperror.BadRequest, "webhook must subscribe to at least one event", nil)
+	}
+	unique := make([]string, 0, len(events))
+	for _, e := range events {
+		if !slices.Contains(Events, e) {
+			return nil, apperror.New(apperror.BadRequest, "unknown webhook event "+e, nil)
+		}
+		if !slices.Contains(unique, e) {
+			unique = append(unique, e)
+		}
+	}
+	return unique, nil
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, secretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// newPayload собирает тело запроса вебхука
+func newPayload(eventName string, shopID uint, data any) (json.RawMessage, error) {
+	payload, err := json.Marshal(Payload{
+		Event:      eventName,
+		ShopID:     shopID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	})
+	if err != nil {
+		return nil, apperror.New(apperror.InternalError, "failed to encode webhook payload", err)
+	}
+	return payload, nil
+}
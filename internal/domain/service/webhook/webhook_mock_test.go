@@ -0,0 +1,357 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webhook.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webhook.go -destination=webhook_mock_test.go -package=webhook Repository ShopRepository DeliveryStore
+//
+
+// Package webhook is a generated GoMock package.
+package webhook
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	email "github.com/EM-Stawberry/Stawberry/pkg/email"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeliveryStore is a mock of DeliveryStore interface.
+type MockDeliveryStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliveryStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockDeliveryStoreMockRecorder is the mock recorder for MockDeliveryStore.
+type MockDeliveryStoreMockRecorder struct {
+	mock *MockDeliveryStore
+}
+
+// NewMockDeliveryStore creates a new mock instance.
+func NewMockDeliveryStore(ctrl *gomock.Controller) *MockDeliveryStore {
+	mock := &MockDeliveryStore{ctrl: ctrl}
+	mock.recorder = &MockDeliveryStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeliveryStore) EXPECT() *MockDeliveryStoreMockRecorder {
+	return m.recorder
+}
+
+// ClaimDeliveries mocks base method.
+func (m *MockDeliveryStore) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.PendingWebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeliveries", ctx, limit, lease)
+	ret0, _ := ret[0].([]entity.PendingWebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeliveries indicates an expected call of ClaimDeliveries.
+func (mr *MockDeliveryStoreMockRecorder) ClaimDeliveries(ctx, limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeliveries", reflect.TypeOf((*MockDeliveryStore)(nil).ClaimDeliveries), ctx, limit, lease)
+}
+
+// MarkDelivered mocks base method.
+func (m *MockDeliveryStore) MarkDelivered(ctx context.Context, id int64, responseStatus int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDelivered", ctx, id, responseStatus)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDelivered indicates an expected call of MarkDelivered.
+func (mr *MockDeliveryStoreMockRecorder) MarkDelivered(ctx, id, responseStatus any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDelivered", reflect.TypeOf((*MockDeliveryStore)(nil).MarkDelivered), ctx, id, responseStatus)
+}
+
+// MarkFailed mocks base method.
+func (m *MockDeliveryStore) MarkFailed(ctx context.Context, id int64, responseStatus int, lastError string, retryAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, id, responseStatus, lastError, retryAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockDeliveryStoreMockRecorder) MarkFailed(ctx, id, responseStatus, lastError, retryAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockDeliveryStore)(nil).MarkFailed), ctx, id, responseStatus, lastError, retryAt)
+}
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDeliveries mocks base method.
+func (m *MockRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.PendingWebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeliveries", ctx, limit, lease)
+	ret0, _ := ret[0].([]entity.PendingWebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeliveries indicates an expected call of ClaimDeliveries.
+func (mr *MockRepositoryMockRecorder) ClaimDeliveries(ctx, limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeliveries", reflect.TypeOf((*MockRepository)(nil).ClaimDeliveries), ctx, limit, lease)
+}
+
+// CreateClaimedDelivery mocks base method.
+func (m *MockRepository) CreateClaimedDelivery(ctx context.Context, delivery entity.WebhookDelivery, lease time.Duration) (entity.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClaimedDelivery", ctx, delivery, lease)
+	ret0, _ := ret[0].(entity.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateClaimedDelivery indicates an expected call of CreateClaimedDelivery.
+func (mr *MockRepositoryMockRecorder) CreateClaimedDelivery(ctx, delivery, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClaimedDelivery", reflect.TypeOf((*MockRepository)(nil).CreateClaimedDelivery), ctx, delivery, lease)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockRepository) DeleteWebhook(ctx context.Context, shopID, webhookID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, shopID, webhookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockRepositoryMockRecorder) DeleteWebhook(ctx, shopID, webhookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockRepository)(nil).DeleteWebhook), ctx, shopID, webhookID)
+}
+
+// GetWebhook mocks base method.
+func (m *MockRepository) GetWebhook(ctx context.Context, shopID, webhookID uint) (entity.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, shopID, webhookID)
+	ret0, _ := ret[0].(entity.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockRepositoryMockRecorder) GetWebhook(ctx, shopID, webhookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockRepository)(nil).GetWebhook), ctx, shopID, webhookID)
+}
+
+// InsertDeliveries mocks base method.
+func (m *MockRepository) InsertDeliveries(ctx context.Context, exec email.Execer, deliveries []entity.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertDeliveries", ctx, exec, deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertDeliveries indicates an expected call of InsertDeliveries.
+func (mr *MockRepositoryMockRecorder) InsertDeliveries(ctx, exec, deliveries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertDeliveries", reflect.TypeOf((*MockRepository)(nil).InsertDeliveries), ctx, exec, deliveries)
+}
+
+// InsertWebhook mocks base method.
+func (m *MockRepository) InsertWebhook(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhook", ctx, webhook)
+	ret0, _ := ret[0].(entity.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhook indicates an expected call of InsertWebhook.
+func (mr *MockRepositoryMockRecorder) InsertWebhook(ctx, webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhook", reflect.TypeOf((*MockRepository)(nil).InsertWebhook), ctx, webhook)
+}
+
+// MarkDelivered mocks base method.
+func (m *MockRepository) MarkDelivered(ctx context.Context, id int64, responseStatus int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDelivered", ctx, id, responseStatus)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDelivered indicates an expected call of MarkDelivered.
+func (mr *MockRepositoryMockRecorder) MarkDelivered(ctx, id, responseStatus any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDelivered", reflect.TypeOf((*MockRepository)(nil).MarkDelivered), ctx, id, responseStatus)
+}
+
+// MarkFailed mocks base method.
+func (m *MockRepository) MarkFailed(ctx context.Context, id int64, responseStatus int, lastError string, retryAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, id, responseStatus, lastError, retryAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockRepositoryMockRecorder) MarkFailed(ctx, id, responseStatus, lastError, retryAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockRepository)(nil).MarkFailed), ctx, id, responseStatus, lastError, retryAt)
+}
+
+// SelectDeliveries mocks base method.
+func (m *MockRepository) SelectDeliveries(ctx context.Context, webhookID uint, offset, limit int) ([]entity.WebhookDelivery, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectDeliveries", ctx, webhookID, offset, limit)
+	ret0, _ := ret[0].([]entity.WebhookDelivery)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectDeliveries indicates an expected call of SelectDeliveries.
+func (mr *MockRepositoryMockRecorder) SelectDeliveries(ctx, webhookID, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectDeliveries", reflect.TypeOf((*MockRepository)(nil).SelectDeliveries), ctx, webhookID, offset, limit)
+}
+
+// SelectProductShopIDs mocks base method.
+func (m *MockRepository) SelectProductShopIDs(ctx context.Context, productID uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectProductShopIDs", ctx, productID)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectProductShopIDs indicates an expected call of SelectProductShopIDs.
+func (mr *MockRepositoryMockRecorder) SelectProductShopIDs(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectProductShopIDs", reflect.TypeOf((*MockRepository)(nil).SelectProductShopIDs), ctx, productID)
+}
+
+// SelectShopWebhooks mocks base method.
+func (m *MockRepository) SelectShopWebhooks(ctx context.Context, shopID uint) ([]entity.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectShopWebhooks", ctx, shopID)
+	ret0, _ := ret[0].([]entity.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectShopWebhooks indicates an expected call of SelectShopWebhooks.
+func (mr *MockRepositoryMockRecorder) SelectShopWebhooks(ctx, shopID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectShopWebhooks", reflect.TypeOf((*MockRepository)(nil).SelectShopWebhooks), ctx, shopID)
+}
+
+// SelectSubscribedWebhooks mocks base method.
+func (m *MockRepository) SelectSubscribedWebhooks(ctx context.Context, shopIDs []uint, eventName string) ([]entity.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectSubscribedWebhooks", ctx, shopIDs, eventName)
+	ret0, _ := ret[0].([]entity.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectSubscribedWebhooks indicates an expected call of SelectSubscribedWebhooks.
+func (mr *MockRepositoryMockRecorder) SelectSubscribedWebhooks(ctx, shopIDs, eventName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectSubscribedWebhooks", reflect.TypeOf((*MockRepository)(nil).SelectSubscribedWebhooks), ctx, shopIDs, eventName)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockRepository) UpdateWebhook(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(entity.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockRepositoryMockRecorder) UpdateWebhook(ctx, webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockRepository)(nil).UpdateWebhook), ctx, webhook)
+}
+
+// MockShopRepository is a mock of ShopRepository interface.
+type MockShopRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockShopRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockShopRepositoryMockRecorder is the mock recorder for MockShopRepository.
+type MockShopRepositoryMockRecorder struct {
+	mock *MockShopRepository
+}
+
+// NewMockShopRepository creates a new mock instance.
+func NewMockShopRepository(ctrl *gomock.Controller) *MockShopRepository {
+	mock := &MockShopRepository{ctrl: ctrl}
+	mock.recorder = &MockShopRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShopRepository) EXPECT() *MockShopRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetMemberRole mocks base method.
+func (m *MockShopRepository) GetMemberRole(ctx context.Context, shopID, userID uint) (entity.ShopRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberRole", ctx, shopID, userID)
+	ret0, _ := ret[0].(entity.ShopRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberRole indicates an expected call of GetMemberRole.
+func (mr *MockShopRepositoryMockRecorder) GetMemberRole(ctx, shopID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberRole", reflect.TypeOf((*MockShopRepository)(nil).GetMemberRole), ctx, shopID, userID)
+}
+
+// GetStoreByID mocks base method.
+func (m *MockShopRepository) GetStoreByID(ctx context.Context, id uint) (entity.Store, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStoreByID", ctx, id)
+	ret0, _ := ret[0].(entity.Store)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStoreByID indicates an expected call of GetStoreByID.
+func (mr *MockShopRepositoryMockRecorder) GetStoreByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStoreByID", reflect.TypeOf((*MockShopRepository)(nil).GetStoreByID), ctx, id)
+}
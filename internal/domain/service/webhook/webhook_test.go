@@ -0,0 +1,375 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/event"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("WebhookService", func() {
+	var (
+		ctrl      *gomock.Controller
+		mockRepo  *MockRepository
+		mockShops *MockShopRepository
+		deliverer *Deliverer
+		service   *Service
+		ctx       context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockShops = NewMockShopRepository(ctrl)
+		// без воркеров: доставки в тестах отправляются напрямую, на httptest сервер в loopback
+		deliverer = NewDeliverer(mockRepo, &config.WebhookConfig{
+			BatchSize: 10, MaxAttempts: 3, PollInterval: time.Hour, Timeout: time.Second,
+			AllowPrivateNetworks: true,
+		}, zap.NewNop())
+		service = NewService(mockRepo, mockShops, deliverer)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		deliverer.Stop(ctx)
+		ctrl.Finish()
+	})
+
+	expectRole := func(role entity.ShopRole) {
+		mockShops.EXPECT().GetStoreByID(ctx, uint(2)).Return(entity.Store{ID: 2}, nil)
+		mockShops.EXPECT().GetMemberRole(ctx, uint(2), uint(5)).Return(role, nil)
+	}
+
+	Describe("CreateWebhook", func() {
+		It("creates an active webhook with a generated secret", func() {
+			expectRole(entity.ShopRoleManager)
+			mockRepo.EXPECT().InsertWebhook(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, w entity.Webhook) (entity.Webhook, error) {
+					w.ID = 1
+					return w, nil
+				})
+
+			webhook, err := service.CreateWebhook(ctx, 2, 5, "https://shop.example.com/hooks",
+				[]string{event.NameOfferCreated, event.NameOfferCreated, event.NameReviewAdded})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(webhook.Active).To(BeTrue())
+			Expect(webhook.Secret).To(HaveLen(2 * secretLength))
+			Expect(webhook.Events).To(Equal([]string{event.NameOfferCreated, event.NameReviewAdded}))
+		})
+
+		It("forbids viewers", func() {
+			expectRole(entity.ShopRoleViewer)
+
+			_, err := service.CreateWebhook(ctx, 2, 5, "https://shop.example.com/hooks",
+				[]string{event.NameOfferCreated})
+
+			Expect(err.(*apperror.Error).Code()).To(Equal(apperror.Forbidden))
+		})
+
+		It("forbids users outside the shop", func() {
+			mockShops.EXPECT().GetStoreByID(ctx, uint(2)).Return(entity.Store{ID: 2}, nil)
+			mockShops.EXPECT().GetMemberRole(ctx, uint(2), uint(5)).
+				Return(entity.ShopRole(""), apperror.ErrShopMemberNotFound)
+
+			_, err := service.CreateWebhook(ctx, 2, 5, "https://shop.example.com/hooks",
+				[]string{event.NameOfferCreated})
+
+			Expect(err.(*apperror.Error).Code()).To(Equal(apperror.Forbidden))
+		})
+
+		DescribeTable("rejects invalid webhooks",
+			func(rawURL string, events []string) {
+				expectRole(entity.ShopRoleOwner)
+
+				_, err := service.CreateWebhook(ctx, 2, 5, rawURL, events)
+
+				Expect(err.(*apperror.Error).Code()).To(Equal(apperror.BadRequest))
+			},
+			Entry("relative url", "/hooks", []string{event.NameOfferCreated}),
+			Entry("unsupported scheme", "ftp://shop.example.com/hooks", []string{event.NameOfferCreated}),
+			Entry("no events", "https://shop.example.com/hooks", nil),
+			Entry("unknown event", "https://shop.example.com/hooks", []string{event.NameUserRegistered}),
+		)
+	})
+
+	Describe("internal addresses", func() {
+		var strict *Deliverer
+
+		BeforeEach(func() {
+			strict = NewDeliverer(mockRepo, &config.WebhookConfig{
+				BatchSize: 10, MaxAttempts: 3, PollInterval: time.Hour, Timeout: time.Second,
+			}, zap.NewNop())
+			service = NewService(mockRepo, mockShops, strict)
+		})
+
+		AfterEach(func() {
+			strict.Stop(ctx)
+		})
+
+		DescribeTable("are rejected when the webhook is created",
+			func(rawURL string) {
+				expectRole(entity.ShopRoleOwner)
+
+				_, err := service.CreateWebhook(ctx, 2, 5, rawURL, []string{event.NameOfferCreated})
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.(*apperror.Error).Code()).To(Equal(apperror.BadRequest))
+			},
+			Entry("loopback", "http://127.0.0.1:8080/hooks"),
+			Entry("localhost", "http://localhost/hooks"),
+			Entry("private network", "http://10.0.0.5/hooks"),
+			Entry("cloud metadata", "http://169.254.169.254/latest/meta-data"),
+			Entry("ipv6 loopback", "http://[::1]/hooks"),
+			Entry("ipv4-mapped ipv6", "http://[::ffff:192.168.1.1]/hooks"),
+			Entry("carrier-grade nat", "http://100.100.100.200/hooks"),
+		)
+
+		It("accepts a public address", func() {
+			expectRole(entity.ShopRoleOwner)
+			mockRepo.EXPECT().InsertWebhook(ctx, gomock.Any()).Return(entity.Webhook{ID: 1}, nil)
+
+			_, err := service.CreateWebhook(ctx, 2, 5, "https://93.184.216.34/hooks", []string{event.NameOfferCreated})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("are refused at dial time without exposing the dial error", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				Fail("request reached an internal address")
+			}))
+			defer server.Close()
+			mockRepo.EXPECT().MarkFailed(gomock.Any(), int64(15), 0, errAddressNotAllowed.Error(), gomock.Any()).
+				Return(nil)
+
+			result := strict.deliver(ctx, entity.PendingWebhookDelivery{
+				WebhookDelivery: entity.WebhookDelivery{ID: 15, WebhookID: 1, Event: EventPing, Attempts: 1},
+				URL:             server.URL,
+				Secret:          "secret",
+			}, 3)
+
+			Expect(result.LastError).To(Equal(errAddressNotAllowed.Error()))
+		})
+	})
+
+	Describe("SubscribeEvents", func() {
+		var bus *event.Bus
+
+		BeforeEach(func() {
+			bus = event.NewBus(zap.NewNop())
+			service.SubscribeEvents(bus)
+		})
+
+		It("queues a delivery for every webhook of the offer's shop", func() {
+			offer := entity.Offer{ID: 7, UserID: 3, ShopID: 2, ProductID: 9, Status: "pending", Price: 10}
+			mockRepo.EXPECT().SelectSubscribedWebhooks(ctx, []uint{2}, event.NameOfferCreated).
+				Return([]entity.Webhook{{ID: 1, ShopID: 2}, {ID: 4, ShopID: 2}}, nil)
+			mockRepo.EXPECT().InsertDeliveries(ctx, nil, gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ any, deliveries []entity.WebhookDelivery) error {
+					Expect(deliveries).To(HaveLen(2))
+					Expect(deliveries[1].WebhookID).To(Equal(uint(4)))
+
+					var payload struct {
+						Event  string    `json:"event"`
+						ShopID uint      `json:"shop_id"`
+						Data   OfferData `json:"data"`
+					}
+					Expect(json.Unmarshal(deliveries[0].Payload, &payload)).To(Succeed())
+					Expect(payload.Event).To(Equal(event.NameOfferCreated))
+					Expect(payload.ShopID).To(Equal(uint(2)))
+					Expect(payload.Data.ID).To(Equal(uint(7)))
+					Expect(payload.Data.BuyerID).To(Equal(uint(3)))
+					return nil
+				})
+
+			Expect(bus.Publish(ctx, nil, event.OfferCreated{Offer: offer})).To(Succeed())
+		})
+
+		It("skips the insert when no webhook is subscribed", func() {
+			mockRepo.EXPECT().SelectSubscribedWebhooks(ctx, []uint{2}, event.NameOfferStatusChanged).Return(nil, nil)
+
+			err := bus.Publish(ctx, nil, event.OfferStatusChanged{Offer: entity.Offer{ShopID: 2}, Expired: true})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("sends product reviews to every shop selling the product", func() {
+			mockRepo.EXPECT().SelectProductShopIDs(ctx, uint(9)).Return([]uint{2, 6}, nil)
+			mockRepo.EXPECT().SelectSubscribedWebhooks(ctx, []uint{2, 6}, event.NameReviewAdded).
+				Return([]entity.Webhook{{ID: 1, ShopID: 6}}, nil)
+			mockRepo.EXPECT().InsertDeliveries(ctx, nil, gomock.Len(1)).Return(nil)
+
+			err := bus.Publish(ctx, nil, event.ReviewAdded{
+				ReviewType: entity.ReviewTypeProduct, ReviewID: 11, TargetID: 9, UserID: 3, Rating: 5,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("sends seller reviews to the reviewed shop", func() {
+			mockRepo.EXPECT().SelectSubscribedWebhooks(ctx, []uint{2}, event.NameReviewAdded).Return(nil, nil)
+
+			err := bus.Publish(ctx, nil, event.ReviewAdded{
+				ReviewType: entity.ReviewTypeSeller, ReviewID: 11, TargetID: 2, UserID: 3, Rating: 4,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("delivery", func() {
+		var (
+			server   *httptest.Server
+			status   int
+			received *http.Request
+			body     []byte
+			pending  entity.PendingWebhookDelivery
+		)
+
+		BeforeEach(func() {
+			status = http.StatusOK
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(status)
+			}))
+			pending = entity.PendingWebhookDelivery{
+				WebhookDelivery: entity.WebhookDelivery{
+					ID: 15, WebhookID: 1, Event: event.NameOfferCreated,
+					Payload: json.RawMessage(`{"event":"offer.created"}`), Attempts: 1,
+				},
+				URL:    server.URL,
+				Secret: "secret",
+			}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("signs the request and marks the delivery as delivered", func() {
+			mockRepo.EXPECT().MarkDelivered(gomock.Any(), int64(15), http.StatusOK).Return(nil)
+
+			result := deliverer.deliver(ctx, pending, 3)
+
+			Expect(result.Status).To(Equal(entity.WebhookDeliveryDelivered))
+			Expect(*result.ResponseStatus).To(Equal(http.StatusOK))
+			Expect(body).To(MatchJSON(pending.Payload))
+			Expect(received.Header.Get(HeaderEvent)).To(Equal(event.NameOfferCreated))
+			Expect(received.Header.Get(HeaderDelivery)).To(Equal("15"))
+
+			var timestamp int64
+			var signature string
+			parts := strings.SplitN(received.Header.Get(HeaderSignature), ",", 2)
+			Expect(parts).To(HaveLen(2))
+			Expect(json.Unmarshal([]byte(strings.TrimPrefix(parts[0], "t=")), &timestamp)).To(Succeed())
+			signature = strings.TrimPrefix(parts[1], "v1=")
+			Expect(signature).To(Equal(Sign("secret", timestamp, body)))
+		})
+
+		It("schedules a retry with backoff on an error response", func() {
+			status = http.StatusInternalServerError
+			pending.Attempts = 2
+			mockRepo.EXPECT().MarkFailed(gomock.Any(), int64(15), http.StatusInternalServerError, gomock.Any(),
+				gomock.Not(gomock.Nil())).
+				DoAndReturn(func(_ context.Context, _ int64, _ int, _ string, retryAt *time.Time) error {
+					Expect(*retryAt).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+					return nil
+				})
+
+			result := deliverer.deliver(ctx, pending, 3)
+
+			Expect(result.Status).To(BeEmpty())
+			Expect(result.LastError).To(ContainSubstring("500"))
+		})
+
+		It("fails the delivery after the last attempt", func() {
+			status = http.StatusGone
+			pending.Attempts = 3
+			mockRepo.EXPECT().MarkFailed(gomock.Any(), int64(15), http.StatusGone, gomock.Any(), gomock.Nil()).
+				Return(nil)
+
+			result := deliverer.deliver(ctx, pending, 3)
+
+			Expect(result.Status).To(Equal(entity.WebhookDeliveryFailed))
+		})
+
+		It("does not expose connection errors in the delivery log", func() {
+			server.Close()
+			mockRepo.EXPECT().MarkFailed(gomock.Any(), int64(15), 0, "webhook request failed", gomock.Any()).
+				Return(nil)
+
+			result := deliverer.deliver(ctx, pending, 3)
+
+			Expect(result.LastError).To(Equal("webhook request failed"))
+		})
+
+		It("does not follow redirects", func() {
+			status = http.StatusFound
+			mockRepo.EXPECT().MarkFailed(gomock.Any(), int64(15), http.StatusFound, gomock.Any(), gomock.Any()).
+				Return(nil)
+
+			deliverer.deliver(ctx, pending, 3)
+		})
+
+		It("pings the webhook immediately without retrying", func() {
+			status = http.StatusServiceUnavailable
+			expectRole(entity.ShopRoleOwner)
+			mockRepo.EXPECT().GetWebhook(ctx, uint(2), uint(1)).
+				Return(entity.Webhook{ID: 1, ShopID: 2, URL: server.URL, Secret: "secret"}, nil)
+			mockRepo.EXPECT().CreateClaimedDelivery(ctx, gomock.Any(), sendLease).DoAndReturn(
+				func(_ context.Context, d entity.WebhookDelivery, _ time.Duration) (entity.WebhookDelivery, error) {
+					Expect(d.Event).To(Equal(EventPing))
+					d.ID = 20
+					d.Attempts = 1
+					return d, nil
+				})
+			mockRepo.EXPECT().
+				MarkFailed(gomock.Any(), int64(20), http.StatusServiceUnavailable, gomock.Any(), gomock.Nil()).
+				Return(nil)
+
+			result, err := service.Ping(ctx, 2, 5, 1)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Status).To(Equal(entity.WebhookDeliveryFailed))
+			Expect(received.Header.Get(HeaderEvent)).To(Equal(EventPing))
+		})
+
+		It("drains claimed deliveries when woken", func() {
+			done := make(chan struct{})
+			gomock.InOrder(
+				// первая выборка при старте воркера
+				mockRepo.EXPECT().ClaimDeliveries(gomock.Any(), 10, sendLease).Return(nil, nil),
+				mockRepo.EXPECT().ClaimDeliveries(gomock.Any(), 10, sendLease).
+					Return([]entity.PendingWebhookDelivery{pending}, nil),
+				mockRepo.EXPECT().MarkDelivered(gomock.Any(), int64(15), http.StatusOK).Return(nil),
+				mockRepo.EXPECT().ClaimDeliveries(gomock.Any(), 10, sendLease).
+					DoAndReturn(func(context.Context, int, time.Duration) ([]entity.PendingWebhookDelivery, error) {
+						close(done)
+						return nil, nil
+					}),
+			)
+
+			worker := NewDeliverer(mockRepo, &config.WebhookConfig{
+				WorkerPool: 1, BatchSize: 10, MaxAttempts: 3, PollInterval: time.Hour, Timeout: time.Second,
+				AllowPrivateNetworks: true,
+			}, zap.NewNop())
+			worker.Wake()
+
+			Eventually(done).Should(BeClosed())
+			worker.Stop(ctx)
+		})
+	})
+})
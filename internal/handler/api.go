@@ -13,8 +13,10 @@ import (
 	"github.com/go-playground/validator/v10"
 	"golang.org/x/text/currency"
 
+	"github.com/EM-Stawberry/Stawberry/config"
 	// Импорт сваггер-генератора
 	"github.com/EM-Stawberry/Stawberry/docs"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/reviews"
 	"github.com/EM-Stawberry/Stawberry/pkg/database"
@@ -35,11 +37,17 @@ import (
 func SetupRouter(
 	healthH *HealthHandler,
 	productH *ProductHandler,
+	categoryH *CategoryHandler,
+	productImageH *ProductImageHandler,
+	storeH *StoreHandler,
+	webhookH *WebhookHandler,
 	offerH *OfferHandler,
 	userH *UserHandler,
 	notificationH *NotificationHandler,
 	productReviewH *reviews.ProductReviewsHandler,
 	sellerReviewH *reviews.SellerReviewsHandler,
+	reviewModerationH *reviews.ReviewModerationHandler,
+	reviewInteractionH *reviews.ReviewInteractionHandler,
 	guestOfferH *guesthandler.Handler,
 	userS middleware.UserGetter,
 	tokenS middleware.TokenValidator,
@@ -47,6 +55,10 @@ func SetupRouter(
 	logger *zap.Logger,
 	auditMiddleware *middleware.AuditMiddleware,
 	auditH *AuditHandler,
+	emailTemplateH *EmailTemplateHandler,
+	emailOutboxH *EmailOutboxHandler,
+	mailboxH *MailboxHandler,
+	environment string,
 ) *gin.Engine {
 	router := gin.New()
 
@@ -58,7 +70,8 @@ func SetupRouter(
 	router.Use(middleware.ZapRecovery(logger))
 	router.Use(middleware.CORS())
 	router.Use(middleware.Errors())
-	router.Use(middleware.Timeout())
+	// поток уведомлений живет, пока клиент подключен, таймаут запроса к нему не применяется
+	router.Use(middleware.Timeout(basePath + "/notifications/stream"))
 
 	// Swagger UI эндпоинт
 	docs.SwaggerInfo.BasePath = basePath
@@ -72,6 +85,8 @@ func SetupRouter(
 
 	// secured это эндпойнты, которые не сработают без авторизационного токера
 	secured := public.Group("/").Use(middleware.AuthMiddleware(userS, tokenS))
+	// verified ставится на secured эндпойнты, доступные только с подтвержденной почтой
+	verified := middleware.RequireVerifiedEmail()
 
 	// healtcheck эндпойнты
 	{
@@ -80,6 +95,12 @@ func SetupRouter(
 		secured.GET("/auth_required", healthH.authCheck)
 	}
 
+	// перехваченные письма, mailboxH есть только в dev-режиме с транспортом memory
+	if mailboxH != nil {
+		base.GET("/dev/mailbox", mailboxH.GetMailbox)
+		base.DELETE("/dev/mailbox", mailboxH.ClearMailbox)
+	}
+
 	// эндпойнты регистрации-авторизации
 	auth := public.Group("/auth")
 	{
@@ -87,11 +108,53 @@ func SetupRouter(
 		auth.POST("/login", userH.Login)
 		auth.POST("/logout", userH.Logout)
 		auth.POST("/refresh", userH.Refresh)
+		auth.POST("/password/forgot", userH.ForgotPassword)
+		auth.POST("/password/reset", userH.ResetPassword)
+		// ссылка из письма открывается без авторизации, пользователя определяет токен
+		// GET только показывает подтверждение, почту подтверждает POST
+		auth.GET("/email/verify", userH.VerifyEmailPage)
+		auth.POST("/email/verify", userH.VerifyEmail)
+		secured.POST("/auth/email/resend", userH.ResendVerification)
+	}
+
+	// эндпойнты профиля
+	{
+		secured.GET("/me", userH.GetProfile)
+		secured.PATCH("/me", userH.UpdateProfile)
+		secured.PUT("/me/email", userH.ChangeEmail)
+		secured.PUT("/me/password", userH.ChangePassword)
+		secured.DELETE("/me", userH.DeleteAccount)
 	}
 	// эндпойнты для продуктов
 	{
 		public.GET("/products", productH.GetProducts)
 		public.GET("/products/:id", productH.GetProductByID)
+		secured.POST("/products/:id/images", productImageH.PostProductImage)
+		secured.DELETE("/products/:id/images/:imageID", productImageH.DeleteProductImage)
+	}
+
+	// эндпойнты категорий
+	{
+		public.GET("/categories/:id/attributes", categoryH.GetAttributeSchema)
+	}
+
+	// эндпойнты магазинов
+	{
+		public.GET("/shops", storeH.GetStores)
+		public.GET("/shops/:id", storeH.GetStore)
+		secured.POST("/shops", storeH.PostStore)
+		secured.PUT("/shops/:id", storeH.PutStore)
+		secured.GET("/shops/:id/members", storeH.GetMembers)
+		secured.PUT("/shops/:id/members/:userID", storeH.PutMember)
+		secured.DELETE("/shops/:id/members/:userID", storeH.DeleteMember)
+		secured.POST("/shops/:id/invitations", storeH.PostInvitation)
+		secured.POST("/shops/invitations/accept", storeH.AcceptInvitation)
+		secured.GET("/shops/:id/webhooks", webhookH.GetWebhooks)
+		secured.POST("/shops/:id/webhooks", webhookH.PostWebhook)
+		secured.PUT("/shops/:id/webhooks/:webhookID", webhookH.PutWebhook)
+		secured.DELETE("/shops/:id/webhooks/:webhookID", webhookH.DeleteWebhook)
+		secured.GET("/shops/:id/webhooks/:webhookID/deliveries", webhookH.GetDeliveries)
+		secured.POST("/shops/:id/webhooks/:webhookID/ping", webhookH.PingWebhook)
 	}
 
 	// эндпойнты для гостевых заявок
@@ -103,30 +166,85 @@ func SetupRouter(
 	{
 		secured.PATCH("offers/:offerID", offerH.PatchOfferStatus)
 		secured.GET("offers", offerH.GetUserOffers)
-		secured.POST("offers", offerH.PostOffer)
+		secured.POST("offers", verified, offerH.PostOffer)
+	}
+
+	// эндпойнты уведомлений
+	{
+		secured.GET("/notifications", notificationH.GetNotifications)
+		secured.GET("/notifications/unread-count", notificationH.GetUnreadCount)
+		secured.PATCH("/notifications/:id/read", notificationH.MarkRead)
+		secured.POST("/notifications/read-all", notificationH.MarkAllRead)
+		secured.GET("/notifications/preferences", notificationH.GetPreferences)
+		secured.PUT("/notifications/preferences", notificationH.UpdatePreferences)
+		// ссылка из письма открывается без авторизации, пользователя определяет подписанный токен.
+		// GET только показывает подтверждение, отписывает POST
+		public.GET("/notifications/unsubscribe", notificationH.UnsubscribePage)
+		public.POST("/notifications/unsubscribe", notificationH.Unsubscribe)
+		// EventSource не умеет передавать заголовки, поэтому токен принимается и в query
+		public.GET("/notifications/stream",
+			middleware.TokenFromQuery("access_token"),
+			middleware.AuthMiddleware(userS, tokenS),
+			notificationH.StreamNotifications)
 	}
 
 	// эндпойнты отзывов
 	{
 		public.GET("/products/:id/reviews", productReviewH.GetReviews)
 		public.GET("/sellers/:id/reviews", sellerReviewH.GetReviews)
-		secured.POST("/products/:id/reviews", productReviewH.AddReview)
-		secured.POST("/sellers/:id/reviews", sellerReviewH.AddReview)
+		secured.POST("/products/:id/reviews", verified, productReviewH.AddReview)
+		secured.POST("/sellers/:id/reviews", verified, sellerReviewH.AddReview)
+		secured.PUT("/products/:id/reviews/:reviewID", verified, productReviewH.UpdateReview)
+		secured.DELETE("/products/:id/reviews/:reviewID", productReviewH.DeleteReview)
+		secured.POST("/products/:id/reviews/:reviewID/photos", verified, productReviewH.AddPhoto)
+		secured.DELETE("/products/:id/reviews/:reviewID/photos/:photoID", productReviewH.DeletePhoto)
+		secured.PUT("/sellers/:id/reviews/:reviewID", verified, sellerReviewH.UpdateReview)
+		secured.DELETE("/sellers/:id/reviews/:reviewID", sellerReviewH.DeleteReview)
+		secured.POST("/products/:id/reviews/:reviewID/report", reviewModerationH.ReportProductReview)
+		secured.POST("/sellers/:id/reviews/:reviewID/report", reviewModerationH.ReportSellerReview)
+		secured.PUT("/products/:id/reviews/:reviewID/reply", reviewInteractionH.ReplyToProductReview)
+		secured.DELETE("/products/:id/reviews/:reviewID/reply", reviewInteractionH.DeleteProductReviewReply)
+		secured.PUT("/sellers/:id/reviews/:reviewID/reply", reviewInteractionH.ReplyToSellerReview)
+		secured.DELETE("/sellers/:id/reviews/:reviewID/reply", reviewInteractionH.DeleteSellerReviewReply)
+		secured.POST("/products/:id/reviews/:reviewID/vote", reviewInteractionH.VoteProductReview)
+		secured.DELETE("/products/:id/reviews/:reviewID/vote", reviewInteractionH.RetractProductReviewVote)
+		secured.POST("/sellers/:id/reviews/:reviewID/vote", reviewInteractionH.VoteSellerReview)
+		secured.DELETE("/sellers/:id/reviews/:reviewID/vote", reviewInteractionH.RetractSellerReviewVote)
 	}
 
-	// заглушка эндпоинта админа
-	// admin := secured.Group("/admin", middleware.Admin)
-	secured.GET("/audit", auditH.DisplayLogs)
-
-	// Эндпоинты для бд
+	// эндпойнты администратора, роль проверяется по базе на каждый запрос
+	adminOnly := []gin.HandlerFunc{
+		middleware.AuthMiddleware(userS, tokenS),
+		middleware.RequireRole(entity.UserRoleAdmin),
+	}
+	admin := public.Group("/admin", adminOnly...)
 	{
-		secured.POST("/dev/seed-db", seedDB)
-		secured.POST("/dev/clear-db", clearDB)
+		admin.POST("/products", productH.PostProduct)
+		admin.PUT("/products/:id", productH.PutProduct)
+		admin.POST("/products/:id/variants", productH.PostVariant)
+		admin.PUT("/products/:id/variants/:variantID", productH.PutVariant)
+		admin.POST("/categories/:id/attributes", categoryH.PostAttributeSchema)
+		admin.DELETE("/categories/:id/attributes/:name", categoryH.DeleteAttributeSchema)
+		admin.GET("/reviews/reports", reviewModerationH.GetModerationQueue)
+		admin.POST("/reviews/:type/:reviewID/hide", reviewModerationH.HideReview)
+		admin.POST("/reviews/:type/:reviewID/restore", reviewModerationH.RestoreReview)
+		admin.POST("/users/:id/review-ban", reviewModerationH.BanReviewer)
+		admin.POST("/users/:id/review-unban", reviewModerationH.UnbanReviewer)
+		admin.GET("/emails/templates", emailTemplateH.GetTemplates)
+		admin.GET("/emails/templates/:name/preview", emailTemplateH.PreviewTemplate)
+		admin.GET("/emails/outbox", emailOutboxH.GetOutbox)
+		admin.POST("/emails/outbox/:id/retry", emailOutboxH.RetryOutboxMessage)
 	}
 
-	// Эти заглушки можно убрать после реализации соответствующих хендлеров
-	_ = productH
-	_ = notificationH
+	// журнал аудита содержит действия всех пользователей, путь сохранен для совместимости
+	public.Group("/audit", adminOnly...).GET("", auditH.DisplayLogs)
+
+	// Эндпоинты для бд, пересоздают все таблицы, поэтому есть только в dev-режиме
+	if environment == config.EnvDev {
+		dev := public.Group("/dev", adminOnly...)
+		dev.POST("/seed-db", seedDB)
+		dev.POST("/clear-db", clearDB)
+	}
 
 	return router
 }
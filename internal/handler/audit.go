@@ -47,9 +47,11 @@ func NewAuditHandler(as AuditService) *AuditHandler {
 // @Param limit query integer false "Items per page (default 100)" minimum(1) maximum(500)
 // @Param page query integer false "Page number (default 1)" minimum(1)
 // @Success 200 {object} map[string]interface{} "Returns paginated audit logs"
+// @Security BearerAuth
 // @Failure 400 {object} apperror.AppError "Invalid request parameters"
+// @Failure 403 {object} apperror.AppError "Admin role required"
 // @Failure 500 {object} apperror.AppError "Internal server error"
-// @Router /audit/logs [get]
+// @Router /audit [get]
 func (h *AuditHandler) DisplayLogs(c *gin.Context) {
 	params, err := parseAuditQueryParams(c)
 	if err != nil {
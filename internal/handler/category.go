@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryService interface {
+	GetAttributeSchema(ctx context.Context, categoryID int) ([]entity.AttributeSchema, error)
+	SaveAttributeSchema(ctx context.Context, schema entity.AttributeSchema) (entity.AttributeSchema, error)
+	DeleteAttributeSchema(ctx context.Context, categoryID int, name string) error
+}
+
+type CategoryHandler struct {
+	categoryService CategoryService
+}
+
+func NewCategoryHandler(categoryService CategoryService) *CategoryHandler {
+	return &CategoryHandler{categoryService: categoryService}
+}
+
+// GetAttributeSchema godoc
+// @Summary      Получить схему атрибутов категории
+// @Description  Возвращает атрибуты категории, включая унаследованные от родительских категорий
+// @Tags         categories
+// @Produce      json
+// @Param        id          path      int   true   "ID категории"
+// @Param        filterable  query     bool  false  "Вернуть только атрибуты, по которым можно фильтровать"
+// @Success      200         {array}   entity.AttributeSchema
+// @Failure      400         {object}  apperror.Error "Некорректный ID"
+// @Failure      404         {object}  apperror.Error "Категория не найдена"
+// @Router       /categories/{id}/attributes [get]
+func (h *CategoryHandler) GetAttributeSchema(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || categoryID < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid category id", err))
+		return
+	}
+
+	schemas, err := h.categoryService.GetAttributeSchema(c.Request.Context(), categoryID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if filterable, _ := strconv.ParseBool(c.Query("filterable")); filterable {
+		filtered := make([]entity.AttributeSchema, 0, len(schemas))
+		for _, schema := range schemas {
+			if schema.Filterable {
+				filtered = append(filtered, schema)
+			}
+		}
+		schemas = filtered
+	}
+
+	c.JSON(http.StatusOK, schemas)
+}
+
+// PostAttributeSchema godoc
+// @Summary      Добавить атрибут в схему категории
+// @Description  Создает атрибут категории или заменяет существующий с тем же именем
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                         true  "ID категории"
+// @Param        body  body      dto.PostAttributeSchemaReq  true  "Описание атрибута"
+// @Security     BearerAuth
+// @Success      200   {object}  entity.AttributeSchema
+// @Failure      400   {object}  apperror.Error "Некорректные данные"
+// @Failure      403   {object}  apperror.Error "Недостаточно прав"
+// @Failure      404   {object}  apperror.Error "Категория не найдена"
+// @Router       /admin/categories/{id}/attributes [post]
+func (h *CategoryHandler) PostAttributeSchema(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || categoryID < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid category id", err))
+		return
+	}
+
+	var req dto.PostAttributeSchemaReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid attribute schema", err))
+		return
+	}
+
+	schema, err := h.categoryService.SaveAttributeSchema(c.Request.Context(), req.ConvertToEntity(categoryID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schema)
+}
+
+// DeleteAttributeSchema godoc
+// @Summary      Удалить атрибут из схемы категории
+// @Tags         categories
+// @Param        id    path  int     true  "ID категории"
+// @Param        name  path  string  true  "Имя атрибута"
+// @Security     BearerAuth
+// @Success      204
+// @Failure      400   {object}  apperror.Error "Некорректный ID"
+// @Failure      403   {object}  apperror.Error "Недостаточно прав"
+// @Failure      404   {object}  apperror.Error "Атрибут не найден"
+// @Router       /admin/categories/{id}/attributes/{name} [delete]
+func (h *CategoryHandler) DeleteAttributeSchema(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || categoryID < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid category id", err))
+		return
+	}
+
+	if err := h.categoryService.DeleteAttributeSchema(c.Request.Context(), categoryID, c.Param("name")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// confirmationPage страница для ссылок из писем. GET по ссылке только показывает форму,
+// а действие выполняет POST: ссылки открывают почтовые сканеры и предзагрузка браузера
+type confirmationPage struct {
+	Title    string
+	Question string
+	Button   string
+	Done     string
+}
+
+type confirmationPageData struct {
+	confirmationPage
+	Token    string
+	Finished bool
+}
+
+var confirmationTemplate = template.Must(template.New("confirmation").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Stawberry: {{.Title}}</title></head>
+<body>
+{{- if .Finished}}
+<p>{{.Done}}</p>
+{{- else}}
+<p>{{.Question}}</p>
+<form method="post" action="?token={{.Token}}">
+<button type="submit">{{.Button}}</button>
+</form>
+{{- end}}
+</body>
+</html>
+`))
+
+// renderForm показывает форму, которая отправляет токен из ссылки POST запросом
+func (p confirmationPage) renderForm(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		_ = c.Error(apperror.New(apperror.BadRequest, "token is required", nil))
+		return
+	}
+	p.render(c, confirmationPageData{confirmationPage: p, Token: token})
+}
+
+// renderDone показывает результат после отправки формы
+func (p confirmationPage) renderDone(c *gin.Context) {
+	p.render(c, confirmationPageData{confirmationPage: p, Finished: true})
+}
+
+func (p confirmationPage) render(c *gin.Context, data confirmationPageData) {
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	if err := confirmationTemplate.Execute(c.Writer, data); err != nil {
+		_ = c.Error(apperror.New(apperror.InternalError, "failed to render confirmation page", err))
+	}
+}
+
+// wantsHTML форма со страницы подтверждения ждет страницу, API клиенты и почтовые клиенты - JSON
+func wantsHTML(c *gin.Context) bool {
+	return c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) == binding.MIMEHTML
+}
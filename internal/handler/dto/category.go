@@ -0,0 +1,24 @@
+package dto
+
+import "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+
+type PostAttributeSchemaReq struct {
+	Name       string   `json:"name" binding:"required,max=100"`
+	Type       string   `json:"type" binding:"required,oneof=string number boolean enum"`
+	Unit       string   `json:"unit" binding:"max=50"`
+	EnumValues []string `json:"enum_values"`
+	Required   bool     `json:"required"`
+	Filterable bool     `json:"filterable"`
+}
+
+func (pa *PostAttributeSchemaReq) ConvertToEntity(categoryID int) entity.AttributeSchema {
+	return entity.AttributeSchema{
+		CategoryID: categoryID,
+		Name:       pa.Name,
+		Type:       pa.Type,
+		Unit:       pa.Unit,
+		EnumValues: pa.EnumValues,
+		Required:   pa.Required,
+		Filterable: pa.Filterable,
+	}
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "github.com/EM-Stawberry/Stawberry/pkg/email"
+
+type EmailTemplatesResp struct {
+	Templates []string `json:"templates"`
+	Locales   []string `json:"locales"`
+}
+
+type GetOutboxMessagesResp struct {
+	Data []email.OutboxMessage `json:"data"`
+	Meta PaginationMeta        `json:"meta"`
+}
@@ -1,10 +1,49 @@
 package dto
 
-// пока не нужно, но может понадобиться
-//type GetNotificationReq struct{}
-//
-//type GetNotificationResp struct{}
-//
-//func (gn *GetNotificationReq) ConvertToSvc() notification.Notification {
-//	return notification.Notification{}
-//}
+import "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+
+type PaginationMeta struct {
+	CurrentPage int `json:"current_page"`
+	PerPage     int `json:"per_page"`
+	TotalItems  int `json:"total_items"`
+	TotalPages  int `json:"total_pages"`
+}
+
+type GetNotificationsResp struct {
+	Data []entity.Notification `json:"data"`
+	Meta PaginationMeta        `json:"meta"`
+}
+
+type UnreadCountResp struct {
+	Count int `json:"count"`
+}
+
+type ReadAllNotificationsResp struct {
+	Updated int `json:"updated"`
+}
+
+type NotificationPreferencesResp struct {
+	Preferences []entity.NotificationPreference `json:"preferences"`
+}
+
+type UpdateNotificationPreferencesReq struct {
+	Preferences []NotificationPreferenceReq `json:"preferences" binding:"required,min=1,dive"`
+}
+
+type NotificationPreferenceReq struct {
+	Event   string `json:"event" binding:"required"`
+	Channel string `json:"channel" binding:"required"`
+	Enabled *bool  `json:"enabled" binding:"required"`
+}
+
+func (r *UpdateNotificationPreferencesReq) ConvertToEntity() []entity.NotificationPreference {
+	preferences := make([]entity.NotificationPreference, len(r.Preferences))
+	for i, p := range r.Preferences {
+		preferences[i] = entity.NotificationPreference{Event: p.Event, Channel: p.Channel, Enabled: *p.Enabled}
+	}
+	return preferences
+}
+
+type UnsubscribeResp struct {
+	Message string `json:"message"`
+}
@@ -7,7 +7,7 @@ import (
 )
 
 type PostOfferReq struct {
-	ProductID uint    `json:"product_id" binding:"required"`
+	VariantID uint    `json:"variant_id" binding:"required"`
 	ShopID    uint    `json:"shop_id" binding:"required"`
 	Price     float64 `json:"price" binding:"required,gte=0"`
 	Currency  string  `json:"currency" binding:"required,iso4217"`
@@ -22,7 +22,7 @@ func (po *PostOfferReq) ConvertToEntity() entity.Offer {
 		Price:     po.Price,
 		Currency:  po.Currency,
 		ShopID:    po.ShopID,
-		ProductID: po.ProductID,
+		VariantID: po.VariantID,
 	}
 }
 
@@ -57,6 +57,7 @@ type OfferResp struct {
 	ExpiresAt time.Time
 	ShopID    uint
 	ProductID uint
+	VariantID uint
 }
 
 type GetUserOffersResp struct {
@@ -82,6 +83,7 @@ func FormUserOffers(ofrs []entity.Offer, page, limit, total, totalPages int) Get
 			ExpiresAt: ofr.ExpiresAt,
 			ShopID:    ofr.ShopID,
 			ProductID: ofr.ProductID,
+			VariantID: ofr.VariantID,
 		})
 	}
 
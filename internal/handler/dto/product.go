@@ -1,30 +1,47 @@
 package dto
 
-import "github.com/EM-Stawberry/Stawberry/internal/domain/service/product"
+import (
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/product"
+)
 
+// PostProductReq используется и для создания, и для полной замены продукта
 type PostProductReq struct {
-	StoreID     uint    `json:"store_id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Category    string  `json:"category"`
-	InStock     bool    `json:"in_stock"`
+	Name        string                 `json:"name" binding:"required"`
+	Description string                 `json:"description"`
+	CategoryID  int                    `json:"category_id" binding:"required,gt=0"`
+	Attributes  map[string]interface{} `json:"attributes"`
+}
+
+type PostVariantReq struct {
+	SKU        string                 `json:"sku" binding:"required,max=64"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+type PostVariantResp struct {
+	ID int `json:"id"`
+}
+
+func (pv *PostVariantReq) ConvertToEntity(productID int) entity.ProductVariant {
+	return entity.ProductVariant{
+		ProductID:  productID,
+		SKU:        pv.SKU,
+		Attributes: pv.Attributes,
+	}
 }
 
 type PostProductResp struct {
-	ID uint `json:"id"`
+	ID int `json:"id"`
 }
 
-/* func (pp *PostProductReq) ConvertToSvc() product.Product {
-	return product.Product{
-		StoreID:     pp.StoreID,
+func (pp *PostProductReq) ConvertToEntity() entity.Product {
+	return entity.Product{
 		Name:        pp.Name,
 		Description: pp.Description,
-		Price:       pp.Price,
-		Category:    pp.Category,
-		InStock:     pp.InStock,
+		CategoryID:  pp.CategoryID,
+		Attributes:  pp.Attributes,
 	}
-} */
+}
 
 type PatchProductReq struct {
 	StoreID     *uint    `json:"store_id,omitempty"`
@@ -0,0 +1,43 @@
+package dto
+
+import "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+
+// PostStoreReq используется и для создания, и для обновления профиля магазина
+type PostStoreReq struct {
+	Name         string            `json:"name" binding:"required,max=255"`
+	Description  string            `json:"description" binding:"max=5000"`
+	LogoURL      string            `json:"logo_url" binding:"omitempty,url,max=500"`
+	ContactEmail string            `json:"contact_email" binding:"omitempty,email,max=255"`
+	ContactPhone string            `json:"contact_phone" binding:"max=50"`
+	Address      string            `json:"address" binding:"max=1000"`
+	WorkingHours map[string]string `json:"working_hours"`
+}
+
+type PostStoreResp struct {
+	ID uint `json:"id"`
+}
+
+func (ps *PostStoreReq) ConvertToEntity() entity.Store {
+	return entity.Store{
+		Name:         ps.Name,
+		Description:  ps.Description,
+		LogoURL:      ps.LogoURL,
+		ContactEmail: ps.ContactEmail,
+		ContactPhone: ps.ContactPhone,
+		Address:      ps.Address,
+		WorkingHours: ps.WorkingHours,
+	}
+}
+
+type PostShopInvitationReq struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+	Role  string `json:"role" binding:"required,oneof=manager viewer"`
+}
+
+type AcceptShopInvitationReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type PutShopMemberReq struct {
+	Role string `json:"role" binding:"required,oneof=manager viewer"`
+}
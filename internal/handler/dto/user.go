@@ -1,6 +1,9 @@
 package dto
 
 import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
 )
 
@@ -10,6 +13,8 @@ type RegistrationUserReq struct {
 	Email       string `json:"email" binding:"required"`
 	Phone       string `json:"phone" binding:"required"`
 	Fingerprint string `json:"fingerprint" binding:"required"`
+	// Locale язык писем, по умолчанию en
+	Locale string `json:"locale" binding:"omitempty,oneof=en ru"`
 }
 
 type RegistrationUserResp struct {
@@ -23,6 +28,7 @@ func (ru *RegistrationUserReq) ConvertToSvc() user.User {
 		Password: ru.Password,
 		Email:    ru.Email,
 		Phone:    ru.Phone,
+		Locale:   ru.Locale,
 	}
 }
 
@@ -51,3 +57,66 @@ type LogoutReq struct {
 	RefreshToken string `json:"refresh_token,omitempty"`
 	Fingerprint  string `json:"fingerprint" validate:"required"`
 }
+
+type ForgotPasswordReq struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordReq struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8,max=256"`
+}
+
+type VerifyEmailResp struct {
+	Message string `json:"message"`
+}
+
+type UserProfileResp struct {
+	ID              uint       `json:"id"`
+	Name            string     `json:"name"`
+	Email           string     `json:"email"`
+	Phone           string     `json:"phone"`
+	IsStore         bool       `json:"is_store"`
+	Role            string     `json:"role"`
+	EmailVerified   bool       `json:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+}
+
+func UserProfileFromEntity(u entity.User) UserProfileResp {
+	return UserProfileResp{
+		ID:              u.ID,
+		Name:            u.Name,
+		Email:           u.Email,
+		Phone:           u.Phone,
+		IsStore:         u.IsStore,
+		Role:            string(u.Role),
+		EmailVerified:   u.EmailVerified(),
+		EmailVerifiedAt: u.EmailVerifiedAt,
+	}
+}
+
+type UpdateProfileReq struct {
+	Name  *string `json:"name" binding:"omitempty,min=1,max=255"`
+	Phone *string `json:"phone" binding:"omitempty,min=1,max=20"`
+}
+
+func (r *UpdateProfileReq) ConvertToSvc() user.UpdateProfile {
+	return user.UpdateProfile{
+		Name:  r.Name,
+		Phone: r.Phone,
+	}
+}
+
+type ChangeEmailReq struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type ChangePasswordReq struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=256"`
+}
+
+type DeleteAccountReq struct {
+	Password string `json:"password" binding:"required"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+
+type PostWebhookReq struct {
+	URL    string   `json:"url" binding:"required,url,max=2000"`
+	Events []string `json:"events" binding:"required,min=1"`
+}
+
+type PutWebhookReq struct {
+	URL    string   `json:"url" binding:"required,url,max=2000"`
+	Events []string `json:"events" binding:"required,min=1"`
+	Active *bool    `json:"active" binding:"required"`
+}
+
+func (pw *PutWebhookReq) ConvertToEntity(shopID, webhookID uint) entity.Webhook {
+	return entity.Webhook{
+		ID:     webhookID,
+		ShopID: shopID,
+		URL:    pw.URL,
+		Events: pw.Events,
+		Active: *pw.Active,
+	}
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+	"github.com/gin-gonic/gin"
+)
+
+type EmailTemplates interface {
+	Names() []string
+	Preview(name, locale string) (email.Rendered, error)
+}
+
+type EmailTemplateHandler struct {
+	templates EmailTemplates
+}
+
+func NewEmailTemplateHandler(templates EmailTemplates) *EmailTemplateHandler {
+	return &EmailTemplateHandler{templates: templates}
+}
+
+// GetTemplates godoc
+// @Summary      Шаблоны писем
+// @Description  Возвращает имена шаблонов писем и поддерживаемые языки
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.EmailTemplatesResp
+// @Failure      401  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Router       /admin/emails/templates [get]
+func (h *EmailTemplateHandler) GetTemplates(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.EmailTemplatesResp{
+		Templates: h.templates.Names(),
+		Locales:   email.Locales,
+	})
+}
+
+// PreviewTemplate godoc
+// @Summary      Предпросмотр письма
+// @Description  Собирает письмо по шаблону на примере данных. С format=html возвращает HTML для просмотра в браузере
+// @Tags         admin
+// @Produce      json
+// @Produce      html
+// @Param        name    path      string  true   "Имя шаблона"
+// @Param        locale  query     string  false  "Язык: en или ru"    default(en)
+// @Param        format  query     string  false  "json, html или text" default(json)
+// @Security     BearerAuth
+// @Success      200     {object}  email.Rendered
+// @Failure      400     {object}  apperror.Error
+// @Failure      401     {object}  apperror.Error
+// @Failure      403     {object}  apperror.Error
+// @Failure      404     {object}  apperror.Error "Шаблон не найден"
+// @Failure      500     {object}  apperror.Error
+// @Router       /admin/emails/templates/{name}/preview [get]
+func (h *EmailTemplateHandler) PreviewTemplate(c *gin.Context) {
+	name := c.Param("name")
+	if !slices.Contains(h.templates.Names(), name) {
+		_ = c.Error(apperror.New(apperror.NotFound, "email template not found", nil))
+		return
+	}
+
+	locale := c.DefaultQuery("locale", email.DefaultLocale)
+	if !slices.Contains(email.Locales, locale) {
+		_ = c.Error(apperror.New(apperror.BadRequest, "unsupported locale", nil))
+		return
+	}
+
+	rendered, err := h.templates.Preview(name, locale)
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.InternalError, "failed to render email template", err))
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		c.JSON(http.StatusOK, rendered)
+	case "html":
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(rendered.HTML))
+	case "text":
+		c.String(http.StatusOK, rendered.Text)
+	default:
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid format value (must be json, html or text)", nil))
+	}
+}
+
+type EmailOutboxService interface {
+	GetMessages(ctx context.Context, status string, page, limit int) ([]email.OutboxMessage, int, error)
+	Retry(ctx context.Context, id int64) (email.OutboxMessage, error)
+}
+
+type EmailOutboxHandler struct {
+	outboxService EmailOutboxService
+}
+
+func NewEmailOutboxHandler(outboxService EmailOutboxService) *EmailOutboxHandler {
+	return &EmailOutboxHandler{outboxService: outboxService}
+}
+
+// GetOutbox godoc
+// @Summary      Исходящие письма
+// @Description  Возвращает письма из outbox с указанным статусом, по умолчанию неотправленные (dead)
+// @Tags         admin
+// @Produce      json
+// @Param        status  query     string  false  "pending, sent или dead" default(dead)
+// @Param        page    query     int     false  "Номер страницы"         default(1)
+// @Param        limit   query     int     false  "Размер страницы"        default(10)
+// @Security     BearerAuth
+// @Success      200     {object}  dto.GetOutboxMessagesResp
+// @Failure      400     {object}  apperror.Error
+// @Failure      401     {object}  apperror.Error
+// @Failure      403     {object}  apperror.Error
+// @Failure      500     {object}  apperror.Error
+// @Router       /admin/emails/outbox [get]
+func (h *EmailOutboxHandler) GetOutbox(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid page number", err))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid limit value (must be 1-100)", err))
+		return
+	}
+
+	msgs, total, err := h.outboxService.GetMessages(
+		c.Request.Context(), c.DefaultQuery("status", email.StatusDead), page, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetOutboxMessagesResp{
+		Data: msgs,
+		Meta: dto.PaginationMeta{
+			CurrentPage: page,
+			PerPage:     limit,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(limit))),
+		},
+	})
+}
+
+// RetryOutboxMessage godoc
+// @Summary      Повторить отправку письма
+// @Description  Возвращает неотправленное письмо в очередь со сброшенным счетчиком попыток
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      int  true  "ID письма"
+// @Security     BearerAuth
+// @Success      200  {object}  email.OutboxMessage
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error "Письмо не найдено"
+// @Failure      409  {object}  apperror.Error "Письмо уже отправлено или содержит одноразовый токен"
+// @Failure      500  {object}  apperror.Error
+// @Router       /admin/emails/outbox/{id}/retry [post]
+func (h *EmailOutboxHandler) RetryOutboxMessage(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid email id", err))
+		return
+	}
+
+	msg, err := h.outboxService.Retry(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, msg)
+}
@@ -1,13 +1,21 @@
 package helpers
 
-import "github.com/gin-gonic/gin"
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/gin-gonic/gin"
+)
 
 const (
 	UserIDKey      = "userID"
 	UserIsStoreKey = "userIsStore"
 	UserIsAdminKey = "userIsAdmin"
+	UserRoleKey    = "userRole"
+	UserVerified   = "userEmailVerified"
 	UserName       = "userName"
 	UserEmail      = "userEmail"
+	TokenExpiresAt = "tokenExpiresAt"
 )
 
 func UserIDContext(c *gin.Context) (uint, bool) {
@@ -34,6 +42,30 @@ func UserIsAdminContext(c *gin.Context) (bool, bool) {
 	return isAdminValue, true
 }
 
+func UserRoleContext(c *gin.Context) (entity.UserRole, bool) {
+	role, exists := c.Get(UserRoleKey)
+	if !exists {
+		return "", false
+	}
+	roleValue, ok := role.(entity.UserRole)
+	if !ok {
+		return "", false
+	}
+	return roleValue, true
+}
+
+func UserVerifiedContext(c *gin.Context) (bool, bool) {
+	verified, exists := c.Get(UserVerified)
+	if !exists {
+		return false, false
+	}
+	verifiedValue, ok := verified.(bool)
+	if !ok {
+		return false, false
+	}
+	return verifiedValue, true
+}
+
 func UserIsStoreContext(c *gin.Context) (bool, bool) {
 	isStore, exists := c.Get(UserIsStoreKey)
 	if !exists {
@@ -69,3 +101,16 @@ func UserEmailContext(c *gin.Context) (string, bool) {
 	}
 	return emailValue, true
 }
+
+// TokenExpiresAtContext возвращает время истечения access token текущего запроса
+func TokenExpiresAtContext(c *gin.Context) (time.Time, bool) {
+	expiresAt, exists := c.Get(TokenExpiresAt)
+	if !exists {
+		return time.Time{}, false
+	}
+	expiresAtValue, ok := expiresAt.(time.Time)
+	if !ok {
+		return time.Time{}, false
+	}
+	return expiresAtValue, true
+}
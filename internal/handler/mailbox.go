@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+	"github.com/gin-gonic/gin"
+)
+
+type Mailbox interface {
+	Messages(to string) []email.CapturedMessage
+	Clear()
+}
+
+// MailboxHandler показывает письма, перехваченные транспортом memory. Регистрируется только в dev-режиме
+type MailboxHandler struct {
+	mailbox Mailbox
+}
+
+func NewMailboxHandler(mailbox Mailbox) *MailboxHandler {
+	return &MailboxHandler{mailbox: mailbox}
+}
+
+// GetMailbox godoc
+// @Summary      Перехваченные письма
+// @Description  Возвращает письма, отправленные через транспорт memory. Доступно только в dev-режиме
+// @Tags         dev
+// @Produce      json
+// @Param        to   query     string  false  "Адрес получателя"
+// @Success      200  {array}   email.CapturedMessage
+// @Router       /dev/mailbox [get]
+func (h *MailboxHandler) GetMailbox(c *gin.Context) {
+	c.JSON(http.StatusOK, h.mailbox.Messages(c.Query("to")))
+}
+
+// ClearMailbox godoc
+// @Summary      Очистить перехваченные письма
+// @Description  Удаляет все письма, перехваченные транспортом memory. Доступно только в dev-режиме
+// @Tags         dev
+// @Success      204
+// @Router       /dev/mailbox [delete]
+func (h *MailboxHandler) ClearMailbox(c *gin.Context) {
+	h.mailbox.Clear()
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole пропускает запрос дальше, только если роль пользователя входит в roles.
+// Должен стоять после AuthMiddleware.
+func RequireRole(roles ...entity.UserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, ok := helpers.UserRoleContext(c)
+		if !ok {
+			_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+			c.Abort()
+			return
+		}
+		if !slices.Contains(roles, role) {
+			_ = c.Error(apperror.New(apperror.Forbidden, "insufficient permissions", nil))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -224,15 +224,11 @@ func (am *AuditMiddleware) Middleware() gin.HandlerFunc {
 }
 
 func getRole(c *gin.Context) string {
-	isStore, _ := helpers.UserIsStoreContext(c)
-	if isStore {
-		return "shop"
+	role, ok := helpers.UserRoleContext(c)
+	if !ok {
+		return string(entity.UserRoleUser)
 	}
-	isAdmin, _ := helpers.UserIsAdminContext(c)
-	if isAdmin {
-		return "admin"
-	}
-	return "user"
+	return string(role)
 }
 
 func sanitizeSensitiveData(data map[string]interface{}) {
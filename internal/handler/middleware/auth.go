@@ -60,8 +60,10 @@ func AuthMiddleware(userGetter UserGetter, validator TokenValidator) gin.Handler
 		c.Set(helpers.UserIsStoreKey, user.IsStore)
 		c.Set(helpers.UserName, user.Name)
 		c.Set(helpers.UserEmail, user.Email)
-
-		c.Set(helpers.UserIsAdminKey, false)
+		c.Set(helpers.UserRoleKey, user.Role)
+		c.Set(helpers.UserIsAdminKey, user.Role == entity.UserRoleAdmin)
+		c.Set(helpers.UserVerified, user.EmailVerified())
+		c.Set(helpers.TokenExpiresAt, access.ExpiresAt)
 		c.Next()
 	}
 }
@@ -43,7 +43,7 @@ func Errors() gin.HandlerFunc {
 
 func errorStatus(code string) int {
 	switch code {
-	case apperror.NotFound:
+	case apperror.NotFound, apperror.ReviewNotFound:
 		return http.StatusNotFound
 	case apperror.DatabaseError:
 		return http.StatusInternalServerError
@@ -57,6 +57,18 @@ func errorStatus(code string) int {
 		return http.StatusConflict
 	case apperror.Forbidden:
 		return http.StatusForbidden
+	case apperror.TooManyRequests:
+		return http.StatusTooManyRequests
+	case apperror.ReviewDuplicate, apperror.ReviewHidden:
+		return http.StatusConflict
+	case apperror.ReviewUnauthorized, apperror.ReviewAuthorBanned, apperror.ReviewEditExpired:
+		return http.StatusForbidden
+	case apperror.ReviewDealRequired:
+		return http.StatusUnprocessableEntity
+	case apperror.ReviewInvalid:
+		return http.StatusBadRequest
+	case apperror.ReviewDatabaseError:
+		return http.StatusInternalServerError
 	case apperror.InternalError:
 		fallthrough
 
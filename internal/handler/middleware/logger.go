@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
@@ -133,12 +134,39 @@ func SetupGinWithZap(logger *zap.Logger) {
 	gin.DefaultErrorWriter = &zapWriter{logger: logger.WithOptions(zap.IncreaseLevel(zapcore.ErrorLevel))}
 }
 
+// secretQueryParams query parameters that carry credentials: the SSE access token
+// and the one-time tokens from email links
+var secretQueryParams = []string{"access_token", "token"}
+
+// redactQuery hides credential values so they never reach the logs
+func redactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		// an unparsable query may still contain a token, so it is not logged at all
+		return "[unparsable query]"
+	}
+	redacted := false
+	for _, param := range secretQueryParams {
+		if values.Has(param) {
+			values.Set(param, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return rawQuery
+	}
+	return values.Encode()
+}
+
 // ZapLogger returns a gin.HandlerFunc middleware that logs requests using Zap
 func ZapLogger(logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
-		query := c.Request.URL.RawQuery
+		query := redactQuery(c.Request.URL.RawQuery)
 
 		c.Next()
 
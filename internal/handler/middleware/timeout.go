@@ -11,8 +11,20 @@ import (
 // change this to change request timeout
 const to = 10 * time.Second
 
-func Timeout() gin.HandlerFunc {
+// Timeout ограничивает время обработки запроса. Маршруты из longLived (например, потоки событий)
+// держат соединение открытым и под ограничение не попадают
+func Timeout(longLived ...string) gin.HandlerFunc {
+	skip := make(map[string]struct{}, len(longLived))
+	for _, path := range longLived {
+		skip[path] = struct{}{}
+	}
+
 	return func(c *gin.Context) {
+		if _, ok := skip[c.FullPath()]; ok {
+			c.Next()
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), to)
 		defer cancel()
 
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// TokenFromQuery берет access token из параметра запроса, если нет заголовка Authorization.
+// Нужен для EventSource в браузере, который не умеет передавать заголовки.
+// Подключается только к отдельным маршрутам перед AuthMiddleware
+func TokenFromQuery(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(authorizationHeader) == "" {
+			if token := c.Query(param); token != "" {
+				c.Request.Header.Set(authorizationHeader, bearerSchema+" "+token)
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireVerifiedEmail пропускает запрос дальше, только если пользователь подтвердил почту.
+// Должен стоять после AuthMiddleware.
+func RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		verified, ok := helpers.UserVerifiedContext(c)
+		if !ok {
+			_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+			c.Abort()
+			return
+		}
+		if !verified {
+			_ = c.Error(apperror.ErrEmailNotVerified)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
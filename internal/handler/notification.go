@@ -1,73 +1,396 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"math"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
 	"github.com/gin-gonic/gin"
 )
 
+var unsubscribePage = confirmationPage{
+	Title:    "unsubscribe",
+	Question: "Stop receiving these emails from Stawberry?",
+	Button:   "Unsubscribe",
+	Done:     "You have been unsubscribed from these emails.",
+}
+
 type NotificationService interface {
-	GetNotification(id string, offset int, limit int) ([]entity.Notification, int, error)
+	GetUserNotifications(
+		ctx context.Context, userID uint, unreadOnly bool, page, limit int,
+	) ([]entity.Notification, int, error)
+	CountUnread(ctx context.Context, userID uint) (int, error)
+	MarkRead(ctx context.Context, userID uint, notificationID uint) error
+	MarkAllRead(ctx context.Context, userID uint) (int, error)
+	Stream(ctx context.Context, userID uint, lastEventID uint) (<-chan entity.Notification, bool, error)
+	GetPreferences(ctx context.Context, userID uint) ([]entity.NotificationPreference, error)
+	UpdatePreferences(
+		ctx context.Context, userID uint, preferences []entity.NotificationPreference,
+	) ([]entity.NotificationPreference, error)
+	Unsubscribe(ctx context.Context, token string) error
 }
 
+const (
+	// heartbeatInterval период комментариев-пингов, чтобы прокси не закрывали простаивающий поток
+	heartbeatInterval = 15 * time.Second
+	// reconnectDelayMs через сколько клиент должен переподключиться после обрыва
+	reconnectDelayMs = 3000
+)
+
 type NotificationHandler struct {
-	offerService NotificationService
+	notificationService NotificationService
 }
 
 func NewNotificationHandler(notificationService NotificationService) *NotificationHandler {
-	return &NotificationHandler{offerService: notificationService}
+	return &NotificationHandler{notificationService: notificationService}
 }
 
-// GetNotification обработчик уведомлений
-// получает все уведомления (одобрение или неодобрение заявки) авторизированного пользователя
-func (h *NotificationHandler) GetNotification(c *gin.Context) {
-	// сделать user, ok := c.Get("user")
-	// сейчас в контекст кладется userID, в будущем структура user
-	userID, ok := c.Get("userID")
+// GetNotifications godoc
+// @Summary      Уведомления пользователя
+// @Description  Возвращает уведомления авторизованного пользователя о событиях его офферов, новые первыми
+// @Tags         notifications
+// @Produce      json
+// @Param        page    query     int   false  "Номер страницы"            default(1)
+// @Param        limit   query     int   false  "Размер страницы (1-100)"   default(10)
+// @Param        unread  query     bool  false  "Только непрочитанные"
+// @Security     BearerAuth
+// @Success      200     {object}  dto.GetNotificationsResp
+// @Failure      400     {object}  apperror.Error
+// @Failure      401     {object}  apperror.Error
+// @Failure      500     {object}  apperror.Error
+// @Router       /notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
 	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid userID from context"})
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
 		return
 	}
 
 	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
 	if err != nil || page < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page number"})
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid page number", err))
 		return
 	}
 
 	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	if err != nil || limit < 1 || limit > 100 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit value"})
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid limit value (must be 1-100)", err))
+		return
+	}
+
+	unreadOnly, err := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid unread value", err))
+		return
+	}
+
+	notifications, total, err := h.notificationService.GetUserNotifications(
+		c.Request.Context(), userID, unreadOnly, page, limit)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	offset := (page - 1) * limit
+	c.JSON(http.StatusOK, dto.GetNotificationsResp{
+		Data: notifications,
+		Meta: dto.PaginationMeta{
+			CurrentPage: page,
+			PerPage:     limit,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(limit))),
+		},
+	})
+}
 
-	// исправить вызов h.offerService.GetNotification()
-	// сейчас в контекст кладется userID, в будущем структура user
-	uid, ok := userID.(uint)
+// GetUnreadCount godoc
+// @Summary      Число непрочитанных уведомлений
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.UnreadCountResp
+// @Failure      401  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
 	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
 		return
 	}
-	notifications, total, err := h.offerService.GetNotification(strconv.FormatUint(uint64(uid), 10), offset, limit)
+
+	count, err := h.notificationService.CountUnread(c.Request.Context(), userID)
 	if err != nil {
 		_ = c.Error(err)
 		return
 	}
 
-	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	c.JSON(http.StatusOK, dto.UnreadCountResp{Count: count})
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": notifications,
-		"meta": gin.H{
-			"current_page": page,
-			"per_page":     limit,
-			"total_items":  total,
-			"total_pages":  totalPages,
-		},
-	})
+// MarkRead godoc
+// @Summary      Отметить уведомление прочитанным
+// @Tags         notifications
+// @Produce      json
+// @Param        id   path      int  true  "ID уведомления"
+// @Security     BearerAuth
+// @Success      204
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error "Уведомление не найдено"
+// @Failure      500  {object}  apperror.Error
+// @Router       /notifications/{id}/read [patch]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid notification id", err))
+		return
+	}
+
+	if err := h.notificationService.MarkRead(c.Request.Context(), userID, uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MarkAllRead godoc
+// @Summary      Отметить все уведомления прочитанными
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.ReadAllNotificationsResp
+// @Failure      401  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	updated, err := h.notificationService.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ReadAllNotificationsResp{Updated: updated})
+}
+
+// StreamNotifications godoc
+// @Summary      Поток уведомлений
+// @Description  Server-Sent Events с новыми уведомлениями пользователя. ID события равен ID уведомления,
+// @Description  при переподключении с заголовком Last-Event-ID досылаются пропущенные уведомления.
+// @Description  Если пропущено больше 100, вместо них приходит событие resync, и клиент перечитывает
+// @Description  уведомления через GET /notifications.
+// @Description  Поток закрывается событием token_expired, когда истекает access token, и при остановке сервера.
+// @Description  Для EventSource токен можно передать в параметре access_token
+// @Tags         notifications
+// @Produce      text/event-stream
+// @Param        Last-Event-ID  header    int     false  "ID последнего полученного уведомления"
+// @Param        access_token   query     string  false  "Access token, если нельзя передать заголовок"
+// @Security     BearerAuth
+// @Success      200  {string}  string  "Поток событий notification"
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Router       /notifications/stream [get]
+func (h *NotificationHandler) StreamNotifications(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	var lastEventID uint64
+	if raw := c.GetHeader("Last-Event-ID"); raw != "" {
+		var err error
+		lastEventID, err = strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			_ = c.Error(apperror.New(apperror.BadRequest, "invalid Last-Event-ID", err))
+			return
+		}
+	}
+
+	// пользователь проверяется только при подключении, поэтому поток живет не дольше токена:
+	// за это время аккаунт могли удалить или сменить пароль
+	expiresAt, ok := helpers.TokenExpiresAtContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	ctx := c.Request.Context()
+	events, resync, err := h.notificationService.Stream(ctx, userID, uint(lastEventID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	_, _ = fmt.Fprintf(c.Writer, "retry: %d\n\n", reconnectDelayMs)
+	if resync {
+		// пропущенных уведомлений слишком много, клиент перечитывает их через GET /notifications
+		_, _ = fmt.Fprint(c.Writer, "event: resync\ndata: {}\n\n")
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	expired := time.NewTimer(time.Until(expiresAt))
+	defer expired.Stop()
+
+	for {
+		select {
+		case n, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(n)
+			if err != nil {
+				return
+			}
+			_, err = fmt.Fprintf(c.Writer, "id: %d\nevent: notification\ndata: %s\n\n", n.ID, data)
+			if err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case <-expired.C:
+			// клиент переподключается с новым токеном
+			_, _ = fmt.Fprint(c.Writer, "event: token_expired\ndata: {}\n\n")
+			c.Writer.Flush()
+			return
+		case <-ctx.Done():
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+// GetPreferences godoc
+// @Summary      Настройки уведомлений
+// @Description  Возвращает для каждого типа событий, включен ли канал доставки: в приложении, email, вебхук
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.NotificationPreferencesResp
+// @Failure      401  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	preferences, err := h.notificationService.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NotificationPreferencesResp{Preferences: preferences})
+}
+
+// UpdatePreferences godoc
+// @Summary      Изменить настройки уведомлений
+// @Description  Меняет только переданные настройки, возвращает все настройки пользователя
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.UpdateNotificationPreferencesReq  true  "Настройки"
+// @Security     BearerAuth
+// @Success      200      {object}  dto.NotificationPreferencesResp
+// @Failure      400      {object}  apperror.Error
+// @Failure      401      {object}  apperror.Error
+// @Failure      500      {object}  apperror.Error
+// @Router       /notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	var req dto.UpdateNotificationPreferencesReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid notification preferences", err))
+		return
+	}
+
+	preferences, err := h.notificationService.UpdatePreferences(c.Request.Context(), userID, req.ConvertToEntity())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NotificationPreferencesResp{Preferences: preferences})
+}
+
+// UnsubscribePage godoc
+// @Summary      Страница отписки от писем
+// @Description  Ссылка из письма. Только показывает форму подтверждения, настройки не меняет:
+// @Description  GET по ссылке могут выполнить почтовые сканеры и предзагрузка браузера
+// @Tags         notifications
+// @Produce      html
+// @Param        token  query     string  true  "Токен из ссылки"
+// @Success      200    {string}  string  "Страница с формой подтверждения"
+// @Failure      400    {object}  apperror.Error
+// @Router       /notifications/unsubscribe [get]
+func (h *NotificationHandler) UnsubscribePage(c *gin.Context) {
+	unsubscribePage.renderForm(c)
+}
+
+// Unsubscribe godoc
+// @Summary      Отписаться от писем
+// @Description  Отключает письма о том типе событий, о котором было письмо. Вызывается формой
+// @Description  со страницы отписки и почтовым клиентом при отписке в один клик (RFC 8058)
+// @Tags         notifications
+// @Produce      json,html
+// @Param        token  query     string  true  "Токен из ссылки"
+// @Success      200    {object}  dto.UnsubscribeResp
+// @Failure      400    {object}  apperror.Error
+// @Failure      401    {object}  apperror.Error "Неверный токен"
+// @Failure      500    {object}  apperror.Error
+// @Router       /notifications/unsubscribe [post]
+func (h *NotificationHandler) Unsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		_ = c.Error(apperror.New(apperror.BadRequest, "token is required", nil))
+		return
+	}
+
+	if err := h.notificationService.Unsubscribe(c.Request.Context(), token); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if wantsHTML(c) {
+		unsubscribePage.renderDone(c)
+		return
+	}
+	c.JSON(http.StatusOK, dto.UnsubscribeResp{Message: "you have been unsubscribed from these emails"})
 }
@@ -18,7 +18,7 @@ import (
 )
 
 type OfferService interface {
-	CreateOffer(ctx context.Context, offer entity.Offer, usr entity.User) (uint, error)
+	CreateOffer(ctx context.Context, offer entity.Offer) (uint, error)
 	GetUserOffers(ctx context.Context, userID uint, page, limit int) ([]entity.Offer, int, error)
 	GetOffer(ctx context.Context, offerID uint) (entity.Offer, error)
 	UpdateOfferStatus(ctx context.Context, offer entity.Offer, userID uint, isStore bool) (entity.Offer, error)
@@ -42,6 +42,8 @@ func NewOfferHandler(offerService OfferService) *OfferHandler {
 // @failure 400 {object} apperror.Error
 // @failure 401 {object} apperror.Error
 // @failure 403 {object} apperror.Error
+// @failure 404 {object} apperror.Error
+// @failure 409 {object} apperror.Error
 // @failure 500 {object} apperror.Error
 // @Router /offers [post]
 func (h *OfferHandler) PostOffer(c *gin.Context) {
@@ -68,27 +70,12 @@ func (h *OfferHandler) PostOffer(c *gin.Context) {
 		return
 	}
 
-	var usr entity.User
-	usr.Name, ok = helpers.UserNameContext(c)
-	if !ok {
-		_ = c.Error(apperror.New(apperror.InternalError,
-			"user name key not found in ctx", nil))
-		return
-	}
-
-	usr.Email, ok = helpers.UserEmailContext(c)
-	if !ok {
-		_ = c.Error(apperror.New(apperror.InternalError,
-			"user email key not found in ctx", nil))
-		return
-	}
-
 	offerEnt := offerPost.ConvertToEntity()
 	offerEnt.UserID = userID
 
-	offerID, err := h.offerService.CreateOffer(c.Request.Context(), offerEnt, usr)
+	offerID, err := h.offerService.CreateOffer(c.Request.Context(), offerEnt)
 	if err != nil {
-		_ = c.Error(apperror.New(apperror.InternalError, "Failed to create offer", err))
+		_ = c.Error(err)
 		return
 	}
 
@@ -10,6 +10,7 @@ import (
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
 
 	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
 
@@ -19,6 +20,10 @@ import (
 type ProductService interface {
 	GetFilteredProducts(ctx context.Context, filter model.ProductFilter, limit, offset int) ([]entity.Product, int, error)
 	GetProductByID(ctx context.Context, id string) (entity.Product, error)
+	CreateProduct(ctx context.Context, product entity.Product) (int, error)
+	UpdateProduct(ctx context.Context, product entity.Product) error
+	CreateVariant(ctx context.Context, variant entity.ProductVariant) (int, error)
+	UpdateVariant(ctx context.Context, variant entity.ProductVariant) error
 }
 
 type ProductHandler struct {
@@ -31,7 +36,7 @@ func NewProductHandler(productService ProductService) *ProductHandler {
 
 // GetProductByID godoc
 // @Summary      Получить продукт по его ID
-// @Description  Возвращает один продукт по его идентификатору
+// @Description  Возвращает один продукт по его идентификатору вместе с матрицей вариантов
 // @Tags         products
 // @Param        id   path      int  true  "ID продукта"
 // @Success      200  {object}  entity.Product
@@ -135,3 +140,148 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 		},
 	})
 }
+
+// PostProduct godoc
+// @Summary      Создать продукт
+// @Description  Создает продукт, атрибуты проверяются по схеме категории
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.PostProductReq  true  "Данные продукта"
+// @Security     BearerAuth
+// @Success      201   {object}  dto.PostProductResp
+// @Failure      400   {object}  apperror.Error "Некорректные данные или атрибуты"
+// @Failure      403   {object}  apperror.Error "Недостаточно прав"
+// @Failure      404   {object}  apperror.Error "Категория не найдена"
+// @Router       /admin/products [post]
+func (h *ProductHandler) PostProduct(c *gin.Context) {
+	var req dto.PostProductReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid product data", err))
+		return
+	}
+
+	id, err := h.productService.CreateProduct(c.Request.Context(), req.ConvertToEntity())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.PostProductResp{ID: id})
+}
+
+// PutProduct godoc
+// @Summary      Обновить продукт
+// @Description  Полностью заменяет данные и атрибуты продукта, атрибуты проверяются по схеме категории
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                 true  "ID продукта"
+// @Param        body  body      dto.PostProductReq  true  "Данные продукта"
+// @Security     BearerAuth
+// @Success      204
+// @Failure      400   {object}  apperror.Error "Некорректные данные или атрибуты"
+// @Failure      403   {object}  apperror.Error "Недостаточно прав"
+// @Failure      404   {object}  apperror.Error "Продукт или категория не найдены"
+// @Router       /admin/products/{id} [put]
+func (h *ProductHandler) PutProduct(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid product id", err))
+		return
+	}
+
+	var req dto.PostProductReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid product data", err))
+		return
+	}
+
+	product := req.ConvertToEntity()
+	product.ID = id
+
+	if err := h.productService.UpdateProduct(c.Request.Context(), product); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PostVariant godoc
+// @Summary      Добавить вариант продукта
+// @Description  Создает вариант продукта со своим SKU, атрибуты варианта проверяются по схеме категории
+// @Tags         products
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                 true  "ID продукта"
+// @Param        body  body      dto.PostVariantReq  true  "Данные варианта"
+// @Security     BearerAuth
+// @Success      201   {object}  dto.PostVariantResp
+// @Failure      400   {object}  apperror.Error "Некорректные данные или атрибуты"
+// @Failure      404   {object}  apperror.Error "Продукт не найден"
+// @Failure      409   {object}  apperror.Error "SKU уже занят"
+// @Router       /admin/products/{id}/variants [post]
+func (h *ProductHandler) PostVariant(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || productID < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid product id", err))
+		return
+	}
+
+	var req dto.PostVariantReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid variant data", err))
+		return
+	}
+
+	id, err := h.productService.CreateVariant(c.Request.Context(), req.ConvertToEntity(productID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.PostVariantResp{ID: id})
+}
+
+// PutVariant godoc
+// @Summary      Обновить вариант продукта
+// @Tags         products
+// @Accept       json
+// @Param        id         path  int                 true  "ID продукта"
+// @Param        variantID  path  int                 true  "ID варианта"
+// @Param        body       body  dto.PostVariantReq  true  "Данные варианта"
+// @Security     BearerAuth
+// @Success      204
+// @Failure      400  {object}  apperror.Error "Некорректные данные или атрибуты"
+// @Failure      404  {object}  apperror.Error "Вариант не найден"
+// @Failure      409  {object}  apperror.Error "SKU уже занят"
+// @Router       /admin/products/{id}/variants/{variantID} [put]
+func (h *ProductHandler) PutVariant(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || productID < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid product id", err))
+		return
+	}
+	variantID, err := strconv.Atoi(c.Param("variantID"))
+	if err != nil || variantID < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid variant id", err))
+		return
+	}
+
+	var req dto.PostVariantReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid variant data", err))
+		return
+	}
+
+	variant := req.ConvertToEntity(productID)
+	variant.ID = variantID
+
+	if err := h.productService.UpdateVariant(c.Request.Context(), variant); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead запас на заголовки multipart-формы сверх размера самого файла
+const multipartOverhead = 64 << 10
+
+type ProductImageService interface {
+	UploadProductImage(ctx context.Context, userID uint, productID int, data []byte) (entity.ProductImage, error)
+	DeleteProductImage(ctx context.Context, userID uint, productID int, imageID int) error
+}
+
+type ProductImageHandler struct {
+	imageService ProductImageService
+	maxSize      int64
+}
+
+func NewProductImageHandler(imageService ProductImageService, maxSize int64) *ProductImageHandler {
+	return &ProductImageHandler{imageService: imageService, maxSize: maxSize}
+}
+
+// PostProductImage godoc
+// @Summary      Загрузить изображение продукта
+// @Description  Загружает изображение (jpeg, png, gif) и создает превью. Доступно владельцу магазина с этим продуктом
+// @Tags         products
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id     path      int   true  "ID продукта"
+// @Param        image  formData  file  true  "Файл изображения"
+// @Security     BearerAuth
+// @Success      201    {object}  entity.ProductImage
+// @Failure      400    {object}  apperror.Error "Некорректный файл"
+// @Failure      403    {object}  apperror.Error "Продукт не продается в магазине пользователя"
+// @Router       /products/{id}/images [post]
+func (h *ProductImageHandler) PostProductImage(c *gin.Context) {
+	userID, ok := storeOwnerID(c)
+	if !ok {
+		return
+	}
+
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || productID < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid product id", err))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "image file is required", err))
+		return
+	}
+	if fileHeader.Size > h.maxSize {
+		_ = c.Error(apperror.New(apperror.BadRequest, "image is too large", nil))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "failed to read image", err))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, h.maxSize+1))
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "failed to read image", err))
+		return
+	}
+
+	image, err := h.imageService.UploadProductImage(c.Request.Context(), userID, productID, data)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, image)
+}
+
+// DeleteProductImage godoc
+// @Summary      Удалить изображение продукта
+// @Tags         products
+// @Param        id       path  int  true  "ID продукта"
+// @Param        imageID  path  int  true  "ID изображения"
+// @Security     BearerAuth
+// @Success      204
+// @Failure      400      {object}  apperror.Error "Некорректный ID"
+// @Failure      403      {object}  apperror.Error "Продукт не продается в магазине пользователя"
+// @Failure      404      {object}  apperror.Error "Изображение не найдено"
+// @Router       /products/{id}/images/{imageID} [delete]
+func (h *ProductImageHandler) DeleteProductImage(c *gin.Context) {
+	userID, ok := storeOwnerID(c)
+	if !ok {
+		return
+	}
+
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || productID < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid product id", err))
+		return
+	}
+	imageID, err := strconv.Atoi(c.Param("imageID"))
+	if err != nil || imageID < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid image id", err))
+		return
+	}
+
+	if err := h.imageService.DeleteProductImage(c.Request.Context(), userID, productID, imageID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,97 @@
+package reviews_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// stubTokenValidator accepts tokens of the form "token-<userID>".
+type stubTokenValidator struct{}
+
+func (stubTokenValidator) ValidateToken(_ context.Context, token string) (entity.AccessToken, error) {
+	var userID uint
+	if _, err := fmt.Sscanf(token, "token-%d", &userID); err != nil {
+		return entity.AccessToken{}, apperror.ErrInvalidToken
+	}
+	return entity.AccessToken{UserID: userID}, nil
+}
+
+// stubUserGetter knows every user except the deleted one. Only adminUserID has the admin role,
+// and every user except unverifiedUserID has a verified email.
+type stubUserGetter struct{}
+
+const (
+	deletedUserID    = 404
+	adminUserID      = 100
+	unverifiedUserID = 403
+)
+
+func (stubUserGetter) GetUserByID(_ context.Context, id uint) (entity.User, error) {
+	if id == deletedUserID {
+		return entity.User{}, apperror.ErrUserNotFound
+	}
+	role := entity.UserRoleUser
+	if id == adminUserID {
+		role = entity.UserRoleAdmin
+	}
+	user := entity.User{ID: id, Name: "user", Email: "user@example.com", Role: role}
+	if id != unverifiedUserID {
+		verifiedAt := time.Now()
+		user.EmailVerifiedAt = &verifiedAt
+	}
+	return user, nil
+}
+
+// newReviewsRouter builds a router with the shared error middleware and a secured group
+// behind the real AuthMiddleware, the same way SetupRouter does.
+func newReviewsRouter() (*gin.Engine, gin.IRoutes) {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(middleware.Errors())
+	secured := router.Group("/").Use(middleware.AuthMiddleware(stubUserGetter{}, stubTokenValidator{}))
+	return router, secured
+}
+
+// adminGroup adds a group behind the admin role check, like the admin group in SetupRouter.
+func adminGroup(router *gin.Engine, prefix string) *gin.RouterGroup {
+	return router.Group(prefix,
+		middleware.AuthMiddleware(stubUserGetter{}, stubTokenValidator{}),
+		middleware.RequireRole(entity.UserRoleAdmin))
+}
+
+// serve sends the request as the given user, anonymously when userID is 0.
+// Strings are sent as a raw body, anything else is encoded as JSON.
+func serve(router *gin.Engine, method, path string, body any, userID uint) *httptest.ResponseRecorder {
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = bytes.NewBufferString(b)
+	default:
+		raw, _ := json.Marshal(b)
+		reader = bytes.NewBuffer(raw)
+	}
+
+	req, _ := http.NewRequest(method, path, reader)
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if userID != 0 {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer token-%d", userID))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
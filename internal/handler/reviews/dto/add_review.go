@@ -4,3 +4,16 @@ type AddReviewDTO struct {
 	Rating int    `json:"rating" binding:"required,min=1,max=5"`
 	Review string `json:"review" binding:"required"`
 }
+
+// AddSellerReviewDTO is a review about a shop left after an accepted offer.
+type AddSellerReviewDTO struct {
+	OfferID int    `json:"offer_id" binding:"required,min=1"`
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Review  string `json:"review" binding:"required"`
+}
+
+// UpdateReviewDTO replaces the rating and text of an existing review.
+type UpdateReviewDTO struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Review string `json:"review" binding:"required"`
+}
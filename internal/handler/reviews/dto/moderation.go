@@ -0,0 +1,22 @@
+package dto
+
+// ReportReviewDTO is a complaint about a review.
+type ReportReviewDTO struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+// ModerationDTO carries the reason a moderator gives for a decision.
+type ModerationDTO struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+// ReplyDTO is a shop's answer to a review. ShopID is required only for product reviews.
+type ReplyDTO struct {
+	ShopID int    `json:"shop_id" binding:"omitempty,min=1"`
+	Body   string `json:"body" binding:"required,max=2000"`
+}
+
+// VoteDTO marks a review as helpful or not helpful.
+type VoteDTO struct {
+	Helpful *bool `json:"helpful" binding:"required"`
+}
@@ -0,0 +1,315 @@
+package reviews
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/reviews/dto"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ReviewInteractionService interface {
+	ReplyToReview(
+		ctx context.Context, reviewType string, targetID int, reviewID int, shopID int, userID int, body string,
+	) (entity.ReviewReply, bool, error)
+	DeleteReply(ctx context.Context, reviewType string, targetID int, reviewID int, userID int) error
+	VoteReview(
+		ctx context.Context, reviewType string, targetID int, reviewID int, userID int, helpful bool,
+	) error
+	RetractVote(ctx context.Context, reviewType string, targetID int, reviewID int, userID int) error
+}
+
+type ReviewInteractionHandler struct {
+	ris    ReviewInteractionService
+	logger *zap.Logger
+}
+
+func NewReviewInteractionHandler(ris ReviewInteractionService, l *zap.Logger) *ReviewInteractionHandler {
+	return &ReviewInteractionHandler{
+		ris:    ris,
+		logger: l,
+	}
+}
+
+// ReplyToProductReview godoc
+// @Summary Ответ магазина на отзыв о продукте
+// @Description Публикует или изменяет ответ магазина, который продает продукт. На отзыв возможен один ответ
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reviewID path int true "Review ID"
+// @Param reply body dto.ReplyDTO true "Магазин и текст ответа"
+// @Security BearerAuth
+// @Success 201 {object} entity.ReviewReply "Ответ опубликован"
+// @Success 200 {object} entity.ReviewReply "Ответ изменен"
+// @Failure 400 {object} map[string]string "Некорректный ввод"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Пользователь не управляет магазином или магазин не продает продукт"
+// @Failure 404 {object} map[string]string "Отзыв не найден"
+// @Failure 409 {object} map[string]string "На отзыв уже ответил другой магазин или отзыв скрыт"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /products/{id}/reviews/{reviewID}/reply [put]
+func (h *ReviewInteractionHandler) ReplyToProductReview(c *gin.Context) {
+	h.replyToReview(c, entity.ReviewTypeProduct)
+}
+
+// ReplyToSellerReview godoc
+// @Summary Ответ магазина на отзыв о продавце
+// @Description Публикует или изменяет ответ магазина на отзыв о нем. На отзыв возможен один ответ
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Param reviewID path int true "Review ID"
+// @Param reply body dto.ReplyDTO true "Текст ответа"
+// @Security BearerAuth
+// @Success 201 {object} entity.ReviewReply "Ответ опубликован"
+// @Success 200 {object} entity.ReviewReply "Ответ изменен"
+// @Failure 400 {object} map[string]string "Некорректный ввод"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Пользователь не управляет магазином"
+// @Failure 404 {object} map[string]string "Отзыв не найден"
+// @Failure 409 {object} map[string]string "Отзыв скрыт"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /sellers/{id}/reviews/{reviewID}/reply [put]
+func (h *ReviewInteractionHandler) ReplyToSellerReview(c *gin.Context) {
+	h.replyToReview(c, entity.ReviewTypeSeller)
+}
+
+func (h *ReviewInteractionHandler) replyToReview(c *gin.Context, reviewType string) {
+	const op = "reviewInteractionHandler.replyToReview()"
+	log := h.logger.With(zap.String("op", op))
+
+	targetID, reviewID, ok := parseReviewPath(c)
+	if !ok {
+		return
+	}
+
+	var reply dto.ReplyDTO
+	if err := c.ShouldBindJSON(&reply); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid input", err))
+		log.Warn("Failed to bind JSON", zap.Error(err))
+		return
+	}
+	if reviewType == entity.ReviewTypeProduct && reply.ShopID == 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "shop_id is required", nil))
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	saved, created, err := h.ris.ReplyToReview(c.Request.Context(), reviewType, targetID, reviewID,
+		reply.ShopID, int(userID), reply.Body)
+	if err != nil {
+		writeReviewError(c, log, err, "failed to reply to review")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, saved)
+}
+
+// DeleteProductReviewReply godoc
+// @Summary Удаление ответа на отзыв о продукте
+// @Description Удаляет ответ магазина. Доступно владельцу и менеджерам ответившего магазина
+// @Tags reviews
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reviewID path int true "Review ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Ответ удален"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Пользователь не управляет магазином"
+// @Failure 404 {object} map[string]string "Отзыв или ответ не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /products/{id}/reviews/{reviewID}/reply [delete]
+func (h *ReviewInteractionHandler) DeleteProductReviewReply(c *gin.Context) {
+	h.deleteReply(c, entity.ReviewTypeProduct)
+}
+
+// DeleteSellerReviewReply godoc
+// @Summary Удаление ответа на отзыв о продавце
+// @Description Удаляет ответ магазина. Доступно владельцу и менеджерам магазина
+// @Tags reviews
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Param reviewID path int true "Review ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Ответ удален"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Пользователь не управляет магазином"
+// @Failure 404 {object} map[string]string "Отзыв или ответ не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /sellers/{id}/reviews/{reviewID}/reply [delete]
+func (h *ReviewInteractionHandler) DeleteSellerReviewReply(c *gin.Context) {
+	h.deleteReply(c, entity.ReviewTypeSeller)
+}
+
+func (h *ReviewInteractionHandler) deleteReply(c *gin.Context, reviewType string) {
+	const op = "reviewInteractionHandler.deleteReply()"
+	log := h.logger.With(zap.String("op", op))
+
+	targetID, reviewID, ok := parseReviewPath(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	if err := h.ris.DeleteReply(c.Request.Context(), reviewType, targetID, reviewID, int(userID)); err != nil {
+		writeReviewError(c, log, err, "failed to delete reply")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "reply deleted successfully"})
+}
+
+// VoteProductReview godoc
+// @Summary Оценка полезности отзыва о продукте
+// @Description Отмечает отзыв полезным или бесполезным. Повторный голос заменяет предыдущий
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reviewID path int true "Review ID"
+// @Param vote body dto.VoteDTO true "Голос"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Голос учтен"
+// @Failure 400 {object} map[string]string "Некорректный ввод или голос за свой отзыв"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 404 {object} map[string]string "Отзыв не найден"
+// @Failure 409 {object} map[string]string "Отзыв скрыт"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /products/{id}/reviews/{reviewID}/vote [post]
+func (h *ReviewInteractionHandler) VoteProductReview(c *gin.Context) {
+	h.voteReview(c, entity.ReviewTypeProduct)
+}
+
+// VoteSellerReview godoc
+// @Summary Оценка полезности отзыва о продавце
+// @Description Отмечает отзыв полезным или бесполезным. Повторный голос заменяет предыдущий
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Param reviewID path int true "Review ID"
+// @Param vote body dto.VoteDTO true "Голос"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Голос учтен"
+// @Failure 400 {object} map[string]string "Некорректный ввод или голос за свой отзыв"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 404 {object} map[string]string "Отзыв не найден"
+// @Failure 409 {object} map[string]string "Отзыв скрыт"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /sellers/{id}/reviews/{reviewID}/vote [post]
+func (h *ReviewInteractionHandler) VoteSellerReview(c *gin.Context) {
+	h.voteReview(c, entity.ReviewTypeSeller)
+}
+
+func (h *ReviewInteractionHandler) voteReview(c *gin.Context, reviewType string) {
+	const op = "reviewInteractionHandler.voteReview()"
+	log := h.logger.With(zap.String("op", op))
+
+	targetID, reviewID, ok := parseReviewPath(c)
+	if !ok {
+		return
+	}
+
+	var vote dto.VoteDTO
+	if err := c.ShouldBindJSON(&vote); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid input", err))
+		log.Warn("Failed to bind JSON", zap.Error(err))
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	err := h.ris.VoteReview(c.Request.Context(), reviewType, targetID, reviewID, int(userID), *vote.Helpful)
+	if err != nil {
+		writeReviewError(c, log, err, "failed to vote for review")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "vote saved successfully"})
+}
+
+// RetractProductReviewVote godoc
+// @Summary Отзыв голоса за отзыв о продукте
+// @Description Удаляет голос пользователя за полезность отзыва
+// @Tags reviews
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reviewID path int true "Review ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Голос удален"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 404 {object} map[string]string "Отзыв или голос не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /products/{id}/reviews/{reviewID}/vote [delete]
+func (h *ReviewInteractionHandler) RetractProductReviewVote(c *gin.Context) {
+	h.retractVote(c, entity.ReviewTypeProduct)
+}
+
+// RetractSellerReviewVote godoc
+// @Summary Отзыв голоса за отзыв о продавце
+// @Description Удаляет голос пользователя за полезность отзыва
+// @Tags reviews
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Param reviewID path int true "Review ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Голос удален"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 404 {object} map[string]string "Отзыв или голос не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /sellers/{id}/reviews/{reviewID}/vote [delete]
+func (h *ReviewInteractionHandler) RetractSellerReviewVote(c *gin.Context) {
+	h.retractVote(c, entity.ReviewTypeSeller)
+}
+
+func (h *ReviewInteractionHandler) retractVote(c *gin.Context, reviewType string) {
+	const op = "reviewInteractionHandler.retractVote()"
+	log := h.logger.With(zap.String("op", op))
+
+	targetID, reviewID, ok := parseReviewPath(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	if err := h.ris.RetractVote(c.Request.Context(), reviewType, targetID, reviewID, int(userID)); err != nil {
+		writeReviewError(c, log, err, "failed to retract vote")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "vote retracted successfully"})
+}
@@ -0,0 +1,173 @@
+package reviews_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/reviews"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+type mockReviewInteractionService struct {
+	replies   map[int]entity.ReviewReply
+	votes     map[int]bool
+	replyShop int
+}
+
+func (m *mockReviewInteractionService) ReplyToReview(
+	_ context.Context, reviewType string, targetID int, reviewID int, shopID int, _ int, body string,
+) (entity.ReviewReply, bool, error) {
+	switch reviewID {
+	case 999:
+		return entity.ReviewReply{}, false, apperror.NewReviewError(apperror.NotFound, "review not found")
+	case 8:
+		return entity.ReviewReply{}, false, apperror.NewReviewError(apperror.ReviewDuplicate,
+			"the review already has a reply from another shop")
+	}
+	if reviewType == entity.ReviewTypeSeller {
+		shopID = targetID
+	}
+	m.replyShop = shopID
+	_, exists := m.replies[reviewID]
+	reply := entity.ReviewReply{ID: 1, ReviewType: reviewType, ReviewID: reviewID, ShopID: shopID, Body: body}
+	m.replies[reviewID] = reply
+	return reply, !exists, nil
+}
+
+func (m *mockReviewInteractionService) DeleteReply(_ context.Context, _ string, _ int, reviewID int, _ int) error {
+	if _, ok := m.replies[reviewID]; !ok {
+		return apperror.NewReviewError(apperror.NotFound, "reply not found")
+	}
+	delete(m.replies, reviewID)
+	return nil
+}
+
+func (m *mockReviewInteractionService) VoteReview(
+	_ context.Context, _ string, _ int, reviewID int, userID int, helpful bool,
+) error {
+	if reviewID == 7 && userID == 1 {
+		return apperror.NewReviewError(apperror.ReviewInvalid, "you cannot vote for your own review")
+	}
+	m.votes[reviewID] = helpful
+	return nil
+}
+
+func (m *mockReviewInteractionService) RetractVote(_ context.Context, _ string, _ int, reviewID int, _ int) error {
+	if _, ok := m.votes[reviewID]; !ok {
+		return apperror.NewReviewError(apperror.NotFound, "vote not found")
+	}
+	delete(m.votes, reviewID)
+	return nil
+}
+
+var _ = Describe("ReviewInteractionHandler", func() {
+	var (
+		service *mockReviewInteractionService
+		router  *gin.Engine
+	)
+
+	send := func(method, path string, body any) *httptest.ResponseRecorder {
+		return serve(router, method, path, body, 1)
+	}
+
+	BeforeEach(func() {
+		service = &mockReviewInteractionService{
+			replies: make(map[int]entity.ReviewReply),
+			votes:   make(map[int]bool),
+		}
+		handler := reviews.NewReviewInteractionHandler(service, zap.NewNop())
+		var secured gin.IRoutes
+		router, secured = newReviewsRouter()
+		secured.PUT("/api/products/:id/reviews/:reviewID/reply", handler.ReplyToProductReview)
+		secured.DELETE("/api/products/:id/reviews/:reviewID/reply", handler.DeleteProductReviewReply)
+		secured.PUT("/api/sellers/:id/reviews/:reviewID/reply", handler.ReplyToSellerReview)
+		secured.POST("/api/products/:id/reviews/:reviewID/vote", handler.VoteProductReview)
+		secured.DELETE("/api/sellers/:id/reviews/:reviewID/vote", handler.RetractSellerReviewVote)
+		secured.POST("/api/sellers/:id/reviews/:reviewID/vote", handler.VoteSellerReview)
+	})
+
+	Context("Reply", func() {
+		It("should create and then edit a reply to a seller review", func() {
+			w := send(http.MethodPut, "/api/sellers/2/reviews/3/reply", gin.H{"body": "Thank you!"})
+
+			Expect(w.Code).To(Equal(http.StatusCreated))
+			Expect(service.replyShop).To(Equal(2))
+
+			w = send(http.MethodPut, "/api/sellers/2/reviews/3/reply", gin.H{"body": "Thank you again!"})
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var reply entity.ReviewReply
+			Expect(json.Unmarshal(w.Body.Bytes(), &reply)).To(Succeed())
+			Expect(reply.Body).To(Equal("Thank you again!"))
+		})
+
+		It("should require the shop for a product review reply", func() {
+			w := send(http.MethodPut, "/api/products/1/reviews/3/reply", gin.H{"body": "Thank you!"})
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should reply to a product review on behalf of the shop", func() {
+			w := send(http.MethodPut, "/api/products/1/reviews/3/reply", gin.H{"shop_id": 5, "body": "Thank you!"})
+
+			Expect(w.Code).To(Equal(http.StatusCreated))
+			Expect(service.replyShop).To(Equal(5))
+		})
+
+		It("should return 409 when another shop has already replied", func() {
+			w := send(http.MethodPut, "/api/products/1/reviews/8/reply", gin.H{"shop_id": 5, "body": "Thank you!"})
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+
+		It("should return 401 without a token", func() {
+			w := serve(router, http.MethodPut, "/api/sellers/2/reviews/3/reply", gin.H{"body": "Thank you!"}, 0)
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(service.replies).To(BeEmpty())
+		})
+
+		It("should return 404 when deleting a missing reply", func() {
+			w := send(http.MethodDelete, "/api/products/1/reviews/3/reply", nil)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("Vote", func() {
+		It("should save a vote", func() {
+			w := send(http.MethodPost, "/api/sellers/1/reviews/3/vote", gin.H{"helpful": false})
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(service.votes).To(HaveKeyWithValue(3, false))
+		})
+
+		It("should return 400 without the vote value", func() {
+			w := send(http.MethodPost, "/api/products/1/reviews/3/vote", gin.H{})
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return 400 for the own review", func() {
+			w := send(http.MethodPost, "/api/products/1/reviews/7/vote", gin.H{"helpful": true})
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should retract a vote", func() {
+			service.votes[3] = true
+
+			w := send(http.MethodDelete, "/api/sellers/1/reviews/3/vote", nil)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(service.votes).To(BeEmpty())
+		})
+	})
+})
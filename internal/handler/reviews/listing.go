@@ -0,0 +1,55 @@
+package reviews
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// parseReviewFilter reads pagination, sorting and rating bounds from the query string.
+// Ranges and sort names are validated by the service.
+func parseReviewFilter(c *gin.Context) (entity.ReviewFilter, int, error) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		return entity.ReviewFilter{}, 0, errors.New("invalid page number")
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		return entity.ReviewFilter{}, 0, errors.New("invalid limit value")
+	}
+
+	filter := entity.ReviewFilter{
+		Sort:   c.Query("sort"),
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	}
+
+	for param, dst := range map[string]*int{"min_rating": &filter.MinRating, "max_rating": &filter.MaxRating} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+		if *dst, err = strconv.Atoi(raw); err != nil {
+			return entity.ReviewFilter{}, 0, errors.New("invalid " + param)
+		}
+	}
+
+	return filter, page, nil
+}
+
+func reviewListResponse(reviews any, total int, summary entity.ReviewSummary, page, limit int) gin.H {
+	return gin.H{
+		"data":    reviews,
+		"summary": summary,
+		"meta": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total_items":  total,
+			"total_pages":  int(math.Ceil(float64(total) / float64(limit))),
+		},
+	}
+}
@@ -0,0 +1,340 @@
+package reviews
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/reviews/dto"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ReviewModerationService interface {
+	ReportReview(
+		ctx context.Context, reviewType string, targetID int, reviewID int, reporterID int, reason string,
+	) (int, error)
+	GetModerationQueue(ctx context.Context, limit, offset int) ([]entity.ModerationQueueItem, int, error)
+	HideReview(ctx context.Context, reviewType string, reviewID int, reason string, moderatorID int) error
+	RestoreReview(ctx context.Context, reviewType string, reviewID int, reason string, moderatorID int) error
+	BanReviewer(ctx context.Context, userID int, reason string, moderatorID int) error
+	UnbanReviewer(ctx context.Context, userID int, reason string, moderatorID int) error
+}
+
+type ReviewModerationHandler struct {
+	rms    ReviewModerationService
+	logger *zap.Logger
+}
+
+func NewReviewModerationHandler(rms ReviewModerationService, l *zap.Logger) *ReviewModerationHandler {
+	return &ReviewModerationHandler{
+		rms:    rms,
+		logger: l,
+	}
+}
+
+// ReportProductReview godoc
+// @Summary Жалоба на отзыв о продукте
+// @Description Отправляет жалобу на чужой отзыв. На один отзыв можно пожаловаться один раз
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reviewID path int true "Review ID"
+// @Param report body dto.ReportReviewDTO true "Причина жалобы"
+// @Security BearerAuth
+// @Success 201 {object} map[string]string "Жалоба отправлена"
+// @Failure 400 {object} map[string]string "Некорректный ввод или жалоба на свой отзыв"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 404 {object} map[string]string "Отзыв не найден"
+// @Failure 409 {object} map[string]string "Жалоба уже отправлена"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /products/{id}/reviews/{reviewID}/report [post]
+func (h *ReviewModerationHandler) ReportProductReview(c *gin.Context) {
+	h.reportReview(c, entity.ReviewTypeProduct)
+}
+
+// ReportSellerReview godoc
+// @Summary Жалоба на отзыв о продавце
+// @Description Отправляет жалобу на чужой отзыв. На один отзыв можно пожаловаться один раз
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Param reviewID path int true "Review ID"
+// @Param report body dto.ReportReviewDTO true "Причина жалобы"
+// @Security BearerAuth
+// @Success 201 {object} map[string]string "Жалоба отправлена"
+// @Failure 400 {object} map[string]string "Некорректный ввод или жалоба на свой отзыв"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 404 {object} map[string]string "Отзыв не найден"
+// @Failure 409 {object} map[string]string "Жалоба уже отправлена"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /sellers/{id}/reviews/{reviewID}/report [post]
+func (h *ReviewModerationHandler) ReportSellerReview(c *gin.Context) {
+	h.reportReview(c, entity.ReviewTypeSeller)
+}
+
+func (h *ReviewModerationHandler) reportReview(c *gin.Context, reviewType string) {
+	const op = "reviewModerationHandler.reportReview()"
+	log := h.logger.With(zap.String("op", op))
+
+	targetID, reviewID, ok := parseReviewPath(c)
+	if !ok {
+		return
+	}
+
+	var report dto.ReportReviewDTO
+	if err := c.ShouldBindJSON(&report); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid input", err))
+		log.Warn("Failed to bind JSON", zap.Error(err))
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	_, err := h.rms.ReportReview(c.Request.Context(), reviewType, targetID, reviewID, int(userID), report.Reason)
+	if err != nil {
+		writeReviewError(c, log, err, "failed to report review")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "review reported successfully"})
+}
+
+// GetModerationQueue godoc
+// @Summary Очередь модерации отзывов
+// @Description Возвращает отзывы с нерассмотренными жалобами, сначала самые обжалуемые
+// @Tags admin
+// @Produce json
+// @Param page query integer false "Номер страницы (по умолчанию 1)" minimum(1)
+// @Param limit query integer false "Размер страницы (по умолчанию 20)" minimum(1) maximum(100)
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Очередь модерации"
+// @Failure 400 {object} map[string]string "Некорректные параметры пагинации"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Требуются права администратора"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /admin/reviews/reports [get]
+func (h *ReviewModerationHandler) GetModerationQueue(c *gin.Context) {
+	const op = "reviewModerationHandler.GetModerationQueue()"
+	log := h.logger.With(zap.String("op", op))
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid page number", nil))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid limit value", nil))
+		return
+	}
+
+	items, total, err := h.rms.GetModerationQueue(c.Request.Context(), limit, (page-1)*limit)
+	if err != nil {
+		writeReviewError(c, log, err, "failed to fetch moderation queue")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": items,
+		"meta": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total_items":  total,
+			"total_pages":  int(math.Ceil(float64(total) / float64(limit))),
+		},
+	})
+}
+
+// HideReview godoc
+// @Summary Скрытие отзыва
+// @Description Скрывает отзыв из списков и рейтингов и закрывает жалобы на него
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param type path string true "Тип отзыва" Enums(product, seller)
+// @Param reviewID path int true "Review ID"
+// @Param decision body dto.ModerationDTO true "Причина решения"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Отзыв скрыт"
+// @Failure 400 {object} map[string]string "Некорректный ввод"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Требуются права администратора"
+// @Failure 404 {object} map[string]string "Отзыв не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /admin/reviews/{type}/{reviewID}/hide [post]
+func (h *ReviewModerationHandler) HideReview(c *gin.Context) {
+	h.moderateReview(c, h.rms.HideReview, "review hidden successfully")
+}
+
+// RestoreReview godoc
+// @Summary Восстановление отзыва
+// @Description Снова публикует скрытый отзыв
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param type path string true "Тип отзыва" Enums(product, seller)
+// @Param reviewID path int true "Review ID"
+// @Param decision body dto.ModerationDTO true "Причина решения"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Отзыв восстановлен"
+// @Failure 400 {object} map[string]string "Некорректный ввод"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Требуются права администратора"
+// @Failure 404 {object} map[string]string "Отзыв не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /admin/reviews/{type}/{reviewID}/restore [post]
+func (h *ReviewModerationHandler) RestoreReview(c *gin.Context) {
+	h.moderateReview(c, h.rms.RestoreReview, "review restored successfully")
+}
+
+func (h *ReviewModerationHandler) moderateReview(
+	c *gin.Context,
+	action func(ctx context.Context, reviewType string, reviewID int, reason string, moderatorID int) error,
+	message string,
+) {
+	const op = "reviewModerationHandler.moderateReview()"
+	log := h.logger.With(zap.String("op", op))
+
+	reviewID, err := strconv.Atoi(c.Param("reviewID"))
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid reviewID", nil))
+		return
+	}
+
+	var decision dto.ModerationDTO
+	if err := c.ShouldBindJSON(&decision); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid input", err))
+		log.Warn("Failed to bind JSON", zap.Error(err))
+		return
+	}
+
+	moderatorID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	err = action(c.Request.Context(), c.Param("type"), reviewID, decision.Reason, int(moderatorID))
+	if err != nil {
+		writeReviewError(c, log, err, "failed to moderate review")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// BanReviewer godoc
+// @Summary Запрет на отзывы
+// @Description Запрещает пользователю оставлять отзывы и скрывает все его отзывы
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param decision body dto.ModerationDTO true "Причина решения"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Пользователь заблокирован"
+// @Failure 400 {object} map[string]string "Некорректный ввод"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Требуются права администратора"
+// @Failure 404 {object} map[string]string "Пользователь не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/review-ban [post]
+func (h *ReviewModerationHandler) BanReviewer(c *gin.Context) {
+	h.moderateReviewer(c, h.rms.BanReviewer, "reviewer banned successfully")
+}
+
+// UnbanReviewer godoc
+// @Summary Снятие запрета на отзывы
+// @Description Снова разрешает пользователю оставлять отзывы. Скрытые при блокировке отзывы
+// @Description остаются скрытыми и восстанавливаются по одному
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param decision body dto.ModerationDTO true "Причина решения"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Запрет снят"
+// @Failure 400 {object} map[string]string "Некорректный ввод или пользователь не заблокирован"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Требуются права администратора"
+// @Failure 404 {object} map[string]string "Пользователь не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/review-unban [post]
+func (h *ReviewModerationHandler) UnbanReviewer(c *gin.Context) {
+	h.moderateReviewer(c, h.rms.UnbanReviewer, "reviewer unbanned successfully")
+}
+
+func (h *ReviewModerationHandler) moderateReviewer(
+	c *gin.Context,
+	action func(ctx context.Context, userID int, reason string, moderatorID int) error,
+	message string,
+) {
+	const op = "reviewModerationHandler.moderateReviewer()"
+	log := h.logger.With(zap.String("op", op))
+
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid userID", err))
+		return
+	}
+
+	var decision dto.ModerationDTO
+	if err := c.ShouldBindJSON(&decision); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid input", err))
+		log.Warn("Failed to bind JSON", zap.Error(err))
+		return
+	}
+
+	moderatorID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	if err := action(c.Request.Context(), userID, decision.Reason, int(moderatorID)); err != nil {
+		writeReviewError(c, log, err, "failed to moderate reviewer")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// parseReviewPath reads the reviewed object ID and the review ID from the path.
+func parseReviewPath(c *gin.Context) (int, int, bool) {
+	targetID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid id", nil))
+		return 0, 0, false
+	}
+	reviewID, err := strconv.Atoi(c.Param("reviewID"))
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid reviewID", nil))
+		return 0, 0, false
+	}
+	return targetID, reviewID, true
+}
+
+// writeReviewError passes review errors to the error middleware and hides anything else behind
+// an internal error with the fallback message.
+func writeReviewError(c *gin.Context, log *zap.Logger, err error, fallback string) {
+	var appErr apperror.AppError
+	if errors.As(err, &appErr) {
+		_ = c.Error(err)
+		return
+	}
+	log.Warn(fallback, zap.Error(err))
+	_ = c.Error(apperror.New(apperror.InternalError, fallback, err))
+}
@@ -0,0 +1,200 @@
+package reviews_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/reviews"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+type mockReviewModerationService struct {
+	reportedType string
+	hidden       map[int]string
+	queue        []entity.ModerationQueueItem
+	queueLimit   int
+	queueOffset  int
+}
+
+func (m *mockReviewModerationService) ReportReview(
+	_ context.Context, reviewType string, _ int, reviewID int, reporterID int, _ string,
+) (int, error) {
+	if reviewID == 999 {
+		return 0, apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+	if reporterID == 1 && reviewID == 7 {
+		return 0, apperror.NewReviewError(apperror.ReviewInvalid, "you cannot report your own review")
+	}
+	m.reportedType = reviewType
+	return 1, nil
+}
+
+func (m *mockReviewModerationService) GetModerationQueue(
+	_ context.Context, limit, offset int,
+) ([]entity.ModerationQueueItem, int, error) {
+	m.queueLimit, m.queueOffset = limit, offset
+	return m.queue, len(m.queue), nil
+}
+
+func (m *mockReviewModerationService) HideReview(
+	_ context.Context, _ string, reviewID int, reason string, _ int,
+) error {
+	m.hidden[reviewID] = reason
+	return nil
+}
+
+func (m *mockReviewModerationService) RestoreReview(
+	_ context.Context, _ string, reviewID int, _ string, _ int,
+) error {
+	delete(m.hidden, reviewID)
+	return nil
+}
+
+func (m *mockReviewModerationService) BanReviewer(_ context.Context, userID int, _ string, _ int) error {
+	if userID == 999 {
+		return apperror.NewReviewError(apperror.NotFound, "user not found")
+	}
+	return nil
+}
+
+func (m *mockReviewModerationService) UnbanReviewer(_ context.Context, userID int, _ string, _ int) error {
+	if userID == 999 {
+		return apperror.NewReviewError(apperror.NotFound, "user not found")
+	}
+	return nil
+}
+
+var _ = Describe("ReviewModerationHandler", func() {
+	var (
+		service *mockReviewModerationService
+		router  *gin.Engine
+	)
+
+	post := func(path string, body any) *httptest.ResponseRecorder {
+		return serve(router, http.MethodPost, path, body, 1)
+	}
+
+	adminPost := func(path string, body any) *httptest.ResponseRecorder {
+		return serve(router, http.MethodPost, path, body, adminUserID)
+	}
+
+	BeforeEach(func() {
+		service = &mockReviewModerationService{hidden: make(map[int]string)}
+		handler := reviews.NewReviewModerationHandler(service, zap.NewNop())
+		var secured gin.IRoutes
+		router, secured = newReviewsRouter()
+		secured.POST("/api/products/:id/reviews/:reviewID/report", handler.ReportProductReview)
+		secured.POST("/api/sellers/:id/reviews/:reviewID/report", handler.ReportSellerReview)
+		admin := adminGroup(router, "/api/admin")
+		admin.GET("/reviews/reports", handler.GetModerationQueue)
+		admin.POST("/reviews/:type/:reviewID/hide", handler.HideReview)
+		admin.POST("/reviews/:type/:reviewID/restore", handler.RestoreReview)
+		admin.POST("/users/:id/review-ban", handler.BanReviewer)
+		admin.POST("/users/:id/review-unban", handler.UnbanReviewer)
+	})
+
+	Context("Report", func() {
+		It("should report a seller review", func() {
+			w := post("/api/sellers/1/reviews/3/report", gin.H{"reason": "spam"})
+
+			Expect(w.Code).To(Equal(http.StatusCreated))
+			Expect(service.reportedType).To(Equal(entity.ReviewTypeSeller))
+		})
+
+		It("should return 400 for the own review", func() {
+			w := post("/api/products/1/reviews/7/report", gin.H{"reason": "spam"})
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return 400 without a reason", func() {
+			w := post("/api/products/1/reviews/3/report", gin.H{})
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return 404 for non-existent review", func() {
+			w := post("/api/products/1/reviews/999/report", gin.H{"reason": "spam"})
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("GetModerationQueue", func() {
+		It("should return a page of the queue", func() {
+			service.queue = []entity.ModerationQueueItem{{ReviewType: "product", ReviewID: 3, ReportCount: 2}}
+			w := serve(router, http.MethodGet, "/api/admin/reviews/reports?page=2&limit=5", nil, adminUserID)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(service.queueLimit).To(Equal(5))
+			Expect(service.queueOffset).To(Equal(5))
+			var response struct {
+				Data []entity.ModerationQueueItem `json:"data"`
+			}
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Data).To(HaveLen(1))
+		})
+
+		It("should return 400 for invalid limit", func() {
+			w := serve(router, http.MethodGet, "/api/admin/reviews/reports?limit=1000", nil, adminUserID)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return 403 for users without the admin role", func() {
+			w := serve(router, http.MethodGet, "/api/admin/reviews/reports", nil, 1)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Context("HideReview and RestoreReview", func() {
+		It("should hide and restore the review", func() {
+			Expect(adminPost("/api/admin/reviews/product/3/hide", gin.H{"reason": "insults"}).Code).
+				To(Equal(http.StatusOK))
+			Expect(service.hidden).To(HaveKeyWithValue(3, "insults"))
+
+			Expect(adminPost("/api/admin/reviews/product/3/restore", gin.H{"reason": "appeal"}).Code).
+				To(Equal(http.StatusOK))
+			Expect(service.hidden).To(BeEmpty())
+		})
+
+		It("should return 400 without a reason", func() {
+			Expect(adminPost("/api/admin/reviews/product/3/hide", gin.H{}).Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("BanReviewer", func() {
+		It("should ban the reviewer", func() {
+			Expect(adminPost("/api/admin/users/2/review-ban", gin.H{"reason": "spam"}).Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return 404 for non-existent user", func() {
+			Expect(adminPost("/api/admin/users/999/review-ban", gin.H{"reason": "spam"}).Code).
+				To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("UnbanReviewer", func() {
+		It("should unban the reviewer", func() {
+			Expect(adminPost("/api/admin/users/2/review-unban", gin.H{"reason": "appeal"}).Code).
+				To(Equal(http.StatusOK))
+		})
+
+		It("should return 400 without a reason", func() {
+			Expect(adminPost("/api/admin/users/2/review-unban", gin.H{}).Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return 404 for non-existent user", func() {
+			Expect(adminPost("/api/admin/users/999/review-unban", gin.H{"reason": "appeal"}).Code).
+				To(Equal(http.StatusNotFound))
+		})
+	})
+})
@@ -0,0 +1,126 @@
+package reviews
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// photoFormOverhead leaves room for the multipart headers on top of the file size.
+const photoFormOverhead = 64 << 10
+
+// AddPhoto godoc
+// @Summary Добавление фото к отзыву о продукте
+// @Description Прикрепляет изображение (jpeg, png, gif) к отзыву и создает превью.
+// @Description Доступно автору в течение 48 часов после публикации, число фото у отзыва ограничено
+// @Tags reviews
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reviewID path int true "Review ID"
+// @Param image formData file true "Файл изображения"
+// @Security BearerAuth
+// @Success 201 {object} entity.ReviewPhoto
+// @Failure 400 {object} map[string]string "Некорректный файл или превышен лимит фото"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Не автор отзыва, почта не подтверждена или срок изменения истек"
+// @Failure 404 {object} map[string]string "Отзыв не найден"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /products/{id}/reviews/{reviewID}/photos [post]
+func (h *ProductReviewsHandler) AddPhoto(c *gin.Context) {
+	const op = "productReviewsHandler.AddPhoto()"
+	log := h.logger.With(zap.String("op", op))
+
+	productID, reviewID, ok := parseReviewPath(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxPhotoSize+photoFormOverhead)
+
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "image file is required", err))
+		return
+	}
+	if fileHeader.Size > h.maxPhotoSize {
+		_ = c.Error(apperror.New(apperror.BadRequest, "image is too large", nil))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "failed to read image", err))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, h.maxPhotoSize+1))
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "failed to read image", err))
+		return
+	}
+
+	photo, err := h.prs.AddPhoto(c.Request.Context(), productID, reviewID, int(userID), data)
+	if err != nil {
+		writeReviewError(c, log, err, "failed to add photo")
+		return
+	}
+
+	c.JSON(http.StatusCreated, photo)
+}
+
+// DeletePhoto godoc
+// @Summary Удаление фото из отзыва о продукте
+// @Description Автор может удалить фото в течение 48 часов после публикации отзыва
+// @Tags reviews
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reviewID path int true "Review ID"
+// @Param photoID path int true "Photo ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Фото удалено"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Не автор отзыва или срок изменения истек"
+// @Failure 404 {object} map[string]string "Фото не найдено"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /products/{id}/reviews/{reviewID}/photos/{photoID} [delete]
+func (h *ProductReviewsHandler) DeletePhoto(c *gin.Context) {
+	const op = "productReviewsHandler.DeletePhoto()"
+	log := h.logger.With(zap.String("op", op))
+
+	productID, reviewID, ok := parseReviewPath(c)
+	if !ok {
+		return
+	}
+	photoID, err := strconv.Atoi(c.Param("photoID"))
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid photoID", nil))
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	if err := h.prs.DeletePhoto(c.Request.Context(), productID, reviewID, photoID, int(userID)); err != nil {
+		writeReviewError(c, log, err, "failed to delete photo")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "photo deleted successfully"})
+}
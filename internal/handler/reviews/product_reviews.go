@@ -2,7 +2,6 @@ package reviews
 
 import (
 	"context"
-	"errors"
 	"net/http"
 	"strconv"
 
@@ -15,34 +14,48 @@ import (
 )
 
 type ProductReviewsService interface {
-	AddReview(ctx context.Context, productID int, userID int, rating int, review string) (int, error)
-	GetReviewsByProductID(ctx context.Context, productID int) ([]entity.ProductReview, error)
+	AddReview(ctx context.Context, productID int, userID int, rating int, review string) (int, bool, error)
+	GetReviewsByProductID(
+		ctx context.Context, productID int, filter entity.ReviewFilter,
+	) (entity.ProductReviewPage, error)
+	UpdateReview(ctx context.Context, productID int, reviewID int, userID int, rating int, review string) error
+	DeleteReview(ctx context.Context, productID int, reviewID int, userID int) error
+	AddPhoto(ctx context.Context, productID int, reviewID int, userID int, data []byte) (entity.ReviewPhoto, error)
+	DeletePhoto(ctx context.Context, productID int, reviewID int, photoID int, userID int) error
 }
 
 type ProductReviewsHandler struct {
-	prs    ProductReviewsService
-	logger *zap.Logger
+	prs          ProductReviewsService
+	maxPhotoSize int64
+	logger       *zap.Logger
 }
 
-func NewProductReviewHandler(prs ProductReviewsService, l *zap.Logger) *ProductReviewsHandler {
+func NewProductReviewHandler(prs ProductReviewsService, maxPhotoSize int64, l *zap.Logger) *ProductReviewsHandler {
 	return &ProductReviewsHandler{
-		prs:    prs,
-		logger: l,
+		prs:          prs,
+		maxPhotoSize: maxPhotoSize,
+		logger:       l,
 	}
 }
 
 // AddReview godoc
 // @Summary Добавление отзыва о продукте
-// @Description Добавляет новый отзыв о продукте
+// @Description Добавляет отзыв о продукте. У пользователя может быть только один отзыв на продукт,
+// @Description повторная отправка заменяет его. Отзыв помечается подтвержденным, если у пользователя
+// @Description есть принятый оффер на этот продукт. Заменить отзыв можно только в течение 48 часов
 // @Tags reviews
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
 // @Param review body dto.AddReviewDTO true "Данные отзыва"
 // @Security BearerAuth
+// @Success 200 {object} map[string]string "Отзыв обновлен"
 // @Success 201 {object} map[string]string "Отзыв успешно добавлен"
 // @Failure 400 {object} map[string]string "Некорректный ввод"
 // @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Автору запрещены отзывы или срок изменения истек"
+// @Failure 404 {object} map[string]string "Продукт не найден"
+// @Failure 409 {object} map[string]string "Отзыв скрыт модератором"
 // @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
 // @Router /products/{id}/reviews [post]
 func (h *ProductReviewsHandler) AddReview(c *gin.Context) {
@@ -51,42 +64,35 @@ func (h *ProductReviewsHandler) AddReview(c *gin.Context) {
 
 	productID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid productID"})
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid productID", err))
 		log.Warn("Failed to parse productID", zap.Error(err))
 		return
 	}
 
 	var addReview dto.AddReviewDTO
 	if err := c.ShouldBindJSON(&addReview); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid input", err))
 		log.Warn("Failed to bind JSON", zap.Error(err))
 		return
 	}
 
 	userID, ok := helpers.UserIDContext(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
 		log.Warn("Failed to get userID from context", zap.Error(err))
 		return
 	}
 
 	uid := int(userID)
 
-	id, err := h.prs.AddReview(c.Request.Context(), productID, uid, addReview.Rating, addReview.Review)
+	_, created, err := h.prs.AddReview(c.Request.Context(), productID, uid, addReview.Rating, addReview.Review)
 	if err != nil {
-		var reviewErr *apperror.ReviewError
-		if errors.As(err, &reviewErr) {
-			switch reviewErr.Code {
-			case apperror.NotFound:
-				c.JSON(http.StatusNotFound, gin.H{"error": reviewErr.Message})
-				return
-			default:
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add review"})
-			}
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add review"})
-		}
-		log.Warn("Failed to add review", zap.Int("id: ", id), zap.Error(err))
+		writeReviewError(c, log, err, "failed to add review")
+		return
+	}
+
+	if !created {
+		c.JSON(http.StatusOK, gin.H{"message": "review updated successfully"})
 		return
 	}
 
@@ -95,13 +101,21 @@ func (h *ProductReviewsHandler) AddReview(c *gin.Context) {
 
 // GetReviews godoc
 // @Summary Получение списка отзывов о продукте
-// @Description Получает все отзывы о продукте по его ID
+// @Description Возвращает страницу опубликованных отзывов о продукте и сводку по оценкам:
+// @Description средний рейтинг и распределение оценок от 1 до 5 по всем отзывам
 // @Tags reviews
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
-// @Success 200 {array} entity.ProductReview "Список отзывов"
-// @Failure 400 {object} map[string]string "Некорректный ID продукта"
+// @Param page query integer false "Номер страницы (по умолчанию 1)" minimum(1)
+// @Param limit query integer false "Размер страницы (по умолчанию 20)" minimum(1) maximum(100)
+// @Param sort query string false "Сортировка" Enums(newest, highest, lowest, most_helpful)
+// @Param min_rating query integer false "Минимальная оценка" minimum(1) maximum(5)
+// @Param max_rating query integer false "Максимальная оценка" minimum(1) maximum(5)
+// @Param verified_only query bool false "Только подтвержденные покупки"
+// @Success 200 {object} map[string]interface{} "Страница отзывов со сводкой"
+// @Failure 400 {object} map[string]string "Некорректные параметры запроса"
+// @Failure 404 {object} map[string]string "Продукт не найден"
 // @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
 // @Router /products/{id}/reviews [get]
 func (h *ProductReviewsHandler) GetReviews(c *gin.Context) {
@@ -110,28 +124,119 @@ func (h *ProductReviewsHandler) GetReviews(c *gin.Context) {
 
 	productID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid productID"})
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid productID", err))
 		log.Warn("Failed to parse productID", zap.Error(err))
 		return
 	}
 
-	reviews, err := h.prs.GetReviewsByProductID(c.Request.Context(), productID)
+	filter, page, err := parseReviewFilter(c)
 	if err != nil {
-		var reviewErr *apperror.ReviewError
-		if errors.As(err, &reviewErr) {
-			switch reviewErr.Code {
-			case apperror.NotFound:
-				c.JSON(http.StatusNotFound, gin.H{"error": reviewErr.Message})
-				return
-			default:
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch reviews"})
-			}
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch reviews"})
+		_ = c.Error(apperror.New(apperror.BadRequest, err.Error(), nil))
+		return
+	}
+
+	if raw := c.Query("verified_only"); raw != "" {
+		filter.VerifiedOnly, err = strconv.ParseBool(raw)
+		if err != nil {
+			_ = c.Error(apperror.New(apperror.BadRequest, "invalid verified_only", nil))
+			log.Warn("Failed to parse verified_only", zap.Error(err))
+			return
 		}
-		log.Warn("Failed to fetch reviews", zap.Error(err))
+	}
+
+	result, err := h.prs.GetReviewsByProductID(c.Request.Context(), productID, filter)
+	if err != nil {
+		writeReviewError(c, log, err, "failed to fetch reviews")
+		return
+	}
+
+	c.JSON(http.StatusOK, reviewListResponse(result.Reviews, result.Total, result.Summary, page, filter.Limit))
+}
+
+// UpdateReview godoc
+// @Summary Изменение отзыва о продукте
+// @Description Автор может изменить отзыв в течение 48 часов после публикации
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reviewID path int true "Review ID"
+// @Param review body dto.UpdateReviewDTO true "Данные отзыва"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Отзыв обновлен"
+// @Failure 400 {object} map[string]string "Некорректный ввод"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Не автор отзыва, почта не подтверждена или срок изменения истек"
+// @Failure 404 {object} map[string]string "Отзыв не найден"
+// @Failure 409 {object} map[string]string "Отзыв скрыт модератором"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /products/{id}/reviews/{reviewID} [put]
+func (h *ProductReviewsHandler) UpdateReview(c *gin.Context) {
+	const op = "productReviewsHandler.UpdateReview()"
+	log := h.logger.With(zap.String("op", op))
+
+	productID, reviewID, ok := parseReviewPath(c)
+	if !ok {
+		return
+	}
+
+	var update dto.UpdateReviewDTO
+	if err := c.ShouldBindJSON(&update); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid input", err))
+		log.Warn("Failed to bind JSON", zap.Error(err))
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	err := h.prs.UpdateReview(c.Request.Context(), productID, reviewID, int(userID), update.Rating, update.Review)
+	if err != nil {
+		writeReviewError(c, log, err, "failed to update review")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "review updated successfully"})
+}
+
+// DeleteReview godoc
+// @Summary Удаление отзыва о продукте
+// @Description Автор может удалить отзыв в течение 48 часов после публикации
+// @Tags reviews
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reviewID path int true "Review ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Отзыв удален"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Не автор отзыва или срок изменения истек"
+// @Failure 404 {object} map[string]string "Отзыв не найден"
+// @Failure 409 {object} map[string]string "Отзыв скрыт модератором"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /products/{id}/reviews/{reviewID} [delete]
+func (h *ProductReviewsHandler) DeleteReview(c *gin.Context) {
+	const op = "productReviewsHandler.DeleteReview()"
+	log := h.logger.With(zap.String("op", op))
+
+	productID, reviewID, ok := parseReviewPath(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	if err := h.prs.DeleteReview(c.Request.Context(), productID, reviewID, int(userID)); err != nil {
+		writeReviewError(c, log, err, "failed to delete review")
 		return
 	}
 
-	c.JSON(http.StatusOK, reviews)
+	c.JSON(http.StatusOK, gin.H{"message": "review deleted successfully"})
 }
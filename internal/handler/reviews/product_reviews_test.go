@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -12,6 +14,7 @@ import (
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/reviews"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/reviews/dto"
 	"github.com/gin-gonic/gin"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -19,7 +22,10 @@ import (
 )
 
 type mockProductReviewsService struct {
-	reviews map[int][]entity.ProductReview
+	reviews    map[int][]entity.ProductReview
+	lastFilter entity.ReviewFilter
+	// lastPhoto is the content of the last uploaded photo
+	lastPhoto []byte
 }
 
 func newMockProductReviewsService() *mockProductReviewsService {
@@ -30,9 +36,13 @@ func newMockProductReviewsService() *mockProductReviewsService {
 
 func (m *mockProductReviewsService) AddReview(
 	_ context.Context, productID int, userID int, rating int, review string,
-) (int, error) {
+) (int, bool, error) {
 	if productID == 999 {
-		return 0, apperror.NewReviewError(apperror.NotFound, "product not found")
+		return 0, false, apperror.NewReviewError(apperror.NotFound, "product not found")
+	}
+	if productID == 77 {
+		return 0, false, apperror.NewReviewError(apperror.ReviewUnauthorized,
+			"you cannot review products of your own shop")
 	}
 
 	reviewEntity := entity.ProductReview{
@@ -42,16 +52,80 @@ func (m *mockProductReviewsService) AddReview(
 		Review:    review,
 	}
 	m.reviews[productID] = append(m.reviews[productID], reviewEntity)
-	return productID, nil
+	return len(m.reviews[productID]), true, nil
 }
 
 func (m *mockProductReviewsService) GetReviewsByProductID(
-	_ context.Context, productID int,
-) ([]entity.ProductReview, error) {
+	_ context.Context, productID int, filter entity.ReviewFilter,
+) (entity.ProductReviewPage, error) {
+	m.lastFilter = filter
 	if productID == 999 {
-		return nil, apperror.NewReviewError(apperror.NotFound, "product not found")
+		return entity.ProductReviewPage{}, apperror.NewReviewError(apperror.NotFound, "product not found")
+	}
+	if filter.Sort == "random" {
+		return entity.ProductReviewPage{}, apperror.NewReviewError(apperror.ReviewInvalid, "unknown sort order")
+	}
+
+	var result []entity.ProductReview
+	for _, review := range m.reviews[productID] {
+		if filter.VerifiedOnly && !review.Verified {
+			continue
+		}
+		result = append(result, review)
+	}
+	return entity.ProductReviewPage{Reviews: result, Total: len(result)}, nil
+}
+
+func (m *mockProductReviewsService) UpdateReview(
+	_ context.Context, _ int, reviewID int, userID int, _ int, _ string,
+) error {
+	return mockReviewChangeError(reviewID, userID)
+}
+
+func (m *mockProductReviewsService) DeleteReview(_ context.Context, _ int, reviewID int, userID int) error {
+	return mockReviewChangeError(reviewID, userID)
+}
+
+func (m *mockProductReviewsService) AddPhoto(
+	_ context.Context, _ int, reviewID int, userID int, data []byte,
+) (entity.ReviewPhoto, error) {
+	if err := mockReviewChangeError(reviewID, userID); err != nil {
+		return entity.ReviewPhoto{}, err
+	}
+	m.lastPhoto = data
+	return entity.ReviewPhoto{ID: 1, ReviewID: reviewID, URL: "/static/reviews/1/a.png"}, nil
+}
+
+func (m *mockProductReviewsService) DeletePhoto(_ context.Context, _ int, reviewID int, photoID int, userID int) error {
+	if photoID == 999 {
+		return apperror.NewReviewError(apperror.NotFound, "photo not found")
 	}
-	return m.reviews[productID], nil
+	return mockReviewChangeError(reviewID, userID)
+}
+
+// mockReviewChangeError mimics the edit rules: review 999 does not exist, review 50 is too old
+// and only user 1 is the author.
+func mockReviewChangeError(reviewID int, userID int) error {
+	switch {
+	case reviewID == 999:
+		return apperror.NewReviewError(apperror.NotFound, "review not found")
+	case userID != 1:
+		return apperror.NewReviewError(apperror.ReviewUnauthorized, "only the author can change this review")
+	case reviewID == 50:
+		return apperror.NewReviewError(apperror.ReviewEditExpired, "the review can no longer be changed")
+	}
+	return nil
+}
+
+const maxTestPhotoSize = 1 << 10
+
+type productReviewList struct {
+	Data    []entity.ProductReview `json:"data"`
+	Summary entity.ReviewSummary   `json:"summary"`
+	Meta    struct {
+		TotalItems int `json:"total_items"`
+		TotalPages int `json:"total_pages"`
+	} `json:"meta"`
 }
 
 var _ = Describe("ProductReviewsHandler", func() {
@@ -63,70 +137,178 @@ var _ = Describe("ProductReviewsHandler", func() {
 
 	BeforeEach(func() {
 		service = newMockProductReviewsService()
-		handler = reviews.NewProductReviewHandler(service, zap.NewNop())
-		gin.SetMode(gin.ReleaseMode)
-		router = gin.New()
-		router.Use(middleware.Errors())
+		handler = reviews.NewProductReviewHandler(service, maxTestPhotoSize, zap.NewNop())
+		var secured gin.IRoutes
+		router, secured = newReviewsRouter()
 
-		router.Use(func(c *gin.Context) {
-			c.Set("userID", 1)
-			c.Next()
-		})
-		router.POST("/api/products/:id/reviews", handler.AddReview)
 		router.GET("/api/products/:id/reviews", handler.GetReviews)
+		secured.POST("/api/products/:id/reviews", middleware.RequireVerifiedEmail(), handler.AddReview)
+		secured.PUT("/api/products/:id/reviews/:reviewID", middleware.RequireVerifiedEmail(), handler.UpdateReview)
+		secured.DELETE("/api/products/:id/reviews/:reviewID", handler.DeleteReview)
+		secured.POST("/api/products/:id/reviews/:reviewID/photos", middleware.RequireVerifiedEmail(), handler.AddPhoto)
+		secured.DELETE("/api/products/:id/reviews/:reviewID/photos/:photoID", handler.DeletePhoto)
 	})
 
-	Context("AddReview", func() {
-		// It("should add a new review successfully", func() {
-		// 	// Arrange
-		// 	review := dto.AddReviewDTO{
-		// 		Rating: 5,
-		// 		Review: "Great product!",
-		// 	}
-		// 	body, _ := json.Marshal(review)
-		// 	req, _ := http.NewRequest("POST", "/api/products/1/reviews", bytes.NewBuffer(body))
-		// 	req.Header.Set("Content-Type", "application/json")
-		// 	w := httptest.NewRecorder()
-
-		// 	// Act
-		// 	router.ServeHTTP(w, req)
-
-		// 	// Assert
-		// 	Expect(w.Code).To(Equal(http.StatusCreated))
-		// 	var response map[string]string
-		// 	err := json.Unmarshal(w.Body.Bytes(), &response)
-		// 	Expect(err).ShouldNot(HaveOccurred())
-		// 	Expect(response["message"]).To(Equal("review added successfully"))
-		// })
-
-		// It("should return 404 for non-existent product", func() {
-		// 	// Arrange
-		// 	review := dto.AddReviewDTO{
-		// 		Rating: 5,
-		// 		Review: "Great product!",
-		// 	}
-		// 	body, _ := json.Marshal(review)
-		// 	req, _ := http.NewRequest("POST", "/api/products/999/reviews", bytes.NewBuffer(body))
-		// 	req.Header.Set("Content-Type", "application/json")
-		// 	w := httptest.NewRecorder()
-
-		// 	// Act
-		// 	router.ServeHTTP(w, req)
-
-		// 	// Assert
-		// 	Expect(w.Code).To(Equal(http.StatusNotFound))
-		// })
+	Context("UpdateReview and DeleteReview", func() {
+		update := func(path string, userID uint) *httptest.ResponseRecorder {
+			return serve(router, http.MethodPut, path, dto.UpdateReviewDTO{Rating: 4, Review: "Still good"}, userID)
+		}
 
-		It("should return 400 for invalid input", func() {
-			// Arrange
-			req, _ := http.NewRequest("POST", "/api/products/1/reviews", bytes.NewBuffer([]byte("invalid json")))
-			req.Header.Set("Content-Type", "application/json")
+		It("should update the review of the author", func() {
+			Expect(update("/api/products/1/reviews/1", 1).Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return 403 for another user", func() {
+			Expect(update("/api/products/1/reviews/1", 2).Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should return 403 until the email is verified", func() {
+			Expect(update("/api/products/1/reviews/1", unverifiedUserID).Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should return 403 after the edit window", func() {
+			Expect(update("/api/products/1/reviews/50", 1).Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should return 404 for non-existent review", func() {
+			w := serve(router, http.MethodDelete, "/api/products/1/reviews/999", nil, 1)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should return 401 without an authenticated user", func() {
+			w := serve(router, http.MethodDelete, "/api/products/1/reviews/1", nil, 0)
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Context("AddPhoto and DeletePhoto", func() {
+		upload := func(path string, data []byte, userID uint) *httptest.ResponseRecorder {
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			part, _ := form.CreateFormFile("image", "photo.png")
+			_, _ = part.Write(data)
+			_ = form.Close()
+
+			req, _ := http.NewRequest(http.MethodPost, path, &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			if userID != 0 {
+				req.Header.Set("Authorization", fmt.Sprintf("Bearer token-%d", userID))
+			}
 			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		It("should upload a photo of the author", func() {
+			w := upload("/api/products/1/reviews/3/photos", []byte("photo"), 1)
+
+			Expect(w.Code).To(Equal(http.StatusCreated))
+			Expect(service.lastPhoto).To(Equal([]byte("photo")))
+			var photo entity.ReviewPhoto
+			Expect(json.Unmarshal(w.Body.Bytes(), &photo)).To(Succeed())
+			Expect(photo.URL).To(Equal("/static/reviews/1/a.png"))
+		})
+
+		It("should reject a photo larger than the limit", func() {
+			w := upload("/api/products/1/reviews/3/photos", make([]byte, maxTestPhotoSize+1), 1)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			Expect(service.lastPhoto).To(BeNil())
+		})
+
+		It("should require the image file", func() {
+			w := serve(router, http.MethodPost, "/api/products/1/reviews/3/photos", nil, 1)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return 403 for another user", func() {
+			w := upload("/api/products/1/reviews/3/photos", []byte("photo"), 2)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should return 403 until the email is verified", func() {
+			w := upload("/api/products/1/reviews/3/photos", []byte("photo"), unverifiedUserID)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(service.lastPhoto).To(BeNil())
+		})
+
+		It("should delete a photo", func() {
+			w := serve(router, http.MethodDelete, "/api/products/1/reviews/3/photos/1", nil, 1)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return 404 for a missing photo", func() {
+			w := serve(router, http.MethodDelete, "/api/products/1/reviews/3/photos/999", nil, 1)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
 
+	Context("AddReview", func() {
+		review := dto.AddReviewDTO{
+			Rating: 5,
+			Review: "Great product!",
+		}
+
+		It("should add a new review successfully", func() {
 			// Act
-			router.ServeHTTP(w, req)
+			w := serve(router, http.MethodPost, "/api/products/1/reviews", review, 1)
 
 			// Assert
+			Expect(w.Code).To(Equal(http.StatusCreated))
+			var response map[string]string
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(response["message"]).To(Equal("review added successfully"))
+			Expect(service.reviews[1]).To(HaveLen(1))
+			Expect(service.reviews[1][0].UserID).To(Equal(1))
+		})
+
+		It("should return 404 for non-existent product", func() {
+			w := serve(router, http.MethodPost, "/api/products/999/reviews", review, 1)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			var response map[string]string
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response["code"]).To(Equal(apperror.NotFound))
+		})
+
+		It("should return 403 for a product of the own shop", func() {
+			w := serve(router, http.MethodPost, "/api/products/77/reviews", review, 1)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(service.reviews[77]).To(BeEmpty())
+		})
+
+		It("should return 401 without a token", func() {
+			w := serve(router, http.MethodPost, "/api/products/1/reviews", review, 0)
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(service.reviews[1]).To(BeEmpty())
+		})
+
+		It("should return 401 for a deleted user", func() {
+			w := serve(router, http.MethodPost, "/api/products/1/reviews", review, deletedUserID)
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should return 403 until the email is verified", func() {
+			w := serve(router, http.MethodPost, "/api/products/1/reviews", review, unverifiedUserID)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(service.reviews[1]).To(BeEmpty())
+		})
+
+		It("should return 400 for invalid input", func() {
+			w := serve(router, http.MethodPost, "/api/products/1/reviews", "invalid json", 1)
+
 			Expect(w.Code).To(Equal(http.StatusBadRequest))
 		})
 	})
@@ -150,14 +332,79 @@ var _ = Describe("ProductReviewsHandler", func() {
 
 			// Assert
 			Expect(w.Code).To(Equal(http.StatusOK))
-			var response []entity.ProductReview
+			var response productReviewList
 			err := json.Unmarshal(w.Body.Bytes(), &response)
 			Expect(err).ShouldNot(HaveOccurred())
-			Expect(response).To(HaveLen(1))
-			Expect(response[0].ProductID).To(Equal(1))
-			Expect(response[0].UserID).To(Equal(1))
-			Expect(response[0].Rating).To(Equal(5))
-			Expect(response[0].Review).To(Equal("Great product!"))
+			Expect(response.Data).To(HaveLen(1))
+			Expect(response.Data[0].ProductID).To(Equal(1))
+			Expect(response.Data[0].UserID).To(Equal(1))
+			Expect(response.Data[0].Rating).To(Equal(5))
+			Expect(response.Data[0].Review).To(Equal("Great product!"))
+			Expect(response.Meta.TotalItems).To(Equal(1))
+			Expect(response.Meta.TotalPages).To(Equal(1))
+		})
+
+		It("should filter verified reviews", func() {
+			// Arrange
+			service.reviews[1] = []entity.ProductReview{
+				{ID: 1, ProductID: 1, UserID: 1, Rating: 5, Verified: true},
+				{ID: 2, ProductID: 1, UserID: 2, Rating: 1},
+			}
+			req, _ := http.NewRequest("GET", "/api/products/1/reviews?verified_only=true", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response productReviewList
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Data).To(HaveLen(1))
+			Expect(response.Data[0].Verified).To(BeTrue())
+		})
+
+		It("should pass paging, sorting and rating bounds to the service", func() {
+			req, _ := http.NewRequest("GET",
+				"/api/products/1/reviews?page=3&limit=5&sort=most_helpful&min_rating=2&max_rating=4", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(service.lastFilter).To(Equal(entity.ReviewFilter{
+				Sort: entity.ReviewSortMostHelpful, MinRating: 2, MaxRating: 4, Limit: 5, Offset: 10,
+			}))
+		})
+
+		It("should return 400 for invalid min_rating", func() {
+			req, _ := http.NewRequest("GET", "/api/products/1/reviews?min_rating=many", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return 400 for unknown sort order", func() {
+			req, _ := http.NewRequest("GET", "/api/products/1/reviews?sort=random", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return 400 for invalid verified_only", func() {
+			// Arrange
+			req, _ := http.NewRequest("GET", "/api/products/1/reviews?verified_only=maybe", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
 		})
 
 		It("should return 404 for non-existent product", func() {
@@ -2,20 +2,22 @@ package reviews
 
 import (
 	"context"
-	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/reviews/dto"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
 type SellerReviewsService interface {
-	AddReview(ctx context.Context, sellerID int, userID int, rating int, review string) (int, error)
-	GetReviewsByID(ctx context.Context, sellerID int) ([]entity.SellerReview, error)
+	AddReview(ctx context.Context, shopID int, userID int, offerID int, rating int, review string) (int, error)
+	GetReviewsByID(ctx context.Context, shopID int, filter entity.ReviewFilter) (entity.SellerReviewPage, error)
+	UpdateReview(ctx context.Context, shopID int, reviewID int, userID int, rating int, review string) error
+	DeleteReview(ctx context.Context, shopID int, reviewID int, userID int) error
 }
 
 type SellerReviewsHandler struct {
@@ -32,17 +34,20 @@ func NewSellerReviewsHandler(srs SellerReviewsService, l *zap.Logger) *SellerRev
 
 // AddReviews godoc
 // @Summary Добавление отзыва о продавце
-// @Description Добавляет новый отзыв о продавце
+// @Description Добавляет отзыв о магазине по принятому офферу. На одну сделку можно оставить один отзыв
 // @Tags reviews
 // @Accept json
 // @Produce json
-// @Param id path int true "Seller ID"
-// @Param review body dto.AddReviewDTO true "Данные отзыва"
+// @Param id path int true "Shop ID"
+// @Param review body dto.AddSellerReviewDTO true "Данные отзыва"
 // @Security BearerAuth
 // @Success 201 {object} map[string]string "Отзыв успешно добавлен"
 // @Failure 400 {object} map[string]string "Некорректный ввод"
 // @Failure 401 {object} map[string]string "Неавторизованный доступ"
-// @Failure 404 {object} map[string]string "Продавец не найден"
+// @Failure 403 {object} map[string]string "Оффер принадлежит другому пользователю или автору запрещены отзывы"
+// @Failure 404 {object} map[string]string "Продавец или оффер не найден"
+// @Failure 409 {object} map[string]string "Сделка уже оценена"
+// @Failure 422 {object} map[string]string "Оффер не принят или сделан другому продавцу"
 // @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
 // @Router /sellers/{id}/reviews [post]
 func (h *SellerReviewsHandler) AddReview(c *gin.Context) {
@@ -51,41 +56,29 @@ func (h *SellerReviewsHandler) AddReview(c *gin.Context) {
 
 	sellerID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sellerID"})
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid sellerID", err))
 		log.Warn("Failed to parse productID", zap.Error(err))
 		return
 	}
 
-	var addReview dto.AddReviewDTO
+	var addReview dto.AddSellerReviewDTO
 	if err := c.ShouldBindJSON(&addReview); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid input", err))
 		log.Warn("Failed to bind JSON", zap.Error(err))
 		return
 	}
 
-	userID, ok := c.Get("userID")
+	userID, ok := helpers.UserIDContext(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
-		log.Warn("Failed to get userID from context", zap.Error(err))
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
 		return
 	}
 
-	uid, ok := userID.(int)
-	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid userID type"})
-		log.Warn("Invalid userID type")
-		return
-	}
-
-	id, err := h.srs.AddReview(c.Request.Context(), sellerID, uid, addReview.Rating, addReview.Review)
+	_, err = h.srs.AddReview(
+		c.Request.Context(), sellerID, int(userID), addReview.OfferID, addReview.Rating, addReview.Review,
+	)
 	if err != nil {
-		var reviewErr *apperror.ReviewError
-		if errors.As(err, &reviewErr) {
-			c.JSON(http.StatusNotFound, gin.H{"error": reviewErr.Message})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add review"})
-		log.Warn("Failed to add review", zap.Int("id", id), zap.Error(err))
+		writeReviewError(c, log, err, "failed to add review")
 		return
 	}
 
@@ -94,13 +87,19 @@ func (h *SellerReviewsHandler) AddReview(c *gin.Context) {
 
 // GetReviews godoc
 // @Summary Получение списка отзывов о продавце
-// @Description Получает все отзывы о продавце по его ID
+// @Description Возвращает страницу опубликованных отзывов о магазине и сводку по оценкам:
+// @Description средний рейтинг и распределение оценок от 1 до 5 по всем отзывам
 // @Tags reviews
 // @Accept json
 // @Produce json
-// @Param id path int true "Seller ID"
-// @Success 200 {array} entity.SellerReview "Список отзывов"
-// @Failure 400 {object} map[string]string "Некорректный ID продавца"
+// @Param id path int true "Shop ID"
+// @Param page query integer false "Номер страницы (по умолчанию 1)" minimum(1)
+// @Param limit query integer false "Размер страницы (по умолчанию 20)" minimum(1) maximum(100)
+// @Param sort query string false "Сортировка" Enums(newest, highest, lowest, most_helpful)
+// @Param min_rating query integer false "Минимальная оценка" minimum(1) maximum(5)
+// @Param max_rating query integer false "Максимальная оценка" minimum(1) maximum(5)
+// @Success 200 {object} map[string]interface{} "Страница отзывов со сводкой"
+// @Failure 400 {object} map[string]string "Некорректные параметры запроса"
 // @Failure 404 {object} map[string]string "Продавец не найден"
 // @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
 // @Router /sellers/{id}/reviews [get]
@@ -110,29 +109,110 @@ func (h *SellerReviewsHandler) GetReviews(c *gin.Context) {
 
 	sellerID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sellerID"})
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid sellerID", err))
 		log.Warn("Failed to parse sellerID", zap.Error(err))
 		return
 	}
 
-	reviews, err := h.srs.GetReviewsByID(c.Request.Context(), sellerID)
+	filter, page, err := parseReviewFilter(c)
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, err.Error(), nil))
+		return
+	}
+
+	result, err := h.srs.GetReviewsByID(c.Request.Context(), sellerID, filter)
+	if err != nil {
+		writeReviewError(c, log, err, "failed to fetch reviews")
+		return
+	}
+
+	c.JSON(http.StatusOK, reviewListResponse(result.Reviews, result.Total, result.Summary, page, filter.Limit))
+}
+
+// UpdateReview godoc
+// @Summary Изменение отзыва о продавце
+// @Description Автор может изменить отзыв в течение 48 часов после публикации
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Param reviewID path int true "Review ID"
+// @Param review body dto.UpdateReviewDTO true "Данные отзыва"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Отзыв обновлен"
+// @Failure 400 {object} map[string]string "Некорректный ввод"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Не автор отзыва, почта не подтверждена или срок изменения истек"
+// @Failure 404 {object} map[string]string "Отзыв не найден"
+// @Failure 409 {object} map[string]string "Отзыв скрыт модератором"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /sellers/{id}/reviews/{reviewID} [put]
+func (h *SellerReviewsHandler) UpdateReview(c *gin.Context) {
+	const op = "sellerReviewsHandler.UpdateReview()"
+	log := h.logger.With(zap.String("op", op))
+
+	shopID, reviewID, ok := parseReviewPath(c)
+	if !ok {
+		return
+	}
+
+	var update dto.UpdateReviewDTO
+	if err := c.ShouldBindJSON(&update); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid input", err))
+		log.Warn("Failed to bind JSON", zap.Error(err))
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	err := h.srs.UpdateReview(c.Request.Context(), shopID, reviewID, int(userID), update.Rating, update.Review)
 	if err != nil {
-		var reviewErr *apperror.ReviewError
-		if errors.As(err, &reviewErr) {
-			c.JSON(http.StatusNotFound, gin.H{"error": reviewErr.Message})
-			return
-		}
-
-		if err.Error() == "sql: no rows in result set" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "seller not found"})
-			log.Warn("Seller not found", zap.Int("sellerID", sellerID))
-			return
-		}
-
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch reviews"})
-		log.Warn("Failed to fetch reviews", zap.Error(err))
+		writeReviewError(c, log, err, "failed to update review")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "review updated successfully"})
+}
+
+// DeleteReview godoc
+// @Summary Удаление отзыва о продавце
+// @Description Автор может удалить отзыв в течение 48 часов после публикации
+// @Tags reviews
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Param reviewID path int true "Review ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Отзыв удален"
+// @Failure 400 {object} map[string]string "Некорректный ID"
+// @Failure 401 {object} map[string]string "Неавторизованный доступ"
+// @Failure 403 {object} map[string]string "Не автор отзыва или срок изменения истек"
+// @Failure 404 {object} map[string]string "Отзыв не найден"
+// @Failure 409 {object} map[string]string "Отзыв скрыт модератором"
+// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Router /sellers/{id}/reviews/{reviewID} [delete]
+func (h *SellerReviewsHandler) DeleteReview(c *gin.Context) {
+	const op = "sellerReviewsHandler.DeleteReview()"
+	log := h.logger.With(zap.String("op", op))
+
+	shopID, reviewID, ok := parseReviewPath(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	if err := h.srs.DeleteReview(c.Request.Context(), shopID, reviewID, int(userID)); err != nil {
+		writeReviewError(c, log, err, "failed to delete review")
 		return
 	}
 
-	c.JSON(http.StatusOK, reviews)
+	c.JSON(http.StatusOK, gin.H{"message": "review deleted successfully"})
 }
@@ -1,7 +1,6 @@
 package reviews_test
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"net/http"
@@ -9,6 +8,7 @@ import (
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/reviews"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/reviews/dto"
 	"github.com/gin-gonic/gin"
@@ -28,29 +28,55 @@ func newMockSellerReviewsService() *mockSellerReviewsService {
 }
 
 func (m *mockSellerReviewsService) AddReview(
-	_ context.Context, sellerID int, userID int, rating int, review string,
+	_ context.Context, sellerID int, userID int, offerID int, rating int, review string,
 ) (int, error) {
 	if sellerID == 999 {
 		return 0, apperror.NewReviewError(apperror.NotFound, "seller not found")
 	}
+	if sellerID == 77 {
+		return 0, apperror.NewReviewError(apperror.ReviewUnauthorized, "you cannot review your own shop")
+	}
+	if offerID == 42 {
+		return 0, apperror.NewReviewError(apperror.ReviewDuplicate, "this deal has already been reviewed")
+	}
 
 	reviewEntity := entity.SellerReview{
-		SellerID: sellerID,
-		UserID:   userID,
-		Rating:   rating,
-		Review:   review,
+		ShopID:  sellerID,
+		OfferID: &offerID,
+		UserID:  userID,
+		Rating:  rating,
+		Review:  review,
 	}
 	m.reviews[sellerID] = append(m.reviews[sellerID], reviewEntity)
 	return sellerID, nil
 }
 
 func (m *mockSellerReviewsService) GetReviewsByID(
-	_ context.Context, sellerID int,
-) ([]entity.SellerReview, error) {
+	_ context.Context, sellerID int, _ entity.ReviewFilter,
+) (entity.SellerReviewPage, error) {
 	if sellerID == 999 {
-		return nil, apperror.NewReviewError(apperror.NotFound, "seller not found")
+		return entity.SellerReviewPage{}, apperror.NewReviewError(apperror.NotFound, "seller not found")
+	}
+	summary := entity.ReviewSummary{Histogram: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}}
+	for _, review := range m.reviews[sellerID] {
+		summary.Histogram[review.Rating]++
+		summary.Total++
 	}
-	return m.reviews[sellerID], nil
+	return entity.SellerReviewPage{
+		Reviews: m.reviews[sellerID],
+		Total:   len(m.reviews[sellerID]),
+		Summary: summary,
+	}, nil
+}
+
+func (m *mockSellerReviewsService) UpdateReview(
+	_ context.Context, _ int, reviewID int, userID int, _ int, _ string,
+) error {
+	return mockReviewChangeError(reviewID, userID)
+}
+
+func (m *mockSellerReviewsService) DeleteReview(_ context.Context, _ int, reviewID int, userID int) error {
+	return mockReviewChangeError(reviewID, userID)
 }
 
 var _ = Describe("SellerReviewsHandler", func() {
@@ -63,31 +89,23 @@ var _ = Describe("SellerReviewsHandler", func() {
 	BeforeEach(func() {
 		service = newMockSellerReviewsService()
 		handler = reviews.NewSellerReviewsHandler(service, zap.NewNop())
-		gin.SetMode(gin.ReleaseMode)
-		router = gin.New()
+		var secured gin.IRoutes
+		router, secured = newReviewsRouter()
 
-		router.Use(func(c *gin.Context) {
-			c.Set("userID", 1)
-			c.Next()
-		})
-		router.POST("/api/sellers/:id/reviews", handler.AddReview)
 		router.GET("/api/sellers/:id/reviews", handler.GetReviews)
+		secured.POST("/api/sellers/:id/reviews", middleware.RequireVerifiedEmail(), handler.AddReview)
 	})
 
 	Context("AddReview", func() {
-		It("should add a new review successfully", func() {
-			// Arrange
-			review := dto.AddReviewDTO{
-				Rating: 5,
-				Review: "Great seller!",
-			}
-			body, _ := json.Marshal(review)
-			req, _ := http.NewRequest("POST", "/api/sellers/1/reviews", bytes.NewBuffer(body))
-			req.Header.Set("Content-Type", "application/json")
-			w := httptest.NewRecorder()
+		review := dto.AddSellerReviewDTO{
+			OfferID: 1,
+			Rating:  5,
+			Review:  "Great seller!",
+		}
 
+		It("should add a new review successfully", func() {
 			// Act
-			router.ServeHTTP(w, req)
+			w := serve(router, http.MethodPost, "/api/sellers/1/reviews", review, 1)
 
 			// Assert
 			Expect(w.Code).To(Equal(http.StatusCreated))
@@ -95,36 +113,52 @@ var _ = Describe("SellerReviewsHandler", func() {
 			err := json.Unmarshal(w.Body.Bytes(), &response)
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(response["message"]).To(Equal("review added successfully"))
+			Expect(service.reviews[1]).To(HaveLen(1))
+			Expect(service.reviews[1][0].UserID).To(Equal(1))
 		})
 
 		It("should return 404 for non-existent seller", func() {
-			// Arrange
-			review := dto.AddReviewDTO{
-				Rating: 5,
-				Review: "Great seller!",
-			}
-			body, _ := json.Marshal(review)
-			req, _ := http.NewRequest("POST", "/api/sellers/999/reviews", bytes.NewBuffer(body))
-			req.Header.Set("Content-Type", "application/json")
-			w := httptest.NewRecorder()
+			w := serve(router, http.MethodPost, "/api/sellers/999/reviews", review, 1)
 
-			// Act
-			router.ServeHTTP(w, req)
-
-			// Assert
 			Expect(w.Code).To(Equal(http.StatusNotFound))
 		})
 
-		It("should return 400 for invalid input", func() {
-			// Arrange
-			req, _ := http.NewRequest("POST", "/api/sellers/1/reviews", bytes.NewBuffer([]byte("invalid json")))
-			req.Header.Set("Content-Type", "application/json")
-			w := httptest.NewRecorder()
+		It("should return 403 for the own shop", func() {
+			w := serve(router, http.MethodPost, "/api/sellers/77/reviews", review, 1)
 
-			// Act
-			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(service.reviews[77]).To(BeEmpty())
+		})
+
+		It("should return 409 when the deal is already reviewed", func() {
+			duplicate := review
+			duplicate.OfferID = 42
+
+			w := serve(router, http.MethodPost, "/api/sellers/1/reviews", duplicate, 1)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+			var response map[string]string
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response["code"]).To(Equal(apperror.ReviewDuplicate))
+		})
+
+		It("should return 401 without a token", func() {
+			w := serve(router, http.MethodPost, "/api/sellers/1/reviews", review, 0)
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(service.reviews[1]).To(BeEmpty())
+		})
+
+		It("should return 400 without an offer", func() {
+			w := serve(router, http.MethodPost, "/api/sellers/1/reviews",
+				dto.AddReviewDTO{Rating: 5, Review: "Great seller!"}, 1)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return 400 for invalid input", func() {
+			w := serve(router, http.MethodPost, "/api/sellers/1/reviews", "invalid json", 1)
 
-			// Assert
 			Expect(w.Code).To(Equal(http.StatusBadRequest))
 		})
 	})
@@ -134,10 +168,10 @@ var _ = Describe("SellerReviewsHandler", func() {
 			// Arrange
 			service.reviews[1] = []entity.SellerReview{
 				{
-					SellerID: 1,
-					UserID:   1,
-					Rating:   5,
-					Review:   "Great seller!",
+					ShopID: 1,
+					UserID: 1,
+					Rating: 5,
+					Review: "Great seller!",
 				},
 			}
 			req, _ := http.NewRequest("GET", "/api/sellers/1/reviews", nil)
@@ -148,14 +182,18 @@ var _ = Describe("SellerReviewsHandler", func() {
 
 			// Assert
 			Expect(w.Code).To(Equal(http.StatusOK))
-			var response []entity.SellerReview
+			var response struct {
+				Data    []entity.SellerReview `json:"data"`
+				Summary entity.ReviewSummary  `json:"summary"`
+			}
 			err := json.Unmarshal(w.Body.Bytes(), &response)
 			Expect(err).ShouldNot(HaveOccurred())
-			Expect(response).To(HaveLen(1))
-			Expect(response[0].SellerID).To(Equal(1))
-			Expect(response[0].UserID).To(Equal(1))
-			Expect(response[0].Rating).To(Equal(5))
-			Expect(response[0].Review).To(Equal("Great seller!"))
+			Expect(response.Data).To(HaveLen(1))
+			Expect(response.Data[0].ShopID).To(Equal(1))
+			Expect(response.Data[0].UserID).To(Equal(1))
+			Expect(response.Data[0].Rating).To(Equal(5))
+			Expect(response.Data[0].Review).To(Equal("Great seller!"))
+			Expect(response.Summary.Histogram).To(HaveKeyWithValue(5, 1))
 		})
 
 		It("should return 404 for non-existent seller", func() {
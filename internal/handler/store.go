@@ -0,0 +1,415 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/gin-gonic/gin"
+)
+
+type StoreService interface {
+	CreateStore(ctx context.Context, store entity.Store) (uint, error)
+	UpdateStore(ctx context.Context, store entity.Store, userID uint) error
+	GetStore(ctx context.Context, id uint) (entity.Store, error)
+	GetStores(ctx context.Context, filter model.StoreFilter, limit, offset int) ([]entity.Store, int, error)
+
+	GetMembers(ctx context.Context, shopID, userID uint) ([]entity.ShopMember, error)
+	InviteMember(
+		ctx context.Context, shopID, userID uint, email string, role entity.ShopRole,
+	) (entity.ShopInvitation, error)
+	AcceptInvitation(ctx context.Context, token string, userID uint, userEmail string) (entity.ShopInvitation, error)
+	UpdateMemberRole(ctx context.Context, shopID, userID, memberID uint, role entity.ShopRole) error
+	RemoveMember(ctx context.Context, shopID, userID, memberID uint) error
+}
+
+type StoreHandler struct {
+	storeService StoreService
+}
+
+func NewStoreHandler(storeService StoreService) *StoreHandler {
+	return &StoreHandler{storeService: storeService}
+}
+
+// GetStores godoc
+// @Summary      Получить список магазинов
+// @Description  Возвращает магазины с поиском по названию и описанию и пагинацией
+// @Tags         shops
+// @Produce      json
+// @Param        page    query     int     false  "Номер страницы (по умолчанию 1)"
+// @Param        limit   query     int     false  "Размер страницы (по умолчанию 10, максимум 100)"
+// @Param        search  query     string  false  "Поиск по названию и описанию (по подстроке)"
+// @Success      200     {object}  map[string]interface{} "Список магазинов и метаинформация"
+// @Failure      400     {object}  apperror.Error "Некорректный запрос"
+// @Router       /shops [get]
+func (h *StoreHandler) GetStores(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid page number", err))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid limit value (should be between 1 and 100)", err))
+		return
+	}
+
+	var filter model.StoreFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid query parameters", err))
+		return
+	}
+
+	stores, total, err := h.storeService.GetStores(c.Request.Context(), filter, limit, (page-1)*limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": stores,
+		"meta": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total_items":  total,
+			"total_pages":  int(math.Ceil(float64(total) / float64(limit))),
+		},
+	})
+}
+
+// GetStore godoc
+// @Summary      Получить магазин
+// @Description  Возвращает публичный профиль магазина с количеством товаров и рейтингом продавца
+// @Tags         shops
+// @Produce      json
+// @Param        id   path      int  true  "ID магазина"
+// @Success      200  {object}  entity.Store
+// @Failure      400  {object}  apperror.Error "Некорректный ID"
+// @Failure      404  {object}  apperror.Error "Магазин не найден"
+// @Router       /shops/{id} [get]
+func (h *StoreHandler) GetStore(c *gin.Context) {
+	id, ok := parseStoreID(c)
+	if !ok {
+		return
+	}
+
+	store, err := h.storeService.GetStore(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, store)
+}
+
+// PostStore godoc
+// @Summary      Создать магазин
+// @Description  Создает магазин, владельцем становится текущий пользователь. Доступно только аккаунтам магазинов
+// @Tags         shops
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.PostStoreReq  true  "Профиль магазина"
+// @Security     BearerAuth
+// @Success      201   {object}  dto.PostStoreResp
+// @Failure      400   {object}  apperror.Error "Некорректные данные"
+// @Failure      403   {object}  apperror.Error "Аккаунт не является аккаунтом магазина"
+// @Router       /shops [post]
+func (h *StoreHandler) PostStore(c *gin.Context) {
+	userID, ok := storeOwnerID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostStoreReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid shop data", err))
+		return
+	}
+
+	store := req.ConvertToEntity()
+	store.UserID = userID
+
+	id, err := h.storeService.CreateStore(c.Request.Context(), store)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.PostStoreResp{ID: id})
+}
+
+// PutStore godoc
+// @Summary      Обновить магазин
+// @Description  Полностью заменяет профиль магазина. Доступно только владельцу магазина
+// @Tags         shops
+// @Accept       json
+// @Param        id    path  int               true  "ID магазина"
+// @Param        body  body  dto.PostStoreReq  true  "Профиль магазина"
+// @Security     BearerAuth
+// @Success      204
+// @Failure      400   {object}  apperror.Error "Некорректные данные"
+// @Failure      403   {object}  apperror.Error "Пользователь не владеет магазином"
+// @Failure      404   {object}  apperror.Error "Магазин не найден"
+// @Router       /shops/{id} [put]
+func (h *StoreHandler) PutStore(c *gin.Context) {
+	userID, ok := storeOwnerID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseStoreID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostStoreReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid shop data", err))
+		return
+	}
+
+	store := req.ConvertToEntity()
+	store.ID = id
+
+	if err := h.storeService.UpdateStore(c.Request.Context(), store, userID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetMembers godoc
+// @Summary      Получить сотрудников магазина
+// @Description  Возвращает участников магазина с их ролями. Доступно любому участнику магазина
+// @Tags         shops
+// @Produce      json
+// @Param        id   path      int  true  "ID магазина"
+// @Security     BearerAuth
+// @Success      200  {array}   entity.ShopMember
+// @Failure      403  {object}  apperror.Error "Пользователь не состоит в магазине"
+// @Failure      404  {object}  apperror.Error "Магазин не найден"
+// @Router       /shops/{id}/members [get]
+func (h *StoreHandler) GetMembers(c *gin.Context) {
+	userID, ok := storeOwnerID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseStoreID(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.storeService.GetMembers(c.Request.Context(), id, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// PostInvitation godoc
+// @Summary      Пригласить сотрудника
+// @Description  Отправляет приглашение в магазин на email с ролью manager или viewer. Доступно только владельцу
+// @Tags         shops
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                        true  "ID магазина"
+// @Param        body  body      dto.PostShopInvitationReq  true  "Email и роль сотрудника"
+// @Security     BearerAuth
+// @Success      201   {object}  entity.ShopInvitation
+// @Failure      400   {object}  apperror.Error "Некорректные данные"
+// @Failure      403   {object}  apperror.Error "Пользователь не владеет магазином"
+// @Failure      404   {object}  apperror.Error "Магазин не найден"
+// @Router       /shops/{id}/invitations [post]
+func (h *StoreHandler) PostInvitation(c *gin.Context) {
+	userID, ok := storeOwnerID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseStoreID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostShopInvitationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid invitation data", err))
+		return
+	}
+
+	role := entity.ShopRole(req.Role)
+	invitation, err := h.storeService.InviteMember(c.Request.Context(), id, userID, req.Email, role)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// AcceptInvitation godoc
+// @Summary      Принять приглашение в магазин
+// @Description  Добавляет текущего пользователя в магазин по токену из письма. Email аккаунта должен совпадать
+// @Tags         shops
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.AcceptShopInvitationReq  true  "Токен приглашения"
+// @Security     BearerAuth
+// @Success      200   {object}  entity.ShopInvitation
+// @Failure      400   {object}  apperror.Error "Некорректный или просроченный токен"
+// @Failure      403   {object}  apperror.Error "Приглашение отправлено на другой email"
+// @Failure      404   {object}  apperror.Error "Приглашение не найдено"
+// @Failure      409   {object}  apperror.Error "Приглашение уже принято"
+// @Router       /shops/invitations/accept [post]
+func (h *StoreHandler) AcceptInvitation(c *gin.Context) {
+	userID, ok := storeOwnerID(c)
+	if !ok {
+		return
+	}
+
+	userEmail, ok := helpers.UserEmailContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	var req dto.AcceptShopInvitationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid invitation token", err))
+		return
+	}
+
+	invitation, err := h.storeService.AcceptInvitation(c.Request.Context(), req.Token, userID, userEmail)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitation)
+}
+
+// PutMember godoc
+// @Summary      Изменить роль сотрудника
+// @Description  Меняет роль сотрудника на manager или viewer. Доступно только владельцу магазина
+// @Tags         shops
+// @Accept       json
+// @Param        id      path  int                   true  "ID магазина"
+// @Param        userID  path  int                   true  "ID сотрудника"
+// @Param        body    body  dto.PutShopMemberReq  true  "Новая роль"
+// @Security     BearerAuth
+// @Success      204
+// @Failure      400     {object}  apperror.Error "Некорректные данные"
+// @Failure      403     {object}  apperror.Error "Пользователь не владеет магазином"
+// @Failure      404     {object}  apperror.Error "Сотрудник не найден"
+// @Failure      409     {object}  apperror.Error "Нельзя изменить роль владельца"
+// @Router       /shops/{id}/members/{userID} [put]
+func (h *StoreHandler) PutMember(c *gin.Context) {
+	userID, ok := storeOwnerID(c)
+	if !ok {
+		return
+	}
+
+	id, memberID, ok := parseMemberPath(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PutShopMemberReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid member data", err))
+		return
+	}
+
+	err := h.storeService.UpdateMemberRole(c.Request.Context(), id, userID, memberID, entity.ShopRole(req.Role))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteMember godoc
+// @Summary      Удалить сотрудника
+// @Description  Владелец может удалить любого сотрудника, остальные участники могут покинуть магазин сами
+// @Tags         shops
+// @Param        id      path  int  true  "ID магазина"
+// @Param        userID  path  int  true  "ID сотрудника"
+// @Security     BearerAuth
+// @Success      204
+// @Failure      403     {object}  apperror.Error "Недостаточно прав"
+// @Failure      404     {object}  apperror.Error "Сотрудник не найден"
+// @Failure      409     {object}  apperror.Error "Нельзя удалить владельца"
+// @Router       /shops/{id}/members/{userID} [delete]
+func (h *StoreHandler) DeleteMember(c *gin.Context) {
+	userID, ok := storeOwnerID(c)
+	if !ok {
+		return
+	}
+
+	id, memberID, ok := parseMemberPath(c)
+	if !ok {
+		return
+	}
+
+	if err := h.storeService.RemoveMember(c.Request.Context(), id, userID, memberID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func parseMemberPath(c *gin.Context) (uint, uint, bool) {
+	id, ok := parseStoreID(c)
+	if !ok {
+		return 0, 0, false
+	}
+
+	memberID, err := strconv.ParseUint(c.Param("userID"), 10, 32)
+	if err != nil || memberID < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid user id", err))
+		return 0, 0, false
+	}
+	return id, uint(memberID), true
+}
+
+func parseStoreID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid shop id", err))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// storeOwnerID достает ID пользователя и проверяет, что это аккаунт магазина
+func storeOwnerID(c *gin.Context) (uint, bool) {
+	isStore, ok := helpers.UserIsStoreContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return 0, false
+	}
+	if !isStore {
+		_ = c.Error(apperror.New(apperror.Forbidden, "available only for store accounts", nil))
+		return 0, false
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return 0, false
+	}
+	return userID, true
+}
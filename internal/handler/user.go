@@ -9,6 +9,7 @@ import (
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
 	"github.com/gin-gonic/gin"
 )
 
@@ -20,6 +21,14 @@ type UserService interface {
 	Refresh(ctx context.Context, refreshToken, fingerprint string) (string, string, error)
 	Logout(ctx context.Context, refreshToken, fingerprint string) error
 	GetUserByID(ctx context.Context, id uint) (entity.User, error)
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+	VerifyEmail(ctx context.Context, token string) (entity.User, error)
+	ResendVerification(ctx context.Context, userID uint) error
+	UpdateProfile(ctx context.Context, userID uint, update user.UpdateProfile) (entity.User, error)
+	ChangeEmail(ctx context.Context, userID uint, newEmail, password string) (entity.User, error)
+	ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string) error
+	DeleteAccount(ctx context.Context, userID uint, password string) error
 }
 
 type UserHandler struct {
@@ -203,6 +212,307 @@ func (h *UserHandler) Logout(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+// ForgotPassword godoc
+//
+//	@Summary		Запрос сброса пароля
+//	@Description	Отправляет на почту одноразовый токен сброса пароля. Ответ одинаковый для любой почты,
+//	@Description	чтобы по нему нельзя было узнать, зарегистрирован ли адрес
+//	@Tags			auth
+//	@Accept			json
+//	@Param			body	body	dto.ForgotPasswordReq	true	"Почта аккаунта"
+//	@Success		202
+//	@Failure		400	{object}	apperror.AppError
+//	@Router			/auth/password/forgot [post]
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid email", err))
+		return
+	}
+
+	if err := h.userService.ForgotPassword(c.Request.Context(), req.Email); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ResetPassword godoc
+//
+//	@Summary		Сброс пароля
+//	@Description	Задает новый пароль по токену из письма и завершает все сессии пользователя
+//	@Tags			auth
+//	@Accept			json
+//	@Param			body	body	dto.ResetPasswordReq	true	"Токен из письма и новый пароль"
+//	@Success		204
+//	@Failure		400	{object}	apperror.AppError	"Некорректный, просроченный или использованный токен"
+//	@Router			/auth/password/reset [post]
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid password reset data", err))
+		return
+	}
+
+	if err := h.userService.ResetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+var verifyEmailPage = confirmationPage{
+	Title:    "confirm email",
+	Question: "Confirm this email address for your Stawberry account?",
+	Button:   "Confirm email",
+	Done:     "Your email has been verified.",
+}
+
+// VerifyEmailPage godoc
+//
+//	@Summary		Страница подтверждения почты
+//	@Description	Ссылка из письма. Только показывает форму подтверждения, токен не гасит:
+//	@Description	GET по ссылке могут выполнить почтовые сканеры и предзагрузка браузера
+//	@Tags			auth
+//	@Produce		html
+//	@Param			token	query		string	true	"Токен из письма"
+//	@Success		200		{string}	string	"Страница с формой подтверждения"
+//	@Failure		400		{object}	apperror.AppError
+//	@Router			/auth/email/verify [get]
+func (h *UserHandler) VerifyEmailPage(c *gin.Context) {
+	verifyEmailPage.renderForm(c)
+}
+
+// VerifyEmail godoc
+//
+//	@Summary		Подтверждение почты
+//	@Description	Подтверждает почту по токену из ссылки в письме. Вызывается формой со страницы
+//	@Description	подтверждения, открывается без авторизации
+//	@Tags			auth
+//	@Produce		json,html
+//	@Param			token	query		string	true	"Токен из письма"
+//	@Success		200		{object}	dto.VerifyEmailResp
+//	@Failure		400		{object}	apperror.AppError	"Некорректный, просроченный или использованный токен"
+//	@Router			/auth/email/verify [post]
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		_ = c.Error(apperror.New(apperror.BadRequest, "token is required", nil))
+		return
+	}
+
+	if _, err := h.userService.VerifyEmail(c.Request.Context(), token); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if wantsHTML(c) {
+		verifyEmailPage.renderDone(c)
+		return
+	}
+	c.JSON(http.StatusOK, dto.VerifyEmailResp{Message: "your email has been verified"})
+}
+
+// ResendVerification godoc
+//
+//	@Summary		Повторная отправка ссылки подтверждения
+//	@Description	Отправляет новую ссылку подтверждения почты. Не чаще раза в минуту и не больше пяти раз в час
+//	@Tags			auth
+//	@Security		BearerAuth
+//	@Success		202
+//	@Failure		401	{object}	apperror.AppError
+//	@Failure		409	{object}	apperror.AppError	"Почта уже подтверждена"
+//	@Failure		429	{object}	apperror.AppError	"Письмо недавно отправлялось"
+//	@Router			/auth/email/resend [post]
+func (h *UserHandler) ResendVerification(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	if err := h.userService.ResendVerification(c.Request.Context(), userID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// GetProfile godoc
+//
+//	@Summary		Профиль пользователя
+//	@Description	Возвращает профиль авторизованного пользователя вместе со статусом подтверждения почты
+//	@Tags			users
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.UserProfileResp
+//	@Failure		401	{object}	apperror.AppError
+//	@Router			/me [get]
+func (h *UserHandler) GetProfile(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	user, err := h.userService.GetUserByID(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.UserProfileFromEntity(user))
+}
+
+// UpdateProfile godoc
+//
+//	@Summary		Изменение профиля
+//	@Description	Меняет имя и телефон авторизованного пользователя. Непереданные поля не меняются
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			body	body		dto.UpdateProfileReq	true	"Новые имя и телефон"
+//	@Success		200		{object}	dto.UserProfileResp
+//	@Failure		400		{object}	apperror.AppError
+//	@Failure		401		{object}	apperror.AppError
+//	@Router			/me [patch]
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	var req dto.UpdateProfileReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid profile data", err))
+		return
+	}
+	if req.Name == nil && req.Phone == nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "nothing to update", nil))
+		return
+	}
+
+	user, err := h.userService.UpdateProfile(c.Request.Context(), userID, req.ConvertToSvc())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.UserProfileFromEntity(user))
+}
+
+// ChangeEmail godoc
+//
+//	@Summary		Смена почты
+//	@Description	Меняет почту после проверки пароля и отправляет на нее ссылку подтверждения.
+//	@Description	До подтверждения новой почты офферы и отзывы недоступны
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			body	body		dto.ChangeEmailReq	true	"Новая почта и текущий пароль"
+//	@Success		200		{object}	dto.UserProfileResp
+//	@Failure		400		{object}	apperror.AppError
+//	@Failure		401		{object}	apperror.AppError	"Неверный пароль"
+//	@Failure		409		{object}	apperror.AppError	"Почта занята"
+//	@Router			/me/email [put]
+func (h *UserHandler) ChangeEmail(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	var req dto.ChangeEmailReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid email data", err))
+		return
+	}
+
+	user, err := h.userService.ChangeEmail(c.Request.Context(), userID, req.Email, req.Password)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.UserProfileFromEntity(user))
+}
+
+// ChangePassword godoc
+//
+//	@Summary		Смена пароля
+//	@Description	Меняет пароль после проверки старого и завершает все сессии пользователя
+//	@Tags			users
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Param			body	body	dto.ChangePasswordReq	true	"Старый и новый пароль"
+//	@Success		204
+//	@Failure		400	{object}	apperror.AppError
+//	@Failure		401	{object}	apperror.AppError	"Неверный старый пароль"
+//	@Router			/me/password [put]
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	var req dto.ChangePasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid password data", err))
+		return
+	}
+
+	if err := h.userService.ChangePassword(c.Request.Context(), userID, req.OldPassword, req.NewPassword); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteAccount godoc
+//
+//	@Summary		Удаление аккаунта
+//	@Description	Удаляет аккаунт после проверки пароля. Личные данные затираются, отзывы остаются
+//	@Description	от имени удаленного пользователя, ожидающие офферы отменяются
+//	@Tags			users
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Param			body	body	dto.DeleteAccountReq	true	"Текущий пароль"
+//	@Success		204
+//	@Failure		400	{object}	apperror.AppError
+//	@Failure		401	{object}	apperror.AppError	"Неверный пароль"
+//	@Failure		409	{object}	apperror.AppError	"Пользователь владеет магазином"
+//	@Router			/me [delete]
+func (h *UserHandler) DeleteAccount(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "user not authenticated", nil))
+		return
+	}
+
+	var req dto.DeleteAccountReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid account data", err))
+		return
+	}
+
+	if err := h.userService.DeleteAccount(c.Request.Context(), userID, req.Password); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	setRefreshCookie(c, "", h.basePath, h.domain, -1)
+
+	c.Status(http.StatusNoContent)
+}
+
 func setRefreshCookie(c *gin.Context, refreshToken, basePath, domain string, maxAge int) {
 	jwtCookie := http.Cookie{
 		Name:     "refresh_token",
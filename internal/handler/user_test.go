@@ -6,10 +6,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/EM-Stawberry/Stawberry/config"
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
 	"github.com/gin-gonic/gin"
 	. "github.com/onsi/ginkgo/v2"
@@ -416,6 +420,280 @@ var _ = Describe("UserHandler", func() {
 			})
 		})
 	})
+
+	Describe("ForgotPassword", func() {
+		BeforeEach(func() {
+			router.POST("/password/forgot", handler.ForgotPassword)
+		})
+
+		Context("when the request is valid", func() {
+			It("should return accepted", func() {
+				mockService.EXPECT().ForgotPassword(gomock.Any(), "test@example.com").Return(nil)
+
+				jsonData, _ := json.Marshal(dto.ForgotPasswordReq{Email: "test@example.com"})
+				req := httptest.NewRequest("POST", "/password/forgot", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusAccepted))
+			})
+		})
+
+		Context("when email is malformed", func() {
+			It("should return bad request", func() {
+				jsonData, _ := json.Marshal(dto.ForgotPasswordReq{Email: "not-an-email"})
+				req := httptest.NewRequest("POST", "/password/forgot", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("ResetPassword", func() {
+		BeforeEach(func() {
+			router.POST("/password/reset", handler.ResetPassword)
+		})
+
+		Context("when the token is valid", func() {
+			It("should return no content", func() {
+				mockService.EXPECT().ResetPassword(gomock.Any(), "token", "new-password").Return(nil)
+
+				jsonData, _ := json.Marshal(dto.ResetPasswordReq{Token: "token", Password: "new-password"})
+				req := httptest.NewRequest("POST", "/password/reset", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+			})
+		})
+
+		Context("when the token is invalid or used", func() {
+			It("should return bad request", func() {
+				mockService.EXPECT().
+					ResetPassword(gomock.Any(), "token", "new-password").
+					Return(apperror.ErrInvalidPasswordResetToken)
+
+				jsonData, _ := json.Marshal(dto.ResetPasswordReq{Token: "token", Password: "new-password"})
+				req := httptest.NewRequest("POST", "/password/reset", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("when the password is too short", func() {
+			It("should return bad request", func() {
+				jsonData, _ := json.Marshal(dto.ResetPasswordReq{Token: "token", Password: "short"})
+				req := httptest.NewRequest("POST", "/password/reset", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("VerifyEmail", func() {
+		BeforeEach(func() {
+			router.GET("/email/verify", handler.VerifyEmailPage)
+			router.POST("/email/verify", handler.VerifyEmail)
+		})
+
+		Context("when the link is opened", func() {
+			It("should only show the confirmation form", func() {
+				req := httptest.NewRequest("GET", "/email/verify?token=token", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Header().Get("Content-Type")).To(HavePrefix("text/html"))
+				Expect(w.Body.String()).To(ContainSubstring(`<form method="post" action="?token=token">`))
+			})
+		})
+
+		Context("when the token is valid", func() {
+			It("should show the result to the confirmation form", func() {
+				mockService.EXPECT().VerifyEmail(gomock.Any(), "token").Return(entity.User{ID: 1}, nil)
+
+				req := httptest.NewRequest("POST", "/email/verify?token=token", nil)
+				req.Header.Set("Accept", "text/html")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Body.String()).To(ContainSubstring("Your email has been verified."))
+			})
+
+			It("should confirm the email", func() {
+				mockService.EXPECT().VerifyEmail(gomock.Any(), "token").Return(entity.User{ID: 1}, nil)
+
+				req := httptest.NewRequest("POST", "/email/verify?token=token", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+			})
+		})
+
+		Context("when the token is invalid or used", func() {
+			It("should return bad request", func() {
+				mockService.EXPECT().VerifyEmail(gomock.Any(), "token").
+					Return(entity.User{}, apperror.ErrInvalidVerificationToken)
+
+				req := httptest.NewRequest("POST", "/email/verify?token=token", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("when the token is missing", func() {
+			It("should return bad request", func() {
+				req := httptest.NewRequest("POST", "/email/verify", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("ResendVerification", func() {
+		BeforeEach(func() {
+			router.POST("/email/resend", func(c *gin.Context) {
+				c.Set(helpers.UserIDKey, uint(1))
+			}, handler.ResendVerification)
+		})
+
+		It("should return accepted", func() {
+			mockService.EXPECT().ResendVerification(gomock.Any(), uint(1)).Return(nil)
+
+			req := httptest.NewRequest("POST", "/email/resend", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusAccepted))
+		})
+
+		It("should return too many requests when rate limited", func() {
+			mockService.EXPECT().ResendVerification(gomock.Any(), uint(1)).
+				Return(apperror.New(apperror.TooManyRequests, "try again later", nil))
+
+			req := httptest.NewRequest("POST", "/email/resend", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusTooManyRequests))
+		})
+	})
+
+	Describe("GetProfile", func() {
+		BeforeEach(func() {
+			router.GET("/me", func(c *gin.Context) {
+				c.Set(helpers.UserIDKey, uint(1))
+			}, handler.GetProfile)
+		})
+
+		It("should expose the verification status", func() {
+			verifiedAt := time.Date(2025, 7, 16, 10, 0, 0, 0, time.UTC)
+			mockService.EXPECT().GetUserByID(gomock.Any(), uint(1)).
+				Return(entity.User{ID: 1, Name: "Anna", Role: entity.UserRoleUser, EmailVerifiedAt: &verifiedAt}, nil)
+
+			req := httptest.NewRequest("GET", "/me", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var resp dto.UserProfileResp
+			Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
+			Expect(resp.EmailVerified).To(BeTrue())
+			Expect(resp.EmailVerifiedAt).To(HaveValue(BeTemporally("==", verifiedAt)))
+		})
+	})
+
+	Describe("Account management", func() {
+		BeforeEach(func() {
+			authenticated := func(c *gin.Context) {
+				c.Set(helpers.UserIDKey, uint(1))
+			}
+			router.PATCH("/me", authenticated, handler.UpdateProfile)
+			router.PUT("/me/email", authenticated, handler.ChangeEmail)
+			router.PUT("/me/password", authenticated, handler.ChangePassword)
+			router.DELETE("/me", authenticated, handler.DeleteAccount)
+		})
+
+		send := func(method, path string, body any) *httptest.ResponseRecorder {
+			jsonData, _ := json.Marshal(body)
+			req := httptest.NewRequest(method, path, bytes.NewBuffer(jsonData))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		It("should update only the passed profile fields", func() {
+			phone := "+79990000000"
+			mockService.EXPECT().UpdateProfile(gomock.Any(), uint(1), user.UpdateProfile{Phone: &phone}).
+				Return(entity.User{ID: 1, Phone: phone}, nil)
+
+			w := send("PATCH", "/me", map[string]string{"phone": phone})
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should reject an empty profile update", func() {
+			w := send("PATCH", "/me", map[string]string{})
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return the unverified profile after changing the email", func() {
+			mockService.EXPECT().ChangeEmail(gomock.Any(), uint(1), "new@example.com", "secret").
+				Return(entity.User{ID: 1, Email: "new@example.com"}, nil)
+
+			w := send("PUT", "/me/email", dto.ChangeEmailReq{Email: "new@example.com", Password: "secret"})
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var resp dto.UserProfileResp
+			Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
+			Expect(resp.EmailVerified).To(BeFalse())
+		})
+
+		It("should return unauthorized for a wrong old password", func() {
+			mockService.EXPECT().ChangePassword(gomock.Any(), uint(1), "wrong", "new-password").
+				Return(apperror.ErrIncorrectPassword)
+
+			w := send("PUT", "/me/password", dto.ChangePasswordReq{OldPassword: "wrong", NewPassword: "new-password"})
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should delete the account and clear the refresh cookie", func() {
+			mockService.EXPECT().DeleteAccount(gomock.Any(), uint(1), "secret").Return(nil)
+
+			w := send("DELETE", "/me", dto.DeleteAccountReq{Password: "secret"})
+
+			Expect(w.Code).To(Equal(http.StatusNoContent))
+			Expect(w.Header().Get("Set-Cookie")).To(ContainSubstring("refresh_token=;"))
+		})
+
+		It("should return conflict when the user owns a shop", func() {
+			mockService.EXPECT().DeleteAccount(gomock.Any(), uint(1), "secret").Return(apperror.ErrAccountOwnsShop)
+
+			w := send("DELETE", "/me", dto.DeleteAccountReq{Password: "secret"})
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+		})
+	})
 })
 
 func TestUserHandler(t *testing.T) {
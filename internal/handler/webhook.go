@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, shopID, userID uint, rawURL string, events []string) (entity.Webhook, error)
+	GetWebhooks(ctx context.Context, shopID, userID uint) ([]entity.Webhook, error)
+	UpdateWebhook(ctx context.Context, userID uint, webhook entity.Webhook) (entity.Webhook, error)
+	DeleteWebhook(ctx context.Context, shopID, userID, webhookID uint) error
+	GetDeliveries(
+		ctx context.Context, shopID, userID, webhookID uint, page, limit int,
+	) ([]entity.WebhookDelivery, int, error)
+	Ping(ctx context.Context, shopID, userID, webhookID uint) (entity.WebhookDelivery, error)
+}
+
+type WebhookHandler struct {
+	webhookService WebhookService
+}
+
+func NewWebhookHandler(webhookService WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// GetWebhooks godoc
+// @Summary      Получить вебхуки магазина
+// @Description  Возвращает вебхуки магазина без секретов. Доступно владельцу и менеджерам
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      int  true  "ID магазина"
+// @Security     BearerAuth
+// @Success      200  {array}   entity.Webhook
+// @Failure      403  {object}  apperror.Error "Недостаточно прав"
+// @Failure      404  {object}  apperror.Error "Магазин не найден"
+// @Router       /shops/{id}/webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	userID, ok := storeOwnerID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseStoreID(c)
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.webhookService.GetWebhooks(c.Request.Context(), id, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// PostWebhook godoc
+// @Summary      Создать вебхук
+// @Description  Регистрирует адрес для событий offer.created, offer.status_changed и review.added.
+// @Description  Запросы подписываются HMAC-SHA256 в заголовке X-Stawberry-Signature, секрет возвращается
+// @Description  только в этом ответе
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                 true  "ID магазина"
+// @Param        body  body      dto.PostWebhookReq  true  "Адрес и события"
+// @Security     BearerAuth
+// @Success      201   {object}  entity.Webhook
+// @Failure      400   {object}  apperror.Error "Некорректные данные"
+// @Failure      403   {object}  apperror.Error "Недостаточно прав"
+// @Failure      404   {object}  apperror.Error "Магазин не найден"
+// @Router       /shops/{id}/webhooks [post]
+func (h *WebhookHandler) PostWebhook(c *gin.Context) {
+	userID, ok := storeOwnerID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseStoreID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostWebhookReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid webhook data", err))
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Request.Context(), id, userID, req.URL, req.Events)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// PutWebhook godoc
+// @Summary      Изменить вебхук
+// @Description  Меняет адрес, события и активность вебхука. Секрет не меняется
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int                true  "ID магазина"
+// @Param        webhookID  path      int                true  "ID вебхука"
+// @Param        body       body      dto.PutWebhookReq  true  "Адрес, события и активность"
+// @Security     BearerAuth
+// @Success      200        {object}  entity.Webhook
+// @Failure      400        {object}  apperror.Error "Некорректные данные"
+// @Failure      403        {object}  apperror.Error "Недостаточно прав"
+// @Failure      404        {object}  apperror.Error "Вебхук не найден"
+// @Router       /shops/{id}/webhooks/{webhookID} [put]
+func (h *WebhookHandler) PutWebhook(c *gin.Context) {
+	userID, ok := storeOwnerID(c)
+	if !ok {
+		return
+	}
+
+	id, webhookID, ok := parseWebhookPath(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PutWebhookReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid webhook data", err))
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c.Request.Context(), userID, req.ConvertToEntity(id, webhookID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook godoc
+// @Summary      Удалить вебхук
+// @Description  Удаляет вебхук вместе с журналом доставок
+// @Tags         webhooks
+// @Param        id         path  int  true  "ID магазина"
+// @Param        webhookID  path  int  true  "ID вебхука"
+// @Security     BearerAuth
+// @Success      204
+// @Failure      403        {object}  apperror.Error "Недостаточно прав"
+// @Failure      404        {object}  apperror.Error "Вебхук не найден"
+// @Router       /shops/{id}/webhooks/{webhookID} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	userID, ok := storeOwnerID(c)
+	if !ok {
+		return
+	}
+
+	id, webhookID, ok := parseWebhookPath(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), id, userID, webhookID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetDeliveries godoc
+// @Summary      Получить журнал доставок вебхука
+// @Description  Возвращает попытки доставки событий, новые первыми
+// @Tags         webhooks
+// @Produce      json
+// @Param        id         path      int  true   "ID магазина"
+// @Param        webhookID  path      int  true   "ID вебхука"
+// @Param        page       query     int  false  "Номер страницы (по умолчанию 1)"
+// @Param        limit      query     int  false  "Размер страницы (по умолчанию 20, максимум 100)"
+// @Security     BearerAuth
+// @Success      200        {object}  map[string]interface{} "Доставки и метаинформация"
+// @Failure      400        {object}  apperror.Error "Некорректный запрос"
+// @Failure      403        {object}  apperror.Error "Недостаточно прав"
+// @Failure      404        {object}  apperror.Error "Вебхук не найден"
+// @Router       /shops/{id}/webhooks/{webhookID}/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	userID, ok := storeOwnerID(c)
+	if !ok {
+		return
+	}
+
+	id, webhookID, ok := parseWebhookPath(c)
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid page number", err))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid limit value (should be between 1 and 100)", err))
+		return
+	}
+
+	deliveries, total, err := h.webhookService.GetDeliveries(c.Request.Context(), id, userID, webhookID, page, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": deliveries,
+		"meta": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total_items":  total,
+			"total_pages":  int(math.Ceil(float64(total) / float64(limit))),
+		},
+	})
+}
+
+// PingWebhook godoc
+// @Summary      Проверить вебхук
+// @Description  Сразу отправляет событие ping и возвращает результат доставки. Неудачная проверка не повторяется
+// @Tags         webhooks
+// @Produce      json
+// @Param        id         path      int  true  "ID магазина"
+// @Param        webhookID  path      int  true  "ID вебхука"
+// @Security     BearerAuth
+// @Success      200        {object}  entity.WebhookDelivery
+// @Failure      403        {object}  apperror.Error "Недостаточно прав"
+// @Failure      404        {object}  apperror.Error "Вебхук не найден"
+// @Router       /shops/{id}/webhooks/{webhookID}/ping [post]
+func (h *WebhookHandler) PingWebhook(c *gin.Context) {
+	userID, ok := storeOwnerID(c)
+	if !ok {
+		return
+	}
+
+	id, webhookID, ok := parseWebhookPath(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.Ping(c.Request.Context(), id, userID, webhookID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+func parseWebhookPath(c *gin.Context) (uint, uint, bool) {
+	id, ok := parseStoreID(c)
+	if !ok {
+		return 0, 0, false
+	}
+
+	webhookID, err := strconv.ParseUint(c.Param("webhookID"), 10, 32)
+	if err != nil || webhookID < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid webhook id", err))
+		return 0, 0, false
+	}
+	return id, uint(webhookID), true
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type CategoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewCategoryRepository(db *sqlx.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// GetCategoryByID получает категорию по ее ID
+func (r *CategoryRepository) GetCategoryByID(
+	ctx context.Context,
+	id int,
+) (entity.Category, error) {
+	query, args := sq.Select("id", "name", "parent_id").
+		From("categories").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var categoryModel model.Category
+	if err := r.db.GetContext(ctx, &categoryModel, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Category{}, apperror.ErrCategoryNotFound
+		}
+		return entity.Category{}, apperror.New(apperror.DatabaseError, "failed to fetch category", err)
+	}
+
+	return model.ConvertCategoryToEntity(categoryModel), nil
+}
+
+// GetAttributeSchemas получает схему атрибутов категории с учетом всех ее родителей.
+// Если атрибут с одним именем описан на нескольких уровнях дерева, берется ближайший к категории.
+func (r *CategoryRepository) GetAttributeSchemas(
+	ctx context.Context,
+	categoryID int,
+) ([]entity.AttributeSchema, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 0 AS depth FROM categories WHERE id = $1
+			UNION ALL
+			SELECT c.id, c.parent_id, a.depth + 1 FROM categories c
+			JOIN ancestors a ON c.id = a.parent_id
+		)
+		SELECT DISTINCT ON (s.name)
+			s.id, s.category_id, s.name, s.type, s.unit, s.enum_values, s.is_required, s.is_filterable
+		FROM category_attribute_schemas s
+		JOIN ancestors a ON a.id = s.category_id
+		ORDER BY s.name, a.depth
+	`
+
+	var schemaModels []model.AttributeSchema
+	if err := r.db.SelectContext(ctx, &schemaModels, query, categoryID); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch attribute schemas", err)
+	}
+
+	schemas := make([]entity.AttributeSchema, 0, len(schemaModels))
+	for _, sm := range schemaModels {
+		schema, err := model.ConvertAttributeSchemaToEntity(sm)
+		if err != nil {
+			return nil, apperror.New(apperror.DatabaseError, "failed to unmarshal attribute enum values", err)
+		}
+		schemas = append(schemas, schema)
+	}
+
+	return schemas, nil
+}
+
+// UpsertAttributeSchema создает атрибут в схеме категории или обновляет существующий с тем же именем
+func (r *CategoryRepository) UpsertAttributeSchema(
+	ctx context.Context,
+	schema entity.AttributeSchema,
+) (entity.AttributeSchema, error) {
+	schemaModel, err := model.ConvertAttributeSchemaFromEntity(schema)
+	if err != nil {
+		return entity.AttributeSchema{}, apperror.New(apperror.InternalError, "failed to marshal attribute enum values", err)
+	}
+
+	query, args := sq.Insert("category_attribute_schemas").
+		Columns("category_id", "name", "type", "unit", "enum_values", "is_required", "is_filterable").
+		Values(schemaModel.CategoryID, schemaModel.Name, schemaModel.Type, schemaModel.Unit,
+			schemaModel.EnumValues, schemaModel.Required, schemaModel.Filterable).
+		Suffix("ON CONFLICT (category_id, name) DO UPDATE SET " +
+			"type = EXCLUDED.type, unit = EXCLUDED.unit, enum_values = EXCLUDED.enum_values, " +
+			"is_required = EXCLUDED.is_required, is_filterable = EXCLUDED.is_filterable " +
+			"RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&schema.ID); err != nil {
+		return entity.AttributeSchema{}, apperror.New(apperror.DatabaseError, "failed to save attribute schema", err)
+	}
+
+	return schema, nil
+}
+
+// DeleteAttributeSchema удаляет атрибут из схемы категории
+func (r *CategoryRepository) DeleteAttributeSchema(
+	ctx context.Context,
+	categoryID int,
+	name string,
+) error {
+	query, args := sq.Delete("category_attribute_schemas").
+		Where(sq.Eq{"category_id": categoryID, "name": name}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to delete attribute schema", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to get affected rows", err)
+	}
+	if affected == 0 {
+		return apperror.ErrAttributeSchemaNotFound
+	}
+
+	return nil
+}
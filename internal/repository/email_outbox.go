@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+)
+
+const outboxColumns = "id, idempotency_key, recipient, template, locale, data, unsubscribe_url, status, " +
+	"attempts, next_attempt_at, last_error, created_at, sent_at"
+
+type EmailOutboxRepository struct {
+	db *sqlx.DB
+}
+
+func NewEmailOutboxRepository(db *sqlx.DB) *EmailOutboxRepository {
+	return &EmailOutboxRepository{db: db}
+}
+
+// AddMessages записывает письма через exec, чтобы они попали в транзакцию вызывающего.
+// Письма с уже записанным ключом идемпотентности пропускаются
+func (r *EmailOutboxRepository) AddMessages(ctx context.Context, exec email.Execer, msgs []email.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	if exec == nil {
+		exec = r.db
+	}
+
+	qb := squirrel.Insert("email_outbox").
+		Columns("idempotency_key", "recipient", "template", "locale", "data", "unsubscribe_url")
+	for _, msg := range msgs {
+		data, err := json.Marshal(msg.Data)
+		if err != nil {
+			return apperror.New(apperror.InternalError, "failed to encode email data", err)
+		}
+		key := sql.NullString{String: msg.IdempotencyKey, Valid: msg.IdempotencyKey != ""}
+		qb = qb.Values(key, msg.To, msg.Template, msg.Locale, data, msg.UnsubscribeURL)
+	}
+
+	query, args := qb.Suffix("ON CONFLICT (idempotency_key) DO NOTHING").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	if _, err := exec.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to enqueue emails", err)
+	}
+	return nil
+}
+
+// ClaimMessages забирает готовые к отправке письма. SKIP LOCKED позволяет нескольким
+// воркерам и репликам разбирать outbox параллельно без повторной отправки
+func (r *EmailOutboxRepository) ClaimMessages(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]email.OutboxMessage, error) {
+	query := `UPDATE email_outbox
+		SET attempts = attempts + 1, next_attempt_at = NOW() + make_interval(secs => $1)
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + outboxColumns
+
+	var rows []model.OutboxMessage
+	if err := r.db.SelectContext(ctx, &rows, query, lease.Seconds(), limit); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to claim emails", err)
+	}
+
+	msgs := make([]email.OutboxMessage, len(rows))
+	for i, row := range rows {
+		msgs[i] = row.ConvertToEmail()
+	}
+	return msgs, nil
+}
+
+// redactSecretData стирает данные писем с токенами, которые больше не будут отправлены
+func redactSecretData() squirrel.CaseBuilder {
+	return squirrel.Case().
+		When(squirrel.Eq{"template": email.SecretTemplates}, "'{}'::jsonb").
+		Else("data")
+}
+
+// MarkSent отмечает письмо отправленным. Данные писем с токенами больше не нужны и стираются
+func (r *EmailOutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	query, args := squirrel.Update("email_outbox").
+		Set("status", email.StatusSent).
+		Set("data", redactSecretData()).
+		Set("sent_at", squirrel.Expr("NOW()")).
+		Set("last_error", "").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to mark email as sent", err)
+	}
+	return nil
+}
+
+// MarkFailed сохраняет ошибку отправки. Без retryAt письмо становится dead, и данные писем
+// с токенами стираются так же, как после отправки
+func (r *EmailOutboxRepository) MarkFailed(ctx context.Context, id int64, lastError string, retryAt *time.Time) error {
+	qb := squirrel.Update("email_outbox").
+		Set("last_error", lastError).
+		Where(squirrel.Eq{"id": id})
+	if retryAt != nil {
+		qb = qb.Set("next_attempt_at", *retryAt)
+	} else {
+		qb = qb.Set("status", email.StatusDead).Set("data", redactSecretData())
+	}
+
+	query, args := qb.PlaceholderFormat(squirrel.Dollar).MustSql()
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to save email error", err)
+	}
+	return nil
+}
+
+// SelectOutboxMessages возвращает письма с указанным статусом, новые первыми
+func (r *EmailOutboxRepository) SelectOutboxMessages(
+	ctx context.Context,
+	status string,
+	offset, limit int,
+) ([]email.OutboxMessage, int, error) {
+	query, args := squirrel.Select(outboxColumns+", COUNT(*) OVER() AS total_count").
+		From("email_outbox").
+		Where(squirrel.Eq{"status": status}).
+		OrderBy("created_at DESC", "id DESC").
+		Offset(uint64(offset)).
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var rows []model.OutboxMessageWithCount
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, 0, apperror.New(apperror.DatabaseError, "failed to fetch emails", err)
+	}
+
+	msgs := make([]email.OutboxMessage, len(rows))
+	total := 0
+	for i, row := range rows {
+		msgs[i] = row.ConvertToEmail()
+		total = row.TotalCount
+	}
+	return msgs, total, nil
+}
+
+// RetryOutboxMessage возвращает неотправленное письмо в очередь с новым счетчиком попыток.
+// Письма с токенами не повторяются: их данные стерты, пользователь запрашивает новый токен
+func (r *EmailOutboxRepository) RetryOutboxMessage(ctx context.Context, id int64) (email.OutboxMessage, error) {
+	query, args := squirrel.Update("email_outbox").
+		Set("status", email.StatusPending).
+		Set("attempts", 0).
+		Set("next_attempt_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": id}).
+		Where(squirrel.NotEq{"status": email.StatusSent}).
+		Where(squirrel.NotEq{"template": email.SecretTemplates}).
+		Suffix("RETURNING " + outboxColumns).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var row model.OutboxMessage
+	err := r.db.GetContext(ctx, &row, query, args...)
+	if err == nil {
+		return row.ConvertToEmail(), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return email.OutboxMessage{}, apperror.New(apperror.DatabaseError, "failed to retry email", err)
+	}
+
+	var template string
+	err = r.db.GetContext(ctx, &template, "SELECT template FROM email_outbox WHERE id = $1", id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return email.OutboxMessage{}, apperror.ErrEmailNotFound
+	}
+	if err != nil {
+		return email.OutboxMessage{}, apperror.New(apperror.DatabaseError, "failed to retry email", err)
+	}
+	if slices.Contains(email.SecretTemplates, template) {
+		return email.OutboxMessage{}, apperror.New(apperror.Conflict,
+			fmt.Sprintf("email %d contains a one-time token and cannot be retried", id), nil)
+	}
+	return email.OutboxMessage{}, apperror.New(apperror.Conflict,
+		fmt.Sprintf("email %d has already been sent", id), nil)
+}
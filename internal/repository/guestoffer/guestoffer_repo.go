@@ -6,13 +6,14 @@ import (
 	"errors"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
 )
 
 // StoreInfoGetter interface for getting store information specific to guest offers
 type StoreInfoGetter interface {
-	GetStoreOwnerEmailByStoreID(ctx context.Context, storeID uint) (string, error)
+	GetStoreOwnerContactByStoreID(ctx context.Context, storeID uint) (entity.StoreOwnerContact, error)
 }
 
 type Repository struct {
@@ -24,30 +25,34 @@ func NewRepository(db *sqlx.DB) StoreInfoGetter {
 	return &Repository{db: db}
 }
 
-// GetStoreOwnerEmailByStoreID retrieves the email of the store owner by store ID.
+// GetStoreOwnerContactByStoreID retrieves the email and email locale of the store owner by store ID.
 // It implements the StoreInfoGetter interface.
-func (r *Repository) GetStoreOwnerEmailByStoreID(ctx context.Context, storeID uint) (string, error) {
-	query, args, err := squirrel.Select("users.email").
+func (r *Repository) GetStoreOwnerContactByStoreID(
+	ctx context.Context, storeID uint,
+) (entity.StoreOwnerContact, error) {
+	query, args, err := squirrel.Select("users.email", "users.locale").
 		From("users").
 		Join("shops ON users.id = shops.user_id").
 		Where(squirrel.Eq{"shops.id": storeID}).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
-		return "", apperror.NewGuestOfferError(
+		return entity.StoreOwnerContact{}, apperror.NewGuestOfferError(
 			apperror.GuestOfferDatabaseError,
 			"failed to build query for store owner email",
 		)
 	}
 
-	var email string
-	err = r.db.GetContext(ctx, &email, query, args...)
+	var contact entity.StoreOwnerContact
+	err = r.db.GetContext(ctx, &contact, query, args...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return "", apperror.NewGuestOfferError(apperror.GuestOfferStoreNotFound, "store not found for guest offer")
+			return entity.StoreOwnerContact{}, apperror.NewGuestOfferError(
+				apperror.GuestOfferStoreNotFound, "store not found for guest offer")
 		}
-		return "", apperror.NewGuestOfferError(apperror.GuestOfferDatabaseError, "failed to get store owner email")
+		return entity.StoreOwnerContact{}, apperror.NewGuestOfferError(
+			apperror.GuestOfferDatabaseError, "failed to get store owner email")
 	}
 
-	return email, nil
+	return contact, nil
 }
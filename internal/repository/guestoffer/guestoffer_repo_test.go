@@ -13,6 +13,9 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+const ownerQuery = "SELECT users.email, users.locale FROM users " +
+	"JOIN shops ON users.id = shops.user_id WHERE shops.id = \\$1"
+
 var _ = Describe("GuestOfferRepository", func() {
 	var (
 		db      *sql.DB
@@ -38,30 +41,31 @@ var _ = Describe("GuestOfferRepository", func() {
 		Expect(mock.ExpectationsWereMet()).To(Succeed())
 	})
 
-	Describe("GetStoreOwnerEmailByStoreID", func() {
+	Describe("GetStoreOwnerContactByStoreID", func() {
 		Context("when the store exists", func() {
-			It("should return the store owner's email", func() {
+			It("should return the store owner's email and locale", func() {
 				expectedEmail := "owner@example.com"
-				rows := go_sqlmock.NewRows([]string{"email"}).AddRow(expectedEmail)
+				rows := go_sqlmock.NewRows([]string{"email", "locale"}).AddRow(expectedEmail, "ru")
 
-				mock.ExpectQuery("SELECT users.email FROM users JOIN shops ON users.id = shops.user_id WHERE shops.id = \\$1").
+				mock.ExpectQuery(ownerQuery).
 					WithArgs(storeID).
 					WillReturnRows(rows)
 
-				email, err := repo.GetStoreOwnerEmailByStoreID(ctx, storeID)
+				contact, err := repo.GetStoreOwnerContactByStoreID(ctx, storeID)
 
 				Expect(err).NotTo(HaveOccurred())
-				Expect(email).To(Equal(expectedEmail))
+				Expect(contact.Email).To(Equal(expectedEmail))
+				Expect(contact.Locale).To(Equal("ru"))
 			})
 		})
 
 		Context("when the store does not exist", func() {
 			It("should return a GuestOfferStoreNotFound error", func() {
-				mock.ExpectQuery("SELECT users.email FROM users JOIN shops ON users.id = shops.user_id WHERE shops.id = \\$1").
+				mock.ExpectQuery(ownerQuery).
 					WithArgs(storeID).
 					WillReturnError(sql.ErrNoRows)
 
-				_, err := repo.GetStoreOwnerEmailByStoreID(ctx, storeID)
+				_, err := repo.GetStoreOwnerContactByStoreID(ctx, storeID)
 
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(BeAssignableToTypeOf(&apperror.GuestOfferError{}))
@@ -72,11 +76,11 @@ var _ = Describe("GuestOfferRepository", func() {
 		Context("when a database error occurs", func() {
 			It("should return a GuestOfferDatabaseError", func() {
 				dbError := errors.New("database connection error")
-				mock.ExpectQuery("SELECT users.email FROM users JOIN shops ON users.id = shops.user_id WHERE shops.id = \\$1").
+				mock.ExpectQuery(ownerQuery).
 					WithArgs(storeID).
 					WillReturnError(dbError)
 
-				_, err := repo.GetStoreOwnerEmailByStoreID(ctx, storeID)
+				_, err := repo.GetStoreOwnerContactByStoreID(ctx, storeID)
 
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(BeAssignableToTypeOf(&apperror.GuestOfferError{}))
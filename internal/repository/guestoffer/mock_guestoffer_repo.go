@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,17 +41,17 @@ func (m *MockStoreInfoGetter) EXPECT() *MockStoreInfoGetterMockRecorder {
 	return m.recorder
 }
 
-// GetStoreOwnerEmailByStoreID mocks base method.
-func (m *MockStoreInfoGetter) GetStoreOwnerEmailByStoreID(ctx context.Context, storeID uint) (string, error) {
+// GetStoreOwnerContactByStoreID mocks base method.
+func (m *MockStoreInfoGetter) GetStoreOwnerContactByStoreID(ctx context.Context, storeID uint) (entity.StoreOwnerContact, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetStoreOwnerEmailByStoreID", ctx, storeID)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetStoreOwnerContactByStoreID", ctx, storeID)
+	ret0, _ := ret[0].(entity.StoreOwnerContact)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetStoreOwnerEmailByStoreID indicates an expected call of GetStoreOwnerEmailByStoreID.
-func (mr *MockStoreInfoGetterMockRecorder) GetStoreOwnerEmailByStoreID(ctx, storeID any) *gomock.Call {
+// GetStoreOwnerContactByStoreID indicates an expected call of GetStoreOwnerContactByStoreID.
+func (mr *MockStoreInfoGetterMockRecorder) GetStoreOwnerContactByStoreID(ctx, storeID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStoreOwnerEmailByStoreID", reflect.TypeOf((*MockStoreInfoGetter)(nil).GetStoreOwnerEmailByStoreID), ctx, storeID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStoreOwnerContactByStoreID", reflect.TypeOf((*MockStoreInfoGetter)(nil).GetStoreOwnerContactByStoreID), ctx, storeID)
 }
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type Category struct {
+	ID       int           `db:"id"`
+	Name     string        `db:"name"`
+	ParentID sql.NullInt64 `db:"parent_id"`
+}
+
+type AttributeSchema struct {
+	ID         int            `db:"id"`
+	CategoryID int            `db:"category_id"`
+	Name       string         `db:"name"`
+	Type       string         `db:"type"`
+	Unit       sql.NullString `db:"unit"`
+	EnumValues []byte         `db:"enum_values"`
+	Required   bool           `db:"is_required"`
+	Filterable bool           `db:"is_filterable"`
+}
+
+func ConvertCategoryToEntity(c Category) entity.Category {
+	category := entity.Category{
+		ID:   c.ID,
+		Name: c.Name,
+	}
+	if c.ParentID.Valid {
+		parentID := int(c.ParentID.Int64)
+		category.ParentID = &parentID
+	}
+	return category
+}
+
+func ConvertAttributeSchemaToEntity(s AttributeSchema) (entity.AttributeSchema, error) {
+	schema := entity.AttributeSchema{
+		ID:         s.ID,
+		CategoryID: s.CategoryID,
+		Name:       s.Name,
+		Type:       s.Type,
+		Unit:       s.Unit.String,
+		Required:   s.Required,
+		Filterable: s.Filterable,
+	}
+	if len(s.EnumValues) > 0 {
+		if err := json.Unmarshal(s.EnumValues, &schema.EnumValues); err != nil {
+			return entity.AttributeSchema{}, err
+		}
+	}
+	return schema, nil
+}
+
+func ConvertAttributeSchemaFromEntity(s entity.AttributeSchema) (AttributeSchema, error) {
+	schema := AttributeSchema{
+		ID:         s.ID,
+		CategoryID: s.CategoryID,
+		Name:       s.Name,
+		Type:       s.Type,
+		Unit:       sql.NullString{String: s.Unit, Valid: s.Unit != ""},
+		Required:   s.Required,
+		Filterable: s.Filterable,
+	}
+	if len(s.EnumValues) > 0 {
+		enumValues, err := json.Marshal(s.EnumValues)
+		if err != nil {
+			return AttributeSchema{}, err
+		}
+		schema.EnumValues = enumValues
+	}
+	return schema, nil
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+)
+
+type OutboxMessage struct {
+	ID             int64          `db:"id"`
+	IdempotencyKey sql.NullString `db:"idempotency_key"`
+	Recipient      string         `db:"recipient"`
+	Template       string         `db:"template"`
+	Locale         string         `db:"locale"`
+	Data           []byte         `db:"data"`
+	UnsubscribeURL string         `db:"unsubscribe_url"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	NextAttemptAt  time.Time      `db:"next_attempt_at"`
+	LastError      string         `db:"last_error"`
+	CreatedAt      time.Time      `db:"created_at"`
+	SentAt         *time.Time     `db:"sent_at"`
+}
+
+type OutboxMessageWithCount struct {
+	OutboxMessage
+	TotalCount int `db:"total_count"`
+}
+
+func (m *OutboxMessage) ConvertToEmail() email.OutboxMessage {
+	return email.OutboxMessage{
+		ID:             m.ID,
+		IdempotencyKey: m.IdempotencyKey.String,
+		To:             m.Recipient,
+		Template:       m.Template,
+		Locale:         m.Locale,
+		Data:           json.RawMessage(m.Data),
+		UnsubscribeURL: m.UnsubscribeURL,
+		Status:         m.Status,
+		Attempts:       m.Attempts,
+		NextAttemptAt:  m.NextAttemptAt,
+		LastError:      m.LastError,
+		CreatedAt:      m.CreatedAt,
+		SentAt:         m.SentAt,
+	}
+}
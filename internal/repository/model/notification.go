@@ -1,10 +1,58 @@
 package model
 
-import "time"
+import (
+	"database/sql"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
 
 type Notification struct {
-	ID      uint      `gorm:"column:id;primary_key"`
-	UserID  uint      `gorm:"column:user_id"`
-	Message string    `gorm:"column:message"`
-	SentAt  time.Time `gorm:"column:sent_at"`
+	ID      uint       `db:"id"`
+	UserID  uint       `db:"user_id"`
+	Type    string     `db:"type"`
+	OfferID *uint      `db:"offer_id"`
+	Message string     `db:"message"`
+	SentAt  time.Time  `db:"sent_at"`
+	ReadAt  *time.Time `db:"read_at"`
+}
+
+type NotificationWithCount struct {
+	Notification
+	TotalCount int `db:"total_count"`
+}
+
+func (n *Notification) ConvertToEntity() entity.Notification {
+	return entity.Notification{
+		ID:      n.ID,
+		UserID:  n.UserID,
+		Type:    n.Type,
+		OfferID: n.OfferID,
+		Message: n.Message,
+		SentAt:  n.SentAt,
+		ReadAt:  n.ReadAt,
+	}
+}
+
+type NotificationPreference struct {
+	Event   string `db:"event"`
+	Channel string `db:"channel"`
+	Enabled bool   `db:"enabled"`
+}
+
+func (p *NotificationPreference) ConvertToEntity() entity.NotificationPreference {
+	return entity.NotificationPreference{
+		Event:   p.Event,
+		Channel: p.Channel,
+		Enabled: p.Enabled,
+	}
+}
+
+// RecipientPreference строка получателя с одной из его настроек, channel пустой, если настроек нет
+type RecipientPreference struct {
+	UserID  uint           `db:"user_id"`
+	Email   string         `db:"email"`
+	Locale  string         `db:"locale"`
+	Channel sql.NullString `db:"channel"`
+	Enabled sql.NullBool   `db:"enabled"`
 }
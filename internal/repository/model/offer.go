@@ -17,6 +17,7 @@ type Offer struct {
 	ShopID    uint      `db:"shop_id"`
 	UserID    uint      `db:"user_id"`
 	ProductID uint      `db:"product_id"`
+	VariantID uint      `db:"variant_id"`
 }
 
 type OfferWithCount struct {
@@ -36,6 +37,7 @@ func (o *Offer) ConvertToEntity() entity.Offer {
 		ShopID:    o.ShopID,
 		UserID:    o.UserID,
 		ProductID: o.ProductID,
+		VariantID: o.VariantID,
 	}
 }
 
@@ -51,5 +53,6 @@ func ConvertOfferEntityToModel(offer entity.Offer) Offer {
 		ShopID:    offer.ShopID,
 		UserID:    offer.UserID,
 		ProductID: offer.ProductID,
+		VariantID: offer.VariantID,
 	}
 }
@@ -1,6 +1,10 @@
 package model
 
 import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 )
 
@@ -31,3 +35,64 @@ func ConvertProductToEntity(p Product) entity.Product {
 		Attributes:   make(map[string]interface{}),
 	}
 }
+
+type ProductImage struct {
+	ID           int            `db:"id"`
+	ProductID    int            `db:"product_id"`
+	Key          string         `db:"image_key"`
+	ThumbnailKey sql.NullString `db:"thumbnail_key"`
+	ContentType  string         `db:"content_type"`
+	CreatedAt    time.Time      `db:"created_at"`
+}
+
+func ConvertProductImageToEntity(pi ProductImage) entity.ProductImage {
+	return entity.ProductImage{
+		ID:           pi.ID,
+		ProductID:    pi.ProductID,
+		Key:          pi.Key,
+		ThumbnailKey: pi.ThumbnailKey.String,
+		ContentType:  pi.ContentType,
+		CreatedAt:    pi.CreatedAt,
+	}
+}
+
+type ProductVariant struct {
+	ID         int    `db:"id"`
+	ProductID  int    `db:"product_id"`
+	SKU        string `db:"sku"`
+	Attributes []byte `db:"attributes"`
+}
+
+type VariantInventory struct {
+	VariantID   int    `db:"variant_id"`
+	ShopID      int    `db:"shop_id"`
+	Price       int    `db:"price"`
+	Currency    string `db:"currency"`
+	IsAvailable bool   `db:"is_available"`
+}
+
+func ConvertProductVariantToEntity(pv ProductVariant) (entity.ProductVariant, error) {
+	attrs := make(map[string]interface{})
+	if len(pv.Attributes) > 0 {
+		if err := json.Unmarshal(pv.Attributes, &attrs); err != nil {
+			return entity.ProductVariant{}, err
+		}
+	}
+
+	return entity.ProductVariant{
+		ID:         pv.ID,
+		ProductID:  pv.ProductID,
+		SKU:        pv.SKU,
+		Attributes: attrs,
+	}, nil
+}
+
+func ConvertVariantInventoryToEntity(vi VariantInventory) entity.VariantInventory {
+	return entity.VariantInventory{
+		VariantID:   vi.VariantID,
+		ShopID:      vi.ShopID,
+		Price:       vi.Price,
+		Currency:    vi.Currency,
+		IsAvailable: vi.IsAvailable,
+	}
+}
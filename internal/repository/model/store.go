@@ -1,14 +1,105 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 )
 
 type Store struct {
-	ID          uint      `json:"id" gorm:"primaryKey"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Products    []Product `gorm:"foreignKey:StoreID"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID           uint      `db:"id"`
+	UserID       uint      `db:"user_id"`
+	Name         string    `db:"name"`
+	Description  string    `db:"description"`
+	LogoURL      string    `db:"logo_url"`
+	ContactEmail string    `db:"contact_email"`
+	ContactPhone string    `db:"contact_phone"`
+	Address      string    `db:"address"`
+	WorkingHours []byte    `db:"working_hours"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+type StoreFilter struct {
+	Search string `form:"search"`
+}
+
+// StoreWithStats магазин вместе с агрегатами по товарам и отзывам
+type StoreWithStats struct {
+	Store
+	ProductCount int     `db:"product_count"`
+	Rating       float64 `db:"rating"`
+	ReviewCount  int     `db:"review_count"`
+	TotalCount   int     `db:"total_count"`
+}
+
+func ConvertStoreToEntity(s StoreWithStats) (entity.Store, error) {
+	hours := make(map[string]string)
+	if len(s.WorkingHours) > 0 {
+		if err := json.Unmarshal(s.WorkingHours, &hours); err != nil {
+			return entity.Store{}, err
+		}
+	}
+
+	return entity.Store{
+		ID:           s.ID,
+		UserID:       s.UserID,
+		Name:         s.Name,
+		Description:  s.Description,
+		LogoURL:      s.LogoURL,
+		ContactEmail: s.ContactEmail,
+		ContactPhone: s.ContactPhone,
+		Address:      s.Address,
+		WorkingHours: hours,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+		ProductCount: s.ProductCount,
+		Rating:       s.Rating,
+		ReviewCount:  s.ReviewCount,
+	}, nil
+}
+
+type ShopMember struct {
+	ShopID    uint      `db:"shop_id"`
+	UserID    uint      `db:"user_id"`
+	Name      string    `db:"name"`
+	Email     string    `db:"email"`
+	Role      string    `db:"role"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type ShopInvitation struct {
+	ID         uint       `db:"id"`
+	ShopID     uint       `db:"shop_id"`
+	Email      string     `db:"email"`
+	Role       string     `db:"role"`
+	InvitedBy  uint       `db:"invited_by"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	AcceptedAt *time.Time `db:"accepted_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+func ConvertShopMemberToEntity(m ShopMember) entity.ShopMember {
+	return entity.ShopMember{
+		ShopID:    m.ShopID,
+		UserID:    m.UserID,
+		Name:      m.Name,
+		Email:     m.Email,
+		Role:      entity.ShopRole(m.Role),
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func ConvertShopInvitationToEntity(i ShopInvitation) entity.ShopInvitation {
+	return entity.ShopInvitation{
+		ID:         i.ID,
+		ShopID:     i.ShopID,
+		Email:      i.Email,
+		Role:       entity.ShopRole(i.Role),
+		InvitedBy:  i.InvitedBy,
+		ExpiresAt:  i.ExpiresAt,
+		AcceptedAt: i.AcceptedAt,
+		CreatedAt:  i.CreatedAt,
+	}
 }
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
 )
@@ -12,7 +14,12 @@ type User struct {
 	Phone         string `db:"phone_number"`
 	Password      string `db:"password_hash"`
 	IsStore       bool   `db:"is_store"`
+	Role          string `db:"role"`
+	Locale        string `db:"locale"`
 	Notifications []Notification
+
+	// EmailVerifiedAt nil, пока пользователь не подтвердил почту
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
 }
 
 func ConvertUserFromSvc(u user.User) User {
@@ -22,6 +29,8 @@ func ConvertUserFromSvc(u user.User) User {
 		Phone:    u.Phone,
 		Password: u.Password,
 		IsStore:  u.IsStore,
+		Role:     string(u.Role),
+		Locale:   u.Locale,
 	}
 }
 
@@ -33,5 +42,9 @@ func ConvertUserToEntity(u User) entity.User {
 		Phone:    u.Phone,
 		Password: u.Password,
 		IsStore:  u.IsStore,
+		Role:     entity.UserRole(u.Role),
+		Locale:   u.Locale,
+
+		EmailVerifiedAt: u.EmailVerifiedAt,
 	}
 }
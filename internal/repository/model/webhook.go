@@ -0,0 +1,85 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type Webhook struct {
+	ID        uint      `db:"id"`
+	ShopID    uint      `db:"shop_id"`
+	URL       string    `db:"url"`
+	Secret    string    `db:"secret"`
+	Events    []byte    `db:"events"`
+	Active    bool      `db:"active"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// ConvertToEntity переводит вебхук в сущность. Секрет заполняется, только если withSecret
+func (w *Webhook) ConvertToEntity(withSecret bool) (entity.Webhook, error) {
+	webhook := entity.Webhook{
+		ID:        w.ID,
+		ShopID:    w.ShopID,
+		URL:       w.URL,
+		Active:    w.Active,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+	if withSecret {
+		webhook.Secret = w.Secret
+	}
+	if err := json.Unmarshal(w.Events, &webhook.Events); err != nil {
+		return entity.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+type WebhookDelivery struct {
+	ID             int64         `db:"id"`
+	WebhookID      uint          `db:"webhook_id"`
+	Event          string        `db:"event"`
+	Payload        []byte        `db:"payload"`
+	Status         string        `db:"status"`
+	Attempts       int           `db:"attempts"`
+	ResponseStatus sql.NullInt32 `db:"response_status"`
+	LastError      string        `db:"last_error"`
+	NextAttemptAt  time.Time     `db:"next_attempt_at"`
+	CreatedAt      time.Time     `db:"created_at"`
+	DeliveredAt    *time.Time    `db:"delivered_at"`
+}
+
+type WebhookDeliveryWithCount struct {
+	WebhookDelivery
+	TotalCount int `db:"total_count"`
+}
+
+// PendingWebhookDelivery доставка с адресом и секретом вебхука
+type PendingWebhookDelivery struct {
+	WebhookDelivery
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+func (d *WebhookDelivery) ConvertToEntity() entity.WebhookDelivery {
+	delivery := entity.WebhookDelivery{
+		ID:            d.ID,
+		WebhookID:     d.WebhookID,
+		Event:         d.Event,
+		Payload:       json.RawMessage(d.Payload),
+		Status:        d.Status,
+		Attempts:      d.Attempts,
+		LastError:     d.LastError,
+		NextAttemptAt: d.NextAttemptAt,
+		CreatedAt:     d.CreatedAt,
+		DeliveredAt:   d.DeliveredAt,
+	}
+	if d.ResponseStatus.Valid {
+		status := int(d.ResponseStatus.Int32)
+		delivery.ResponseStatus = &status
+	}
+	return delivery
+}
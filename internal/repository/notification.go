@@ -1,11 +1,20 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
 
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
 )
 
+const notificationColumns = "id, user_id, type, offer_id, message, sent_at, read_at"
+
 type NotificationRepository struct {
 	db *sqlx.DB
 }
@@ -14,15 +23,295 @@ func NewNotificationRepository(db *sqlx.DB) *NotificationRepository {
 	return &NotificationRepository{db: db}
 }
 
+// InsertNotifications сохраняет уведомления одним запросом и возвращает их с ID и временем отправки
+func (r *NotificationRepository) InsertNotifications(
+	ctx context.Context,
+	notifications []entity.Notification,
+) ([]entity.Notification, error) {
+	if len(notifications) == 0 {
+		return nil, nil
+	}
+
+	builder := squirrel.Insert("notifications").
+		Columns("user_id", "type", "offer_id", "message").
+		Suffix("RETURNING " + notificationColumns).
+		PlaceholderFormat(squirrel.Dollar)
+	for _, n := range notifications {
+		builder = builder.Values(n.UserID, n.Type, n.OfferID, n.Message)
+	}
+	query, args := builder.MustSql()
+
+	var rows []model.Notification
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error inserting notifications", err)
+	}
+
+	return convertNotifications(rows), nil
+}
+
+// SelectNotificationsAfter возвращает уведомления пользователя с ID больше afterID в порядке создания.
+// Используется, чтобы дослать пропущенное при переподключении к потоку
+func (r *NotificationRepository) SelectNotificationsAfter(
+	ctx context.Context,
+	userID uint,
+	afterID uint,
+	limit int,
+) ([]entity.Notification, error) {
+	query, args := squirrel.Select(notificationColumns).
+		From("notifications").
+		Where(squirrel.Eq{"user_id": userID}).
+		Where(squirrel.Gt{"id": afterID}).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var rows []model.Notification
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error selecting missed notifications", err)
+	}
+
+	return convertNotifications(rows), nil
+}
+
+// SelectUserNotifications возвращает страницу уведомлений пользователя, новые первыми,
+// и общее число уведомлений, подходящих под фильтр
 func (r *NotificationRepository) SelectUserNotifications(
-	id string,
+	ctx context.Context,
+	userID uint,
+	unreadOnly bool,
 	offset, limit int,
 ) ([]entity.Notification, int, error) {
-	var total int64
+	builder := squirrel.Select(notificationColumns+", COUNT(*) OVER() AS total_count").
+		From("notifications").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("sent_at DESC", "id DESC").
+		Offset(uint64(offset)).
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar)
+	if unreadOnly {
+		builder = builder.Where(squirrel.Eq{"read_at": nil})
+	}
+	query, args := builder.MustSql()
+
+	rows := make([]model.NotificationWithCount, 0, limit)
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, 0, apperror.New(apperror.DatabaseError, "error selecting user notifications", err)
+	}
+
+	if len(rows) == 0 {
+		return []entity.Notification{}, 0, nil
+	}
+
+	notifications := make([]entity.Notification, len(rows))
+	for i, row := range rows {
+		notifications[i] = row.ConvertToEntity()
+	}
+
+	return notifications, rows[0].TotalCount, nil
+}
+
+func (r *NotificationRepository) CountUnreadNotifications(ctx context.Context, userID uint) (int, error) {
+	query, args := squirrel.Select("COUNT(*)").
+		From("notifications").
+		Where(squirrel.Eq{"user_id": userID, "read_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, args...); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "error counting unread notifications", err)
+	}
+
+	return count, nil
+}
+
+// MarkNotificationRead отмечает уведомление прочитанным. Время прочтения не перезаписывается,
+// уведомления других пользователей считаются несуществующими
+func (r *NotificationRepository) MarkNotificationRead(
+	ctx context.Context,
+	userID uint,
+	notificationID uint,
+) error {
+	query, args := squirrel.Update("notifications").
+		Set("read_at", squirrel.Expr("COALESCE(read_at, CURRENT_TIMESTAMP)")).
+		Where(squirrel.Eq{"id": notificationID, "user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "error marking notification as read", err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return apperror.ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+// MarkAllNotificationsRead отмечает прочитанными все уведомления пользователя
+// и возвращает число отмеченных
+func (r *NotificationRepository) MarkAllNotificationsRead(ctx context.Context, userID uint) (int, error) {
+	query, args := squirrel.Update("notifications").
+		Set("read_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"user_id": userID, "read_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
 
-	_ = id
-	_ = offset
-	_ = limit
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "error marking notifications as read", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "error marking notifications as read", err)
+	}
+
+	return int(affected), nil
+}
+
+// SelectShopManagerIDs возвращает владельцев и менеджеров магазина,
+// которые получают уведомления об офферах магазину
+func (r *NotificationRepository) SelectShopManagerIDs(ctx context.Context, shopID uint) ([]uint, error) {
+	query, args := squirrel.Select("user_id").
+		From("shop_members").
+		Where(squirrel.Eq{
+			"shop_id": shopID,
+			"role":    []string{string(entity.ShopRoleOwner), string(entity.ShopRoleManager)},
+		}).
+		OrderBy("user_id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var ids []uint
+	if err := r.db.SelectContext(ctx, &ids, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error selecting shop managers", err)
+	}
+
+	return ids, nil
+}
+
+// SelectOfferDetails возвращает название товара, артикул варианта и название магазина для писем об оффере
+func (r *NotificationRepository) SelectOfferDetails(
+	ctx context.Context,
+	shopID, variantID uint,
+) (entity.OfferDetails, error) {
+	query, args := squirrel.Select("p.name AS product_name", "v.sku AS variant_sku", "s.name AS shop_name").
+		From("product_variants v").
+		InnerJoin("products p ON p.id = v.product_id").
+		InnerJoin("shops s ON s.id = ?", shopID).
+		Where(squirrel.Eq{"v.id": variantID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var details entity.OfferDetails
+	if err := r.db.GetContext(ctx, &details, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.OfferDetails{}, apperror.ErrVariantNotFound
+		}
+		return entity.OfferDetails{}, apperror.New(apperror.DatabaseError, "error selecting offer details", err)
+	}
+
+	return details, nil
+}
+
+// SelectNotificationPreferences возвращает настройки, которые пользователь менял
+func (r *NotificationRepository) SelectNotificationPreferences(
+	ctx context.Context,
+	userID uint,
+) ([]entity.NotificationPreference, error) {
+	query, args := squirrel.Select("event", "channel", "enabled").
+		From("notification_preferences").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("event", "channel").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var rows []model.NotificationPreference
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error selecting notification preferences", err)
+	}
+
+	preferences := make([]entity.NotificationPreference, len(rows))
+	for i := range rows {
+		preferences[i] = rows[i].ConvertToEntity()
+	}
+	return preferences, nil
+}
+
+// UpsertNotificationPreferences сохраняет настройки пользователя одним запросом
+func (r *NotificationRepository) UpsertNotificationPreferences(
+	ctx context.Context,
+	userID uint,
+	preferences []entity.NotificationPreference,
+) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	builder := squirrel.Insert("notification_preferences").
+		Columns("user_id", "event", "channel", "enabled").
+		Suffix("ON CONFLICT (user_id, event, channel) " +
+			"DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = CURRENT_TIMESTAMP").
+		PlaceholderFormat(squirrel.Dollar)
+	for _, p := range preferences {
+		builder = builder.Values(userID, p.Event, p.Channel, p.Enabled)
+	}
+	query, args := builder.MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "error saving notification preferences", err)
+	}
+	return nil
+}
+
+// SelectRecipients возвращает адреса получателей и их настройки для типа событий
+func (r *NotificationRepository) SelectRecipients(
+	ctx context.Context,
+	userIDs []uint,
+	event string,
+) ([]entity.NotificationRecipient, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args := squirrel.Select("u.id AS user_id", "u.email", "u.locale", "p.channel", "p.enabled").
+		From("users u").
+		LeftJoin("notification_preferences p ON p.user_id = u.id AND p.event = ?", event).
+		Where(squirrel.Eq{"u.id": userIDs}).
+		OrderBy("u.id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var rows []model.RecipientPreference
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error selecting notification recipients", err)
+	}
+
+	recipients := make([]entity.NotificationRecipient, 0, len(userIDs))
+	for _, row := range rows {
+		if len(recipients) == 0 || recipients[len(recipients)-1].UserID != row.UserID {
+			recipients = append(recipients, entity.NotificationRecipient{
+				UserID:   row.UserID,
+				Email:    row.Email,
+				Locale:   row.Locale,
+				Event:    event,
+				Channels: make(map[string]bool),
+			})
+		}
+		if row.Channel.Valid {
+			recipients[len(recipients)-1].Channels[row.Channel.String] = row.Enabled.Bool
+		}
+	}
+	return recipients, nil
+}
 
-	return nil, int(total), nil
+func convertNotifications(rows []model.Notification) []entity.Notification {
+	notifications := make([]entity.Notification, len(rows))
+	for i := range rows {
+		notifications[i] = rows[i].ConvertToEntity()
+	}
+	return notifications
 }
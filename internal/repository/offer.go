@@ -9,12 +9,18 @@ import (
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
 	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
 
 	"github.com/jmoiron/sqlx"
 
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
 )
 
+const offerColumns = "id, offer_price, currency, status, created_at, updated_at, expires_at, " +
+	"shop_id, product_id, variant_id, user_id"
+
 type OfferRepository struct {
 	db *sqlx.DB
 }
@@ -23,20 +29,15 @@ func NewOfferRepository(db *sqlx.DB) *OfferRepository {
 	return &OfferRepository{db: db}
 }
 
+// InsertOffer создает оффер. onInsert вызывается в той же транзакции, чтобы связанные записи,
+// например письма в outbox, сохранились только вместе с оффером
 func (r *OfferRepository) InsertOffer(
 	ctx context.Context,
 	offer entity.Offer,
+	onInsert func(tx email.Execer, offerID uint) error,
 ) (uint, error) {
 	offerModel := model.ConvertOfferEntityToModel(offer)
 
-	checkExistingOfferQuery, args := squirrel.Select("count(*)").
-		From("offers").
-		Where(squirrel.Eq{"status": "pending",
-			"product_id": offerModel.ProductID,
-			"shop_id":    offerModel.ShopID,
-			"user_id":    offerModel.UserID}).
-		PlaceholderFormat(squirrel.Dollar).
-		MustSql()
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return 0, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
@@ -45,6 +46,30 @@ func (r *OfferRepository) InsertOffer(
 		_ = tx.Rollback()
 	}()
 
+	// продукт определяется по варианту, клиент передает только вариант
+	getVariantProductQuery, args := squirrel.Select("product_id").
+		From("product_variants").
+		Where(squirrel.Eq{"id": offerModel.VariantID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	err = tx.QueryRowxContext(ctx, getVariantProductQuery, args...).Scan(&offerModel.ProductID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, apperror.ErrVariantNotFound
+		}
+		return 0, apperror.New(apperror.DatabaseError, "error fetching product variant", err)
+	}
+
+	checkExistingOfferQuery, args := squirrel.Select("count(*)").
+		From("offers").
+		Where(squirrel.Eq{"status": "pending",
+			"variant_id": offerModel.VariantID,
+			"shop_id":    offerModel.ShopID,
+			"user_id":    offerModel.UserID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
 	var existingOfferCount int
 	err = tx.QueryRowxContext(ctx, checkExistingOfferQuery, args...).Scan(&existingOfferCount)
 	if err != nil {
@@ -53,15 +78,15 @@ func (r *OfferRepository) InsertOffer(
 
 	if existingOfferCount > 0 {
 		return 0, apperror.New(apperror.Conflict,
-			"user already has an active offer for this product in this shop", nil)
+			"user already has an active offer for this product variant in this shop", nil)
 	}
 
 	insertOfferQuery, args := squirrel.Insert("offers").
 		Columns("offer_price", "currency", "status", "created_at", "updated_at", "expires_at",
-			"shop_id", "user_id", "product_id").
+			"shop_id", "user_id", "product_id", "variant_id").
 		Values(offerModel.Price, offerModel.Currency, offerModel.Status,
 			offerModel.CreatedAt, offerModel.UpdatedAt, offerModel.ExpiresAt,
-			offerModel.ShopID, offerModel.UserID, offerModel.ProductID).
+			offerModel.ShopID, offerModel.UserID, offerModel.ProductID, offerModel.VariantID).
 		Suffix("returning id").
 		PlaceholderFormat(squirrel.Dollar).
 		MustSql()
@@ -69,9 +94,19 @@ func (r *OfferRepository) InsertOffer(
 	var offerID uint
 	err = tx.QueryRowxContext(ctx, insertOfferQuery, args...).Scan(&offerID)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return 0, apperror.New(apperror.NotFound, "product variant is not sold in this shop", err)
+		}
 		return 0, apperror.New(apperror.DatabaseError, "error inserting offer into database", err)
 	}
 
+	if onInsert != nil {
+		if err := onInsert(tx, offerID); err != nil {
+			return 0, err
+		}
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return 0, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
@@ -97,25 +132,7 @@ func (r *OfferRepository) SelectUserOffers(
 	userID uint,
 	limit, offset int,
 ) ([]entity.Offer, int, error) {
-	var total int
-
-	tx, err := r.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return nil, 0, apperror.New(apperror.DatabaseError,
-			"failed to begin transaction", err)
-	}
-	defer func() {
-		_ = tx.Rollback()
-	}()
-
-	// lazy update
-	if err = r.updateExpiredOffers(ctx, userID, tx); err != nil {
-		return nil, 0, err
-	}
-
-	selectUserOffersQuery, args := squirrel.Select("id, offer_price, currency, status, " +
-		"created_at, updated_at, expires_at, shop_id, product_id, user_id," +
-		"COUNT (*) OVER() as total_count").
+	selectUserOffersQuery, args := squirrel.Select(offerColumns + ", COUNT (*) OVER() as total_count").
 		From("offers").
 		Where(squirrel.Eq{"status": "pending", "user_id": userID}).
 		OrderBy("created_at desc").
@@ -126,21 +143,16 @@ func (r *OfferRepository) SelectUserOffers(
 
 	offersWithCount := make([]model.OfferWithCount, 0, limit)
 
-	err = tx.SelectContext(ctx, &offersWithCount, selectUserOffersQuery, args...)
+	err := r.db.SelectContext(ctx, &offersWithCount, selectUserOffersQuery, args...)
 	if err != nil {
-		return nil, 0, apperror.New(apperror.DatabaseError, "error selecting user offers after lazy update", err)
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		return nil, 0, apperror.New(apperror.DatabaseError, "failed to commit transaction for lazy update", err)
+		return nil, 0, apperror.New(apperror.DatabaseError, "error selecting user offers", err)
 	}
 
 	if len(offersWithCount) == 0 {
 		return []entity.Offer{}, 0, nil
 	}
 
-	total = offersWithCount[0].TotalCount
+	total := offersWithCount[0].TotalCount
 
 	offers := make([]entity.Offer, len(offersWithCount))
 	for i, offerModel := range offersWithCount {
@@ -150,29 +162,49 @@ func (r *OfferRepository) SelectUserOffers(
 	return offers, total, nil
 }
 
-// SelectUserOffers lazy update helper function
-func (r *OfferRepository) updateExpiredOffers(ctx context.Context, userID uint, tx *sqlx.Tx) error {
-	updateExpiredQuery, args := squirrel.Update("offers").
+// ExpireUserOffers отменяет просроченные офферы пользователя и возвращает их
+func (r *OfferRepository) ExpireUserOffers(ctx context.Context, userID uint) ([]entity.Offer, error) {
+	return r.expireOffers(ctx, squirrel.Eq{"user_id": userID})
+}
+
+// ExpireOffers отменяет все просроченные офферы и возвращает их
+func (r *OfferRepository) ExpireOffers(ctx context.Context) ([]entity.Offer, error) {
+	return r.expireOffers(ctx, nil)
+}
+
+func (r *OfferRepository) expireOffers(ctx context.Context, where squirrel.Sqlizer) ([]entity.Offer, error) {
+	now := time.Now()
+	builder := squirrel.Update("offers").
 		Set("status", "cancelled").
-		Set("updated_at", time.Now()).
-		Where(squirrel.Lt{"expires_at": time.Now()}).
+		Set("updated_at", now).
+		Where(squirrel.Lt{"expires_at": now}).
 		Where(squirrel.Eq{"status": "pending"}).
-		Where(squirrel.Eq{"user_id": userID}).
-		PlaceholderFormat(squirrel.Dollar).
-		MustSql()
+		Suffix("returning " + offerColumns).
+		PlaceholderFormat(squirrel.Dollar)
+	if where != nil {
+		builder = builder.Where(where)
+	}
+	updateExpiredQuery, args := builder.MustSql()
 
-	_, err := tx.ExecContext(ctx, updateExpiredQuery, args...)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return apperror.New(apperror.DatabaseError, "error updating expired offers", err)
+	var expired []model.Offer
+	if err := r.db.SelectContext(ctx, &expired, updateExpiredQuery, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error updating expired offers", err)
 	}
-	return nil
+
+	offers := make([]entity.Offer, len(expired))
+	for i, offerModel := range expired {
+		offers[i] = offerModel.ConvertToEntity()
+	}
+	return offers, nil
 }
 
+// UpdateOfferStatus меняет статус оффера. onUpdate вызывается в той же транзакции с обновленным оффером
 func (r *OfferRepository) UpdateOfferStatus(
 	ctx context.Context,
 	offerEntity entity.Offer,
 	userID uint,
 	isStore bool,
+	onUpdate func(tx email.Execer, offer entity.Offer) error,
 ) (entity.Offer, error) {
 	offer := model.ConvertOfferEntityToModel(offerEntity)
 
@@ -180,7 +212,7 @@ func (r *OfferRepository) UpdateOfferStatus(
 		Set("status", offer.Status).
 		Set("updated_at", time.Now()).
 		Where(squirrel.Eq{"id": offer.ID}).
-		Suffix("returning status").
+		Suffix("returning " + offerColumns).
 		PlaceholderFormat(squirrel.Dollar).
 		MustSql()
 
@@ -198,7 +230,7 @@ func (r *OfferRepository) UpdateOfferStatus(
 	}
 
 	if isStore {
-		err = isUserShopOwner(ctx, offer.ID, userID, tx)
+		err = canUserManageOffer(ctx, offer.ID, userID, tx)
 		if err != nil {
 			return entity.Offer{}, err
 		}
@@ -210,7 +242,7 @@ func (r *OfferRepository) UpdateOfferStatus(
 			Set("status", offer.Status).
 			Set("updated_at", time.Now()).
 			Where(squirrel.Eq{"id": offer.ID, "user_id": userID}).
-			Suffix("returning status").
+			Suffix("returning " + offerColumns).
 			PlaceholderFormat(squirrel.Dollar).
 			MustSql()
 	}
@@ -225,34 +257,42 @@ func (r *OfferRepository) UpdateOfferStatus(
 		return entity.Offer{}, apperror.New(apperror.DatabaseError, "error scanning into struct", err)
 	}
 
+	updated := offerResp.ConvertToEntity()
+	if onUpdate != nil {
+		if err := onUpdate(tx, updated); err != nil {
+			return entity.Offer{}, err
+		}
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return entity.Offer{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
 	}
 
-	return offerResp.ConvertToEntity(), nil
+	return updated, nil
 }
 
-func isUserShopOwner(ctx context.Context, offerID, userID uint, tx *sqlx.Tx) error {
-	validateShopOwnerIDQuery, args := squirrel.Select("users.id").
-		From("users").
-		InnerJoin("shops on users.id = shops.user_id").
-		InnerJoin("offers on shops.id = offers.shop_id").
-		Where(squirrel.Eq{"offers.id": offerID}).
+// canUserManageOffer проверяет, что пользователь является владельцем или менеджером магазина,
+// которому адресован оффер
+func canUserManageOffer(ctx context.Context, offerID, userID uint, tx *sqlx.Tx) error {
+	memberRoleQuery, args := squirrel.Select("shop_members.role").
+		From("shop_members").
+		InnerJoin("offers on offers.shop_id = shop_members.shop_id").
+		Where(squirrel.Eq{"offers.id": offerID, "shop_members.user_id": userID}).
 		PlaceholderFormat(squirrel.Dollar).
 		MustSql()
 
-	var requiredID uint
-	err := tx.QueryRowxContext(ctx, validateShopOwnerIDQuery, args...).Scan(&requiredID)
+	var role string
+	err := tx.QueryRowxContext(ctx, memberRoleQuery, args...).Scan(&role)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return apperror.ErrUserNotFound
+			return apperror.New(apperror.Unauthorized, "unauthorized to update offer status", nil)
 		}
-		return apperror.New(apperror.InternalError, "error scanning into uint", err)
+		return apperror.New(apperror.InternalError, "error scanning member role", err)
 	}
 
-	if userID != requiredID {
-		return apperror.New(apperror.Unauthorized, "unauthorized to update offer status", nil)
+	if !entity.ShopRole(role).CanManage() {
+		return apperror.New(apperror.Forbidden, "shop viewers cannot update offer status", nil)
 	}
 
 	return nil
@@ -57,6 +57,126 @@ func (r *ProductRepository) GetProductByID(
 	return model.ConvertProductToEntity(productModel), nil
 }
 
+// InsertProduct создает продукт вместе с его атрибутами и вариантом по умолчанию
+func (r *ProductRepository) InsertProduct(
+	ctx context.Context,
+	product entity.Product,
+) (int, error) {
+	tx, err := r.Db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query, args, err := sq.Insert("products").
+		Columns("name", "description", "category_id").
+		Values(product.Name, product.Description, product.CategoryID).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to build SQL query", err)
+	}
+
+	var productID int
+	if err := tx.QueryRowxContext(ctx, query, args...).Scan(&productID); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to insert product", err)
+	}
+
+	if err := upsertAttributes(ctx, tx, productID, product.Attributes); err != nil {
+		return 0, err
+	}
+
+	if _, err := insertVariant(ctx, tx, entity.ProductVariant{
+		ProductID: productID,
+		SKU:       defaultSKU(productID),
+	}); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return productID, nil
+}
+
+// UpdateProduct полностью заменяет данные и атрибуты продукта
+func (r *ProductRepository) UpdateProduct(
+	ctx context.Context,
+	product entity.Product,
+) error {
+	tx, err := r.Db.BeginTxx(ctx, nil)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query, args, err := sq.Update("products").
+		Set("name", product.Name).
+		Set("description", product.Description).
+		Set("category_id", product.CategoryID).
+		Where(sq.Eq{"id": product.ID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to build SQL query", err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to update product", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to get affected rows", err)
+	}
+	if affected == 0 {
+		return apperror.ErrProductNotFound
+	}
+
+	if err := upsertAttributes(ctx, tx, product.ID, product.Attributes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+func upsertAttributes(ctx context.Context, tx *sqlx.Tx, productID int, attrs map[string]interface{}) error {
+	if attrs == nil {
+		attrs = map[string]interface{}{}
+	}
+
+	attributesJSONb, err := json.Marshal(attrs)
+	if err != nil {
+		return apperror.New(apperror.InternalError, "failed to marshal product attributes", err)
+	}
+
+	query, args, err := sq.Insert("product_attributes").
+		Columns("product_id", "attributes").
+		Values(productID, attributesJSONb).
+		Suffix("ON CONFLICT (product_id) DO UPDATE SET attributes = EXCLUDED.attributes").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to build SQL query", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to save product attributes", err)
+	}
+
+	return nil
+}
+
 func (r *ProductRepository) GetFilteredProducts(
 	ctx context.Context,
 	filter model.ProductFilter,
@@ -254,7 +374,7 @@ func (r *ProductRepository) GetAttributesByID(ctx context.Context,
 	return attributes, nil
 }
 
-// GetPriceRangeByProductID получает минимальную и максимальную цену на продукт
+// GetPriceRangeByProductID получает минимальную и максимальную цену на продукт по всем его вариантам
 func (r *ProductRepository) GetPriceRangeByProductID(ctx context.Context,
 	productID int) (int, int, error) {
 	var priceRange struct {
@@ -294,7 +414,8 @@ func (r *ProductRepository) GetPriceRangeByProductID(ctx context.Context,
 	return minPrice, maxPrice, nil
 }
 
-// GetAverageRatingByProductID получает средний рейтинг и количество отзывов на продукт
+// GetAverageRatingByProductID получает средний рейтинг и количество опубликованных отзывов на продукт.
+// Скрытые модератором отзывы не учитываются.
 func (r *ProductRepository) GetAverageRatingByProductID(ctx context.Context,
 	productID int) (float64, int, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
@@ -302,7 +423,7 @@ func (r *ProductRepository) GetAverageRatingByProductID(ctx context.Context,
 	queryBuilder := psql.
 		Select("AVG(rating) average", "COUNT(*) count").
 		From("product_reviews").
-		Where(sq.Eq{"product_id": productID})
+		Where(sq.Eq{"product_id": productID, "status": entity.ReviewStatusPublished})
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	sq "github.com/Masterminds/squirrel"
+)
+
+var productImageColumns = []string{"id", "product_id", "image_key", "thumbnail_key", "content_type", "created_at"}
+
+// GetImagesByProductID получает изображения продукта в порядке загрузки
+func (r *ProductRepository) GetImagesByProductID(
+	ctx context.Context,
+	productID int,
+) ([]entity.ProductImage, error) {
+	query, args := sq.Select(productImageColumns...).
+		From("image_keys").
+		Where(sq.Eq{"product_id": productID}).
+		OrderBy("created_at", "id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var imageModels []model.ProductImage
+	if err := r.Db.SelectContext(ctx, &imageModels, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch product images", err)
+	}
+
+	images := make([]entity.ProductImage, 0, len(imageModels))
+	for _, im := range imageModels {
+		images = append(images, model.ConvertProductImageToEntity(im))
+	}
+
+	return images, nil
+}
+
+// GetImageByID получает изображение продукта по его ID
+func (r *ProductRepository) GetImageByID(
+	ctx context.Context,
+	imageID int,
+) (entity.ProductImage, error) {
+	query, args := sq.Select(productImageColumns...).
+		From("image_keys").
+		Where(sq.Eq{"id": imageID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var imageModel model.ProductImage
+	if err := r.Db.GetContext(ctx, &imageModel, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ProductImage{}, apperror.ErrImageNotFound
+		}
+		return entity.ProductImage{}, apperror.New(apperror.DatabaseError, "failed to fetch product image", err)
+	}
+
+	return model.ConvertProductImageToEntity(imageModel), nil
+}
+
+// InsertImage сохраняет ключи загруженного изображения продукта
+func (r *ProductRepository) InsertImage(
+	ctx context.Context,
+	image entity.ProductImage,
+) (entity.ProductImage, error) {
+	query, args := sq.Insert("image_keys").
+		Columns("product_id", "image_key", "thumbnail_key", "content_type").
+		Values(image.ProductID, image.Key, image.ThumbnailKey, image.ContentType).
+		Suffix("RETURNING id, created_at").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if err := r.Db.QueryRowxContext(ctx, query, args...).Scan(&image.ID, &image.CreatedAt); err != nil {
+		return entity.ProductImage{}, apperror.New(apperror.DatabaseError, "failed to insert product image", err)
+	}
+
+	return image, nil
+}
+
+// DeleteImage удаляет запись об изображении продукта
+func (r *ProductRepository) DeleteImage(
+	ctx context.Context,
+	imageID int,
+) error {
+	query, args := sq.Delete("image_keys").
+		Where(sq.Eq{"id": imageID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.Db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to delete product image", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to get affected rows", err)
+	}
+	if affected == 0 {
+		return apperror.ErrImageNotFound
+	}
+
+	return nil
+}
+
+// IsProductInUserShop проверяет, продается ли продукт в одном из магазинов,
+// где пользователь является владельцем или менеджером
+func (r *ProductRepository) IsProductInUserShop(
+	ctx context.Context,
+	productID int,
+	userID uint,
+) (bool, error) {
+	query, args := sq.Select("1").
+		Prefix("SELECT EXISTS (").
+		From("shop_inventory si").
+		InnerJoin("shop_members m ON m.shop_id = si.shop_id").
+		Where(sq.Eq{
+			"si.product_id": productID,
+			"m.user_id":     userID,
+			"m.role":        []entity.ShopRole{entity.ShopRoleOwner, entity.ShopRoleManager},
+		}).
+		Suffix(")").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var exists bool
+	if err := r.Db.GetContext(ctx, &exists, query, args...); err != nil {
+		return false, apperror.New(apperror.DatabaseError, "failed to check product ownership", err)
+	}
+
+	return exists, nil
+}
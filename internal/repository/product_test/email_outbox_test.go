@@ -0,0 +1,159 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/repository"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+	"github.com/jmoiron/sqlx"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EmailOutboxRepository", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+		repo *repository.EmailOutboxRepository
+		ctx  context.Context
+	)
+
+	outboxRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{
+			"id", "idempotency_key", "recipient", "template", "locale", "data", "unsubscribe_url", "status",
+			"attempts", "next_attempt_at", "last_error", "created_at", "sent_at",
+		}).AddRow(7, "registered:1", "bob@example.com", email.TemplateRegistered, "en", []byte(`{}`), "",
+			email.StatusPending, 1, time.Now(), "", time.Now(), nil)
+	}
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		repo = repository.NewEmailOutboxRepository(sqlx.NewDb(db, "sqlmock"))
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Describe("AddMessages", func() {
+		It("should skip messages whose idempotency key is already stored", func() {
+			mock.ExpectExec(`INSERT INTO email_outbox .* ON CONFLICT \(idempotency_key\) DO NOTHING`).
+				WithArgs("registered:1", "bob@example.com", email.TemplateRegistered, "en", sqlmock.AnyArg(), "",
+					nil, "bob@example.com", email.TemplateGuestOffer, "en", sqlmock.AnyArg(), "").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := repo.AddMessages(ctx, nil, []email.Message{
+				{
+					IdempotencyKey: "registered:1", To: "bob@example.com",
+					Template: email.TemplateRegistered, Locale: "en",
+				},
+				{To: "bob@example.com", Template: email.TemplateGuestOffer, Locale: "en"},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should write through the given transaction", func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(`INSERT INTO email_outbox`).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectRollback()
+
+			tx, err := db.Begin()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo.AddMessages(ctx, tx, []email.Message{{To: "bob@example.com"}})).To(Succeed())
+			Expect(tx.Rollback()).To(Succeed())
+		})
+	})
+
+	Describe("ClaimMessages", func() {
+		It("should claim due messages with SKIP LOCKED and extend their lease", func() {
+			mock.ExpectQuery(`UPDATE email_outbox\s+SET attempts = attempts \+ 1.*FOR UPDATE SKIP LOCKED`).
+				WithArgs(float64(300), 20).
+				WillReturnRows(outboxRow())
+
+			msgs, err := repo.ClaimMessages(ctx, 20, 5*time.Minute)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msgs).To(HaveLen(1))
+			Expect(msgs[0].ID).To(Equal(int64(7)))
+			Expect(msgs[0].IdempotencyKey).To(Equal("registered:1"))
+		})
+	})
+
+	Describe("MarkFailed", func() {
+		It("should schedule the next attempt and keep the message pending", func() {
+			retryAt := time.Now().Add(time.Minute)
+			mock.ExpectExec(`UPDATE email_outbox SET last_error = \$1, next_attempt_at = \$2 WHERE id = \$3`).
+				WithArgs("timeout", retryAt, int64(7)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(repo.MarkFailed(ctx, 7, "timeout", &retryAt)).To(Succeed())
+		})
+
+		It("should move the message to dead letters without a next attempt", func() {
+			mock.ExpectExec(`UPDATE email_outbox SET last_error = \$1, status = \$2, `+
+				`data = CASE WHEN template IN \(\$3,\$4\) THEN '\{\}'::jsonb ELSE data END WHERE id = \$5`).
+				WithArgs("timeout", email.StatusDead, email.TemplatePasswordReset, email.TemplateEmailVerification,
+					int64(7)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(repo.MarkFailed(ctx, 7, "timeout", nil)).To(Succeed())
+		})
+	})
+
+	Describe("RetryOutboxMessage", func() {
+		It("should requeue an unsent message with reset attempts", func() {
+			mock.ExpectQuery(`UPDATE email_outbox SET status = \$1, attempts = \$2, next_attempt_at = NOW\(\) `+
+				`WHERE id = \$3 AND status <> \$4 AND template NOT IN \(\$5,\$6\) RETURNING`).
+				WithArgs(email.StatusPending, 0, int64(7), email.StatusSent,
+					email.TemplatePasswordReset, email.TemplateEmailVerification).
+				WillReturnRows(outboxRow())
+
+			msg, err := repo.RetryOutboxMessage(ctx, 7)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Status).To(Equal(email.StatusPending))
+		})
+
+		It("should return a conflict for an already sent message", func() {
+			mock.ExpectQuery(`UPDATE email_outbox`).WillReturnError(sql.ErrNoRows)
+			mock.ExpectQuery(`SELECT template FROM email_outbox`).WithArgs(int64(7)).
+				WillReturnRows(sqlmock.NewRows([]string{"template"}).AddRow(email.TemplateRegistered))
+
+			_, err := repo.RetryOutboxMessage(ctx, 7)
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.Conflict))
+		})
+
+		It("should not retry a message with a one-time token", func() {
+			mock.ExpectQuery(`UPDATE email_outbox`).WillReturnError(sql.ErrNoRows)
+			mock.ExpectQuery(`SELECT template FROM email_outbox`).WithArgs(int64(7)).
+				WillReturnRows(sqlmock.NewRows([]string{"template"}).AddRow(email.TemplatePasswordReset))
+
+			_, err := repo.RetryOutboxMessage(ctx, 7)
+
+			Expect(err).To(MatchError(ContainSubstring("one-time token")))
+		})
+
+		It("should return not found for an unknown message", func() {
+			mock.ExpectQuery(`UPDATE email_outbox`).WillReturnError(sql.ErrNoRows)
+			mock.ExpectQuery(`SELECT template FROM email_outbox`).WithArgs(int64(7)).
+				WillReturnError(sql.ErrNoRows)
+
+			_, err := repo.RetryOutboxMessage(ctx, 7)
+
+			Expect(err).To(MatchError(apperror.ErrEmailNotFound))
+		})
+	})
+})
@@ -144,8 +144,9 @@ var _ = Describe("ProductRepository", func() {
 			rows := sqlmock.NewRows([]string{"average", "count"}).
 				AddRow(4.5, 10)
 
-			mock.ExpectQuery(`SELECT AVG\(rating\) average, COUNT\(\*\) count FROM product_reviews WHERE product_id = \$1`).
-				WithArgs(productID).
+			mock.ExpectQuery(`SELECT AVG\(rating\) average, COUNT\(\*\) count FROM product_reviews `+
+				`WHERE product_id = \$1 AND status = \$2`).
+				WithArgs(productID, "published").
 				WillReturnRows(rows)
 
 			avg, count, err := repo.GetAverageRatingByProductID(ctx, productID)
@@ -161,8 +162,9 @@ var _ = Describe("ProductRepository", func() {
 			rows := sqlmock.NewRows([]string{"average", "count"}).
 				AddRow(nil, nil)
 
-			mock.ExpectQuery(`SELECT AVG\(rating\) average, COUNT\(\*\) count FROM product_reviews WHERE product_id = \$1`).
-				WithArgs(productID).
+			mock.ExpectQuery(`SELECT AVG\(rating\) average, COUNT\(\*\) count FROM product_reviews `+
+				`WHERE product_id = \$1 AND status = \$2`).
+				WithArgs(productID, "published").
 				WillReturnRows(rows)
 
 			avg, count, err := repo.GetAverageRatingByProductID(ctx, productID)
@@ -175,8 +177,9 @@ var _ = Describe("ProductRepository", func() {
 
 		It("should return error when query fails", func() {
 			productID := 123
-			mock.ExpectQuery(`SELECT AVG\(rating\) average, COUNT\(\*\) count FROM product_reviews WHERE product_id = \$1`).
-				WithArgs(productID).
+			mock.ExpectQuery(`SELECT AVG\(rating\) average, COUNT\(\*\) count FROM product_reviews `+
+				`WHERE product_id = \$1 AND status = \$2`).
+				WithArgs(productID, "published").
 				WillReturnError(sql.ErrConnDone)
 
 			avg, count, err := repo.GetAverageRatingByProductID(ctx, productID)
@@ -0,0 +1,47 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/EM-Stawberry/Stawberry/internal/repository"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/jmoiron/sqlx"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StoreRepository", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+		repo *repository.StoreRepository
+		ctx  context.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		repo = repository.NewStoreRepository(sqlx.NewDb(db, "sqlmock"))
+		ctx = context.Background()
+	})
+
+	Describe("GetStores", func() {
+		It("should escape LIKE wildcards in the search string", func() {
+			pattern := `%50\%\_off\\%`
+			mock.ExpectQuery(`ILIKE \$1 OR s.description ILIKE \$2`).
+				WithArgs(pattern, pattern).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+			stores, total, err := repo.GetStores(ctx, model.StoreFilter{Search: `50%_off\`}, 10, 0)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stores).To(BeEmpty())
+			Expect(total).To(BeZero())
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+})
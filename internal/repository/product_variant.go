@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+// GetVariantsByProductID получает все варианты продукта
+func (r *ProductRepository) GetVariantsByProductID(
+	ctx context.Context,
+	productID int,
+) ([]entity.ProductVariant, error) {
+	query, args := sq.Select("id", "product_id", "sku", "attributes").
+		From("product_variants").
+		Where(sq.Eq{"product_id": productID}).
+		OrderBy("id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var variantModels []model.ProductVariant
+	if err := r.Db.SelectContext(ctx, &variantModels, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch product variants", err)
+	}
+
+	variants := make([]entity.ProductVariant, 0, len(variantModels))
+	for _, vm := range variantModels {
+		variant, err := model.ConvertProductVariantToEntity(vm)
+		if err != nil {
+			return nil, apperror.New(apperror.DatabaseError, "failed to unmarshal variant attributes", err)
+		}
+		variants = append(variants, variant)
+	}
+
+	return variants, nil
+}
+
+// GetVariantInventoryByProductID получает предложения магазинов по всем вариантам продукта
+func (r *ProductRepository) GetVariantInventoryByProductID(
+	ctx context.Context,
+	productID int,
+) ([]entity.VariantInventory, error) {
+	query, args := sq.Select(
+		"variant_id", "shop_id", "CAST(price * 100 AS BIGINT) AS price", "currency", "is_available",
+	).
+		From("shop_inventory").
+		Where(sq.Eq{"product_id": productID}).
+		OrderBy("variant_id", "price").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var inventoryModels []model.VariantInventory
+	if err := r.Db.SelectContext(ctx, &inventoryModels, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch variant inventory", err)
+	}
+
+	inventory := make([]entity.VariantInventory, 0, len(inventoryModels))
+	for _, im := range inventoryModels {
+		inventory = append(inventory, model.ConvertVariantInventoryToEntity(im))
+	}
+
+	return inventory, nil
+}
+
+// InsertVariant создает вариант продукта
+func (r *ProductRepository) InsertVariant(
+	ctx context.Context,
+	variant entity.ProductVariant,
+) (int, error) {
+	return insertVariant(ctx, r.Db, variant)
+}
+
+// UpdateVariant заменяет SKU и атрибуты варианта
+func (r *ProductRepository) UpdateVariant(
+	ctx context.Context,
+	variant entity.ProductVariant,
+) error {
+	attributesJSONb, err := marshalVariantAttributes(variant.Attributes)
+	if err != nil {
+		return err
+	}
+
+	query, args := sq.Update("product_variants").
+		Set("sku", variant.SKU).
+		Set("attributes", attributesJSONb).
+		Where(sq.Eq{"id": variant.ID, "product_id": variant.ProductID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.Db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return variantWriteError(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to get affected rows", err)
+	}
+	if affected == 0 {
+		return apperror.ErrVariantNotFound
+	}
+
+	return nil
+}
+
+// insertVariant используется и отдельно, и внутри транзакции создания продукта
+func insertVariant(ctx context.Context, db sqlx.QueryerContext, variant entity.ProductVariant) (int, error) {
+	attributesJSONb, err := marshalVariantAttributes(variant.Attributes)
+	if err != nil {
+		return 0, err
+	}
+
+	query, args := sq.Insert("product_variants").
+		Columns("product_id", "sku", "attributes").
+		Values(variant.ProductID, variant.SKU, attributesJSONb).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var variantID int
+	if err := db.QueryRowxContext(ctx, query, args...).Scan(&variantID); err != nil {
+		return 0, variantWriteError(err)
+	}
+
+	return variantID, nil
+}
+
+// defaultSKU формирует SKU варианта, который создается вместе с продуктом
+func defaultSKU(productID int) string {
+	return fmt.Sprintf("P%06d", productID)
+}
+
+func marshalVariantAttributes(attrs map[string]interface{}) ([]byte, error) {
+	if attrs == nil {
+		attrs = map[string]interface{}{}
+	}
+	attributesJSONb, err := json.Marshal(attrs)
+	if err != nil {
+		return nil, apperror.New(apperror.InternalError, "failed to marshal variant attributes", err)
+	}
+	return attributesJSONb, nil
+}
+
+func variantWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgerrcode.UniqueViolation:
+			return apperror.New(apperror.DuplicateError, "variant with this sku already exists", err)
+		case pgerrcode.ForeignKeyViolation:
+			return apperror.ErrProductNotFound
+		}
+	}
+	return apperror.New(apperror.DatabaseError, "failed to save product variant", err)
+}
@@ -0,0 +1,247 @@
+package reviews
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+var replyColumns = []string{
+	"id", "review_type", "review_id", "shop_id", "author_id", "body", "created_at", "updated_at",
+}
+
+// ReviewInteractionRepository defines the interface for shop replies and helpfulness votes data operations.
+type ReviewInteractionRepository interface {
+	GetReviewTarget(ctx context.Context, reviewType string, reviewID int) (entity.ReviewTarget, error)
+	GetShopRole(ctx context.Context, shopID int, userID int) (entity.ShopRole, error)
+	ShopSellsProduct(ctx context.Context, shopID int, productID int) (bool, error)
+	GetReply(ctx context.Context, reviewType string, reviewID int) (entity.ReviewReply, error)
+	UpsertReply(ctx context.Context, reply entity.ReviewReply) (entity.ReviewReply, bool, error)
+	DeleteReply(ctx context.Context, reviewType string, reviewID int) error
+	SetVote(ctx context.Context, reviewType string, reviewID int, userID int, helpful bool) error
+	DeleteVote(ctx context.Context, reviewType string, reviewID int, userID int) error
+}
+
+type reviewInteractionRepository struct {
+	db     *sqlx.DB
+	logger *zap.Logger
+}
+
+func NewReviewInteractionRepository(db *sqlx.DB, l *zap.Logger) ReviewInteractionRepository {
+	return &reviewInteractionRepository{
+		db:     db,
+		logger: l,
+	}
+}
+
+func (r *reviewInteractionRepository) GetReviewTarget(
+	ctx context.Context, reviewType string, reviewID int,
+) (entity.ReviewTarget, error) {
+	const op = "reviewInteractionRepository.GetReviewTarget()"
+	return getReviewTarget(ctx, r.db, r.logger.With(zap.String("op", op)), op, reviewType, reviewID)
+}
+
+// GetShopRole returns the role of the user in the shop, or an empty role if they are not a member.
+func (r *reviewInteractionRepository) GetShopRole(
+	ctx context.Context, shopID int, userID int,
+) (entity.ShopRole, error) {
+	const op = "reviewInteractionRepository.GetShopRole()"
+	log := r.logger.With(zap.String("op", op))
+
+	var role string
+	err := r.db.GetContext(ctx, &role,
+		"SELECT role FROM shop_members WHERE shop_id = $1 AND user_id = $2", shopID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		log.Error("Failed to execute query", zap.Error(err))
+		return "", fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return entity.ShopRole(role), nil
+}
+
+func (r *reviewInteractionRepository) ShopSellsProduct(
+	ctx context.Context, shopID int, productID int,
+) (bool, error) {
+	const op = "reviewInteractionRepository.ShopSellsProduct()"
+	log := r.logger.With(zap.String("op", op))
+
+	var exists bool
+	err := r.db.GetContext(ctx, &exists,
+		"SELECT EXISTS (SELECT 1 FROM shop_inventory WHERE shop_id = $1 AND product_id = $2)", shopID, productID)
+	if err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return exists, nil
+}
+
+func (r *reviewInteractionRepository) GetReply(
+	ctx context.Context, reviewType string, reviewID int,
+) (entity.ReviewReply, error) {
+	const op = "reviewInteractionRepository.GetReply()"
+	log := r.logger.With(zap.String("op", op))
+
+	query, args, err := squirrel.
+		Select(replyColumns...).
+		From("review_replies").
+		Where(squirrel.Eq{"review_type": reviewType, "review_id": reviewID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return entity.ReviewReply{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	var reply entity.ReviewReply
+	if err := r.db.GetContext(ctx, &reply, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ReviewReply{}, apperror.NewReviewError(apperror.NotFound, "reply not found")
+		}
+		log.Error("Failed to execute query", zap.Error(err))
+		return entity.ReviewReply{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return reply, nil
+}
+
+// UpsertReply creates the reply to the review or edits it if it belongs to the same shop.
+// It returns the stored reply and whether it was created.
+func (r *reviewInteractionRepository) UpsertReply(
+	ctx context.Context, reply entity.ReviewReply,
+) (entity.ReviewReply, bool, error) {
+	const op = "reviewInteractionRepository.UpsertReply()"
+	log := r.logger.With(zap.String("op", op))
+
+	query, args, err := squirrel.Insert("review_replies").
+		Columns("review_type", "review_id", "shop_id", "author_id", "body").
+		Values(reply.ReviewType, reply.ReviewID, reply.ShopID, reply.AuthorID, reply.Body).
+		Suffix("ON CONFLICT (review_type, review_id) DO UPDATE SET " +
+			"body = EXCLUDED.body, author_id = EXCLUDED.author_id, updated_at = CURRENT_TIMESTAMP " +
+			"WHERE review_replies.shop_id = EXCLUDED.shop_id " +
+			"RETURNING id, created_at, updated_at, (xmax = 0) AS created").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return entity.ReviewReply{}, false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	var created bool
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&reply.ID, &reply.CreatedAt, &reply.UpdatedAt, &created)
+	if err != nil {
+		// Конфликт без обновления означает, что на отзыв уже ответил другой магазин
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ReviewReply{}, false, apperror.NewReviewError(apperror.ReviewDuplicate,
+				"the review already has a reply from another shop")
+		}
+		log.Error("Failed to execute query", zap.Error(err))
+		return entity.ReviewReply{}, false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return reply, created, nil
+}
+
+func (r *reviewInteractionRepository) DeleteReply(ctx context.Context, reviewType string, reviewID int) error {
+	const op = "reviewInteractionRepository.DeleteReply()"
+	log := r.logger.With(zap.String("op", op))
+
+	res, err := r.db.ExecContext(ctx,
+		"DELETE FROM review_replies WHERE review_type = $1 AND review_id = $2", reviewType, reviewID)
+	if err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		return apperror.NewReviewError(apperror.NotFound, "reply not found")
+	}
+
+	return nil
+}
+
+// SetVote stores the user's vote for the review, replacing the previous one, and recounts the review votes.
+func (r *reviewInteractionRepository) SetVote(
+	ctx context.Context, reviewType string, reviewID int, userID int, helpful bool,
+) error {
+	const op = "reviewInteractionRepository.SetVote()"
+	log := r.logger.With(zap.String("op", op))
+
+	return r.inVoteTx(ctx, log, op, reviewType, reviewID, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO review_votes (review_type, review_id, user_id, helpful) VALUES ($1, $2, $3, $4) "+
+				"ON CONFLICT (review_type, review_id, user_id) DO UPDATE SET helpful = EXCLUDED.helpful",
+			reviewType, reviewID, userID, helpful)
+		return err
+	})
+}
+
+func (r *reviewInteractionRepository) DeleteVote(
+	ctx context.Context, reviewType string, reviewID int, userID int,
+) error {
+	const op = "reviewInteractionRepository.DeleteVote()"
+	log := r.logger.With(zap.String("op", op))
+
+	return r.inVoteTx(ctx, log, op, reviewType, reviewID, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			"DELETE FROM review_votes WHERE review_type = $1 AND review_id = $2 AND user_id = $3",
+			reviewType, reviewID, userID)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+			return apperror.NewReviewError(apperror.NotFound, "vote not found")
+		}
+		return nil
+	})
+}
+
+// inVoteTx changes votes in a transaction and then recounts the vote counters of the review.
+func (r *reviewInteractionRepository) inVoteTx(
+	ctx context.Context, log *zap.Logger, op, reviewType string, reviewID int, change func(tx *sqlx.Tx) error,
+) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := change(tx); err != nil {
+		var reviewErr *apperror.ReviewError
+		if errors.As(err, &reviewErr) {
+			return err
+		}
+		log.Error("Failed to change vote", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	recount := fmt.Sprintf("UPDATE %s SET "+
+		"helpful_count = (SELECT COUNT(*) FROM review_votes v "+
+		"WHERE v.review_type = $1 AND v.review_id = $2 AND v.helpful), "+
+		"not_helpful_count = (SELECT COUNT(*) FROM review_votes v "+
+		"WHERE v.review_type = $1 AND v.review_id = $2 AND NOT v.helpful) "+
+		"WHERE id = $2", reviewTables[reviewType].table)
+	if _, err := tx.ExecContext(ctx, recount, reviewType, reviewID); err != nil {
+		log.Error("Failed to recount votes", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Failed to commit transaction", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return nil
+}
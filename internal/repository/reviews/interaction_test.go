@@ -0,0 +1,121 @@
+package reviews_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"time"
+
+	go_sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/reviews"
+	"github.com/jmoiron/sqlx"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("ReviewInteractionRepository", func() {
+	var (
+		db         *sql.DB
+		mock       go_sqlmock.Sqlmock
+		repository reviews.ReviewInteractionRepository
+		ctx        context.Context
+	)
+
+	expectReviewErrorCode := func(err error, code string) {
+		var reviewErr *apperror.ReviewError
+		ExpectWithOffset(1, errors.As(err, &reviewErr)).To(BeTrue())
+		ExpectWithOffset(1, reviewErr.Code()).To(Equal(code))
+	}
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = go_sqlmock.New()
+		Expect(err).NotTo(HaveOccurred())
+
+		repository = reviews.NewReviewInteractionRepository(sqlx.NewDb(db, "sqlmock"), zap.NewNop())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("GetShopRole", func() {
+		It("should return an empty role for a non-member", func() {
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT role FROM shop_members WHERE shop_id = $1 AND user_id = $2")).
+				WithArgs(1, 2).
+				WillReturnError(sql.ErrNoRows)
+
+			role, err := repository.GetShopRole(ctx, 1, 2)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(role).To(BeEmpty())
+		})
+	})
+
+	Context("UpsertReply", func() {
+		upsertQuery := regexp.QuoteMeta("INSERT INTO review_replies (review_type,review_id,shop_id,author_id,body) " +
+			"VALUES ($1,$2,$3,$4,$5) ON CONFLICT (review_type, review_id) DO UPDATE SET")
+
+		It("should store the reply", func() {
+			authorID := 5
+			mock.ExpectQuery(upsertQuery).
+				WithArgs("seller", 3, 1, &authorID, "Thanks!").
+				WillReturnRows(go_sqlmock.NewRows([]string{"id", "created_at", "updated_at", "created"}).
+					AddRow(4, time.Now(), nil, true))
+
+			reply, created, err := repository.UpsertReply(ctx, entity.ReviewReply{
+				ReviewType: entity.ReviewTypeSeller, ReviewID: 3, ShopID: 1, AuthorID: &authorID, Body: "Thanks!",
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(BeTrue())
+			Expect(reply.ID).To(Equal(4))
+			Expect(reply.UpdatedAt).To(BeNil())
+		})
+
+		It("should return a duplicate error when another shop has replied", func() {
+			mock.ExpectQuery(upsertQuery).
+				WillReturnRows(go_sqlmock.NewRows([]string{"id", "created_at", "updated_at", "created"}))
+
+			_, _, err := repository.UpsertReply(ctx, entity.ReviewReply{
+				ReviewType: entity.ReviewTypeProduct, ReviewID: 3, ShopID: 2, Body: "Thanks!",
+			})
+
+			expectReviewErrorCode(err, apperror.ReviewDuplicate)
+		})
+	})
+
+	Context("SetVote", func() {
+		It("should store the vote and recount the review votes", func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta("INSERT INTO review_votes (review_type, review_id, user_id, helpful)")).
+				WithArgs("product", 3, 2, true).
+				WillReturnResult(go_sqlmock.NewResult(0, 1))
+			mock.ExpectExec(regexp.QuoteMeta("UPDATE product_reviews SET helpful_count = ")).
+				WithArgs("product", 3).
+				WillReturnResult(go_sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			Expect(repository.SetVote(ctx, entity.ReviewTypeProduct, 3, 2, true)).To(Succeed())
+		})
+	})
+
+	Context("DeleteVote", func() {
+		It("should return NotFound error when the user has not voted", func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta("DELETE FROM review_votes")).
+				WithArgs("seller", 3, 2).
+				WillReturnResult(go_sqlmock.NewResult(0, 0))
+			mock.ExpectRollback()
+
+			err := repository.DeleteVote(ctx, entity.ReviewTypeSeller, 3, 2)
+
+			expectReviewErrorCode(err, apperror.NotFound)
+		})
+	})
+})
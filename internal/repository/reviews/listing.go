@@ -0,0 +1,116 @@
+package reviews
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// reviewOrder maps sort options to ORDER BY clauses. The trailing id keeps pages stable.
+var reviewOrder = map[string]string{
+	entity.ReviewSortNewest:  "created_at DESC, id DESC",
+	entity.ReviewSortHighest: "rating DESC, created_at DESC, id DESC",
+	entity.ReviewSortLowest:  "rating ASC, created_at DESC, id DESC",
+	// Helpful votes minus not helpful ones, ties go to the review with more helpful votes.
+	entity.ReviewSortMostHelpful: "helpful_count - not_helpful_count DESC, helpful_count DESC, " +
+		"created_at DESC, id DESC",
+}
+
+// totalCountColumn counts all rows matching the filter before LIMIT is applied.
+const totalCountColumn = "COUNT(*) OVER() AS total_count"
+
+// applyReviewFilter adds rating bounds, ordering and pagination to a review query.
+func applyReviewFilter(builder squirrel.SelectBuilder, filter entity.ReviewFilter) squirrel.SelectBuilder {
+	if filter.MinRating > 0 {
+		builder = builder.Where(squirrel.GtOrEq{"rating": filter.MinRating})
+	}
+	if filter.MaxRating > 0 {
+		builder = builder.Where(squirrel.LtOrEq{"rating": filter.MaxRating})
+	}
+
+	order, ok := reviewOrder[filter.Sort]
+	if !ok {
+		order = reviewOrder[entity.ReviewSortNewest]
+	}
+	builder = builder.OrderBy(order)
+
+	if filter.Limit > 0 {
+		builder = builder.Limit(uint64(filter.Limit)).Offset(uint64(filter.Offset))
+	}
+	return builder
+}
+
+// getReviewSummary builds the rating histogram of published reviews of a product or shop.
+func getReviewSummary(
+	ctx context.Context, db *sqlx.DB, log *zap.Logger, op, table, targetColumn string, targetID int,
+) (entity.ReviewSummary, error) {
+	query, args, err := squirrel.
+		Select("rating", "COUNT(*) AS count").
+		From(table).
+		Where(squirrel.Eq{targetColumn: targetID, "status": entity.ReviewStatusPublished}).
+		GroupBy("rating").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return entity.ReviewSummary{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	var rows []struct {
+		Rating int `db:"rating"`
+		Count  int `db:"count"`
+	}
+	if err := db.SelectContext(ctx, &rows, query, args...); err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return entity.ReviewSummary{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	summary := entity.ReviewSummary{Histogram: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}}
+	sum := 0
+	for _, row := range rows {
+		summary.Histogram[row.Rating] = row.Count
+		summary.Total += row.Count
+		sum += row.Rating * row.Count
+	}
+	if summary.Total > 0 {
+		summary.Average = float64(sum) / float64(summary.Total)
+	}
+
+	return summary, nil
+}
+
+// getReplies loads shop replies to the given reviews, keyed by review ID.
+func getReplies(
+	ctx context.Context, db *sqlx.DB, log *zap.Logger, op, reviewType string, reviewIDs []int,
+) (map[int]*entity.ReviewReply, error) {
+	replies := make(map[int]*entity.ReviewReply, len(reviewIDs))
+	if len(reviewIDs) == 0 {
+		return replies, nil
+	}
+
+	query, args, err := squirrel.
+		Select(replyColumns...).
+		From("review_replies").
+		Where(squirrel.Eq{"review_type": reviewType, "review_id": reviewIDs}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return nil, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	var rows []entity.ReviewReply
+	if err := db.SelectContext(ctx, &rows, query, args...); err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return nil, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	for i := range rows {
+		replies[rows[i].ReviewID] = &rows[i]
+	}
+	return replies, nil
+}
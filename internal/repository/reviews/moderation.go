@@ -0,0 +1,500 @@
+package reviews
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// reviewTables maps review types to their tables and the column of the reviewed object.
+var reviewTables = map[string]struct{ table, targetColumn string }{
+	entity.ReviewTypeProduct: {table: "product_reviews", targetColumn: "product_id"},
+	entity.ReviewTypeSeller:  {table: "seller_reviews", targetColumn: "shop_id"},
+}
+
+// ReviewModerationRepository defines the interface for review reports and moderation data operations.
+type ReviewModerationRepository interface {
+	GetReviewTarget(ctx context.Context, reviewType string, reviewID int) (int, int, error)
+	AddReport(ctx context.Context, report entity.ReviewReport) (int, error)
+	GetModerationQueue(ctx context.Context, limit, offset int) ([]entity.ModerationQueueItem, int, error)
+	SetReviewStatus(
+		ctx context.Context, reviewType string, reviewID int, status, action, reason string, moderatorID int,
+	) error
+	BanReviewer(ctx context.Context, userID int, reason string, moderatorID int) error
+	UnbanReviewer(ctx context.Context, userID int, reason string, moderatorID int) error
+}
+
+type reviewModerationRepository struct {
+	db     *sqlx.DB
+	logger *zap.Logger
+}
+
+func NewReviewModerationRepository(db *sqlx.DB, l *zap.Logger) ReviewModerationRepository {
+	return &reviewModerationRepository{
+		db:     db,
+		logger: l,
+	}
+}
+
+// GetReviewTarget returns the author of the review and the ID of the reviewed product or shop.
+func (r *reviewModerationRepository) GetReviewTarget(
+	ctx context.Context, reviewType string, reviewID int,
+) (int, int, error) {
+	const op = "reviewModerationRepository.GetReviewTarget()"
+	target, err := getReviewTarget(ctx, r.db, r.logger.With(zap.String("op", op)), op, reviewType, reviewID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return target.AuthorID, target.TargetID, nil
+}
+
+func (r *reviewModerationRepository) AddReport(
+	ctx context.Context, report entity.ReviewReport,
+) (int, error) {
+	const op = "reviewModerationRepository.AddReport()"
+	log := r.logger.With(zap.String("op", op))
+
+	query, args, err := squirrel.Insert("review_reports").
+		Columns("review_type", "review_id", "reporter_id", "reason").
+		Values(report.ReviewType, report.ReviewID, report.ReporterID, report.Reason).
+		Suffix("RETURNING id").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return 0, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	var id int
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return 0, apperror.NewReviewError(apperror.ReviewDuplicate, "you have already reported this review")
+		}
+		log.Error("Failed to execute query", zap.Error(err))
+		return 0, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return id, nil
+}
+
+type moderationQueueRow struct {
+	ReviewType     string       `db:"review_type"`
+	ReviewID       int          `db:"review_id"`
+	TargetID       int          `db:"target_id"`
+	AuthorID       int          `db:"author_id"`
+	Rating         int          `db:"rating"`
+	Review         string       `db:"review"`
+	Status         string       `db:"status"`
+	ReportCount    int          `db:"report_count"`
+	Reasons        []byte       `db:"reasons"`
+	LastReportedAt sql.NullTime `db:"last_reported_at"`
+	TotalCount     int          `db:"total_count"`
+}
+
+// GetModerationQueue returns reviews with unresolved reports, the most reported first.
+func (r *reviewModerationRepository) GetModerationQueue(
+	ctx context.Context, limit, offset int,
+) ([]entity.ModerationQueueItem, int, error) {
+	const op = "reviewModerationRepository.GetModerationQueue()"
+	log := r.logger.With(zap.String("op", op))
+
+	query := `
+		WITH open_reports AS (
+			SELECT review_type, review_id, COUNT(*) AS report_count, MAX(created_at) AS last_reported_at,
+				JSON_AGG(reason ORDER BY created_at) AS reasons
+			FROM review_reports
+			WHERE resolved_at IS NULL
+			GROUP BY review_type, review_id
+		),
+		all_reviews AS (
+			SELECT 'product' AS review_type, id, product_id AS target_id, user_id, rating, review, status
+			FROM product_reviews
+			UNION ALL
+			SELECT 'seller' AS review_type, id, shop_id AS target_id, user_id, rating, review, status
+			FROM seller_reviews
+		)
+		SELECT q.review_type, q.review_id, rv.target_id, rv.user_id AS author_id, rv.rating, rv.review,
+			rv.status, q.report_count, q.reasons, q.last_reported_at, COUNT(*) OVER() AS total_count
+		FROM open_reports q
+		JOIN all_reviews rv ON rv.review_type = q.review_type AND rv.id = q.review_id
+		ORDER BY q.report_count DESC, q.last_reported_at
+		LIMIT $1 OFFSET $2
+	`
+
+	var rows []moderationQueueRow
+	if err := r.db.SelectContext(ctx, &rows, query, limit, offset); err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return nil, 0, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	items := make([]entity.ModerationQueueItem, 0, len(rows))
+	for _, row := range rows {
+		var reasons []string
+		if err := json.Unmarshal(row.Reasons, &reasons); err != nil {
+			log.Error("Failed to unmarshal report reasons", zap.Error(err))
+			return nil, 0, fmt.Errorf("op: %s, err: %w", op, err)
+		}
+		items = append(items, entity.ModerationQueueItem{
+			ReviewType:     row.ReviewType,
+			ReviewID:       row.ReviewID,
+			TargetID:       row.TargetID,
+			AuthorID:       row.AuthorID,
+			Rating:         row.Rating,
+			Review:         row.Review,
+			Status:         row.Status,
+			ReportCount:    row.ReportCount,
+			Reasons:        reasons,
+			LastReportedAt: row.LastReportedAt.Time,
+		})
+	}
+
+	total := 0
+	if len(rows) > 0 {
+		total = rows[0].TotalCount
+	}
+
+	return items, total, nil
+}
+
+// SetReviewStatus hides or restores the review, resolves its open reports and logs the decision.
+func (r *reviewModerationRepository) SetReviewStatus(
+	ctx context.Context, reviewType string, reviewID int, status, action, reason string, moderatorID int,
+) error {
+	const op = "reviewModerationRepository.SetReviewStatus()"
+	log := r.logger.With(zap.String("op", op))
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	moderationReason := sql.NullString{String: reason, Valid: status == entity.ReviewStatusHidden}
+	query, args, err := squirrel.Update(reviewTables[reviewType].table).
+		Set("status", status).
+		Set("moderation_reason", moderationReason).
+		Where(squirrel.Eq{"id": reviewID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		return apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+
+	if err := resolveReports(ctx, tx, squirrel.Eq{"review_type": reviewType, "review_id": reviewID},
+		moderatorID); err != nil {
+		log.Error("Failed to resolve reports", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	if err := logModerationAction(ctx, tx, squirrel.Eq{
+		"review_type":  reviewType,
+		"review_id":    reviewID,
+		"action":       action,
+		"reason":       reason,
+		"moderator_id": moderatorID,
+	}); err != nil {
+		log.Error("Failed to log moderation action", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Failed to commit transaction", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return nil
+}
+
+// BanReviewer forbids the user to leave reviews, hides all their reviews and resolves reports on them.
+func (r *reviewModerationRepository) BanReviewer(
+	ctx context.Context, userID int, reason string, moderatorID int,
+) error {
+	const op = "reviewModerationRepository.BanReviewer()"
+	log := r.logger.With(zap.String("op", op))
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.ExecContext(ctx,
+		"UPDATE users SET review_banned_at = CURRENT_TIMESTAMP, review_ban_reason = $1 WHERE id = $2",
+		reason, userID)
+	if err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		return apperror.NewReviewError(apperror.NotFound, "user not found")
+	}
+
+	for reviewType, tbl := range reviewTables {
+		hideQuery := fmt.Sprintf("UPDATE %s SET status = $1, moderation_reason = $2 WHERE user_id = $3 "+
+			"RETURNING id", tbl.table)
+		var hidden []int
+		if err := tx.SelectContext(ctx, &hidden, hideQuery, entity.ReviewStatusHidden, reason, userID); err != nil {
+			log.Error("Failed to hide reviews", zap.Error(err))
+			return fmt.Errorf("op: %s, err: %w", op, err)
+		}
+		if len(hidden) == 0 {
+			continue
+		}
+		if err := resolveReports(ctx, tx, squirrel.Eq{"review_type": reviewType, "review_id": hidden},
+			moderatorID); err != nil {
+			log.Error("Failed to resolve reports", zap.Error(err))
+			return fmt.Errorf("op: %s, err: %w", op, err)
+		}
+	}
+
+	if err := logModerationAction(ctx, tx, squirrel.Eq{
+		"user_id":      userID,
+		"action":       entity.ModerationActionBan,
+		"reason":       reason,
+		"moderator_id": moderatorID,
+	}); err != nil {
+		log.Error("Failed to log moderation action", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Failed to commit transaction", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return nil
+}
+
+// UnbanReviewer lets the user leave reviews again. Reviews hidden by the ban stay hidden
+// until a moderator restores them one by one.
+func (r *reviewModerationRepository) UnbanReviewer(
+	ctx context.Context, userID int, reason string, moderatorID int,
+) error {
+	const op = "reviewModerationRepository.UnbanReviewer()"
+	log := r.logger.With(zap.String("op", op))
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var banned bool
+	err = tx.GetContext(ctx, &banned,
+		"SELECT review_banned_at IS NOT NULL FROM users WHERE id = $1 FOR UPDATE", userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.NewReviewError(apperror.NotFound, "user not found")
+		}
+		log.Error("Failed to execute query", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	if !banned {
+		return apperror.NewReviewError(apperror.ReviewInvalid, "user is not banned from reviews")
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE users SET review_banned_at = NULL, review_ban_reason = NULL WHERE id = $1", userID); err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	if err := logModerationAction(ctx, tx, squirrel.Eq{
+		"user_id":      userID,
+		"action":       entity.ModerationActionUnban,
+		"reason":       reason,
+		"moderator_id": moderatorID,
+	}); err != nil {
+		log.Error("Failed to log moderation action", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Failed to commit transaction", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return nil
+}
+
+func resolveReports(ctx context.Context, tx *sqlx.Tx, where squirrel.Eq, moderatorID int) error {
+	query, args, err := squirrel.Update("review_reports").
+		Set("resolved_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Set("resolved_by", moderatorID).
+		Where(where).
+		Where("resolved_at IS NULL").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+func logModerationAction(ctx context.Context, tx *sqlx.Tx, values squirrel.Eq) error {
+	query, args, err := squirrel.Insert("review_moderation_actions").
+		SetMap(values).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+// getReviewTarget returns the author, the reviewed product or shop and the status of a review.
+func getReviewTarget(
+	ctx context.Context, db *sqlx.DB, log *zap.Logger, op, reviewType string, reviewID int,
+) (entity.ReviewTarget, error) {
+	tbl := reviewTables[reviewType]
+	query, args, err := squirrel.Select("user_id", tbl.targetColumn, "status").
+		From(tbl.table).
+		Where(squirrel.Eq{"id": reviewID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return entity.ReviewTarget{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	var target entity.ReviewTarget
+	err = db.QueryRowContext(ctx, query, args...).Scan(&target.AuthorID, &target.TargetID, &target.Status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ReviewTarget{}, apperror.NewReviewError(apperror.NotFound, "review not found")
+		}
+		log.Error("Failed to execute query", zap.Error(err))
+		return entity.ReviewTarget{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return target, nil
+}
+
+// updateReview changes the text and rating of a review in the given table.
+func updateReview(
+	ctx context.Context, db *sqlx.DB, log *zap.Logger, op, table string, reviewID int, rating int, review string,
+) error {
+	query, args, err := squirrel.Update(table).
+		Set("rating", rating).
+		Set("review", review).
+		Set("updated_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": reviewID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		return apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+
+	return nil
+}
+
+// reviewChildTables reference reviews by type and ID without a foreign key,
+// so their rows are deleted together with the review.
+var reviewChildTables = []string{"review_reports", "review_replies", "review_votes"}
+
+// deleteReview removes a review together with its reports, shop reply and votes.
+func deleteReview(
+	ctx context.Context, db *sqlx.DB, log *zap.Logger, op, reviewType string, reviewID int,
+) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("Failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, table := range reviewChildTables {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE review_type = $1 AND review_id = $2",
+			reviewType, reviewID); err != nil {
+			log.Error("Failed to delete review rows", zap.String("table", table), zap.Error(err))
+			return fmt.Errorf("op: %s, err: %w", op, err)
+		}
+	}
+
+	query, args, err := squirrel.Delete(reviewTables[reviewType].table).
+		Where(squirrel.Eq{"id": reviewID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		return apperror.NewReviewError(apperror.NotFound, "review not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Failed to commit transaction", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return nil
+}
+
+// isReviewerBanned reports whether a moderator has forbidden the user to leave reviews.
+func isReviewerBanned(ctx context.Context, db *sqlx.DB, log *zap.Logger, op string, userID int) (bool, error) {
+	var banned bool
+	err := db.GetContext(ctx, &banned, "SELECT review_banned_at IS NOT NULL FROM users WHERE id = $1", userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		log.Error("Failed to execute query", zap.Error(err))
+		return false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return banned, nil
+}
@@ -0,0 +1,198 @@
+package reviews_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"time"
+
+	go_sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/reviews"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("ReviewModerationRepository", func() {
+	var (
+		db         *sql.DB
+		mock       go_sqlmock.Sqlmock
+		repository reviews.ReviewModerationRepository
+		ctx        context.Context
+	)
+
+	expectReviewErrorCode := func(err error, code string) {
+		var reviewErr *apperror.ReviewError
+		ExpectWithOffset(1, errors.As(err, &reviewErr)).To(BeTrue())
+		ExpectWithOffset(1, reviewErr.Code()).To(Equal(code))
+	}
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = go_sqlmock.New()
+		Expect(err).NotTo(HaveOccurred())
+
+		repository = reviews.NewReviewModerationRepository(sqlx.NewDb(db, "sqlmock"), zap.NewNop())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("GetReviewTarget", func() {
+		It("should return the author and the shop of a seller review", func() {
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id, shop_id, status FROM seller_reviews WHERE id = $1")).
+				WithArgs(5).
+				WillReturnRows(go_sqlmock.NewRows([]string{"user_id", "shop_id", "status"}).AddRow(2, 1, "published"))
+
+			authorID, shopID, err := repository.GetReviewTarget(ctx, entity.ReviewTypeSeller, 5)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(authorID).To(Equal(2))
+			Expect(shopID).To(Equal(1))
+		})
+
+		It("should return NotFound error for non-existent review", func() {
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id, product_id, status FROM product_reviews WHERE id = $1")).
+				WithArgs(999).
+				WillReturnError(sql.ErrNoRows)
+
+			_, _, err := repository.GetReviewTarget(ctx, entity.ReviewTypeProduct, 999)
+
+			expectReviewErrorCode(err, apperror.NotFound)
+		})
+	})
+
+	Context("AddReport", func() {
+		It("should return a duplicate error for a repeated report", func() {
+			mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO review_reports")).
+				WithArgs("product", 5, 3, "spam").
+				WillReturnError(&pgconn.PgError{Code: pgerrcode.UniqueViolation})
+
+			_, err := repository.AddReport(ctx, entity.ReviewReport{
+				ReviewType: entity.ReviewTypeProduct, ReviewID: 5, ReporterID: 3, Reason: "spam",
+			})
+
+			expectReviewErrorCode(err, apperror.ReviewDuplicate)
+		})
+	})
+
+	Context("GetModerationQueue", func() {
+		It("should return reported reviews with their reasons", func() {
+			columns := []string{
+				"review_type", "review_id", "target_id", "author_id", "rating", "review", "status",
+				"report_count", "reasons", "last_reported_at", "total_count",
+			}
+			mock.ExpectQuery("FROM open_reports q").
+				WithArgs(20, 0).
+				WillReturnRows(go_sqlmock.NewRows(columns).
+					AddRow("product", 5, 1, 2, 1, "Awful", "published", 2, []byte(`["spam","insults"]`), time.Now(), 3))
+
+			items, total, err := repository.GetModerationQueue(ctx, 20, 0)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(total).To(Equal(3))
+			Expect(items).To(HaveLen(1))
+			Expect(items[0].ReportCount).To(Equal(2))
+			Expect(items[0].Reasons).To(Equal([]string{"spam", "insults"}))
+		})
+	})
+
+	Context("SetReviewStatus", func() {
+		It("should hide the review, resolve reports and log the action", func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(
+				"UPDATE product_reviews SET status = $1, moderation_reason = $2 WHERE id = $3")).
+				WithArgs("hidden", sql.NullString{String: "insults", Valid: true}, 5).
+				WillReturnResult(go_sqlmock.NewResult(0, 1))
+			mock.ExpectExec(regexp.QuoteMeta("UPDATE review_reports SET resolved_at = CURRENT_TIMESTAMP")).
+				WithArgs(9, 5, "product").
+				WillReturnResult(go_sqlmock.NewResult(0, 2))
+			mock.ExpectExec(regexp.QuoteMeta("INSERT INTO review_moderation_actions")).
+				WithArgs("hide", 9, "insults", 5, "product").
+				WillReturnResult(go_sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+
+			err := repository.SetReviewStatus(ctx, entity.ReviewTypeProduct, 5,
+				entity.ReviewStatusHidden, entity.ModerationActionHide, "insults", 9)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return NotFound error for non-existent review", func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta("UPDATE seller_reviews SET status = $1")).
+				WillReturnResult(go_sqlmock.NewResult(0, 0))
+			mock.ExpectRollback()
+
+			err := repository.SetReviewStatus(ctx, entity.ReviewTypeSeller, 999,
+				entity.ReviewStatusPublished, entity.ModerationActionRestore, "appeal", 9)
+
+			expectReviewErrorCode(err, apperror.NotFound)
+		})
+	})
+
+	Context("BanReviewer", func() {
+		It("should return NotFound error for non-existent user", func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET review_banned_at = CURRENT_TIMESTAMP")).
+				WithArgs("spam", 999).
+				WillReturnResult(go_sqlmock.NewResult(0, 0))
+			mock.ExpectRollback()
+
+			err := repository.BanReviewer(ctx, 999, "spam", 9)
+
+			expectReviewErrorCode(err, apperror.NotFound)
+		})
+	})
+
+	Context("UnbanReviewer", func() {
+		It("should lift the ban and log the decision", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(
+				"SELECT review_banned_at IS NOT NULL FROM users WHERE id = $1 FOR UPDATE")).
+				WithArgs(2).
+				WillReturnRows(go_sqlmock.NewRows([]string{"banned"}).AddRow(true))
+			mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET review_banned_at = NULL, review_ban_reason = NULL")).
+				WithArgs(2).
+				WillReturnResult(go_sqlmock.NewResult(0, 1))
+			mock.ExpectExec(regexp.QuoteMeta("INSERT INTO review_moderation_actions")).
+				WithArgs(entity.ModerationActionUnban, 9, "appeal accepted", 2).
+				WillReturnResult(go_sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+
+			Expect(repository.UnbanReviewer(ctx, 2, "appeal accepted", 9)).To(Succeed())
+		})
+
+		It("should reject a user who is not banned", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT review_banned_at IS NOT NULL FROM users")).
+				WithArgs(2).
+				WillReturnRows(go_sqlmock.NewRows([]string{"banned"}).AddRow(false))
+			mock.ExpectRollback()
+
+			err := repository.UnbanReviewer(ctx, 2, "appeal accepted", 9)
+
+			expectReviewErrorCode(err, apperror.ReviewInvalid)
+		})
+
+		It("should return NotFound error for non-existent user", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT review_banned_at IS NOT NULL FROM users")).
+				WithArgs(999).
+				WillReturnError(sql.ErrNoRows)
+			mock.ExpectRollback()
+
+			err := repository.UnbanReviewer(ctx, 999, "appeal accepted", 9)
+
+			expectReviewErrorCode(err, apperror.NotFound)
+		})
+	})
+})
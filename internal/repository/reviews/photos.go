@@ -0,0 +1,181 @@
+package reviews
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+var reviewPhotoColumns = []string{"id", "review_id", "image_key", "thumbnail_key", "content_type", "created_at"}
+
+// InsertReviewPhoto saves the photo unless the review already has maxPhotos photos.
+// The review row is locked while the photos are counted, so concurrent uploads
+// to the same review wait for each other and cannot exceed the limit.
+func (r *productReviewsRepository) InsertReviewPhoto(
+	ctx context.Context, photo entity.ReviewPhoto, maxPhotos int,
+) (entity.ReviewPhoto, error) {
+	const op = "productReviewsRepository.InsertReviewPhoto()"
+	log := r.logger.With(zap.String("op", op))
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("Failed to begin transaction", zap.Error(err))
+		return entity.ReviewPhoto{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var locked int
+	err = tx.QueryRowContext(ctx, "SELECT id FROM product_reviews WHERE id = $1 FOR UPDATE", photo.ReviewID).
+		Scan(&locked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ReviewPhoto{}, apperror.NewReviewError(apperror.NotFound, "review not found")
+		}
+		log.Error("Failed to lock review", zap.Error(err))
+		return entity.ReviewPhoto{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	var count int
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM review_photos WHERE review_id = $1", photo.ReviewID).
+		Scan(&count)
+	if err != nil {
+		log.Error("Failed to count photos", zap.Error(err))
+		return entity.ReviewPhoto{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	if count >= maxPhotos {
+		return entity.ReviewPhoto{}, apperror.NewReviewError(apperror.ReviewInvalid,
+			fmt.Sprintf("a review can have at most %d photos", maxPhotos))
+	}
+
+	query, args, err := squirrel.Insert("review_photos").
+		Columns("review_id", "image_key", "thumbnail_key", "content_type").
+		Values(photo.ReviewID, photo.Key, photo.ThumbnailKey, photo.ContentType).
+		Suffix("RETURNING id, created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return entity.ReviewPhoto{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&photo.ID, &photo.CreatedAt); err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return entity.ReviewPhoto{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Failed to commit transaction", zap.Error(err))
+		return entity.ReviewPhoto{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return photo, nil
+}
+
+func (r *productReviewsRepository) GetReviewPhotoByID(
+	ctx context.Context, photoID int,
+) (entity.ReviewPhoto, error) {
+	const op = "productReviewsRepository.GetReviewPhotoByID()"
+	log := r.logger.With(zap.String("op", op))
+
+	query, args, err := squirrel.
+		Select(reviewPhotoColumns...).
+		From("review_photos").
+		Where(squirrel.Eq{"id": photoID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return entity.ReviewPhoto{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	var photo entity.ReviewPhoto
+	if err := r.db.GetContext(ctx, &photo, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ReviewPhoto{}, apperror.NewReviewError(apperror.NotFound, "photo not found")
+		}
+		log.Error("Failed to execute query", zap.Error(err))
+		return entity.ReviewPhoto{}, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return photo, nil
+}
+
+// GetReviewPhotos returns the photos of the review in upload order.
+func (r *productReviewsRepository) GetReviewPhotos(
+	ctx context.Context, reviewID int,
+) ([]entity.ReviewPhoto, error) {
+	const op = "productReviewsRepository.GetReviewPhotos()"
+	photos, err := getPhotos(ctx, r.db, r.logger.With(zap.String("op", op)), op, []int{reviewID})
+	if err != nil {
+		return nil, err
+	}
+	return photos[reviewID], nil
+}
+
+func (r *productReviewsRepository) DeleteReviewPhoto(ctx context.Context, photoID int) error {
+	const op = "productReviewsRepository.DeleteReviewPhoto()"
+	log := r.logger.With(zap.String("op", op))
+
+	query, args, err := squirrel.
+		Delete("review_photos").
+		Where(squirrel.Eq{"id": photoID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return apperror.NewReviewError(apperror.NotFound, "photo not found")
+	}
+
+	return nil
+}
+
+// getPhotos loads the photos of the given reviews grouped by review ID.
+func getPhotos(
+	ctx context.Context, db *sqlx.DB, log *zap.Logger, op string, reviewIDs []int,
+) (map[int][]entity.ReviewPhoto, error) {
+	photos := make(map[int][]entity.ReviewPhoto, len(reviewIDs))
+	if len(reviewIDs) == 0 {
+		return photos, nil
+	}
+
+	query, args, err := squirrel.
+		Select(reviewPhotoColumns...).
+		From("review_photos").
+		Where(squirrel.Eq{"review_id": reviewIDs}).
+		OrderBy("id").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return nil, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	var rows []entity.ReviewPhoto
+	if err := db.SelectContext(ctx, &rows, query, args...); err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return nil, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	for _, row := range rows {
+		photos[row.ReviewID] = append(photos[row.ReviewID], row)
+	}
+	return photos, nil
+}
@@ -3,10 +3,12 @@ package reviews
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
 	"github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
 	"go.uber.org/zap"
@@ -14,9 +16,31 @@ import (
 
 // ProductReviewRepository defines the interface for product review data operations.
 type ProductReviewRepository interface {
-	AddReview(ctx context.Context, productID int, userID int, rating int, review string) error
+	UpsertReview(
+		ctx context.Context, productID int, userID int, rating int, review string, verified bool,
+		onCreate func(tx email.Execer, reviewID int) error,
+	) (int, bool, error)
+	HasAcceptedOffer(ctx context.Context, userID int, productID int) (bool, error)
 	GetProductByID(ctx context.Context, productID int) (entity.Product, error)
-	GetReviewsByProductID(ctx context.Context, productID int) ([]entity.ProductReview, error)
+	GetReviewsByProductID(
+		ctx context.Context, productID int, filter entity.ReviewFilter,
+	) ([]entity.ProductReview, int, error)
+	GetReviewSummary(ctx context.Context, productID int) (entity.ReviewSummary, error)
+	GetReviewByID(ctx context.Context, reviewID int) (entity.ProductReview, error)
+	GetUserReview(ctx context.Context, productID int, userID int) (entity.ProductReview, error)
+	UpdateReview(ctx context.Context, reviewID int, rating int, review string) error
+	DeleteReview(ctx context.Context, reviewID int) error
+	IsReviewerBanned(ctx context.Context, userID int) (bool, error)
+	IsShopStaffProduct(ctx context.Context, productID int, userID int) (bool, error)
+	InsertReviewPhoto(ctx context.Context, photo entity.ReviewPhoto, maxPhotos int) (entity.ReviewPhoto, error)
+	GetReviewPhotoByID(ctx context.Context, photoID int) (entity.ReviewPhoto, error)
+	GetReviewPhotos(ctx context.Context, reviewID int) ([]entity.ReviewPhoto, error)
+	DeleteReviewPhoto(ctx context.Context, photoID int) error
+}
+
+var productReviewColumns = []string{
+	"id", "product_id as productid", "user_id as userid", "rating", "review",
+	"is_verified", "status", "helpful_count", "not_helpful_count", "created_at", "updated_at",
 }
 
 type productReviewsRepository struct {
@@ -31,29 +55,111 @@ func NewProductReviewRepository(db *sqlx.DB, l *zap.Logger) ProductReviewReposit
 	}
 }
 
-func (r *productReviewsRepository) AddReview(
-	ctx context.Context, productID int, userID int, rating int, review string,
-) error {
-	const op = "productReviewsRepository.AddReview()"
+// UpsertReview creates the user's review of the product or replaces the existing one.
+// It returns the review ID and whether a new review was created. onCreate is called
+// in the same transaction only for a new review, so its failure does not save the review.
+func (r *productReviewsRepository) UpsertReview(
+	ctx context.Context, productID int, userID int, rating int, review string, verified bool,
+	onCreate func(tx email.Execer, reviewID int) error,
+) (int, bool, error) {
+	const op = "productReviewsRepository.UpsertReview()"
 	log := r.logger.With(zap.String("op", op))
 
 	query, args, err := squirrel.Insert("product_reviews").
-		Columns("product_id", "user_id", "rating", "review").
-		Values(productID, userID, rating, review).
+		Columns("product_id", "user_id", "rating", "review", "is_verified").
+		Values(productID, userID, rating, review, verified).
+		Suffix("ON CONFLICT (product_id, user_id) DO UPDATE SET " +
+			"rating = EXCLUDED.rating, review = EXCLUDED.review, is_verified = EXCLUDED.is_verified, " +
+			"updated_at = CURRENT_TIMESTAMP " +
+			"RETURNING id, (xmax = 0) AS created").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return 0, false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("Failed to begin transaction", zap.Error(err))
+		return 0, false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var (
+		id      int
+		created bool
+	)
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&id, &created)
+	if err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return 0, false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	if created && onCreate != nil {
+		if err := onCreate(tx, id); err != nil {
+			return 0, false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Failed to commit transaction", zap.Error(err))
+		return 0, false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return id, created, nil
+}
+
+// HasAcceptedOffer reports whether the user has an accepted offer for the product.
+func (r *productReviewsRepository) HasAcceptedOffer(
+	ctx context.Context, userID int, productID int,
+) (bool, error) {
+	const op = "productReviewsRepository.HasAcceptedOffer()"
+	log := r.logger.With(zap.String("op", op))
+
+	query, args, err := squirrel.Select("1").
+		Prefix("SELECT EXISTS (").
+		From("offers").
+		Where(squirrel.Eq{"user_id": userID, "product_id": productID, "status": "accepted"}).
+		Suffix(")").
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
 		log.Error("Failed to build query", zap.Error(err))
-		return fmt.Errorf("op: %s, err: %w", op, err)
+		return false, fmt.Errorf("op: %s, err: %w", op, err)
 	}
 
-	_, err = r.db.ExecContext(ctx, query, args...)
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, query, args...); err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return exists, nil
+}
+
+// IsShopStaffProduct reports whether the product is sold by a shop the user is a member of.
+func (r *productReviewsRepository) IsShopStaffProduct(
+	ctx context.Context, productID int, userID int,
+) (bool, error) {
+	const op = "productReviewsRepository.IsShopStaffProduct()"
+	log := r.logger.With(zap.String("op", op))
+
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, `
+		SELECT EXISTS (
+			SELECT 1 FROM shop_inventory si
+			JOIN shop_members sm ON sm.shop_id = si.shop_id
+			WHERE si.product_id = $1 AND sm.user_id = $2
+		)`, productID, userID)
 	if err != nil {
 		log.Error("Failed to execute query", zap.Error(err))
-		return fmt.Errorf("op: %s, err: %w", op, err)
+		return false, fmt.Errorf("op: %s, err: %w", op, err)
 	}
 
-	return nil
+	return exists, nil
 }
 
 func (r *productReviewsRepository) GetProductByID(
@@ -88,30 +194,137 @@ func (r *productReviewsRepository) GetProductByID(
 	return product, nil
 }
 
+// GetReviewsByProductID returns a page of published reviews of the product
+// and the number of reviews matching the filter.
 func (r *productReviewsRepository) GetReviewsByProductID(
-	ctx context.Context, productID int,
+	ctx context.Context, productID int, filter entity.ReviewFilter,
 ) (
-	[]entity.ProductReview, error,
+	[]entity.ProductReview, int, error,
 ) {
 	const op = "productReviewsRepository.GetReviewsByProductID()"
 	log := r.logger.With(zap.String("op", op))
 
+	builder := squirrel.
+		Select(productReviewColumns...).
+		Column(totalCountColumn).
+		From("product_reviews").
+		Where(squirrel.Eq{"product_id": productID, "status": entity.ReviewStatusPublished}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	if filter.VerifiedOnly {
+		builder = builder.Where(squirrel.Eq{"is_verified": true})
+	}
+
+	query, args, err := applyReviewFilter(builder, filter).ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return nil, 0, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	var rows []struct {
+		entity.ProductReview
+		TotalCount int `db:"total_count"`
+	}
+	err = r.db.SelectContext(ctx, &rows, query, args...)
+	if err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return nil, 0, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	reviews := make([]entity.ProductReview, 0, len(rows))
+	ids := make([]int, 0, len(rows))
+	total := 0
+	for _, row := range rows {
+		reviews = append(reviews, row.ProductReview)
+		ids = append(ids, row.ID)
+		total = row.TotalCount
+	}
+
+	replies, err := getReplies(ctx, r.db, log, op, entity.ReviewTypeProduct, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	photos, err := getPhotos(ctx, r.db, log, op, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range reviews {
+		reviews[i].Reply = replies[reviews[i].ID]
+		reviews[i].Photos = photos[reviews[i].ID]
+	}
+
+	return reviews, total, nil
+}
+
+func (r *productReviewsRepository) GetReviewSummary(
+	ctx context.Context, productID int,
+) (entity.ReviewSummary, error) {
+	const op = "productReviewsRepository.GetReviewSummary()"
+	return getReviewSummary(ctx, r.db, r.logger.With(zap.String("op", op)), op,
+		"product_reviews", "product_id", productID)
+}
+
+func (r *productReviewsRepository) GetReviewByID(
+	ctx context.Context, reviewID int,
+) (
+	entity.ProductReview, error,
+) {
+	return r.getReview(ctx, "productReviewsRepository.GetReviewByID()", squirrel.Eq{"id": reviewID})
+}
+
+func (r *productReviewsRepository) GetUserReview(
+	ctx context.Context, productID int, userID int,
+) (
+	entity.ProductReview, error,
+) {
+	return r.getReview(ctx, "productReviewsRepository.GetUserReview()",
+		squirrel.Eq{"product_id": productID, "user_id": userID})
+}
+
+func (r *productReviewsRepository) getReview(
+	ctx context.Context, op string, where squirrel.Eq,
+) (
+	entity.ProductReview, error,
+) {
+	log := r.logger.With(zap.String("op", op))
+
 	query, args, err := squirrel.
-		Select("id", "product_id as productid", "user_id as userid", "rating", "review", "created_at").
+		Select(productReviewColumns...).
 		From("product_reviews").
-		Where("product_id = $1", productID).
+		Where(where).
+		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
 		log.Error("Failed to build query", zap.Error(err))
-		return nil, fmt.Errorf("op: %s, err: %s", op, err)
+		return entity.ProductReview{}, fmt.Errorf("op: %s, err: %w", op, err)
 	}
 
-	var reviews []entity.ProductReview
-	err = r.db.SelectContext(ctx, &reviews, query, args...)
+	var review entity.ProductReview
+	err = r.db.GetContext(ctx, &review, query, args...)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ProductReview{}, apperror.NewReviewError(apperror.NotFound, "review not found")
+		}
 		log.Error("Failed to execute query", zap.Error(err))
-		return nil, fmt.Errorf("op: %s, err: %s", op, err)
+		return entity.ProductReview{}, fmt.Errorf("op: %s, err: %w", op, err)
 	}
 
-	return reviews, nil
+	return review, nil
+}
+
+func (r *productReviewsRepository) UpdateReview(
+	ctx context.Context, reviewID int, rating int, review string,
+) error {
+	const op = "productReviewsRepository.UpdateReview()"
+	return updateReview(ctx, r.db, r.logger.With(zap.String("op", op)), op, "product_reviews", reviewID, rating, review)
+}
+
+func (r *productReviewsRepository) DeleteReview(ctx context.Context, reviewID int) error {
+	const op = "productReviewsRepository.DeleteReview()"
+	return deleteReview(ctx, r.db, r.logger.With(zap.String("op", op)), op, entity.ReviewTypeProduct, reviewID)
+}
+
+func (r *productReviewsRepository) IsReviewerBanned(ctx context.Context, userID int) (bool, error) {
+	const op = "productReviewsRepository.IsReviewerBanned()"
+	return isReviewerBanned(ctx, r.db, r.logger.With(zap.String("op", op)), op, userID)
 }
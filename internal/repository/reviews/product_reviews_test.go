@@ -2,193 +2,318 @@ package reviews_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"regexp"
 	"testing"
+	"time"
 
+	go_sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
-	servicereview "github.com/EM-Stawberry/Stawberry/internal/domain/service/reviews"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/reviews"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+	"github.com/jmoiron/sqlx"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
 )
 
-type mockProductReviewRepository struct {
-	products map[int]entity.Product
-	reviews  map[int][]entity.ProductReview
-}
-
-func newMockProductReviewRepository() *mockProductReviewRepository {
-	return &mockProductReviewRepository{
-		products: make(map[int]entity.Product),
-		reviews:  make(map[int][]entity.ProductReview),
-	}
-}
-
-func (m *mockProductReviewRepository) AddReview(
-	_ context.Context, productID int, userID int, rating int, review string,
-) error {
-	if _, exists := m.products[productID]; !exists {
-		return apperror.NewReviewError(apperror.NotFound, "product not found")
-	}
-
-	reviewEntity := entity.ProductReview{
-		ProductID: productID,
-		UserID:    userID,
-		Rating:    rating,
-		Review:    review,
-	}
-	m.reviews[productID] = append(m.reviews[productID], reviewEntity)
-	return nil
-}
-
-func (m *mockProductReviewRepository) GetProductByID(
-	_ context.Context, productID int,
-) (entity.Product, error) {
-	product, exists := m.products[productID]
-	if !exists {
-		return entity.Product{}, apperror.NewReviewError(apperror.NotFound, "product not found")
-	}
-	return product, nil
-}
-
-func (m *mockProductReviewRepository) GetReviewsByProductID(
-	_ context.Context, productID int,
-) ([]entity.ProductReview, error) {
-	return m.reviews[productID], nil
-}
-
 var _ = Describe("ProductReviewsRepository", func() {
 	var (
-		repository servicereview.ProductReviewRepository
+		db         *sql.DB
+		mock       go_sqlmock.Sqlmock
+		repository reviews.ProductReviewRepository
 		ctx        context.Context
 	)
 
 	BeforeEach(func() {
+		var err error
+		db, mock, err = go_sqlmock.New()
+		Expect(err).NotTo(HaveOccurred())
+
+		repository = reviews.NewProductReviewRepository(sqlx.NewDb(db, "sqlmock"), zap.NewNop())
 		ctx = context.Background()
-		repository = newMockProductReviewRepository()
 	})
 
-	Context("AddReview", func() {
-		It("should add a new review successfully", func() {
-			// Arrange
-			productID := 1
-			userID := 2
-			rating := 5
-			review := "Great product!"
-
-			// Добавляем тестовый продукт
-			mockRepo := repository.(*mockProductReviewRepository)
-			mockRepo.products[productID] = entity.Product{
-				ID:          productID,
-				Name:        "Test Product",
-				Description: "Test Description",
-				CategoryID:  1,
-			}
-
-			// Act
-			err := repository.AddReview(ctx, productID, userID, rating, review)
-
-			// Assert
-			Expect(err).NotTo(HaveOccurred())
-			reviews, err := repository.GetReviewsByProductID(ctx, productID)
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("UpsertReview", func() {
+		upsertQuery := regexp.QuoteMeta("INSERT INTO product_reviews " +
+			"(product_id,user_id,rating,review,is_verified) VALUES ($1,$2,$3,$4,$5) " +
+			"ON CONFLICT (product_id, user_id) DO UPDATE SET")
+
+		It("should report whether the review was created", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(upsertQuery).
+				WithArgs(1, 2, 4, "Good product", true).
+				WillReturnRows(go_sqlmock.NewRows([]string{"id", "created"}).AddRow(7, false))
+			mock.ExpectCommit()
+
+			called := false
+			id, created, err := repository.UpsertReview(ctx, 1, 2, 4, "Good product", true,
+				func(email.Execer, int) error {
+					called = true
+					return nil
+				})
+
 			Expect(err).NotTo(HaveOccurred())
-			Expect(reviews).To(HaveLen(1))
-			Expect(reviews[0].ProductID).To(Equal(productID))
-			Expect(reviews[0].UserID).To(Equal(userID))
-			Expect(reviews[0].Rating).To(Equal(rating))
-			Expect(reviews[0].Review).To(Equal(review))
+			Expect(id).To(Equal(7))
+			Expect(created).To(BeFalse())
+			// a replaced review is not a new one
+			Expect(called).To(BeFalse())
 		})
 
-		It("should return error for non-existent product", func() {
-			// Act
-			err := repository.AddReview(ctx, 999, 1, 5, "Review")
+		It("should roll back a new review when the hook fails", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(upsertQuery).
+				WithArgs(1, 2, 4, "Good product", true).
+				WillReturnRows(go_sqlmock.NewRows([]string{"id", "created"}).AddRow(7, true))
+			mock.ExpectRollback()
 
-			// Assert
-			Expect(err).To(HaveOccurred())
-			var reviewErr *apperror.ReviewError
-			Expect(errors.As(err, &reviewErr)).To(BeTrue())
-			Expect(reviewErr.Code).To(Equal(apperror.NotFound))
+			hookErr := errors.New("outbox error")
+			_, _, err := repository.UpsertReview(ctx, 1, 2, 4, "Good product", true,
+				func(_ email.Execer, reviewID int) error {
+					Expect(reviewID).To(Equal(7))
+					return hookErr
+				})
+
+			Expect(err).To(MatchError(hookErr))
 		})
 	})
 
-	Context("GetProductByID", func() {
-		It("should return product if exists", func() {
-			// Arrange
-			productID := 1
-			mockRepo := repository.(*mockProductReviewRepository)
-			mockRepo.products[productID] = entity.Product{
-				ID:          productID,
-				Name:        "Test Product",
-				Description: "Test Description",
-				CategoryID:  1,
-			}
-
-			// Act
-			product, err := repository.GetProductByID(ctx, productID)
-
-			// Assert
+	Context("HasAcceptedOffer", func() {
+		It("should check accepted offers of the user for the product", func() {
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS ( SELECT 1 FROM offers WHERE")).
+				WithArgs(1, "accepted", 2).
+				WillReturnRows(go_sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+			verified, err := repository.HasAcceptedOffer(ctx, 2, 1)
+
 			Expect(err).NotTo(HaveOccurred())
-			Expect(product.ID).To(Equal(productID))
-			Expect(product.Name).To(Equal("Test Product"))
-			Expect(product.Description).To(Equal("Test Description"))
-			Expect(product.CategoryID).To(Equal(1))
+			Expect(verified).To(BeTrue())
 		})
+	})
+
+	Context("IsShopStaffProduct", func() {
+		It("should check shops of the user selling the product", func() {
+			mock.ExpectQuery(regexp.QuoteMeta("JOIN shop_members sm ON sm.shop_id = si.shop_id")).
+				WithArgs(1, 2).
+				WillReturnRows(go_sqlmock.NewRows([]string{"exists"}).AddRow(true))
 
+			own, err := repository.IsShopStaffProduct(ctx, 1, 2)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(own).To(BeTrue())
+		})
+	})
+
+	Context("GetProductByID", func() {
 		It("should return NotFound error for non-existent product", func() {
-			// Act
+			mock.ExpectQuery("SELECT id, name, description, category_id as categoryid FROM products").
+				WithArgs(999).
+				WillReturnError(sql.ErrNoRows)
+
 			_, err := repository.GetProductByID(ctx, 999)
 
-			// Assert
-			Expect(err).To(HaveOccurred())
 			var reviewErr *apperror.ReviewError
 			Expect(errors.As(err, &reviewErr)).To(BeTrue())
-			Expect(reviewErr.Code).To(Equal(apperror.NotFound))
+			Expect(reviewErr.Code()).To(Equal(apperror.NotFound))
 		})
 	})
 
 	Context("GetReviewsByProductID", func() {
-		It("should return reviews for existing product", func() {
-			// Arrange
-			productID := 1
-			userID := 2
-			rating := 5
-			review := "Great product!"
-
-			mockRepo := repository.(*mockProductReviewRepository)
-			mockRepo.products[productID] = entity.Product{
-				ID:          productID,
-				Name:        "Test Product",
-				Description: "Test Description",
-				CategoryID:  1,
-			}
-			mockRepo.reviews[productID] = []entity.ProductReview{
-				{
-					ProductID: productID,
-					UserID:    userID,
-					Rating:    rating,
-					Review:    review,
-				},
-			}
-
-			// Act
-			reviews, err := repository.GetReviewsByProductID(ctx, productID)
-
-			// Assert
+		photoColumns := []string{"id", "review_id", "image_key", "thumbnail_key", "content_type", "created_at"}
+		columns := []string{
+			"id", "productid", "userid", "rating", "review", "is_verified", "status", "helpful_count",
+			"not_helpful_count", "created_at", "updated_at", "total_count",
+		}
+
+		It("should return a page of published reviews with the total count", func() {
+			mock.ExpectQuery(regexp.QuoteMeta("COUNT(*) OVER() AS total_count FROM product_reviews "+
+				"WHERE product_id = $1 AND status = $2 ORDER BY created_at DESC, id DESC LIMIT 20 OFFSET 0")).
+				WithArgs(1, "published").
+				WillReturnRows(go_sqlmock.NewRows(columns).
+					AddRow(1, 1, 2, 5, "Great product!", true, "published", 3, 1, time.Now(), nil, 41))
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT id, review_type, review_id, shop_id, author_id, body, "+
+				"created_at, updated_at FROM review_replies WHERE review_id IN ($1) AND review_type = $2")).
+				WithArgs(1, "product").
+				WillReturnRows(go_sqlmock.NewRows([]string{
+					"id", "review_type", "review_id", "shop_id", "author_id", "body", "created_at", "updated_at",
+				}).AddRow(4, "product", 1, 7, 9, "Thank you!", time.Now(), nil))
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT id, review_id, image_key, thumbnail_key, content_type, " +
+				"created_at FROM review_photos WHERE review_id IN ($1) ORDER BY id")).
+				WithArgs(1).
+				WillReturnRows(go_sqlmock.NewRows(photoColumns).
+					AddRow(5, 1, "reviews/1/a.jpg", "reviews/1/a_thumb.jpg", "image/jpeg", time.Now()))
+
+			result, total, err := repository.GetReviewsByProductID(ctx, 1, entity.ReviewFilter{Limit: 20})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(total).To(Equal(41))
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].ProductID).To(Equal(1))
+			Expect(result[0].UserID).To(Equal(2))
+			Expect(result[0].Verified).To(BeTrue())
+			Expect(result[0].HelpfulCount).To(Equal(3))
+			Expect(result[0].NotHelpfulCount).To(Equal(1))
+			Expect(result[0].Reply).NotTo(BeNil())
+			Expect(result[0].Reply.ShopID).To(Equal(7))
+			Expect(result[0].Photos).To(HaveLen(1))
+			Expect(result[0].Photos[0].Key).To(Equal("reviews/1/a.jpg"))
+			Expect(result[0].UpdatedAt).To(BeNil())
+		})
+
+		It("should filter verified reviews and rating bounds", func() {
+			mock.ExpectQuery(regexp.QuoteMeta("WHERE product_id = $1 AND status = $2 AND is_verified = $3 "+
+				"AND rating >= $4 AND rating <= $5 ORDER BY rating ASC, created_at DESC, id DESC LIMIT 10 OFFSET 10")).
+				WithArgs(1, "published", true, 2, 3).
+				WillReturnRows(go_sqlmock.NewRows(columns))
+
+			result, total, err := repository.GetReviewsByProductID(ctx, 1, entity.ReviewFilter{
+				VerifiedOnly: true, MinRating: 2, MaxRating: 3, Sort: entity.ReviewSortLowest, Limit: 10, Offset: 10,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeEmpty())
+			Expect(total).To(BeZero())
+		})
+	})
+
+	Context("GetReviewSummary", func() {
+		It("should build the histogram and the average", func() {
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT rating, COUNT(*) AS count FROM product_reviews "+
+				"WHERE product_id = $1 AND status = $2 GROUP BY rating")).
+				WithArgs(1, "published").
+				WillReturnRows(go_sqlmock.NewRows([]string{"rating", "count"}).AddRow(5, 3).AddRow(2, 1))
+
+			summary, err := repository.GetReviewSummary(ctx, 1)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summary.Total).To(Equal(4))
+			Expect(summary.Average).To(Equal(4.25))
+			Expect(summary.Histogram).To(Equal(map[int]int{1: 0, 2: 1, 3: 0, 4: 0, 5: 3}))
+		})
+	})
+
+	Context("UpdateReview", func() {
+		It("should return NotFound error for non-existent review", func() {
+			mock.ExpectExec(regexp.QuoteMeta("UPDATE product_reviews SET rating = $1, review = $2, "+
+				"updated_at = CURRENT_TIMESTAMP WHERE id = $3")).
+				WithArgs(3, "Fine", 999).
+				WillReturnResult(go_sqlmock.NewResult(0, 0))
+
+			err := repository.UpdateReview(ctx, 999, 3, "Fine")
+
+			var reviewErr *apperror.ReviewError
+			Expect(errors.As(err, &reviewErr)).To(BeTrue())
+			Expect(reviewErr.Code()).To(Equal(apperror.NotFound))
+		})
+	})
+
+	Context("DeleteReview", func() {
+		It("should delete the review with its reports, reply and votes", func() {
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta("DELETE FROM review_reports WHERE review_type = $1 AND review_id = $2")).
+				WithArgs("product", 1).
+				WillReturnResult(go_sqlmock.NewResult(0, 2))
+			mock.ExpectExec(regexp.QuoteMeta("DELETE FROM review_replies WHERE review_type = $1 AND review_id = $2")).
+				WithArgs("product", 1).
+				WillReturnResult(go_sqlmock.NewResult(0, 1))
+			mock.ExpectExec(regexp.QuoteMeta("DELETE FROM review_votes WHERE review_type = $1 AND review_id = $2")).
+				WithArgs("product", 1).
+				WillReturnResult(go_sqlmock.NewResult(0, 3))
+			mock.ExpectExec(regexp.QuoteMeta("DELETE FROM product_reviews WHERE id = $1")).
+				WithArgs(1).
+				WillReturnResult(go_sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			Expect(repository.DeleteReview(ctx, 1)).To(Succeed())
+		})
+	})
+
+	Context("InsertReviewPhoto", func() {
+		photo := entity.ReviewPhoto{
+			ReviewID: 1, Key: "reviews/1/a.png", ThumbnailKey: "reviews/1/a_thumb.png", ContentType: "image/png",
+		}
+
+		lockQuery := regexp.QuoteMeta("SELECT id FROM product_reviews WHERE id = $1 FOR UPDATE")
+		countQuery := regexp.QuoteMeta("SELECT COUNT(*) FROM review_photos WHERE review_id = $1")
+
+		It("should save the photo while the locked review is under the limit", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(lockQuery).WithArgs(1).
+				WillReturnRows(go_sqlmock.NewRows([]string{"id"}).AddRow(1))
+			mock.ExpectQuery(countQuery).WithArgs(1).
+				WillReturnRows(go_sqlmock.NewRows([]string{"count"}).AddRow(4))
+			mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO review_photos "+
+				"(review_id,image_key,thumbnail_key,content_type) VALUES ($1,$2,$3,$4) RETURNING id, created_at")).
+				WithArgs(1, "reviews/1/a.png", "reviews/1/a_thumb.png", "image/png").
+				WillReturnRows(go_sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, time.Now()))
+			mock.ExpectCommit()
+
+			saved, err := repository.InsertReviewPhoto(ctx, photo, 5)
+
 			Expect(err).NotTo(HaveOccurred())
-			Expect(reviews).To(HaveLen(1))
-			Expect(reviews[0].ProductID).To(Equal(productID))
-			Expect(reviews[0].UserID).To(Equal(userID))
-			Expect(reviews[0].Rating).To(Equal(rating))
-			Expect(reviews[0].Review).To(Equal(review))
+			Expect(saved.ID).To(Equal(3))
+			Expect(saved.Key).To(Equal("reviews/1/a.png"))
 		})
 
-		It("should return empty slice for non-existent product", func() {
-			// Act
-			reviews, err := repository.GetReviewsByProductID(ctx, 999)
+		It("should reject the photo over the limit", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(lockQuery).WithArgs(1).
+				WillReturnRows(go_sqlmock.NewRows([]string{"id"}).AddRow(1))
+			mock.ExpectQuery(countQuery).WithArgs(1).
+				WillReturnRows(go_sqlmock.NewRows([]string{"count"}).AddRow(5))
+			mock.ExpectRollback()
+
+			_, err := repository.InsertReviewPhoto(ctx, photo, 5)
+
+			var reviewErr *apperror.ReviewError
+			Expect(errors.As(err, &reviewErr)).To(BeTrue())
+			Expect(reviewErr.Code()).To(Equal(apperror.ReviewInvalid))
+		})
+
+		It("should return NotFound when the review is gone", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(lockQuery).WithArgs(1).
+				WillReturnRows(go_sqlmock.NewRows([]string{"id"}))
+			mock.ExpectRollback()
+
+			_, err := repository.InsertReviewPhoto(ctx, photo, 5)
+
+			var reviewErr *apperror.ReviewError
+			Expect(errors.As(err, &reviewErr)).To(BeTrue())
+			Expect(reviewErr.Code()).To(Equal(apperror.NotFound))
+		})
+	})
+
+	Context("DeleteReviewPhoto", func() {
+		It("should return NotFound for a missing photo", func() {
+			mock.ExpectExec(regexp.QuoteMeta("DELETE FROM review_photos WHERE id = $1")).
+				WithArgs(9).
+				WillReturnResult(go_sqlmock.NewResult(0, 0))
+
+			err := repository.DeleteReviewPhoto(ctx, 9)
+
+			var reviewErr *apperror.ReviewError
+			Expect(errors.As(err, &reviewErr)).To(BeTrue())
+			Expect(reviewErr.Code()).To(Equal(apperror.NotFound))
+		})
+	})
+
+	Context("IsReviewerBanned", func() {
+		It("should report a banned reviewer", func() {
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT review_banned_at IS NOT NULL FROM users WHERE id = $1")).
+				WithArgs(2).
+				WillReturnRows(go_sqlmock.NewRows([]string{"banned"}).AddRow(true))
+
+			banned, err := repository.IsReviewerBanned(ctx, 2)
 
-			// Assert
 			Expect(err).NotTo(HaveOccurred())
-			Expect(reviews).To(BeEmpty())
+			Expect(banned).To(BeTrue())
 		})
 	})
 })
@@ -3,20 +3,40 @@ package reviews
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
 	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 	"go.uber.org/zap"
 )
 
 // SellerReviewsRepository defines the interface for seller review data operations.
+// Seller reviews are stored per shop and each one is tied to an accepted offer.
 type SellerReviewsRepository interface {
-	AddReview(ctx context.Context, sellerID int, userID int, rating int, review string) (int, error)
-	GetReviewsBySellerID(ctx context.Context, sellerID int) ([]entity.SellerReview, error)
-	GetSellerByID(ctx context.Context, sellerID int) (entity.SellerReview, error)
+	AddReview(
+		ctx context.Context, shopID int, userID int, offerID int, rating int, review string,
+		onInsert func(tx email.Execer, reviewID int) error,
+	) (int, error)
+	GetReviewsByShopID(ctx context.Context, shopID int, filter entity.ReviewFilter) ([]entity.SellerReview, int, error)
+	GetReviewSummary(ctx context.Context, shopID int) (entity.ReviewSummary, error)
+	ShopExists(ctx context.Context, shopID int) (bool, error)
+	GetOfferByID(ctx context.Context, offerID int) (entity.Offer, error)
+	GetReviewByID(ctx context.Context, reviewID int) (entity.SellerReview, error)
+	UpdateReview(ctx context.Context, reviewID int, rating int, review string) error
+	DeleteReview(ctx context.Context, reviewID int) error
+	IsReviewerBanned(ctx context.Context, userID int) (bool, error)
+	IsShopMember(ctx context.Context, shopID int, userID int) (bool, error)
+}
+
+var sellerReviewColumns = []string{
+	"id", "shop_id", "offer_id", "user_id", "rating", "review", "status",
+	"helpful_count", "not_helpful_count", "created_at", "updated_at",
 }
 
 type sellerReviewsRepository struct {
@@ -31,16 +51,19 @@ func NewSellerReviewRepository(db *sqlx.DB, l *zap.Logger) SellerReviewsReposito
 	}
 }
 
+// AddReview saves a review of the deal. onInsert is called in the same transaction,
+// so its failure does not save the review.
 func (r *sellerReviewsRepository) AddReview(
-	ctx context.Context, sellerID int, userID int, rating int, review string,
+	ctx context.Context, shopID int, userID int, offerID int, rating int, review string,
+	onInsert func(tx email.Execer, reviewID int) error,
 ) (int, error) {
 	const op = "sellerReviewsRepository.AddReview()"
 	log := r.logger.With(zap.String("op", op))
 
 	var id int
 	query, args, err := squirrel.Insert("seller_reviews").
-		Columns("seller_id", "user_id", "rating", "review").
-		Values(sellerID, userID, rating, review).
+		Columns("shop_id", "user_id", "offer_id", "rating", "review").
+		Values(shopID, userID, offerID, rating, review).
 		PlaceholderFormat(squirrel.Dollar).
 		Suffix("RETURNING id").
 		ToSql()
@@ -49,55 +72,176 @@ func (r *sellerReviewsRepository) AddReview(
 		return 0, fmt.Errorf("op: %s, err: %w", op, err)
 	}
 
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&id)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("Failed to begin transaction", zap.Error(err))
+		return 0, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&id)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return 0, apperror.NewReviewError(apperror.ReviewDuplicate, "this deal has already been reviewed")
+		}
 		log.Error("Failed to execute query", zap.Error(err))
 		return 0, fmt.Errorf("op: %s, err: %w", op, err)
 	}
 
+	if onInsert != nil {
+		if err := onInsert(tx, id); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error("Failed to commit transaction", zap.Error(err))
+		return 0, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
 	return id, nil
 }
 
-func (r *sellerReviewsRepository) GetReviewsBySellerID(
-	ctx context.Context, sellerID int,
+// GetReviewsByShopID returns a page of published reviews of the shop
+// and the number of reviews matching the filter.
+func (r *sellerReviewsRepository) GetReviewsByShopID(
+	ctx context.Context, shopID int, filter entity.ReviewFilter,
 ) (
-	[]entity.SellerReview, error,
+	[]entity.SellerReview, int, error,
 ) {
-	const op = "sellerReviewsRepository.GetReviewsBySellerID()"
+	const op = "sellerReviewsRepository.GetReviewsByShopID()"
 	log := r.logger.With(zap.String("op", op))
 
-	query, args, err := squirrel.
-		Select("id", "seller_id", "user_id", "rating", "review", "created_at").
+	builder := squirrel.
+		Select(sellerReviewColumns...).
+		Column(totalCountColumn).
 		From("seller_reviews").
-		Where("seller_id = $1", sellerID).ToSql()
+		Where(squirrel.Eq{"shop_id": shopID, "status": entity.ReviewStatusPublished}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	query, args, err := applyReviewFilter(builder, filter).ToSql()
+	if err != nil {
+		log.Error("Failed to build query", zap.Error(err))
+		return nil, 0, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	var rows []struct {
+		entity.SellerReview
+		TotalCount int `db:"total_count"`
+	}
+	err = r.db.SelectContext(ctx, &rows, query, args...)
+	if err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return nil, 0, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	reviews := make([]entity.SellerReview, 0, len(rows))
+	ids := make([]int, 0, len(rows))
+	total := 0
+	for _, row := range rows {
+		reviews = append(reviews, row.SellerReview)
+		ids = append(ids, row.ID)
+		total = row.TotalCount
+	}
+
+	replies, err := getReplies(ctx, r.db, log, op, entity.ReviewTypeSeller, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range reviews {
+		reviews[i].Reply = replies[reviews[i].ID]
+	}
+
+	return reviews, total, nil
+}
+
+func (r *sellerReviewsRepository) GetReviewSummary(
+	ctx context.Context, shopID int,
+) (entity.ReviewSummary, error) {
+	const op = "sellerReviewsRepository.GetReviewSummary()"
+	return getReviewSummary(ctx, r.db, r.logger.With(zap.String("op", op)), op, "seller_reviews", "shop_id", shopID)
+}
+
+func (r *sellerReviewsRepository) ShopExists(
+	ctx context.Context, shopID int,
+) (bool, error) {
+	const op = "sellerReviewsRepository.ShopExists()"
+	log := r.logger.With(zap.String("op", op))
+
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, "SELECT EXISTS (SELECT 1 FROM shops WHERE id = $1)", shopID)
+	if err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return exists, nil
+}
+
+func (r *sellerReviewsRepository) IsShopMember(
+	ctx context.Context, shopID int, userID int,
+) (bool, error) {
+	const op = "sellerReviewsRepository.IsShopMember()"
+	log := r.logger.With(zap.String("op", op))
+
+	var exists bool
+	err := r.db.GetContext(ctx, &exists,
+		"SELECT EXISTS (SELECT 1 FROM shop_members WHERE shop_id = $1 AND user_id = $2)", shopID, userID)
+	if err != nil {
+		log.Error("Failed to execute query", zap.Error(err))
+		return false, fmt.Errorf("op: %s, err: %w", op, err)
+	}
+
+	return exists, nil
+}
+
+func (r *sellerReviewsRepository) GetOfferByID(
+	ctx context.Context, offerID int,
+) (
+	entity.Offer, error,
+) {
+	const op = "sellerReviewsRepository.GetOfferByID()"
+	log := r.logger.With(zap.String("op", op))
+
+	query, args, err := squirrel.
+		Select("id", "shop_id", "user_id", "status").
+		From("offers").
+		Where("id = $1", offerID).
+		ToSql()
 	if err != nil {
 		log.Error("Failed to build query", zap.Error(err))
-		return nil, fmt.Errorf("op: %s, err: %s", op, err)
+		return entity.Offer{}, fmt.Errorf("op: %s, err: %w", op, err)
 	}
 
-	var reviews []entity.SellerReview
-	err = r.db.SelectContext(ctx, &reviews, query, args...)
+	var offer entity.Offer
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&offer.ID, &offer.ShopID, &offer.UserID, &offer.Status)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Offer{}, apperror.NewReviewError(apperror.NotFound, "offer not found")
+		}
 		log.Error("Failed to execute query", zap.Error(err))
-		return nil, fmt.Errorf("op: %s, err: %s", op, err)
+		return entity.Offer{}, fmt.Errorf("op: %s, err: %w", op, err)
 	}
 
-	return reviews, nil
+	return offer, nil
 }
 
-func (r *sellerReviewsRepository) GetSellerByID(
-	ctx context.Context, sellerID int,
+func (r *sellerReviewsRepository) GetReviewByID(
+	ctx context.Context, reviewID int,
 ) (
 	entity.SellerReview, error,
 ) {
-	const op = "sellerReviewsRepository.GetSellerByID()"
+	const op = "sellerReviewsRepository.GetReviewByID()"
 	log := r.logger.With(zap.String("op", op))
 
 	query, args, err := squirrel.
-		Select("id", "seller_id", "user_id", "rating", "review", "created_at").
+		Select(sellerReviewColumns...).
 		From("seller_reviews").
-		Where("seller_id = $1", sellerID).
-		Limit(1).
+		Where(squirrel.Eq{"id": reviewID}).
+		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
 		log.Error("Failed to build query", zap.Error(err))
@@ -107,8 +251,8 @@ func (r *sellerReviewsRepository) GetSellerByID(
 	var review entity.SellerReview
 	err = r.db.GetContext(ctx, &review, query, args...)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return entity.SellerReview{}, apperror.NewReviewError(apperror.NotFound, "seller not found")
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.SellerReview{}, apperror.NewReviewError(apperror.NotFound, "review not found")
 		}
 		log.Error("Failed to execute query", zap.Error(err))
 		return entity.SellerReview{}, fmt.Errorf("op: %s, err: %w", op, err)
@@ -116,3 +260,20 @@ func (r *sellerReviewsRepository) GetSellerByID(
 
 	return review, nil
 }
+
+func (r *sellerReviewsRepository) UpdateReview(
+	ctx context.Context, reviewID int, rating int, review string,
+) error {
+	const op = "sellerReviewsRepository.UpdateReview()"
+	return updateReview(ctx, r.db, r.logger.With(zap.String("op", op)), op, "seller_reviews", reviewID, rating, review)
+}
+
+func (r *sellerReviewsRepository) DeleteReview(ctx context.Context, reviewID int) error {
+	const op = "sellerReviewsRepository.DeleteReview()"
+	return deleteReview(ctx, r.db, r.logger.With(zap.String("op", op)), op, entity.ReviewTypeSeller, reviewID)
+}
+
+func (r *sellerReviewsRepository) IsReviewerBanned(ctx context.Context, userID int) (bool, error) {
+	const op = "sellerReviewsRepository.IsReviewerBanned()"
+	return isReviewerBanned(ctx, r.db, r.logger.With(zap.String("op", op)), op, userID)
+}
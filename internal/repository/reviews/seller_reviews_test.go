@@ -2,152 +2,169 @@ package reviews_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"regexp"
+	"time"
 
+	go_sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
-	servicereview "github.com/EM-Stawberry/Stawberry/internal/domain/service/reviews"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/reviews"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
 )
 
-type mockSellerReviewRepository struct {
-	reviews map[int][]entity.SellerReview
-	nextID  int
-}
-
-func newMockSellerReviewRepository() *mockSellerReviewRepository {
-	return &mockSellerReviewRepository{
-		reviews: make(map[int][]entity.SellerReview),
-		nextID:  1,
-	}
-}
-
-func (m *mockSellerReviewRepository) AddReview(
-	_ context.Context, sellerID int, userID int, rating int, review string,
-) (int, error) {
-	reviewEntity := entity.SellerReview{
-		ID:       m.nextID,
-		SellerID: sellerID,
-		UserID:   userID,
-		Rating:   rating,
-		Review:   review,
-	}
-	m.reviews[sellerID] = append(m.reviews[sellerID], reviewEntity)
-	m.nextID++
-	return reviewEntity.ID, nil
-}
-
-func (m *mockSellerReviewRepository) GetReviewsBySellerID(
-	_ context.Context, sellerID int,
-) ([]entity.SellerReview, error) {
-	return m.reviews[sellerID], nil
-}
-
-func (m *mockSellerReviewRepository) GetSellerByID(
-	_ context.Context, sellerID int,
-) (entity.SellerReview, error) {
-	reviews, exists := m.reviews[sellerID]
-	if !exists || len(reviews) == 0 {
-		return entity.SellerReview{}, apperror.NewReviewError(apperror.NotFound, "seller not found")
-	}
-	return reviews[0], nil
-}
-
 var _ = Describe("SellerReviewsRepository", func() {
 	var (
-		repository servicereview.SellerReviewRepository
+		db         *sql.DB
+		mock       go_sqlmock.Sqlmock
+		repository reviews.SellerReviewsRepository
 		ctx        context.Context
 	)
 
 	BeforeEach(func() {
+		var err error
+		db, mock, err = go_sqlmock.New()
+		Expect(err).NotTo(HaveOccurred())
+
+		repository = reviews.NewSellerReviewRepository(sqlx.NewDb(db, "sqlmock"), zap.NewNop())
 		ctx = context.Background()
-		repository = newMockSellerReviewRepository()
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
 	})
 
 	Context("AddReview", func() {
-		It("should add a new review successfully", func() {
-			// Arrange
-			sellerID := 1
-			userID := 2
-			rating := 5
-			review := "Great seller!"
+		insertQuery := regexp.QuoteMeta(
+			"INSERT INTO seller_reviews (shop_id,user_id,offer_id,rating,review) VALUES ($1,$2,$3,$4,$5) RETURNING id")
 
-			// Act
-			id, err := repository.AddReview(ctx, sellerID, userID, rating, review)
+		It("should add a new review successfully", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(insertQuery).
+				WithArgs(1, 2, 3, 5, "Great seller!").
+				WillReturnRows(go_sqlmock.NewRows([]string{"id"}).AddRow(10))
+			mock.ExpectCommit()
+
+			var hookID int
+			id, err := repository.AddReview(ctx, 1, 2, 3, 5, "Great seller!",
+				func(_ email.Execer, reviewID int) error {
+					hookID = reviewID
+					return nil
+				})
 
-			// Assert
 			Expect(err).NotTo(HaveOccurred())
-			Expect(id).To(BeNumerically(">", 0))
+			Expect(id).To(Equal(10))
+			Expect(hookID).To(Equal(10))
 		})
-	})
 
-	Context("GetReviewsBySellerID", func() {
-		It("should return reviews for existing seller", func() {
-			// Arrange
-			sellerID := 1
-			userID := 2
-			rating := 5
-			review := "Great seller!"
+		It("should roll back the review when the hook fails", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(insertQuery).
+				WithArgs(1, 2, 3, 5, "Great seller!").
+				WillReturnRows(go_sqlmock.NewRows([]string{"id"}).AddRow(10))
+			mock.ExpectRollback()
 
-			// Добавляем тестовый отзыв
-			_, err := repository.AddReview(ctx, sellerID, userID, rating, review)
-			Expect(err).NotTo(HaveOccurred())
+			hookErr := errors.New("outbox error")
+			_, err := repository.AddReview(ctx, 1, 2, 3, 5, "Great seller!",
+				func(email.Execer, int) error { return hookErr })
 
-			// Act
-			reviews, err := repository.GetReviewsBySellerID(ctx, sellerID)
+			Expect(err).To(MatchError(hookErr))
+		})
 
-			// Assert
-			Expect(err).NotTo(HaveOccurred())
-			Expect(reviews).To(HaveLen(1))
-			Expect(reviews[0].SellerID).To(Equal(sellerID))
-			Expect(reviews[0].UserID).To(Equal(userID))
-			Expect(reviews[0].Rating).To(Equal(rating))
-			Expect(reviews[0].Review).To(Equal(review))
+		It("should return a duplicate error when the deal is already reviewed", func() {
+			mock.ExpectBegin()
+			mock.ExpectQuery(insertQuery).
+				WithArgs(1, 2, 3, 5, "Great seller!").
+				WillReturnError(&pgconn.PgError{Code: pgerrcode.UniqueViolation})
+			mock.ExpectRollback()
+
+			_, err := repository.AddReview(ctx, 1, 2, 3, 5, "Great seller!", nil)
+
+			var reviewErr *apperror.ReviewError
+			Expect(errors.As(err, &reviewErr)).To(BeTrue())
+			Expect(reviewErr.Code()).To(Equal(apperror.ReviewDuplicate))
 		})
+	})
 
-		It("should return empty slice for non-existent seller", func() {
-			// Act
-			reviews, err := repository.GetReviewsBySellerID(ctx, 999)
+	Context("GetReviewsByShopID", func() {
+		It("should return reviews of the shop", func() {
+			columns := []string{
+				"id", "shop_id", "offer_id", "user_id", "rating", "review", "status", "helpful_count",
+				"not_helpful_count", "created_at", "updated_at", "total_count",
+			}
+			rows := go_sqlmock.NewRows(columns).
+				AddRow(1, 1, 3, 2, 5, "Great seller!", "published", 4, 1, time.Now(), nil, 2).
+				AddRow(2, 1, nil, 4, 3, "Legacy review", "published", 1, 0, time.Now(), time.Now(), 2)
+			mock.ExpectQuery(regexp.QuoteMeta("FROM seller_reviews WHERE shop_id = $1 AND status = $2 "+
+				"ORDER BY helpful_count - not_helpful_count DESC, helpful_count DESC, created_at DESC, id DESC "+
+				"LIMIT 20 OFFSET 0")).
+				WithArgs(1, "published").
+				WillReturnRows(rows)
+			mock.ExpectQuery(regexp.QuoteMeta("FROM review_replies WHERE review_id IN ($1,$2) AND review_type = $3")).
+				WithArgs(1, 2, "seller").
+				WillReturnRows(go_sqlmock.NewRows([]string{
+					"id", "review_type", "review_id", "shop_id", "author_id", "body", "created_at", "updated_at",
+				}))
+
+			result, total, err := repository.GetReviewsByShopID(ctx, 1,
+				entity.ReviewFilter{Sort: entity.ReviewSortMostHelpful, Limit: 20})
 
-			// Assert
 			Expect(err).NotTo(HaveOccurred())
-			Expect(reviews).To(BeEmpty())
+			Expect(total).To(Equal(2))
+			Expect(result).To(HaveLen(2))
+			Expect(result[0].ShopID).To(Equal(1))
+			Expect(*result[0].OfferID).To(Equal(3))
+			Expect(result[1].OfferID).To(BeNil())
+			Expect(result[1].UpdatedAt).NotTo(BeNil())
+			Expect(result[0].Reply).To(BeNil())
 		})
 	})
 
-	Context("GetSellerByID", func() {
-		It("should return seller review if exists", func() {
-			// Arrange
-			sellerID := 1
-			userID := 2
-			rating := 5
-			review := "Great seller!"
+	Context("ShopExists", func() {
+		It("should report whether the shop exists", func() {
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM shops WHERE id = $1)")).
+				WithArgs(999).
+				WillReturnRows(go_sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+			exists, err := repository.ShopExists(ctx, 999)
 
-			// Добавляем тестовый отзыв
-			_, err := repository.AddReview(ctx, sellerID, userID, rating, review)
 			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+	})
 
-			// Act
-			sellerReview, err := repository.GetSellerByID(ctx, sellerID)
+	Context("GetOfferByID", func() {
+		It("should return the offer participants and status", func() {
+			mock.ExpectQuery("SELECT id, shop_id, user_id, status FROM offers").
+				WithArgs(3).
+				WillReturnRows(go_sqlmock.NewRows([]string{"id", "shop_id", "user_id", "status"}).
+					AddRow(3, 1, 2, "accepted"))
+
+			offer, err := repository.GetOfferByID(ctx, 3)
 
-			// Assert
 			Expect(err).NotTo(HaveOccurred())
-			Expect(sellerReview.SellerID).To(Equal(sellerID))
-			Expect(sellerReview.UserID).To(Equal(userID))
-			Expect(sellerReview.Rating).To(Equal(rating))
-			Expect(sellerReview.Review).To(Equal(review))
+			Expect(offer.ShopID).To(Equal(uint(1)))
+			Expect(offer.UserID).To(Equal(uint(2)))
+			Expect(offer.Status).To(Equal("accepted"))
 		})
 
-		It("should return NotFound error for non-existent seller", func() {
-			// Act
-			_, err := repository.GetSellerByID(ctx, 999)
+		It("should return NotFound error for non-existent offer", func() {
+			mock.ExpectQuery("SELECT id, shop_id, user_id, status FROM offers").
+				WithArgs(999).
+				WillReturnError(sql.ErrNoRows)
+
+			_, err := repository.GetOfferByID(ctx, 999)
 
-			// Assert
-			Expect(err).To(HaveOccurred())
 			var reviewErr *apperror.ReviewError
 			Expect(errors.As(err, &reviewErr)).To(BeTrue())
-			Expect(reviewErr.Code).To(Equal(apperror.NotFound))
+			Expect(reviewErr.Code()).To(Equal(apperror.NotFound))
 		})
 	})
 })
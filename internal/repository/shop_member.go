@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	sq "github.com/Masterminds/squirrel"
+)
+
+// GetMemberRole получает роль пользователя в магазине
+func (r *StoreRepository) GetMemberRole(
+	ctx context.Context,
+	shopID, userID uint,
+) (entity.ShopRole, error) {
+	query, args := sq.Select("role").
+		From("shop_members").
+		Where(sq.Eq{"shop_id": shopID, "user_id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var role string
+	if err := r.db.GetContext(ctx, &role, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", apperror.ErrShopMemberNotFound
+		}
+		return "", apperror.New(apperror.DatabaseError, "failed to fetch shop member", err)
+	}
+
+	return entity.ShopRole(role), nil
+}
+
+// GetMembers получает сотрудников магазина, первым идет владелец
+func (r *StoreRepository) GetMembers(
+	ctx context.Context,
+	shopID uint,
+) ([]entity.ShopMember, error) {
+	query, args := sq.Select("m.shop_id", "m.user_id", "u.name", "u.email", "m.role", "m.created_at").
+		From("shop_members m").
+		InnerJoin("users u ON u.id = m.user_id").
+		Where(sq.Eq{"m.shop_id": shopID}).
+		OrderBy("m.role = 'owner' DESC", "m.created_at", "m.user_id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var memberModels []model.ShopMember
+	if err := r.db.SelectContext(ctx, &memberModels, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch shop members", err)
+	}
+
+	members := make([]entity.ShopMember, 0, len(memberModels))
+	for _, mm := range memberModels {
+		members = append(members, model.ConvertShopMemberToEntity(mm))
+	}
+
+	return members, nil
+}
+
+// UpdateMemberRole меняет роль сотрудника магазина
+func (r *StoreRepository) UpdateMemberRole(
+	ctx context.Context,
+	shopID, userID uint,
+	role entity.ShopRole,
+) error {
+	query, args := sq.Update("shop_members").
+		Set("role", role).
+		Where(sq.Eq{"shop_id": shopID, "user_id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to update shop member", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to get affected rows", err)
+	}
+	if affected == 0 {
+		return apperror.ErrShopMemberNotFound
+	}
+
+	return nil
+}
+
+// DeleteMember удаляет сотрудника из магазина
+func (r *StoreRepository) DeleteMember(
+	ctx context.Context,
+	shopID, userID uint,
+) error {
+	query, args := sq.Delete("shop_members").
+		Where(sq.Eq{"shop_id": shopID, "user_id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to delete shop member", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to get affected rows", err)
+	}
+	if affected == 0 {
+		return apperror.ErrShopMemberNotFound
+	}
+
+	return nil
+}
+
+// InsertInvitation сохраняет приглашение. Сам токен не хранится, только его хэш.
+func (r *StoreRepository) InsertInvitation(
+	ctx context.Context,
+	invitation entity.ShopInvitation,
+	tokenHash string,
+) (uint, error) {
+	query, args := sq.Insert("shop_invitations").
+		Columns("shop_id", "email", "role", "token_hash", "invited_by", "expires_at").
+		Values(invitation.ShopID, invitation.Email, invitation.Role, tokenHash,
+			invitation.InvitedBy, invitation.ExpiresAt).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var id uint
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to insert shop invitation", err)
+	}
+
+	return id, nil
+}
+
+// GetInvitationByTokenHash получает приглашение по хэшу токена
+func (r *StoreRepository) GetInvitationByTokenHash(
+	ctx context.Context,
+	tokenHash string,
+) (entity.ShopInvitation, error) {
+	query, args := sq.Select("id", "shop_id", "email", "role", "invited_by",
+		"expires_at", "accepted_at", "created_at").
+		From("shop_invitations").
+		Where(sq.Eq{"token_hash": tokenHash}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var invitationModel model.ShopInvitation
+	if err := r.db.GetContext(ctx, &invitationModel, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ShopInvitation{}, apperror.ErrShopInvitationNotFound
+		}
+		return entity.ShopInvitation{}, apperror.New(apperror.DatabaseError, "failed to fetch shop invitation", err)
+	}
+
+	return model.ConvertShopInvitationToEntity(invitationModel), nil
+}
+
+// AcceptInvitation помечает приглашение использованным и добавляет пользователя в магазин.
+// Если пользователь уже состоит в магазине, его роль не меняется.
+func (r *StoreRepository) AcceptInvitation(
+	ctx context.Context,
+	invitation entity.ShopInvitation,
+	userID uint,
+) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	acceptQuery, args := sq.Update("shop_invitations").
+		Set("accepted_at", time.Now()).
+		Where(sq.Eq{"id": invitation.ID, "accepted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := tx.ExecContext(ctx, acceptQuery, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to accept shop invitation", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to get affected rows", err)
+	}
+	if affected == 0 {
+		return apperror.New(apperror.Conflict, "invitation has already been accepted", nil)
+	}
+
+	memberQuery, args := sq.Insert("shop_members").
+		Columns("shop_id", "user_id", "role").
+		Values(invitation.ShopID, userID, invitation.Role).
+		Suffix("ON CONFLICT (shop_id, user_id) DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := tx.ExecContext(ctx, memberQuery, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to add shop member", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
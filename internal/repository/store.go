@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// likeEscaper экранирует спецсимволы LIKE, чтобы поиск шел по строке как есть
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+type StoreRepository struct {
+	db *sqlx.DB
+}
+
+func NewStoreRepository(db *sqlx.DB) *StoreRepository {
+	return &StoreRepository{db: db}
+}
+
+// storeSelect выбирает магазины вместе с количеством товаров и рейтингом продавца
+func storeSelect() sq.SelectBuilder {
+	return sq.Select(
+		"s.id", "s.user_id", "s.name", "s.description", "s.logo_url", "s.contact_email",
+		"s.contact_phone", "s.address", "s.working_hours", "s.created_at", "s.updated_at",
+		"(SELECT COUNT(DISTINCT si.product_id) FROM shop_inventory si WHERE si.shop_id = s.id) AS product_count",
+		"CAST(COALESCE((SELECT AVG(r.rating) FROM seller_reviews r "+
+			"WHERE r.shop_id = s.id AND r.status = 'published'), 0) "+
+			"AS DOUBLE PRECISION) AS rating",
+		"(SELECT COUNT(*) FROM seller_reviews r WHERE r.shop_id = s.id AND r.status = 'published') AS review_count",
+	).
+		From("shops s").
+		PlaceholderFormat(sq.Dollar)
+}
+
+// GetStoreByID получает магазин по его ID
+func (r *StoreRepository) GetStoreByID(
+	ctx context.Context,
+	id uint,
+) (entity.Store, error) {
+	query, args := storeSelect().
+		Column("0 AS total_count").
+		Where(sq.Eq{"s.id": id}).
+		MustSql()
+
+	var storeModel model.StoreWithStats
+	if err := r.db.GetContext(ctx, &storeModel, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Store{}, apperror.ErrStoreNotFound
+		}
+		return entity.Store{}, apperror.New(apperror.DatabaseError, "failed to fetch shop", err)
+	}
+
+	store, err := model.ConvertStoreToEntity(storeModel)
+	if err != nil {
+		return entity.Store{}, apperror.New(apperror.DatabaseError, "failed to unmarshal working hours", err)
+	}
+
+	return store, nil
+}
+
+// GetStores получает страницу магазинов, подходящих под фильтр, и их общее количество
+func (r *StoreRepository) GetStores(
+	ctx context.Context,
+	filter model.StoreFilter,
+	limit, offset int,
+) ([]entity.Store, int, error) {
+	builder := storeSelect().
+		Column("COUNT(*) OVER() AS total_count").
+		OrderBy("s.name", "s.id").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	if filter.Search != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Search) + "%"
+		builder = builder.Where(sq.Or{
+			sq.ILike{"s.name": pattern},
+			sq.ILike{"s.description": pattern},
+		})
+	}
+
+	query, args := builder.MustSql()
+
+	var storeModels []model.StoreWithStats
+	if err := r.db.SelectContext(ctx, &storeModels, query, args...); err != nil {
+		return nil, 0, apperror.New(apperror.DatabaseError, "failed to fetch shops", err)
+	}
+
+	if len(storeModels) == 0 {
+		return []entity.Store{}, 0, nil
+	}
+
+	stores := make([]entity.Store, 0, len(storeModels))
+	for _, sm := range storeModels {
+		store, err := model.ConvertStoreToEntity(sm)
+		if err != nil {
+			return nil, 0, apperror.New(apperror.DatabaseError, "failed to unmarshal working hours", err)
+		}
+		stores = append(stores, store)
+	}
+
+	return stores, storeModels[0].TotalCount, nil
+}
+
+// InsertStore создает магазин и добавляет его создателя в участники с ролью owner
+func (r *StoreRepository) InsertStore(
+	ctx context.Context,
+	store entity.Store,
+) (uint, error) {
+	hours, err := marshalWorkingHours(store.WorkingHours)
+	if err != nil {
+		return 0, err
+	}
+
+	query, args := sq.Insert("shops").
+		Columns("user_id", "name", "description", "logo_url", "contact_email",
+			"contact_phone", "address", "working_hours").
+		Values(store.UserID, store.Name, store.Description, store.LogoURL, store.ContactEmail,
+			store.ContactPhone, store.Address, hours).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var id uint
+	if err := tx.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to insert shop", err)
+	}
+
+	memberQuery, memberArgs := sq.Insert("shop_members").
+		Columns("shop_id", "user_id", "role").
+		Values(id, store.UserID, entity.ShopRoleOwner).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := tx.ExecContext(ctx, memberQuery, memberArgs...); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to add shop owner", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return id, nil
+}
+
+// UpdateStore обновляет профиль магазина
+func (r *StoreRepository) UpdateStore(
+	ctx context.Context,
+	store entity.Store,
+) error {
+	hours, err := marshalWorkingHours(store.WorkingHours)
+	if err != nil {
+		return err
+	}
+
+	query, args := sq.Update("shops").
+		Set("name", store.Name).
+		Set("description", store.Description).
+		Set("logo_url", store.LogoURL).
+		Set("contact_email", store.ContactEmail).
+		Set("contact_phone", store.ContactPhone).
+		Set("address", store.Address).
+		Set("working_hours", hours).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": store.ID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to update shop", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to get affected rows", err)
+	}
+	if affected == 0 {
+		return apperror.ErrStoreNotFound
+	}
+
+	return nil
+}
+
+func marshalWorkingHours(hours map[string]string) ([]byte, error) {
+	if hours == nil {
+		hours = map[string]string{}
+	}
+	data, err := json.Marshal(hours)
+	if err != nil {
+		return nil, apperror.New(apperror.InternalError, "failed to marshal working hours", err)
+	}
+	return data, nil
+}
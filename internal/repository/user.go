@@ -4,17 +4,24 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
 	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 )
 
+var userColumns = []string{
+	"id", "name", "email", "phone_number", "password_hash", "is_store", "role", "locale", "email_verified_at",
+}
+
 type UserRepository struct {
 	db *sqlx.DB
 }
@@ -23,22 +30,33 @@ func NewUserRepository(db *sqlx.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-// InsertUser вставляет пользователя в БД
+// InsertUser вставляет пользователя в БД вместе с токеном подтверждения почты.
+// onInsert вызывается в той же транзакции, его ошибка отменяет регистрацию
 func (r *UserRepository) InsertUser(
 	ctx context.Context,
 	user user.User,
+	verification user.VerificationToken,
+	onInsert func(tx email.Execer, userID uint) error,
 ) (uint, error) {
 	userModel := model.ConvertUserFromSvc(user)
 
 	stmt := sq.Insert("users").
-		Columns("name", "email", "phone_number", "password_hash", "is_store").
-		Values(user.Name, user.Email, user.Phone, user.Password, user.IsStore).
+		Columns("name", "email", "phone_number", "password_hash", "is_store", "role", "locale").
+		Values(user.Name, user.Email, user.Phone, user.Password, user.IsStore, userModel.Role, userModel.Locale).
 		Suffix("RETURNING id").
 		PlaceholderFormat(sq.Dollar)
 
 	query, args := stmt.MustSql()
 
-	err := r.db.QueryRowxContext(ctx, query, args...).Scan(&userModel.ID)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = tx.QueryRowxContext(ctx, query, args...).Scan(&userModel.ID)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr); pgErr.Code == pgerrcode.UniqueViolation {
@@ -47,6 +65,20 @@ func (r *UserRepository) InsertUser(
 		return 0, apperror.New(apperror.DuplicateError, "failed to create user", err)
 	}
 
+	if err := insertVerificationToken(ctx, tx, userModel.ID, verification); err != nil {
+		return 0, err
+	}
+
+	if onInsert != nil {
+		if err := onInsert(tx, userModel.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
 	return userModel.ID, nil
 }
 
@@ -57,9 +89,10 @@ func (r *UserRepository) GetUser(
 ) (entity.User, error) {
 	var userModel model.User
 
-	stmt := sq.Select("id", "name", "email", "phone_number", "password_hash", "is_store").
+	stmt := sq.Select(userColumns...).
 		From("users").
 		Where(sq.Eq{"email": email}).
+		Where(sq.Eq{"deleted_at": nil}).
 		PlaceholderFormat(sq.Dollar)
 
 	query, args := stmt.MustSql()
@@ -82,9 +115,10 @@ func (r *UserRepository) GetUserByID(
 ) (entity.User, error) {
 	var userModel model.User
 
-	stmt := sq.Select("id", "name", "email", "phone_number", "password_hash", "is_store").
+	stmt := sq.Select(userColumns...).
 		From("users").
 		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"deleted_at": nil}).
 		PlaceholderFormat(sq.Dollar)
 
 	query, args := stmt.MustSql()
@@ -99,3 +133,409 @@ func (r *UserRepository) GetUserByID(
 
 	return model.ConvertUserToEntity(userModel), nil
 }
+
+// InsertPasswordResetToken сохраняет хэш токена сброса пароля
+func (r *UserRepository) InsertPasswordResetToken(
+	ctx context.Context,
+	userID uint,
+	tokenHash string,
+	expiresAt time.Time,
+) error {
+	query, args := sq.Insert("password_reset_tokens").
+		Columns("user_id", "token_hash", "expires_at").
+		Values(userID, tokenHash, expiresAt).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to save password reset token", err)
+	}
+	return nil
+}
+
+// ResetPassword гасит токен сброса и меняет хэш пароля в одной транзакции.
+// Остальные неиспользованные токены пользователя тоже гасятся. Возвращает ID пользователя
+func (r *UserRepository) ResetPassword(
+	ctx context.Context,
+	tokenHash string,
+	passwordHash string,
+) (uint, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// FOR UPDATE не дает использовать один токен в двух параллельных запросах
+	var userID uint
+	err = tx.QueryRowxContext(ctx, `SELECT user_id FROM password_reset_tokens
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		FOR UPDATE`, tokenHash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, apperror.ErrInvalidPasswordResetToken
+		}
+		return 0, apperror.New(apperror.DatabaseError, "failed to fetch password reset token", err)
+	}
+
+	query, args := sq.Update("password_reset_tokens").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": userID, "used_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to invalidate password reset tokens", err)
+	}
+
+	query, args = sq.Update("users").
+		Set("password_hash", passwordHash).
+		Where(sq.Eq{"id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to update password", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+	return userID, nil
+}
+
+// InsertVerificationToken сохраняет хэш нового токена подтверждения почты
+func (r *UserRepository) InsertVerificationToken(
+	ctx context.Context,
+	userID uint,
+	verification user.VerificationToken,
+) error {
+	return insertVerificationToken(ctx, r.db, userID, verification)
+}
+
+// CountVerificationTokens возвращает, сколько писем подтверждения отправлено пользователю
+// после since, и время последнего из них
+func (r *UserRepository) CountVerificationTokens(
+	ctx context.Context,
+	userID uint,
+	since time.Time,
+) (int, *time.Time, error) {
+	count, lastAt, err := r.countTokens(ctx, "email_verification_tokens", userID, since)
+	if err != nil {
+		return 0, nil, apperror.New(apperror.DatabaseError, "failed to count email verification tokens", err)
+	}
+	return count, lastAt, nil
+}
+
+// CountPasswordResetTokens возвращает, сколько писем сброса пароля отправлено пользователю
+// после since, и время последнего из них
+func (r *UserRepository) CountPasswordResetTokens(
+	ctx context.Context,
+	userID uint,
+	since time.Time,
+) (int, *time.Time, error) {
+	count, lastAt, err := r.countTokens(ctx, "password_reset_tokens", userID, since)
+	if err != nil {
+		return 0, nil, apperror.New(apperror.DatabaseError, "failed to count password reset tokens", err)
+	}
+	return count, lastAt, nil
+}
+
+// countTokens считает токены пользователя в таблице, выданные после since
+func (r *UserRepository) countTokens(
+	ctx context.Context,
+	table string,
+	userID uint,
+	since time.Time,
+) (int, *time.Time, error) {
+	var stats struct {
+		Count  int        `db:"count"`
+		LastAt *time.Time `db:"last_at"`
+	}
+	query, args := sq.Select("COUNT(*) AS count", "MAX(created_at) AS last_at").
+		From(table).
+		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Gt{"created_at": since}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if err := r.db.GetContext(ctx, &stats, query, args...); err != nil {
+		return 0, nil, err
+	}
+	return stats.Count, stats.LastAt, nil
+}
+
+// VerifyEmail гасит токен подтверждения и отмечает почту подтвержденной в одной транзакции.
+// Остальные токены пользователя тоже гасятся. Возвращает пользователя
+func (r *UserRepository) VerifyEmail(ctx context.Context, tokenHash string) (entity.User, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.User{}, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var userID uint
+	err = tx.QueryRowxContext(ctx, `SELECT user_id FROM email_verification_tokens
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		FOR UPDATE`, tokenHash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.User{}, apperror.ErrInvalidVerificationToken
+		}
+		return entity.User{}, apperror.New(apperror.DatabaseError, "failed to fetch email verification token", err)
+	}
+
+	query, args := sq.Update("email_verification_tokens").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": userID, "used_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return entity.User{}, apperror.New(apperror.DatabaseError,
+			"failed to invalidate email verification tokens", err)
+	}
+
+	// COALESCE сохраняет дату первого подтверждения
+	query, args = sq.Update("users").
+		Set("email_verified_at", sq.Expr("COALESCE(email_verified_at, NOW())")).
+		Where(sq.Eq{"id": userID}).
+		Suffix("RETURNING " + strings.Join(userColumns, ", ")).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var userModel model.User
+	if err := tx.QueryRowxContext(ctx, query, args...).StructScan(&userModel); err != nil {
+		return entity.User{}, apperror.New(apperror.DatabaseError, "failed to verify email", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.User{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+	return model.ConvertUserToEntity(userModel), nil
+}
+
+func insertVerificationToken(
+	ctx context.Context,
+	exec sqlx.ExecerContext,
+	userID uint,
+	verification user.VerificationToken,
+) error {
+	query, args := sq.Insert("email_verification_tokens").
+		Columns("user_id", "token_hash", "expires_at").
+		Values(userID, verification.TokenHash, verification.ExpiresAt).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := exec.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to save email verification token", err)
+	}
+	return nil
+}
+
+// UpdateProfile меняет имя и телефон пользователя. Пустые поля update не меняются
+func (r *UserRepository) UpdateProfile(
+	ctx context.Context,
+	userID uint,
+	update user.UpdateProfile,
+) (entity.User, error) {
+	stmt := sq.Update("users").
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		Suffix("RETURNING " + strings.Join(userColumns, ", ")).
+		PlaceholderFormat(sq.Dollar)
+	if update.Name != nil {
+		stmt = stmt.Set("name", *update.Name)
+	}
+	if update.Phone != nil {
+		stmt = stmt.Set("phone_number", *update.Phone)
+	}
+	query, args := stmt.MustSql()
+
+	var userModel model.User
+	if err := r.db.QueryRowxContext(ctx, query, args...).StructScan(&userModel); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.User{}, apperror.ErrUserNotFound
+		}
+		return entity.User{}, apperror.New(apperror.DatabaseError, "failed to update profile", err)
+	}
+	return model.ConvertUserToEntity(userModel), nil
+}
+
+// UpdatePassword меняет хэш пароля пользователя
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID uint, passwordHash string) error {
+	query, args := sq.Update("users").
+		Set("password_hash", passwordHash).
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to update password", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperror.ErrUserNotFound
+	}
+	return nil
+}
+
+// ChangeEmail меняет почту и снимает ее подтверждение в одной транзакции.
+// Старые ссылки подтверждения гасятся, сохраняется токен для новой почты
+func (r *UserRepository) ChangeEmail(
+	ctx context.Context,
+	userID uint,
+	email string,
+	verification user.VerificationToken,
+) (entity.User, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.User{}, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query, args := sq.Update("users").
+		Set("email", email).
+		Set("email_verified_at", nil).
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		Suffix("RETURNING " + strings.Join(userColumns, ", ")).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var userModel model.User
+	if err := tx.QueryRowxContext(ctx, query, args...).StructScan(&userModel); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.User{}, apperror.ErrUserNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return entity.User{}, apperror.New(apperror.DuplicateError, "user with this email already exists", err)
+		}
+		return entity.User{}, apperror.New(apperror.DatabaseError, "failed to change email", err)
+	}
+
+	query, args = sq.Update("email_verification_tokens").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": userID, "used_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return entity.User{}, apperror.New(apperror.DatabaseError,
+			"failed to invalidate email verification tokens", err)
+	}
+
+	if err := insertVerificationToken(ctx, tx, userID, verification); err != nil {
+		return entity.User{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.User{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+	return model.ConvertUserToEntity(userModel), nil
+}
+
+// DeleteUser удаляет аккаунт, затирая личные данные. Строка пользователя остается,
+// потому что на нее без ON DELETE ссылаются отзывы и refresh токены, а магазины - с RESTRICT,
+// поэтому владельцу магазина аккаунт не удалить. Сессии, токены, настройки уведомлений
+// и членство в магазинах удаляются, ожидающие офферы отменяются. Голоса и жалобы на отзывы
+// остаются, чтобы не разошлись счетчики полезности и очередь модерации. Возвращает отмененные офферы.
+// onCancel вызывается с ними в той же транзакции, его ошибка отменяет удаление
+func (r *UserRepository) DeleteUser(
+	ctx context.Context,
+	userID uint,
+	onCancel func(tx email.Execer, offers []entity.Offer) error,
+) ([]entity.Offer, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var ownsShop bool
+	err = tx.QueryRowxContext(ctx, `SELECT
+		EXISTS (SELECT 1 FROM shops WHERE user_id = $1)
+		OR EXISTS (SELECT 1 FROM shop_members WHERE user_id = $1 AND role = 'owner')`, userID).Scan(&ownsShop)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to check user shops", err)
+	}
+	if ownsShop {
+		return nil, apperror.ErrAccountOwnsShop
+	}
+
+	// почта заменяется уникальной заглушкой, чтобы адрес можно было зарегистрировать заново
+	query, args := sq.Update("users").
+		Set("name", "Deleted user").
+		Set("email", sq.Expr("'deleted-' || id || '@deleted.invalid'")).
+		Set("phone_number", "").
+		Set("password_hash", "").
+		Set("email_verified_at", nil).
+		Set("deleted_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to anonymise user", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, apperror.ErrUserNotFound
+	}
+
+	for _, table := range []string{
+		"refresh_tokens",
+		"password_reset_tokens",
+		"email_verification_tokens",
+		"notification_preferences",
+		"shop_members",
+	} {
+		query, args := sq.Delete(table).
+			Where(sq.Eq{"user_id": userID}).
+			PlaceholderFormat(sq.Dollar).
+			MustSql()
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return nil, apperror.New(apperror.DatabaseError, "failed to delete "+table, err)
+		}
+	}
+
+	query, args = sq.Delete("shop_invitations").
+		Where(sq.Eq{"invited_by": userID, "accepted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to delete shop invitations", err)
+	}
+
+	query, args = sq.Update("offers").
+		Set("status", "cancelled").
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"user_id": userID, "status": "pending"}).
+		Suffix("RETURNING " + offerColumns).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var cancelled []model.Offer
+	if err := tx.SelectContext(ctx, &cancelled, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to cancel user offers", err)
+	}
+
+	offers := make([]entity.Offer, len(cancelled))
+	for i, offerModel := range cancelled {
+		offers[i] = offerModel.ConvertToEntity()
+	}
+
+	if onCancel != nil {
+		if err := onCancel(tx, offers); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return offers, nil
+}
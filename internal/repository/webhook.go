@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+)
+
+const (
+	webhookColumns  = "id, shop_id, url, secret, events, active, created_at, updated_at"
+	deliveryColumns = "id, webhook_id, event, payload, status, attempts, response_status, last_error, " +
+		"next_attempt_at, created_at, delivered_at"
+)
+
+type WebhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// InsertWebhook создает вебхук и возвращает его вместе с секретом
+func (r *WebhookRepository) InsertWebhook(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error) {
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return entity.Webhook{}, apperror.New(apperror.InternalError, "failed to encode webhook events", err)
+	}
+
+	query, args := squirrel.Insert("shop_webhooks").
+		Columns("shop_id", "url", "secret", "events", "active").
+		Values(webhook.ShopID, webhook.URL, webhook.Secret, events, webhook.Active).
+		Suffix("RETURNING " + webhookColumns).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var row model.Webhook
+	if err := r.db.GetContext(ctx, &row, query, args...); err != nil {
+		return entity.Webhook{}, apperror.New(apperror.DatabaseError, "failed to create webhook", err)
+	}
+	return convertWebhook(row, true)
+}
+
+// SelectShopWebhooks возвращает вебхуки магазина без секретов
+func (r *WebhookRepository) SelectShopWebhooks(ctx context.Context, shopID uint) ([]entity.Webhook, error) {
+	query, args := squirrel.Select(webhookColumns).
+		From("shop_webhooks").
+		Where(squirrel.Eq{"shop_id": shopID}).
+		OrderBy("id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var rows []model.Webhook
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch webhooks", err)
+	}
+	return convertWebhooks(rows, false)
+}
+
+// GetWebhook возвращает вебхук магазина вместе с секретом
+func (r *WebhookRepository) GetWebhook(ctx context.Context, shopID, webhookID uint) (entity.Webhook, error) {
+	query, args := squirrel.Select(webhookColumns).
+		From("shop_webhooks").
+		Where(squirrel.Eq{"id": webhookID, "shop_id": shopID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var row model.Webhook
+	if err := r.db.GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Webhook{}, apperror.ErrWebhookNotFound
+		}
+		return entity.Webhook{}, apperror.New(apperror.DatabaseError, "failed to fetch webhook", err)
+	}
+	return convertWebhook(row, true)
+}
+
+// UpdateWebhook меняет адрес, события и активность вебхука. Секрет не меняется и не возвращается
+func (r *WebhookRepository) UpdateWebhook(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error) {
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return entity.Webhook{}, apperror.New(apperror.InternalError, "failed to encode webhook events", err)
+	}
+
+	query, args := squirrel.Update("shop_webhooks").
+		Set("url", webhook.URL).
+		Set("events", events).
+		Set("active", webhook.Active).
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": webhook.ID, "shop_id": webhook.ShopID}).
+		Suffix("RETURNING " + webhookColumns).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var row model.Webhook
+	if err := r.db.GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Webhook{}, apperror.ErrWebhookNotFound
+		}
+		return entity.Webhook{}, apperror.New(apperror.DatabaseError, "failed to update webhook", err)
+	}
+	return convertWebhook(row, false)
+}
+
+// DeleteWebhook удаляет вебхук вместе с журналом доставок
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, shopID, webhookID uint) error {
+	query, args := squirrel.Delete("shop_webhooks").
+		Where(squirrel.Eq{"id": webhookID, "shop_id": shopID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to delete webhook", err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return apperror.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// SelectSubscribedWebhooks возвращает активные вебхуки магазинов, подписанные на событие
+func (r *WebhookRepository) SelectSubscribedWebhooks(
+	ctx context.Context,
+	shopIDs []uint,
+	eventName string,
+) ([]entity.Webhook, error) {
+	if len(shopIDs) == 0 {
+		return nil, nil
+	}
+	filter, err := json.Marshal([]string{eventName})
+	if err != nil {
+		return nil, apperror.New(apperror.InternalError, "failed to encode webhook event", err)
+	}
+
+	query, args := squirrel.Select(webhookColumns).
+		From("shop_webhooks").
+		Where(squirrel.Eq{"shop_id": shopIDs, "active": true}).
+		Where(squirrel.Expr("events @> ?::jsonb", string(filter))).
+		OrderBy("id").
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var rows []model.Webhook
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch subscribed webhooks", err)
+	}
+	return convertWebhooks(rows, false)
+}
+
+// SelectProductShopIDs возвращает магазины, которые продают товар
+func (r *WebhookRepository) SelectProductShopIDs(ctx context.Context, productID uint) ([]uint, error) {
+	var shopIDs []uint
+	err := r.db.SelectContext(ctx, &shopIDs,
+		"SELECT DISTINCT shop_id FROM shop_inventory WHERE product_id = $1 ORDER BY shop_id", productID)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch product shops", err)
+	}
+	return shopIDs, nil
+}
+
+// InsertDeliveries записывает доставки через exec, чтобы они попали в транзакцию события
+func (r *WebhookRepository) InsertDeliveries(
+	ctx context.Context,
+	exec email.Execer,
+	deliveries []entity.WebhookDelivery,
+) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if exec == nil {
+		exec = r.db
+	}
+
+	qb := squirrel.Insert("webhook_deliveries").Columns("webhook_id", "event", "payload")
+	for _, d := range deliveries {
+		qb = qb.Values(d.WebhookID, d.Event, []byte(d.Payload))
+	}
+	query, args := qb.PlaceholderFormat(squirrel.Dollar).MustSql()
+
+	if _, err := exec.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to enqueue webhook deliveries", err)
+	}
+	return nil
+}
+
+// CreateClaimedDelivery записывает доставку, которая сразу отправляется вызывающим.
+// Она отложена на lease, чтобы ее не взял воркер
+func (r *WebhookRepository) CreateClaimedDelivery(
+	ctx context.Context,
+	delivery entity.WebhookDelivery,
+	lease time.Duration,
+) (entity.WebhookDelivery, error) {
+	query := `INSERT INTO webhook_deliveries (webhook_id, event, payload, attempts, next_attempt_at)
+		VALUES ($1, $2, $3, 1, NOW() + make_interval(secs => $4))
+		RETURNING ` + deliveryColumns
+
+	var row model.WebhookDelivery
+	err := r.db.GetContext(ctx, &row, query, delivery.WebhookID, delivery.Event, []byte(delivery.Payload),
+		lease.Seconds())
+	if err != nil {
+		return entity.WebhookDelivery{}, apperror.New(apperror.DatabaseError, "failed to create webhook delivery", err)
+	}
+	return row.ConvertToEntity(), nil
+}
+
+// ClaimDeliveries забирает готовые к отправке доставки активных вебхуков и откладывает их на lease.
+// SKIP LOCKED позволяет нескольким воркерам разбирать очередь параллельно
+func (r *WebhookRepository) ClaimDeliveries(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]entity.PendingWebhookDelivery, error) {
+	query := `WITH claimed AS (
+			UPDATE webhook_deliveries
+			SET attempts = attempts + 1, next_attempt_at = NOW() + make_interval(secs => $1)
+			WHERE id IN (
+				SELECT d.id FROM webhook_deliveries d
+				JOIN shop_webhooks w ON w.id = d.webhook_id
+				WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.active
+				ORDER BY d.next_attempt_at
+				LIMIT $2
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING ` + deliveryColumns + `
+		)
+		SELECT c.*, w.url, w.secret FROM claimed c JOIN shop_webhooks w ON w.id = c.webhook_id`
+
+	var rows []model.PendingWebhookDelivery
+	if err := r.db.SelectContext(ctx, &rows, query, lease.Seconds(), limit); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to claim webhook deliveries", err)
+	}
+
+	deliveries := make([]entity.PendingWebhookDelivery, len(rows))
+	for i, row := range rows {
+		deliveries[i] = entity.PendingWebhookDelivery{
+			WebhookDelivery: row.ConvertToEntity(),
+			URL:             row.URL,
+			Secret:          row.Secret,
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, id int64, responseStatus int) error {
+	query, args := squirrel.Update("webhook_deliveries").
+		Set("status", entity.WebhookDeliveryDelivered).
+		Set("response_status", responseStatus).
+		Set("last_error", "").
+		Set("delivered_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to mark webhook delivery as delivered", err)
+	}
+	return nil
+}
+
+// MarkFailed сохраняет ошибку и назначает следующую попытку, без retryAt доставка проваливается.
+// responseStatus 0 - ответа не было
+func (r *WebhookRepository) MarkFailed(
+	ctx context.Context,
+	id int64,
+	responseStatus int,
+	lastError string,
+	retryAt *time.Time,
+) error {
+	qb := squirrel.Update("webhook_deliveries").
+		Set("response_status", sql.NullInt32{Int32: int32(responseStatus), Valid: responseStatus != 0}).
+		Set("last_error", lastError).
+		Where(squirrel.Eq{"id": id})
+	if retryAt != nil {
+		qb = qb.Set("next_attempt_at", *retryAt)
+	} else {
+		qb = qb.Set("status", entity.WebhookDeliveryFailed)
+	}
+
+	query, args := qb.PlaceholderFormat(squirrel.Dollar).MustSql()
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to save webhook delivery error", err)
+	}
+	return nil
+}
+
+// SelectDeliveries возвращает журнал доставок вебхука, новые первыми
+func (r *WebhookRepository) SelectDeliveries(
+	ctx context.Context,
+	webhookID uint,
+	offset, limit int,
+) ([]entity.WebhookDelivery, int, error) {
+	query, args := squirrel.Select(deliveryColumns+", COUNT(*) OVER() AS total_count").
+		From("webhook_deliveries").
+		Where(squirrel.Eq{"webhook_id": webhookID}).
+		OrderBy("created_at DESC", "id DESC").
+		Offset(uint64(offset)).
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
+
+	var rows []model.WebhookDeliveryWithCount
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, 0, apperror.New(apperror.DatabaseError, "failed to fetch webhook deliveries", err)
+	}
+
+	deliveries := make([]entity.WebhookDelivery, len(rows))
+	total := 0
+	for i, row := range rows {
+		deliveries[i] = row.ConvertToEntity()
+		total = row.TotalCount
+	}
+	return deliveries, total, nil
+}
+
+func convertWebhook(row model.Webhook, withSecret bool) (entity.Webhook, error) {
+	webhook, err := row.ConvertToEntity(withSecret)
+	if err != nil {
+		return entity.Webhook{}, apperror.New(apperror.InternalError, "failed to decode webhook events", err)
+	}
+	return webhook, nil
+}
+
+func convertWebhooks(rows []model.Webhook, withSecret bool) ([]entity.Webhook, error) {
+	webhooks := make([]entity.Webhook, len(rows))
+	for i, row := range rows {
+		webhook, err := convertWebhook(row, withSecret)
+		if err != nil {
+			return nil, err
+		}
+		webhooks[i] = webhook
+	}
+	return webhooks, nil
+}
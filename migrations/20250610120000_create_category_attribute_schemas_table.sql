@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE category_attribute_schemas (
+    id SERIAL PRIMARY KEY,
+    category_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    unit VARCHAR(50),
+    enum_values JSONB,
+    is_required BOOLEAN NOT NULL DEFAULT FALSE,
+    is_filterable BOOLEAN NOT NULL DEFAULT FALSE,
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE,
+    UNIQUE (category_id, name),
+    CHECK (type IN ('string', 'number', 'boolean', 'enum'))
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS category_attribute_schemas;
+-- +goose StatementEnd
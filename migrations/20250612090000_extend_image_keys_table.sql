@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE image_keys ADD COLUMN id SERIAL PRIMARY KEY;
+ALTER TABLE image_keys ALTER COLUMN image_key TYPE VARCHAR(255);
+ALTER TABLE image_keys ADD COLUMN thumbnail_key VARCHAR(255);
+ALTER TABLE image_keys ADD COLUMN content_type VARCHAR(50) NOT NULL DEFAULT 'image/jpeg';
+ALTER TABLE image_keys ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+ALTER TABLE image_keys DROP CONSTRAINT image_keys_product_id_fkey;
+ALTER TABLE image_keys ADD CONSTRAINT image_keys_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE image_keys DROP CONSTRAINT image_keys_product_id_fkey;
+ALTER TABLE image_keys ADD CONSTRAINT image_keys_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id);
+
+ALTER TABLE image_keys DROP COLUMN created_at;
+ALTER TABLE image_keys DROP COLUMN content_type;
+ALTER TABLE image_keys DROP COLUMN thumbnail_key;
+ALTER TABLE image_keys ALTER COLUMN image_key TYPE VARCHAR(50);
+ALTER TABLE image_keys DROP COLUMN id;
+-- +goose StatementEnd
@@ -0,0 +1,64 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL,
+    sku VARCHAR(64) NOT NULL UNIQUE,
+    attributes JSONB NOT NULL DEFAULT '{}',
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    UNIQUE (id, product_id)
+);
+
+CREATE INDEX idx_product_variants_product_id ON product_variants(product_id);
+
+-- у каждого существующего продукта появляется вариант по умолчанию
+INSERT INTO product_variants (product_id, sku)
+SELECT id, 'P' || LPAD(id::text, 6, '0') FROM products;
+
+ALTER TABLE shop_inventory ADD COLUMN variant_id INT;
+UPDATE shop_inventory si SET variant_id = pv.id
+FROM product_variants pv WHERE pv.product_id = si.product_id;
+ALTER TABLE shop_inventory ALTER COLUMN variant_id SET NOT NULL;
+
+ALTER TABLE offers ADD COLUMN variant_id INT;
+UPDATE offers o SET variant_id = pv.id
+FROM product_variants pv WHERE pv.product_id = o.product_id;
+ALTER TABLE offers ALTER COLUMN variant_id SET NOT NULL;
+
+ALTER TABLE offers DROP CONSTRAINT offers_product_id_shop_id_fkey;
+ALTER TABLE shop_inventory DROP CONSTRAINT shop_inventory_pkey;
+ALTER TABLE shop_inventory ADD PRIMARY KEY (variant_id, shop_id);
+
+-- product_id остается в shop_inventory и offers для агрегации по родительскому продукту,
+-- составной ключ не дает ему разойтись с вариантом
+ALTER TABLE shop_inventory ADD CONSTRAINT shop_inventory_variant_id_product_id_fkey
+    FOREIGN KEY (variant_id, product_id) REFERENCES product_variants(id, product_id);
+ALTER TABLE offers ADD CONSTRAINT offers_variant_id_shop_id_fkey
+    FOREIGN KEY (variant_id, shop_id) REFERENCES shop_inventory(variant_id, shop_id);
+
+CREATE INDEX idx_shop_inventory_variant_id ON shop_inventory(variant_id);
+CREATE INDEX idx_offers_variant_id ON offers(variant_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_offers_variant_id;
+DROP INDEX IF EXISTS idx_shop_inventory_variant_id;
+
+ALTER TABLE offers DROP CONSTRAINT offers_variant_id_shop_id_fkey;
+ALTER TABLE shop_inventory DROP CONSTRAINT shop_inventory_variant_id_product_id_fkey;
+ALTER TABLE shop_inventory DROP CONSTRAINT shop_inventory_pkey;
+
+-- без вариантов в магазине остается одна позиция на продукт, оставляем первый вариант
+DELETE FROM offers WHERE variant_id NOT IN (SELECT MIN(id) FROM product_variants GROUP BY product_id);
+DELETE FROM shop_inventory WHERE variant_id NOT IN (SELECT MIN(id) FROM product_variants GROUP BY product_id);
+
+ALTER TABLE shop_inventory ADD PRIMARY KEY (product_id, shop_id);
+ALTER TABLE offers ADD CONSTRAINT offers_product_id_shop_id_fkey
+    FOREIGN KEY (product_id, shop_id) REFERENCES shop_inventory(product_id, shop_id);
+
+ALTER TABLE offers DROP COLUMN variant_id;
+ALTER TABLE shop_inventory DROP COLUMN variant_id;
+
+DROP TABLE IF EXISTS product_variants;
+-- +goose StatementEnd
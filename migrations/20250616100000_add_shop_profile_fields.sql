@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE shops
+    ADD COLUMN description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN logo_url VARCHAR(500) NOT NULL DEFAULT '',
+    ADD COLUMN contact_email VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN contact_phone VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN address TEXT NOT NULL DEFAULT '',
+    ADD COLUMN working_hours JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX idx_shops_lower_name ON shops(LOWER(name));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_shops_lower_name;
+
+ALTER TABLE shops
+    DROP COLUMN description,
+    DROP COLUMN logo_url,
+    DROP COLUMN contact_email,
+    DROP COLUMN contact_phone,
+    DROP COLUMN address,
+    DROP COLUMN working_hours,
+    DROP COLUMN created_at,
+    DROP COLUMN updated_at;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS shop_members (
+    shop_id INTEGER NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'manager', 'viewer')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (shop_id, user_id)
+);
+
+CREATE INDEX idx_shop_members_user_id ON shop_members(user_id);
+
+-- Текущие владельцы магазинов становятся участниками с ролью owner
+INSERT INTO shop_members (shop_id, user_id, role)
+SELECT id, user_id, 'owner' FROM shops
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS shop_invitations (
+    id SERIAL PRIMARY KEY,
+    shop_id INTEGER NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('manager', 'viewer')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    invited_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_shop_invitations_shop_id ON shop_invitations(shop_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shop_invitations;
+DROP TABLE IF EXISTS shop_members;
+-- +goose StatementEnd
@@ -0,0 +1,76 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE seller_reviews
+    ADD COLUMN shop_id INT,
+    ADD COLUMN offer_id INT;
+
+-- Отзыв о продавце переносится на его первый магазин
+UPDATE seller_reviews r
+SET shop_id = (SELECT MIN(s.id) FROM shops s WHERE s.user_id = r.seller_id);
+
+-- Отзывы о пользователях без магазинов перенести некуда. Они не удаляются,
+-- а переносятся в архив, из которого их возвращает Down миграция
+CREATE TABLE seller_reviews_without_shop AS
+SELECT id, seller_id, user_id, rating, review, created_at
+FROM seller_reviews
+WHERE shop_id IS NULL;
+
+DELETE FROM seller_reviews WHERE shop_id IS NULL;
+
+-- N-й отзыв покупателя о магазине привязывается к его N-й принятой сделке с этим магазином.
+-- Отзывы, для которых сделки не нашлось, остаются без offer_id.
+WITH ranked_reviews AS (
+    SELECT id, shop_id, user_id,
+           ROW_NUMBER() OVER (PARTITION BY shop_id, user_id ORDER BY created_at, id) AS rn
+    FROM seller_reviews
+),
+ranked_offers AS (
+    SELECT id, shop_id, user_id,
+           ROW_NUMBER() OVER (PARTITION BY shop_id, user_id ORDER BY updated_at, id) AS rn
+    FROM offers
+    WHERE status = 'accepted'
+)
+UPDATE seller_reviews r
+SET offer_id = o.id
+FROM ranked_reviews rr
+JOIN ranked_offers o ON o.shop_id = rr.shop_id AND o.user_id = rr.user_id AND o.rn = rr.rn
+WHERE r.id = rr.id;
+
+ALTER TABLE seller_reviews
+    DROP COLUMN seller_id,
+    ALTER COLUMN shop_id SET NOT NULL,
+    ADD CONSTRAINT seller_reviews_shop_id_fkey FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE,
+    ADD CONSTRAINT seller_reviews_offer_id_fkey FOREIGN KEY (offer_id) REFERENCES offers(id) ON DELETE SET NULL,
+    ADD CONSTRAINT seller_reviews_offer_id_key UNIQUE (offer_id);
+
+CREATE INDEX idx_seller_reviews_shop_id ON seller_reviews(shop_id);
+CREATE INDEX idx_seller_reviews_shop_user ON seller_reviews(shop_id, user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE seller_reviews ADD COLUMN seller_id INT;
+
+UPDATE seller_reviews r
+SET seller_id = s.user_id
+FROM shops s
+WHERE s.id = r.shop_id;
+
+DROP INDEX IF EXISTS idx_seller_reviews_shop_user;
+DROP INDEX IF EXISTS idx_seller_reviews_shop_id;
+
+ALTER TABLE seller_reviews
+    DROP COLUMN offer_id,
+    DROP COLUMN shop_id,
+    ALTER COLUMN seller_id SET NOT NULL,
+    ADD CONSTRAINT seller_reviews_seller_id_fkey FOREIGN KEY (seller_id) REFERENCES users(id);
+
+INSERT INTO seller_reviews (id, seller_id, user_id, rating, review, created_at)
+SELECT id, seller_id, user_id, rating, review, created_at
+FROM seller_reviews_without_shop;
+
+DROP TABLE seller_reviews_without_shop;
+
+CREATE INDEX idx_seller_reviews_seller_id ON seller_reviews(seller_id);
+CREATE INDEX idx_seller_reviews_seller_user ON seller_reviews(seller_id, user_id);
+-- +goose StatementEnd
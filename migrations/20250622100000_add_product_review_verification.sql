@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+-- От каждого пользователя остается только последний отзыв о продукте. Более ранние
+-- не удаляются, а переносятся в архив, из которого их возвращает Down миграция
+CREATE TABLE product_reviews_superseded AS
+SELECT r.id, r.product_id, r.user_id, r.rating, r.review, r.created_at
+FROM product_reviews r
+WHERE EXISTS (
+    SELECT 1 FROM product_reviews newer
+    WHERE newer.product_id = r.product_id
+      AND newer.user_id = r.user_id
+      AND (newer.created_at, newer.id) > (r.created_at, r.id)
+);
+
+DELETE FROM product_reviews r
+USING product_reviews_superseded s
+WHERE s.id = r.id;
+
+ALTER TABLE product_reviews
+    ADD COLUMN is_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN updated_at TIMESTAMP,
+    ADD CONSTRAINT product_reviews_product_user_key UNIQUE (product_id, user_id);
+
+-- Отзыв подтвержден, если у автора есть принятый оффер на этот продукт
+UPDATE product_reviews r
+SET is_verified = TRUE
+WHERE EXISTS (
+    SELECT 1 FROM offers o
+    WHERE o.user_id = r.user_id AND o.product_id = r.product_id AND o.status = 'accepted'
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE product_reviews
+    DROP CONSTRAINT product_reviews_product_user_key,
+    DROP COLUMN updated_at,
+    DROP COLUMN is_verified;
+
+INSERT INTO product_reviews (id, product_id, user_id, rating, review, created_at)
+SELECT id, product_id, user_id, rating, review, created_at
+FROM product_reviews_superseded;
+
+DROP TABLE product_reviews_superseded;
+-- +goose StatementEnd
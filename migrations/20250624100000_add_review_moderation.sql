@@ -0,0 +1,70 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE product_reviews
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published' CHECK (status IN ('published', 'hidden')),
+    ADD COLUMN moderation_reason TEXT;
+
+ALTER TABLE seller_reviews
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published' CHECK (status IN ('published', 'hidden')),
+    ADD COLUMN moderation_reason TEXT,
+    ADD COLUMN updated_at TIMESTAMP;
+
+CREATE INDEX idx_product_reviews_product_status ON product_reviews(product_id, status);
+CREATE INDEX idx_seller_reviews_shop_status ON seller_reviews(shop_id, status);
+
+-- Пользователь с заполненным review_banned_at не может оставлять отзывы
+ALTER TABLE users
+    ADD COLUMN review_banned_at TIMESTAMP,
+    ADD COLUMN review_ban_reason TEXT;
+
+-- Жалобы на отзывы. review_type указывает таблицу отзыва, поэтому внешнего ключа на отзыв нет.
+CREATE TABLE IF NOT EXISTS review_reports (
+    id SERIAL PRIMARY KEY,
+    review_type VARCHAR(20) NOT NULL CHECK (review_type IN ('product', 'seller')),
+    review_id INT NOT NULL,
+    reporter_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    resolved_by INT REFERENCES users(id) ON DELETE SET NULL,
+    UNIQUE (review_type, review_id, reporter_id)
+);
+
+CREATE INDEX idx_review_reports_open ON review_reports(review_type, review_id) WHERE resolved_at IS NULL;
+
+-- Журнал решений модераторов
+CREATE TABLE IF NOT EXISTS review_moderation_actions (
+    id SERIAL PRIMARY KEY,
+    review_type VARCHAR(20) CHECK (review_type IN ('product', 'seller')),
+    review_id INT,
+    user_id INT REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('hide', 'restore', 'ban')),
+    reason TEXT NOT NULL,
+    moderator_id INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_review_moderation_actions_review ON review_moderation_actions(review_type, review_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS review_moderation_actions;
+DROP TABLE IF EXISTS review_reports;
+
+ALTER TABLE users
+    DROP COLUMN review_ban_reason,
+    DROP COLUMN review_banned_at;
+
+DROP INDEX IF EXISTS idx_seller_reviews_shop_status;
+DROP INDEX IF EXISTS idx_product_reviews_product_status;
+
+ALTER TABLE seller_reviews
+    DROP COLUMN updated_at,
+    DROP COLUMN moderation_reason,
+    DROP COLUMN status;
+
+ALTER TABLE product_reviews
+    DROP COLUMN moderation_reason,
+    DROP COLUMN status;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Счетчик полезности используется для сортировки отзывов по полезности
+ALTER TABLE product_reviews ADD COLUMN helpful_count INT NOT NULL DEFAULT 0;
+ALTER TABLE seller_reviews ADD COLUMN helpful_count INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_product_reviews_product_rating ON product_reviews(product_id, rating) WHERE status = 'published';
+CREATE INDEX idx_seller_reviews_shop_rating ON seller_reviews(shop_id, rating) WHERE status = 'published';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_seller_reviews_shop_rating;
+DROP INDEX IF EXISTS idx_product_reviews_product_rating;
+
+ALTER TABLE seller_reviews DROP COLUMN helpful_count;
+ALTER TABLE product_reviews DROP COLUMN helpful_count;
+-- +goose StatementEnd
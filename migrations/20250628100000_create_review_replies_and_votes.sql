@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Ответ магазина на отзыв, не больше одного на отзыв
+CREATE TABLE IF NOT EXISTS review_replies (
+    id SERIAL PRIMARY KEY,
+    review_type VARCHAR(20) NOT NULL CHECK (review_type IN ('product', 'seller')),
+    review_id INT NOT NULL,
+    shop_id INT NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    author_id INT REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (review_type, review_id)
+);
+
+-- Оценки полезности отзывов, одна на пользователя
+CREATE TABLE IF NOT EXISTS review_votes (
+    review_type VARCHAR(20) NOT NULL CHECK (review_type IN ('product', 'seller')),
+    review_id INT NOT NULL,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    helpful BOOLEAN NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (review_type, review_id, user_id)
+);
+
+ALTER TABLE product_reviews ADD COLUMN not_helpful_count INT NOT NULL DEFAULT 0;
+ALTER TABLE seller_reviews ADD COLUMN not_helpful_count INT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE seller_reviews DROP COLUMN not_helpful_count;
+ALTER TABLE product_reviews DROP COLUMN not_helpful_count;
+
+DROP TABLE IF EXISTS review_votes;
+DROP TABLE IF EXISTS review_replies;
+-- +goose StatementEnd
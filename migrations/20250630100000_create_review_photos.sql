@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Фото к отзывам о продуктах, файлы лежат в хранилище изображений
+CREATE TABLE IF NOT EXISTS review_photos (
+    id SERIAL PRIMARY KEY,
+    review_id INT NOT NULL REFERENCES product_reviews(id) ON DELETE CASCADE,
+    image_key VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_photos_review_id ON review_photos(review_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS review_photos;
+-- +goose StatementEnd
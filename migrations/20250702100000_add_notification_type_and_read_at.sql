@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Тип уведомления, связанный оффер и время прочтения
+ALTER TABLE notifications
+    ADD COLUMN IF NOT EXISTS type VARCHAR(50) NOT NULL DEFAULT 'general',
+    ADD COLUMN IF NOT EXISTS offer_id INT REFERENCES offers(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS read_at TIMESTAMP;
+
+-- Непрочитанные уведомления пользователя считаются и выбираются часто
+CREATE INDEX IF NOT EXISTS idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notifications_user_unread;
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS read_at,
+    DROP COLUMN IF EXISTS offer_id,
+    DROP COLUMN IF EXISTS type;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Настройки каналов доставки уведомлений. Хранятся только измененные пользователем,
+-- для остальных действуют значения по умолчанию
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event VARCHAR(50) NOT NULL CHECK (event IN ('offer_received', 'offer_status', 'review_posted', 'marketing')),
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('in_app', 'email', 'webhook')),
+    enabled BOOLEAN NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, event, channel)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification_preferences;
+-- +goose StatementEnd
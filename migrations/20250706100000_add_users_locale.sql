@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Язык писем пользователя
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS locale VARCHAR(2) NOT NULL DEFAULT 'en' CHECK (locale IN ('en', 'ru'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN IF EXISTS locale;
+-- +goose StatementEnd
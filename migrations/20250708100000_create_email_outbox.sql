@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Очередь исходящих писем. Письма пишутся в той же транзакции, что и бизнес-изменение,
+-- и отправляются фоновыми воркерами. Повторная запись с тем же idempotency_key игнорируется
+CREATE TABLE IF NOT EXISTS email_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    idempotency_key VARCHAR(255) UNIQUE,
+    recipient VARCHAR(255) NOT NULL,
+    template VARCHAR(50) NOT NULL,
+    locale VARCHAR(2) NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    unsubscribe_url TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'dead')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP
+);
+
+-- воркеры выбирают только письма, ожидающие отправки
+CREATE INDEX IF NOT EXISTS idx_email_outbox_pending ON email_outbox (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_email_outbox_status ON email_outbox (status, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS email_outbox;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Вебхуки магазинов. events - JSON-массив имен событий, на которые подписан вебхук.
+-- Секретом подписывается тело запроса, поэтому он хранится в открытом виде
+CREATE TABLE IF NOT EXISTS shop_webhooks (
+    id SERIAL PRIMARY KEY,
+    shop_id INT NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    events JSONB NOT NULL DEFAULT '[]',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_shop_webhooks_shop_id ON shop_webhooks (shop_id);
+
+-- Журнал доставок. Доставки пишутся в транзакции события и отправляются фоновыми воркерами,
+-- после ошибки следующая попытка назначается с экспоненциальной задержкой
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id INT NOT NULL REFERENCES shop_webhooks(id) ON DELETE CASCADE,
+    event VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    response_status INT,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP
+);
+
+-- воркеры выбирают только доставки, ожидающие отправки
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending
+    ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS shop_webhooks;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Роль пользователя для проверки прав. Тип user_role создан вместе с таблицей аудита
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role user_role NOT NULL DEFAULT 'user';
+
+UPDATE users SET role = 'shop' WHERE is_store;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN IF EXISTS role;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Дата подтверждения почты. Без нее нельзя делать офферы и оставлять отзывы
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP NULL;
+
+-- аккаунты, созданные до проверки почты, считаются подтвержденными
+UPDATE users SET email_verified_at = CURRENT_TIMESTAMP WHERE email_verified_at IS NULL;
+
+-- Токены подтверждения почты. Хранится только sha256 токена, сам токен уходит в ссылке из письма
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- по created_at ограничивается частота повторной отправки письма
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_user
+    ON email_verification_tokens(user_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS email_verification_tokens;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS email_verified_at;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Дата удаления аккаунта. Строка пользователя не удаляется: на нее ссылаются отзывы и офферы,
+-- а личные данные при удалении затираются
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Письма сброса пароля хранили токен в ключе идемпотентности и данных.
+-- Ключ заменяется хэшем токена, данные отправленных и dead писем стираются
+UPDATE email_outbox
+SET idempotency_key = 'password_reset:' || encode(sha256(convert_to(substring(idempotency_key FROM 16), 'UTF8')), 'hex')
+WHERE template = 'password_reset' AND idempotency_key LIKE 'password\_reset:%';
+
+UPDATE email_outbox
+SET data = '{}'
+WHERE template = 'password_reset' AND status IN ('sent', 'dead');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- токены не восстановить
+SELECT 1;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Письма подтверждения почты хранили ссылку с токеном в ключе идемпотентности и данных.
+-- Ключ заменяется хэшем ссылки, данные отправленных и dead писем стираются
+UPDATE email_outbox
+SET idempotency_key = 'email_verification:'
+    || encode(sha256(convert_to(substring(idempotency_key FROM 20), 'UTF8')), 'hex')
+WHERE template = 'email_verification' AND idempotency_key LIKE 'email\_verification:http%';
+
+UPDATE email_outbox
+SET data = '{}'
+WHERE template = 'email_verification' AND status IN ('sent', 'dead');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- ссылки не восстановить
+SELECT 1;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Приветствие отправлялось с ключом по адресу почты и уходило повторно после смены
+-- и подтверждения новой почты. Ключ теперь строится по ID пользователя
+UPDATE email_outbox o
+SET idempotency_key = 'registered:' || u.id
+FROM users u
+WHERE o.template = 'registered' AND o.idempotency_key = 'registered:' || u.email;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE email_outbox o
+SET idempotency_key = 'registered:' || u.email
+FROM users u
+WHERE o.template = 'registered' AND o.idempotency_key = 'registered:' || u.id;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Удаление отзыва оставляло его ответ магазина и голоса. У этих таблиц и у жалоб
+-- нет внешнего ключа на отзыв, поэтому строки без отзыва удаляются здесь
+DELETE FROM review_replies r
+WHERE NOT EXISTS (
+    SELECT 1 FROM product_reviews p WHERE r.review_type = 'product' AND p.id = r.review_id
+) AND NOT EXISTS (
+    SELECT 1 FROM seller_reviews s WHERE r.review_type = 'seller' AND s.id = r.review_id
+);
+
+DELETE FROM review_votes v
+WHERE NOT EXISTS (
+    SELECT 1 FROM product_reviews p WHERE v.review_type = 'product' AND p.id = v.review_id
+) AND NOT EXISTS (
+    SELECT 1 FROM seller_reviews s WHERE v.review_type = 'seller' AND s.id = v.review_id
+);
+
+DELETE FROM review_reports r
+WHERE NOT EXISTS (
+    SELECT 1 FROM product_reviews p WHERE r.review_type = 'product' AND p.id = r.review_id
+) AND NOT EXISTS (
+    SELECT 1 FROM seller_reviews s WHERE r.review_type = 'seller' AND s.id = r.review_id
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- удаленные строки не восстановить
+SELECT 1;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Индекс по LOWER(name) не используется поиском магазинов через ILIKE '%...%',
+-- вместо него триграммные индексы по названию и описанию
+DROP INDEX IF EXISTS idx_shops_lower_name;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_shops_name_trgm ON shops USING gin (name gin_trgm_ops);
+CREATE INDEX idx_shops_description_trgm ON shops USING gin (description gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_shops_description_trgm;
+DROP INDEX IF EXISTS idx_shops_name_trgm;
+
+CREATE INDEX idx_shops_lower_name ON shops(LOWER(name));
+-- +goose StatementEnd
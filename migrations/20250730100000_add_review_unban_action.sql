@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Снятие запрета на отзывы тоже попадает в журнал модераторов
+ALTER TABLE review_moderation_actions
+    DROP CONSTRAINT review_moderation_actions_action_check,
+    ADD CONSTRAINT review_moderation_actions_action_check
+        CHECK (action IN ('hide', 'restore', 'ban', 'unban'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM review_moderation_actions WHERE action = 'unban';
+
+ALTER TABLE review_moderation_actions
+    DROP CONSTRAINT review_moderation_actions_action_check,
+    ADD CONSTRAINT review_moderation_actions_action_check
+        CHECK (action IN ('hide', 'restore', 'ban'));
+-- +goose StatementEnd
@@ -95,6 +95,11 @@ VALUES
     ('Store 2', 4);
 
 -- Магазин продавца Store Owner 2, id=2
+INSERT INTO
+    shop_members (shop_id, user_id, role)
+VALUES
+    (1, 3, 'owner'),
+    (2, 4, 'owner');
 -- +goose StatementEnd
 -- Вставка категорий (третья транзакция)
 -- +goose StatementBegin
@@ -192,31 +197,31 @@ VALUES
 -- Вставка отзывов о продавцах (девятая транзакция)
 -- +goose StatementBegin
 INSERT INTO
-    seller_reviews (seller_id, user_id, rating, review, created_at)
+    seller_reviews (shop_id, user_id, rating, review, created_at)
 VALUES
     (
-        3,
+        1,
         1,
         5,
         'Fast delivery, great service!',
         '2025-04-20 13:00:00'
-    ), -- John Doe о Store Owner 1
+    ), -- John Doe о Store 1
     (
-        3,
+        1,
         2,
         4,
         'Good store, but communication could be improved.',
         '2025-04-20 14:00:00'
-    ), -- Jane Smith о Store Owner 1
+    ), -- Jane Smith о Store 1
     (
-        4,
+        2,
         1,
         3,
         'Average experience, shipping was slow.',
         '2025-04-20 15:00:00'
     );
 
--- John Doe о Store Owner 2
+-- John Doe о Store 2
 -- +goose StatementEnd
 -- Вставка refresh-токенов (десятая транзакция)
 -- +goose StatementBegin
@@ -272,7 +277,7 @@ WHERE
 -- +goose StatementBegin
 DELETE FROM seller_reviews
 WHERE
-    (seller_id, user_id) IN ((3, 1), (3, 2), (4, 1));
+    (shop_id, user_id) IN ((1, 1), (1, 2), (2, 1));
 
 -- +goose StatementEnd
 -- +goose StatementBegin
@@ -6,9 +6,12 @@ TRUNCATE TABLE refresh_tokens CASCADE;
 TRUNCATE TABLE image_keys CASCADE;
 TRUNCATE TABLE offers CASCADE;
 TRUNCATE TABLE shop_inventory CASCADE;
+TRUNCATE TABLE product_variants CASCADE;
 TRUNCATE TABLE product_attributes CASCADE;
 TRUNCATE TABLE products CASCADE;
 TRUNCATE TABLE categories CASCADE;
+TRUNCATE TABLE shop_invitations CASCADE;
+TRUNCATE TABLE shop_members CASCADE;
 TRUNCATE TABLE shops CASCADE;
 TRUNCATE TABLE notifications CASCADE;
 
@@ -17,13 +20,21 @@ ALTER SEQUENCE shops_id_seq RESTART WITH 1;
 ALTER SEQUENCE notifications_id_seq RESTART WITH 1;
 ALTER SEQUENCE categories_id_seq RESTART WITH 1;
 ALTER SEQUENCE products_id_seq RESTART WITH 1;
+ALTER SEQUENCE product_variants_id_seq RESTART WITH 1;
 ALTER SEQUENCE offers_id_seq RESTART WITH 1;
 
 
 -- Insert test shops (owned by store owners)
-INSERT INTO shops (name, user_id) VALUES
-                                      ('shop1 name', 1),
-                                      ('shop2 name', 2);
+INSERT INTO shops (name, user_id, description, contact_email, address, working_hours) VALUES
+    ('shop1 name', 1, 'Electronics and accessories', 'shop1@example.com', 'Moscow, Tverskaya 1',
+     '{"mon": "09:00-18:00", "tue": "09:00-18:00", "wed": "09:00-18:00", "thu": "09:00-18:00", "fri": "09:00-18:00", "sat": "closed", "sun": "closed"}'),
+    ('shop2 name', 2, 'Home goods, books and more', 'shop2@example.com', 'Saint Petersburg, Nevsky 10',
+     '{"mon": "10:00-20:00", "tue": "10:00-20:00", "wed": "10:00-20:00", "thu": "10:00-20:00", "fri": "10:00-20:00", "sat": "10:00-16:00", "sun": "closed"}');
+
+-- Shop owners are also shop members with the owner role
+INSERT INTO shop_members (shop_id, user_id, role) VALUES
+    (1, 1, 'owner'),
+    (2, 2, 'owner');
 
 -- Insert test categories (using nested set model)
 -- Seed Categories with a nested set model (lft, rgt)
@@ -75,31 +86,48 @@ INSERT INTO product_attributes (product_id, attributes) VALUES
                                                             (9, '{"material": "Mesh", "color": "Black", "weight_capacity": "300 lbs", "adjustability": ["lumbar", "armrests", "height"]}'),
                                                             (10, '{"author": "Alan A. A. Donovan, Brian W. Kernighan", "pages": 416, "format": "Paperback", "isbn": "978-0134190440"}');
 
--- Insert shop inventory (products available in shops with prices)
-INSERT INTO shop_inventory (product_id, shop_id, is_available, price, currency) VALUES
+-- Insert product variants (one default variant per product, phone and case also vary by colour/storage)
+INSERT INTO product_variants (product_id, sku, attributes) VALUES
+    (1, 'P000001', '{}'),
+    (2, 'P000002', '{}'),
+    (3, 'P000003', '{}'),
+    (4, 'P000004', '{}'),
+    (5, 'P000005', '{"storage": "256GB", "color": "Space Gray"}'),
+    (6, 'P000006', '{"color": "Black"}'),
+    (7, 'P000007', '{}'),
+    (8, 'P000008', '{}'),
+    (9, 'P000009', '{}'),
+    (10, 'P000010', '{}'),
+    (5, 'P000005-512-SLV', '{"storage": "512GB", "color": "Silver"}'), -- 11
+    (6, 'P000006-RED', '{"color": "Red"}');                            -- 12
+
+-- Insert shop inventory (product variants available in shops with prices)
+INSERT INTO shop_inventory (product_id, variant_id, shop_id, is_available, price, currency) VALUES
     -- Shop 1 (Electronics focus)
-    (1, 1, TRUE, 1500.00, 'USD'), -- SuperFast Laptop
-    (2, 1, TRUE, 2500.00, 'USD'), -- Gamer Desktop PC
-    (3, 1, TRUE, 750.00, 'USD'),  -- 4K UltraWide Monitor
-    (4, 1, TRUE, 150.00, 'USD'),  -- Mechanical Keyboard
-    (5, 1, TRUE, 999.00, 'USD'),  -- SmartyPhone X
-    (6, 1, FALSE, 40.00, 'USD'),   -- Tough Phone Case
-    (7, 1, TRUE, 50.00, 'USD'),   -- Fast Wall Charger
+    (1, 1, 1, TRUE, 1500.00, 'USD'), -- SuperFast Laptop
+    (2, 2, 1, TRUE, 2500.00, 'USD'), -- Gamer Desktop PC
+    (3, 3, 1, TRUE, 750.00, 'USD'),  -- 4K UltraWide Monitor
+    (4, 4, 1, TRUE, 150.00, 'USD'),  -- Mechanical Keyboard
+    (5, 5, 1, TRUE, 999.00, 'USD'),  -- SmartyPhone X 256GB
+    (5, 11, 1, TRUE, 1199.00, 'USD'), -- SmartyPhone X 512GB
+    (6, 6, 1, FALSE, 40.00, 'USD'),   -- Tough Phone Case
+    (7, 7, 1, TRUE, 50.00, 'USD'),   -- Fast Wall Charger
     -- Shop 2 (General / Home goods)
-    (5, 2, TRUE, 950.00, 'USD'),  -- SmartyPhone X
-    (6, 2, TRUE, 35.00, 'USD'),   -- Tough Phone Case
-    (7, 2, TRUE, 45.00, 'USD'),   -- Fast Wall Charger
-    (8, 2, TRUE, 80.00, 'USD'),   -- Non-stick Pan Set
-    (9, 2, TRUE, 250.00, 'USD'),  -- Ergonomic Office Chair
-    (10, 2, TRUE, 25.00, 'USD');  -- The Art of Go
+    (5, 5, 2, TRUE, 950.00, 'USD'),  -- SmartyPhone X 256GB
+    (6, 6, 2, TRUE, 35.00, 'USD'),   -- Tough Phone Case
+    (6, 12, 2, TRUE, 35.00, 'USD'),  -- Tough Phone Case, red
+    (7, 7, 2, TRUE, 45.00, 'USD'),   -- Fast Wall Charger
+    (8, 8, 2, TRUE, 80.00, 'USD'),   -- Non-stick Pan Set
+    (9, 9, 2, TRUE, 250.00, 'USD'),  -- Ergonomic Office Chair
+    (10, 10, 2, TRUE, 25.00, 'USD');  -- The Art of Go
 
 -- Insert test offers
-INSERT INTO offers (offer_price, currency, status, created_at, updated_at, expires_at, shop_id, user_id, product_id) VALUES
-    (1400.00, 'USD', 'pending', NOW() - INTERVAL '1 day', NOW() - INTERVAL '1 day', NOW() + INTERVAL '6 days', 1, 3, 1),
-    (700.00, 'USD', 'accepted', NOW() - INTERVAL '3 days', NOW() - INTERVAL '2 days', NOW() + INTERVAL '4 days', 1, 4, 3),
-    (30.00, 'USD', 'rejected', NOW() - INTERVAL '4 days', NOW() - INTERVAL '3 days', NOW() + INTERVAL '3 days', 2, 3, 6),
-    (20.00, 'USD', 'pending', NOW() - INTERVAL '6 hours', NOW() - INTERVAL '6 hours', NOW() + INTERVAL '7 days', 2, 4, 10),
-    (75.00, 'USD', 'pending', NOW() - INTERVAL '12 hours', NOW() - INTERVAL '12 hours', NOW() + INTERVAL '6 days', 2, 3, 8);
+INSERT INTO offers (offer_price, currency, status, created_at, updated_at, expires_at, shop_id, user_id, product_id, variant_id) VALUES
+    (1400.00, 'USD', 'pending', NOW() - INTERVAL '1 day', NOW() - INTERVAL '1 day', NOW() + INTERVAL '6 days', 1, 3, 1, 1),
+    (700.00, 'USD', 'accepted', NOW() - INTERVAL '3 days', NOW() - INTERVAL '2 days', NOW() + INTERVAL '4 days', 1, 4, 3, 3),
+    (30.00, 'USD', 'rejected', NOW() - INTERVAL '4 days', NOW() - INTERVAL '3 days', NOW() + INTERVAL '3 days', 2, 3, 6, 6),
+    (20.00, 'USD', 'pending', NOW() - INTERVAL '6 hours', NOW() - INTERVAL '6 hours', NOW() + INTERVAL '7 days', 2, 4, 10, 10),
+    (75.00, 'USD', 'pending', NOW() - INTERVAL '12 hours', NOW() - INTERVAL '12 hours', NOW() + INTERVAL '6 days', 2, 3, 8, 8);
 
 
 -- Insert test notifications
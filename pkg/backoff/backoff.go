@@ -0,0 +1,16 @@
+package backoff
+
+import "time"
+
+// Exponential задержка перед следующей попыткой после attempts неудачных: base, 2*base,
+// 4*base... не больше maxDelay
+func Exponential(attempts int, base, maxDelay time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxDelay {
+			return maxDelay
+		}
+	}
+	return delay
+}
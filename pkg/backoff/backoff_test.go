@@ -0,0 +1,18 @@
+package backoff
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("Exponential",
+	func(attempts int, expected time.Duration) {
+		Expect(Exponential(attempts, 30*time.Second, time.Hour)).To(Equal(expected))
+	},
+	Entry("first attempt", 1, 30*time.Second),
+	Entry("second attempt", 2, time.Minute),
+	Entry("third attempt", 3, 2*time.Minute),
+	Entry("capped", 20, time.Hour),
+)
@@ -2,9 +2,11 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
 	"github.com/EM-Stawberry/Stawberry/pkg/security"
 	"github.com/Masterminds/squirrel"
@@ -41,11 +43,12 @@ func SeedDB() {
 		return
 	}
 
+	// тестовые аккаунты сразу с подтвержденной почтой
 	q := squirrel.Insert("users").
-		Columns("name", "email", "phone_number", "password_hash", "is_store")
+		Columns("name", "email", "phone_number", "password_hash", "is_store", "role", "email_verified_at")
 
 	for _, u := range users {
-		q = q.Values(u.Name, u.Email, u.Phone, u.Password, u.IsStore)
+		q = q.Values(u.Name, u.Email, u.Phone, u.Password, u.IsStore, u.Role, squirrel.Expr("NOW()"))
 	}
 
 	sql, args, err := q.PlaceholderFormat(squirrel.Dollar).ToSql()
@@ -72,7 +75,9 @@ func SeedDB() {
 		return
 	}
 
-	DefaultAdminAcc()
+	if err := DefaultAdminAcc(); err != nil {
+		pkgLog.Warn("Default admin account is not created", zap.Error(err))
+	}
 }
 
 func formDefaultUsers() ([]model.User, error) {
@@ -84,13 +89,18 @@ func formDefaultUsers() ([]model.User, error) {
 			pkgLog.Error("Failed to hash password, aborting seeding", zap.Error(err))
 			return nil, err
 		}
+		isStore := strings.Contains(psw, "shop")
+		role := entity.UserRoleUser
+		if isStore {
+			role = entity.UserRoleShop
+		}
 		users = append(users, model.User{
 			Name:     psw,
 			Phone:    fmt.Sprintf("%sphone", psw),
 			Email:    fmt.Sprintf("%s@%s.com", psw, psw),
 			Password: hash,
-			IsStore:  strings.Contains(psw, "shop"),
-			// IsAdmin: false,
+			IsStore:  isStore,
+			Role:     string(role),
 		})
 	}
 	return users, nil
@@ -111,14 +121,32 @@ func ClearDB() {
 		return
 	}
 
-	DefaultAdminAcc()
+	if err := DefaultAdminAcc(); err != nil {
+		pkgLog.Warn("Default admin account is not created", zap.Error(err))
+	}
 }
 
-func DefaultAdminAcc() {
+// exampleAdminPassword пароль из прежнего .env.example, администратор с ним не создается
+const exampleAdminPassword = "default_admin_password"
+
+// ErrExampleAdminPassword DEFAULT_ADMIN_PSWD оставлен из прежнего .env.example
+var ErrExampleAdminPassword = errors.New("DEFAULT_ADMIN_PSWD is left from the example env file")
+
+// DefaultAdminAcc создает администратора admin@admin.com с паролем из DEFAULT_ADMIN_PSWD.
+// Без пароля администратор не создается, с паролем из .env.example возвращается ErrExampleAdminPassword
+func DefaultAdminAcc() error {
+	switch pkgCfg.DefAdmPswd {
+	case "":
+		pkgLog.Info("DEFAULT_ADMIN_PSWD is not set, default admin account is not created")
+		return nil
+	case exampleAdminPassword:
+		return ErrExampleAdminPassword
+	}
+
 	hash, err := security.HashArgon2id(pkgCfg.DefAdmPswd)
 	if err != nil {
 		pkgLog.Error("Failed to hash default admin password", zap.Error(err))
-		return
+		return nil
 	}
 
 	admin := model.User{
@@ -127,18 +155,20 @@ func DefaultAdminAcc() {
 		Email:    "admin@admin.com",
 		Password: hash,
 		IsStore:  false,
-		// IsAdmin: true,
+		Role:     string(entity.UserRoleAdmin),
 	}
 
-	// PLEASE ADD ADMIN FLAG WHEN POSSIBLE
+	// существующий аккаунт не меняется, поэтому перезапуск не возвращает снятую роль и старый пароль
 	q, args := squirrel.Insert("users").
-		Columns("name", "email", "phone_number", "password_hash", "is_store").
-		Values(admin.Name, admin.Email, admin.Phone, admin.Password, admin.IsStore).
+		Columns("name", "email", "phone_number", "password_hash", "is_store", "role", "email_verified_at").
+		Values(admin.Name, admin.Email, admin.Phone, admin.Password, admin.IsStore, admin.Role,
+			squirrel.Expr("NOW()")).
+		Suffix("ON CONFLICT (email) DO NOTHING").
 		PlaceholderFormat(squirrel.Dollar).MustSql()
 
 	_, err = pkgDB.Exec(q, args...)
-	if err != nil && !strings.Contains(err.Error(), "duplicate key value") {
+	if err != nil {
 		pkgLog.Error("Failed to insert default admin account", zap.Error(err))
-		return
 	}
+	return nil
 }
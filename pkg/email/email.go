@@ -2,43 +2,76 @@ package email
 
 import (
 	"context"
-	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/pkg/backoff"
 	"go.uber.org/zap"
-	"gopkg.in/gomail.v2"
 )
 
 //go:generate go.uber.org/mock/mockgen -source=$GOFILE -destination=mock_email/mock_email.go -package=mock_email
 
 type MailerService interface {
-	Registered(userName string, userMail string)
-	StatusUpdate(offerID uint, status string, userMail string)
-	OfferReceived(offerID uint, userMail string)
+	Registered(userID uint, userName string, userMail string, locale string)
+	ShopInvitation(shopName string, role string, token string, userMail string)
+	GuestOffer(offer GuestOfferData, userMail string, locale string)
+	PasswordReset(userName string, token string, validFor time.Duration, userMail string, locale string)
+	EmailVerification(userName string, verifyURL string, validFor time.Duration, userMail string, locale string)
+	EmailChanged(userName string, newEmail string, oldEmail string, locale string)
+	// Enqueue сохраняет письма в outbox. Через exec письма пишутся в транзакции бизнес-операции
+	// и уходят только после ее коммита, без exec - отдельным запросом
+	Enqueue(ctx context.Context, exec Execer, msgs ...Message) error
 	Stop(ctx context.Context)
-	SendGuestOfferNotification(email string, subject string, body string)
 }
 
-type SMTPMailer struct {
-	enabled bool
-	ctx     context.Context
-	ctxCanc context.CancelFunc
-	dialer  *gomail.Dialer
-	queue   chan *gomail.Message
-	wg      sync.WaitGroup
-	mutex   sync.Mutex
-	stopped bool
-	log     *zap.Logger
+const (
+	// sendLease на сколько письмо откладывается при выборке, чтобы его не взял другой воркер.
+	// Если процесс упадет во время отправки, письмо уйдет повторно после истечения
+	sendLease = 5 * time.Minute
+	// resultTimeout сколько ждать записи результата отправки, в том числе при остановке
+	resultTimeout = 5 * time.Second
+)
+
+// Mailer отправляет письма из outbox через выбранный транспорт. Неотправленные письма
+// остаются в базе и уходят после перезапуска
+type Mailer struct {
+	enabled      bool
+	ctx          context.Context
+	ctxCanc      context.CancelFunc
+	from         string
+	transport    Transport
+	store        OutboxStore
+	wake         chan struct{}
+	wg           sync.WaitGroup
+	batchSize    int
+	maxAttempts  int
+	pollInterval time.Duration
+	templates    *Templates
+	log          *zap.Logger
 }
 
-func NewMailer(log *zap.Logger, emailCfg *config.EmailConfig) MailerService {
+func NewMailer(
+	log *zap.Logger,
+	emailCfg *config.EmailConfig,
+	templates *Templates,
+	store OutboxStore,
+	transport Transport,
+) MailerService {
 	ctx, cancel := context.WithCancel(context.Background())
-	m := &SMTPMailer{
-		ctx:     ctx,
-		ctxCanc: cancel,
-		log:     log,
+	m := &Mailer{
+		ctx:          ctx,
+		ctxCanc:      cancel,
+		from:         emailCfg.From,
+		transport:    transport,
+		store:        store,
+		wake:         make(chan struct{}, 1),
+		batchSize:    emailCfg.BatchSize,
+		maxAttempts:  emailCfg.MaxAttempts,
+		pollInterval: emailCfg.PollInterval,
+		templates:    templates,
+		log:          log,
 	}
 
 	if !emailCfg.Enabled {
@@ -48,23 +81,18 @@ func NewMailer(log *zap.Logger, emailCfg *config.EmailConfig) MailerService {
 
 	m.enabled = true
 
-	m.dialer = gomail.NewDialer(emailCfg.SMTPHost, emailCfg.SMTPPort, emailCfg.From, emailCfg.Password)
-
-	m.log.Info("creating email queue", zap.Int("size", emailCfg.QueueSize))
-	m.queue = make(chan *gomail.Message, emailCfg.QueueSize)
-
 	m.log.Info("starting mailer workers", zap.Int("pool size", emailCfg.WorkerPool))
 	m.wg.Add(emailCfg.WorkerPool)
 	for range emailCfg.WorkerPool {
 		go m.worker()
 	}
 
-	m.log.Info("email notifications are enabled")
+	m.log.Info("email notifications are enabled", zap.String("transport", emailCfg.Transport))
 
 	return m
 }
 
-func (m *SMTPMailer) Stop(ctx context.Context) {
+func (m *Mailer) Stop(ctx context.Context) {
 	if !m.enabled {
 		m.log.Info("mailer stop called, but email is disabled")
 		return
@@ -72,10 +100,6 @@ func (m *SMTPMailer) Stop(ctx context.Context) {
 	m.log.Info("mailer is stopping")
 
 	m.ctxCanc()
-	m.mutex.Lock()
-	m.stopped = true
-	close(m.queue)
-	m.mutex.Unlock()
 
 	workersDone := make(chan struct{}, 1)
 	go func() {
@@ -85,137 +109,204 @@ func (m *SMTPMailer) Stop(ctx context.Context) {
 
 	select {
 	case <-ctx.Done():
-		m.log.Info("mailer workers forcefully stopped (timeout), messages that remained in queue are lost")
+		m.log.Info("mailer workers forcefully stopped (timeout), unsent messages stay in outbox")
 	case <-workersDone:
-		m.log.Info("email queue emptied, mailer workers stopped")
+		m.log.Info("mailer workers stopped, unsent messages stay in outbox")
 	}
 }
 
-func (m *SMTPMailer) sendEmailWithRetry(msg *gomail.Message) error {
-	const maxSendRetries = 3
-	const sendRetryDelay = time.Second
-
-	for i := 0; i < maxSendRetries; i++ {
-		if err := m.dialer.DialAndSend(msg); err != nil {
-			m.log.Error("failed to send email, retrying...",
-				zap.String("subject", msg.GetHeader("Subject")[0]),
-				zap.Int("attempt", i+1),
-				zap.Int("max_attempts", maxSendRetries),
-				zap.Error(err))
-			time.Sleep(sendRetryDelay)
-			continue
-		}
+func (m *Mailer) Enqueue(ctx context.Context, exec Execer, msgs ...Message) error {
+	if !m.enabled || len(msgs) == 0 {
 		return nil
 	}
-	return fmt.Errorf("failed to send email after %d attempts for subject: %s",
-		maxSendRetries, msg.GetHeader("Subject")[0])
+
+	if err := m.store.AddMessages(ctx, exec, msgs); err != nil {
+		return err
+	}
+
+	// будим воркер, чтобы не ждать следующего опроса
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+	return nil
 }
 
-func (m *SMTPMailer) worker() {
+func (m *Mailer) worker() {
 	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+
 	for {
+		m.drain()
+
 		select {
 		case <-m.ctx.Done():
-			for msg := range m.queue {
-				if err := m.sendEmailWithRetry(msg); err != nil {
-					m.log.Error("failed to send email during shutdown after retries", zap.Error(err))
-				}
-			}
 			return
+		case <-ticker.C:
+		case <-m.wake:
+		}
+	}
+}
 
-		case msg, ok := <-m.queue:
-			if !ok {
-				return
-			}
-			if err := m.sendEmailWithRetry(msg); err != nil {
-				m.log.Error("failed to send email after retries", zap.Error(err))
+// drain отправляет письма, пока в outbox есть готовые к отправке
+func (m *Mailer) drain() {
+	for m.ctx.Err() == nil {
+		msgs, err := m.store.ClaimMessages(m.ctx, m.batchSize, sendLease)
+		if err != nil {
+			if m.ctx.Err() == nil {
+				m.log.Error("failed to claim emails from outbox", zap.Error(err))
 			}
+			return
+		}
+		if len(msgs) == 0 {
+			return
+		}
+		for _, msg := range msgs {
+			m.process(msg)
 		}
 	}
 }
 
-func (m *SMTPMailer) enqueue(msg *gomail.Message) {
-	if !m.enabled {
+// process отправляет письмо и записывает результат. После ошибки следующая попытка назначается
+// с экспоненциальной задержкой, после maxAttempts письмо становится dead
+func (m *Mailer) process(msg OutboxMessage) {
+	log := m.log.With(zap.Int64("id", msg.ID), zap.String("template", msg.Template),
+		zap.Int("attempt", msg.Attempts))
+
+	// результат записывается и при остановке, иначе письмо уйдет повторно
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(m.ctx), resultTimeout)
+	defer cancel()
+
+	env, err := m.compose(msg)
+	if err != nil {
+		// шаблон не соберется и при следующей попытке
+		log.Error("failed to render email, moving to dead letters", zap.Error(err))
+		if err := m.store.MarkFailed(ctx, msg.ID, err.Error(), nil); err != nil {
+			log.Error("failed to mark email as dead", zap.Error(err))
+		}
 		return
 	}
 
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
-	if m.stopped {
-		m.log.Info("failed to enqueue email, mailer is stopped",
-			zap.String("subject", msg.GetHeader("Subject")[0]))
+	if err := m.transport.Send(ctx, env); err != nil {
+		var retryAt *time.Time
+		if msg.Attempts < m.maxAttempts {
+			next := time.Now().Add(backoff.Exponential(msg.Attempts, retryBaseDelay, retryMaxDelay))
+			retryAt = &next
+			log.Warn("failed to send email, will retry", zap.Time("retry_at", next), zap.Error(err))
+		} else {
+			log.Error("failed to send email, moving to dead letters", zap.Error(err))
+		}
+		if err := m.store.MarkFailed(ctx, msg.ID, err.Error(), retryAt); err != nil {
+			log.Error("failed to save email send error", zap.Error(err))
+		}
 		return
 	}
 
-	const maxRetries = 5
-	const retryDelay = 200 * time.Millisecond
-
-	for i := 0; i < maxRetries; i++ {
-		select {
-		case m.queue <- msg:
-			return
-		default:
-			if i < maxRetries-1 {
-				m.log.Warn("email queue is full, retrying...",
-					zap.String("subject", msg.GetHeader("Subject")[0]),
-					zap.Int("attempt", i+1),
-					zap.Int("max_attempts", maxRetries))
-				time.Sleep(retryDelay)
-			}
-		}
+	if err := m.store.MarkSent(ctx, msg.ID); err != nil {
+		log.Error("failed to mark email as sent", zap.Error(err))
 	}
 }
 
-func (m *SMTPMailer) StatusUpdate(offerID uint, status string, userMail string) {
-	if !m.enabled {
-		return
-	}
-
-	subject := fmt.Sprintf("Stawberry: Offer Status Update (ID %d)", offerID)
-	body := fmt.Sprintf("The status of your offer (%d) has been changed to: %s", offerID, status)
-	msg := m.createMessage(userMail, subject, body)
-
-	m.enqueue(msg)
+// Registered приветствует пользователя. Ключ строится по ID, поэтому после смены почты
+// и ее повторного подтверждения приветствие не отправляется снова
+func (m *Mailer) Registered(userID uint, userName string, userMail string, locale string) {
+	m.queue(Message{
+		IdempotencyKey: TemplateRegistered + ":" + strconv.FormatUint(uint64(userID), 10),
+		To:             userMail,
+		Template:       TemplateRegistered,
+		Locale:         locale,
+		Data:           RegisteredData{UserName: userName},
+	})
 }
 
-func (m *SMTPMailer) OfferReceived(offerID uint, userMail string) {
-	if !m.enabled {
-		return
-	}
-
-	subject := fmt.Sprintf("Stawberry: New Offer Received (ID %d)", offerID)
-	body := fmt.Sprintf("A new offer (%d) has been received", offerID)
-	msg := m.createMessage(userMail, subject, body)
-
-	m.enqueue(msg)
+func (m *Mailer) ShopInvitation(shopName string, role string, token string, userMail string) {
+	// приглашенного может еще не быть среди пользователей, поэтому язык по умолчанию
+	m.queue(Message{
+		IdempotencyKey: TemplateShopInvitation + ":" + token,
+		To:             userMail,
+		Template:       TemplateShopInvitation,
+		Locale:         DefaultLocale,
+		Data:           ShopInvitationData{ShopName: shopName, Role: role, Token: token},
+	})
 }
 
-func (m *SMTPMailer) Registered(userName string, userMail string) {
-	if !m.enabled {
-		return
-	}
+func (m *Mailer) GuestOffer(offer GuestOfferData, userMail string, locale string) {
+	m.queue(Message{To: userMail, Template: TemplateGuestOffer, Locale: locale, Data: offer})
+}
 
-	subject := "Welcome to Strawberry!"
-	body := fmt.Sprintf("Thank you for registering, %s.", userName)
-	msg := m.createMessage(userMail, subject, body)
+// PasswordReset отправляет одноразовый токен сброса пароля
+func (m *Mailer) PasswordReset(
+	userName string,
+	token string,
+	validFor time.Duration,
+	userMail string,
+	locale string,
+) {
+	m.queue(Message{
+		IdempotencyKey: secretKey(TemplatePasswordReset, token),
+		To:             userMail,
+		Template:       TemplatePasswordReset,
+		Locale:         locale,
+		Data:           PasswordResetData{UserName: userName, Token: token, ValidMinutes: int(validFor.Minutes())},
+	})
+}
 
-	m.enqueue(msg)
+// EmailVerification отправляет ссылку подтверждения почты. Ключ строится по хэшу ссылки с токеном,
+// поэтому повторная отправка с новым токеном не считается дублем
+func (m *Mailer) EmailVerification(
+	userName string,
+	verifyURL string,
+	validFor time.Duration,
+	userMail string,
+	locale string,
+) {
+	m.queue(Message{
+		IdempotencyKey: secretKey(TemplateEmailVerification, verifyURL),
+		To:             userMail,
+		Template:       TemplateEmailVerification,
+		Locale:         locale,
+		Data: EmailVerificationData{
+			UserName: userName, VerifyURL: verifyURL, ValidHours: int(validFor.Hours()),
+		},
+	})
 }
 
-func (m *SMTPMailer) createMessage(to, subject, body string) *gomail.Message {
-	msg := gomail.NewMessage()
-	msg.SetHeader("From", m.dialer.Username)
-	msg.SetHeader("To", to)
-	msg.SetHeader("Subject", subject)
-	msg.SetBody("text/plain", body)
-	return msg
+// EmailChanged сообщает на прежний адрес, что почта аккаунта изменена
+func (m *Mailer) EmailChanged(userName string, newEmail string, oldEmail string, locale string) {
+	m.queue(Message{
+		To:       oldEmail,
+		Template: TemplateEmailChanged,
+		Locale:   locale,
+		Data:     EmailChangedData{UserName: userName, NewEmail: newEmail},
+	})
 }
 
-func (m *SMTPMailer) SendGuestOfferNotification(email string, subject string, body string) {
-	if !m.enabled {
-		return
+// queue сохраняет письмо в outbox отдельным запросом. Ошибка только логируется,
+// письмо не должно откатывать уже выполненное действие
+func (m *Mailer) queue(msg Message) {
+	if err := m.Enqueue(context.Background(), nil, msg); err != nil {
+		m.log.Error("failed to enqueue email", zap.String("template", msg.Template), zap.Error(err))
 	}
+}
 
-	msg := m.createMessage(email, subject, body)
-	m.enqueue(msg)
+// compose собирает письмо из сохраненных шаблона и данных
+func (m *Mailer) compose(msg OutboxMessage) (Envelope, error) {
+	data, err := decodeData(msg.Template, msg.Data)
+	if err != nil {
+		return Envelope{}, err
+	}
+	rendered, err := m.templates.Render(msg.Template, msg.Locale, data, msg.UnsubscribeURL)
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{
+		From:           m.from,
+		To:             msg.To,
+		Subject:        rendered.Subject,
+		Text:           rendered.Text,
+		HTML:           rendered.HTML,
+		UnsubscribeURL: msg.UnsubscribeURL,
+	}, nil
 }
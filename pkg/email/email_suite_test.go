@@ -0,0 +1,13 @@
+package email_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestEmail(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Email Suite")
+}
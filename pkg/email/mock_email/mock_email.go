@@ -12,7 +12,9 @@ package mock_email
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	email "github.com/EM-Stawberry/Stawberry/pkg/email"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,52 +42,95 @@ func (m *MockMailerService) EXPECT() *MockMailerServiceMockRecorder {
 	return m.recorder
 }
 
-// OfferReceived mocks base method.
-func (m *MockMailerService) OfferReceived(offerID uint, userMail string) {
+// EmailChanged mocks base method.
+func (m *MockMailerService) EmailChanged(userName, newEmail, oldEmail, locale string) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "OfferReceived", offerID, userMail)
+	m.ctrl.Call(m, "EmailChanged", userName, newEmail, oldEmail, locale)
 }
 
-// OfferReceived indicates an expected call of OfferReceived.
-func (mr *MockMailerServiceMockRecorder) OfferReceived(offerID, userMail any) *gomock.Call {
+// EmailChanged indicates an expected call of EmailChanged.
+func (mr *MockMailerServiceMockRecorder) EmailChanged(userName, newEmail, oldEmail, locale any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OfferReceived", reflect.TypeOf((*MockMailerService)(nil).OfferReceived), offerID, userMail)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailChanged", reflect.TypeOf((*MockMailerService)(nil).EmailChanged), userName, newEmail, oldEmail, locale)
 }
 
-// Registered mocks base method.
-func (m *MockMailerService) Registered(userName, userMail string) {
+// EmailVerification mocks base method.
+func (m *MockMailerService) EmailVerification(userName, verifyURL string, validFor time.Duration, userMail, locale string) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Registered", userName, userMail)
+	m.ctrl.Call(m, "EmailVerification", userName, verifyURL, validFor, userMail, locale)
 }
 
-// Registered indicates an expected call of Registered.
-func (mr *MockMailerServiceMockRecorder) Registered(userName, userMail any) *gomock.Call {
+// EmailVerification indicates an expected call of EmailVerification.
+func (mr *MockMailerServiceMockRecorder) EmailVerification(userName, verifyURL, validFor, userMail, locale any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailVerification", reflect.TypeOf((*MockMailerService)(nil).EmailVerification), userName, verifyURL, validFor, userMail, locale)
+}
+
+// Enqueue mocks base method.
+func (m *MockMailerService) Enqueue(ctx context.Context, exec email.Execer, msgs ...email.Message) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, exec}
+	for _, a := range msgs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Enqueue", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockMailerServiceMockRecorder) Enqueue(ctx, exec any, msgs ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, exec}, msgs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockMailerService)(nil).Enqueue), varargs...)
+}
+
+// GuestOffer mocks base method.
+func (m *MockMailerService) GuestOffer(offer email.GuestOfferData, userMail, locale string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GuestOffer", offer, userMail, locale)
+}
+
+// GuestOffer indicates an expected call of GuestOffer.
+func (mr *MockMailerServiceMockRecorder) GuestOffer(offer, userMail, locale any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Registered", reflect.TypeOf((*MockMailerService)(nil).Registered), userName, userMail)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GuestOffer", reflect.TypeOf((*MockMailerService)(nil).GuestOffer), offer, userMail, locale)
 }
 
-// SendGuestOfferNotification mocks base method.
-func (m *MockMailerService) SendGuestOfferNotification(email, subject, body string) {
+// PasswordReset mocks base method.
+func (m *MockMailerService) PasswordReset(userName, token string, validFor time.Duration, userMail, locale string) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "SendGuestOfferNotification", email, subject, body)
+	m.ctrl.Call(m, "PasswordReset", userName, token, validFor, userMail, locale)
+}
+
+// PasswordReset indicates an expected call of PasswordReset.
+func (mr *MockMailerServiceMockRecorder) PasswordReset(userName, token, validFor, userMail, locale any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PasswordReset", reflect.TypeOf((*MockMailerService)(nil).PasswordReset), userName, token, validFor, userMail, locale)
 }
 
-// SendGuestOfferNotification indicates an expected call of SendGuestOfferNotification.
-func (mr *MockMailerServiceMockRecorder) SendGuestOfferNotification(email, subject, body any) *gomock.Call {
+// Registered mocks base method.
+func (m *MockMailerService) Registered(userID uint, userName, userMail, locale string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Registered", userID, userName, userMail, locale)
+}
+
+// Registered indicates an expected call of Registered.
+func (mr *MockMailerServiceMockRecorder) Registered(userID, userName, userMail, locale any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendGuestOfferNotification", reflect.TypeOf((*MockMailerService)(nil).SendGuestOfferNotification), email, subject, body)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Registered", reflect.TypeOf((*MockMailerService)(nil).Registered), userID, userName, userMail, locale)
 }
 
-// StatusUpdate mocks base method.
-func (m *MockMailerService) StatusUpdate(offerID uint, status, userMail string) {
+// ShopInvitation mocks base method.
+func (m *MockMailerService) ShopInvitation(shopName, role, token, userMail string) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "StatusUpdate", offerID, status, userMail)
+	m.ctrl.Call(m, "ShopInvitation", shopName, role, token, userMail)
 }
 
-// StatusUpdate indicates an expected call of StatusUpdate.
-func (mr *MockMailerServiceMockRecorder) StatusUpdate(offerID, status, userMail any) *gomock.Call {
+// ShopInvitation indicates an expected call of ShopInvitation.
+func (mr *MockMailerServiceMockRecorder) ShopInvitation(shopName, role, token, userMail any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusUpdate", reflect.TypeOf((*MockMailerService)(nil).StatusUpdate), offerID, status, userMail)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShopInvitation", reflect.TypeOf((*MockMailerService)(nil).ShopInvitation), shopName, role, token, userMail)
 }
 
 // Stop mocks base method.
@@ -0,0 +1,129 @@
+package email
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// Статусы писем в outbox
+const (
+	StatusPending = "pending"
+	StatusSent    = "sent"
+	// StatusDead письмо не удалось отправить за все попытки, повторить можно только вручную
+	StatusDead = "dead"
+)
+
+var Statuses = []string{StatusPending, StatusSent, StatusDead}
+
+// SecretTemplates письма с токенами, дающими доступ к аккаунту. Их данные стираются
+// после отправки и не показываются в API outbox, а ключ идемпотентности строится по хэшу токена
+var SecretTemplates = []string{TemplatePasswordReset, TemplateEmailVerification}
+
+// redactedData подставляется вместо данных письма с токеном
+var redactedData = json.RawMessage(`{"redacted":true}`)
+
+// Message письмо, которое нужно отправить. Собирается из шаблона при отправке
+type Message struct {
+	// IdempotencyKey защищает от повторной записи одного и того же письма, пустой - без защиты
+	IdempotencyKey string
+	To             string
+	Template       string
+	Locale         string
+	Data           any
+	UnsubscribeURL string
+}
+
+// OutboxMessage письмо, сохраненное в outbox
+type OutboxMessage struct {
+	ID             int64           `json:"id"`
+	IdempotencyKey string          `json:"idempotency_key,omitempty"`
+	To             string          `json:"to"`
+	Template       string          `json:"template"`
+	Locale         string          `json:"locale"`
+	Data           json.RawMessage `json:"data"`
+	UnsubscribeURL string          `json:"unsubscribe_url,omitempty"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	SentAt         *time.Time      `json:"sent_at,omitempty"`
+}
+
+// Redacted возвращает письмо без данных, если в них есть токен
+func (m OutboxMessage) Redacted() OutboxMessage {
+	if slices.Contains(SecretTemplates, m.Template) {
+		m.Data = redactedData
+	}
+	return m
+}
+
+// secretKey ключ идемпотентности письма с токеном. В outbox попадает только хэш токена
+func secretKey(template, secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return template + ":" + hex.EncodeToString(sum[:])
+}
+
+// Execer выполняет запрос. Им может быть как база, так и транзакция бизнес-операции
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// OutboxStore хранилище исходящих писем
+type OutboxStore interface {
+	// AddMessages сохраняет письма через exec, если он передан, иначе отдельным запросом
+	AddMessages(ctx context.Context, exec Execer, msgs []Message) error
+	// ClaimMessages выбирает готовые к отправке письма и откладывает их на lease,
+	// чтобы их не взял другой воркер. Счетчик попыток увеличивается
+	ClaimMessages(ctx context.Context, limit int, lease time.Duration) ([]OutboxMessage, error)
+	MarkSent(ctx context.Context, id int64) error
+	// MarkFailed сохраняет ошибку и назначает следующую попытку, без retryAt письмо становится dead
+	MarkFailed(ctx context.Context, id int64, lastError string, retryAt *time.Time) error
+}
+
+// задержка перед повторной отправкой: 30s, 1m, 2m... не больше часа
+const (
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = time.Hour
+)
+
+// OfferReceivedMessage письмо магазину о новом оффере
+func OfferReceivedMessage(data OfferReceivedData, to, locale, unsubscribeURL string) Message {
+	return Message{
+		To: to, Template: TemplateOfferReceived, Locale: locale, Data: data, UnsubscribeURL: unsubscribeURL,
+	}
+}
+
+// StatusUpdateMessage письмо о смене статуса оффера
+func StatusUpdateMessage(data StatusUpdateData, to, locale, unsubscribeURL string) Message {
+	return Message{
+		To: to, Template: TemplateStatusUpdate, Locale: locale, Data: data, UnsubscribeURL: unsubscribeURL,
+	}
+}
+
+// OfferCancelledMessage письмо магазину об оффере, отмененном покупателем
+func OfferCancelledMessage(data OfferCancelledData, to, locale, unsubscribeURL string) Message {
+	return Message{
+		To: to, Template: TemplateOfferCancelled, Locale: locale, Data: data, UnsubscribeURL: unsubscribeURL,
+	}
+}
+
+// decodeData восстанавливает данные шаблона из JSON в тип, который ожидает шаблон
+func decodeData(template string, raw json.RawMessage) (any, error) {
+	s, ok := samples[template]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %q", template)
+	}
+	data := reflect.New(reflect.TypeOf(s.data))
+	if err := json.Unmarshal(raw, data.Interface()); err != nil {
+		return nil, fmt.Errorf("decode %s data: %w", template, err)
+	}
+	return data.Elem().Interface(), nil
+}
@@ -0,0 +1,179 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+// fakeStore запоминает результаты отправки
+type fakeStore struct {
+	added   []Message
+	sent    []int64
+	failed  map[int64]string
+	retryAt map[int64]*time.Time
+}
+
+func (s *fakeStore) AddMessages(_ context.Context, _ Execer, msgs []Message) error {
+	s.added = append(s.added, msgs...)
+	return nil
+}
+
+func (s *fakeStore) ClaimMessages(context.Context, int, time.Duration) ([]OutboxMessage, error) {
+	return nil, nil
+}
+
+func (s *fakeStore) MarkSent(_ context.Context, id int64) error {
+	s.sent = append(s.sent, id)
+	return nil
+}
+
+func (s *fakeStore) MarkFailed(_ context.Context, id int64, lastError string, retryAt *time.Time) error {
+	s.failed[id] = lastError
+	s.retryAt[id] = retryAt
+	return nil
+}
+
+// flakyTransport отвечает заданной ошибкой, а без нее передает письмо дальше
+type flakyTransport struct {
+	*CaptureTransport
+	err error
+}
+
+func (t *flakyTransport) Send(ctx context.Context, env Envelope) error {
+	if t.err != nil {
+		return t.err
+	}
+	return t.CaptureTransport.Send(ctx, env)
+}
+
+var _ = Describe("Outbox worker", func() {
+	var (
+		store     *fakeStore
+		mailer    *Mailer
+		transport *flakyTransport
+		msg       OutboxMessage
+	)
+
+	BeforeEach(func() {
+		templates, err := NewTemplates()
+		Expect(err).NotTo(HaveOccurred())
+
+		store = &fakeStore{failed: map[int64]string{}, retryAt: map[int64]*time.Time{}}
+		transport = &flakyTransport{CaptureTransport: NewCaptureTransport()}
+		mailer = &Mailer{
+			ctx:         context.Background(),
+			from:        "shop@example.com",
+			transport:   transport,
+			store:       store,
+			maxAttempts: 3,
+			templates:   templates,
+			log:         zap.NewNop(),
+		}
+		data, err := json.Marshal(StatusUpdateData{OfferID: 42, Status: "declined"})
+		Expect(err).NotTo(HaveOccurred())
+		msg = OutboxMessage{
+			ID: 1, To: "buyer@example.com", Template: TemplateStatusUpdate, Locale: LocaleEN,
+			Data: data, UnsubscribeURL: "https://example.com/u?token=t", Attempts: 1,
+		}
+	})
+
+	It("should render the stored message and mark it as sent", func() {
+		mailer.process(msg)
+
+		Expect(store.sent).To(Equal([]int64{1}))
+		delivered := transport.Messages("buyer@example.com")
+		Expect(delivered).To(HaveLen(1))
+		Expect(delivered[0].From).To(Equal("shop@example.com"))
+		Expect(delivered[0].Subject).To(ContainSubstring("42"))
+		Expect(delivered[0].Text).To(ContainSubstring("declined"))
+		Expect(delivered[0].UnsubscribeURL).To(Equal("https://example.com/u?token=t"))
+	})
+
+	It("should schedule a retry with backoff when sending fails", func() {
+		transport.err = errors.New("connection refused")
+		msg.Attempts = 2
+
+		before := time.Now()
+		mailer.process(msg)
+
+		Expect(store.sent).To(BeEmpty())
+		Expect(store.failed[1]).To(Equal("connection refused"))
+		Expect(store.retryAt[1]).NotTo(BeNil())
+		Expect(*store.retryAt[1]).To(BeTemporally(">=", before.Add(time.Minute)))
+	})
+
+	It("should move the message to dead letters after the last attempt", func() {
+		transport.err = errors.New("mailbox unavailable")
+		msg.Attempts = 3
+
+		mailer.process(msg)
+
+		Expect(store.failed).To(HaveKey(int64(1)))
+		Expect(store.retryAt[1]).To(BeNil())
+	})
+
+	It("should not retry a message that cannot be rendered", func() {
+		msg.Template = "unknown"
+
+		mailer.process(msg)
+
+		Expect(transport.Messages("")).To(BeEmpty())
+		Expect(store.failed[1]).To(ContainSubstring("unknown"))
+		Expect(store.retryAt[1]).To(BeNil())
+	})
+
+	It("should key the welcome email by the user so a new address is not welcomed again", func() {
+		mailer.enabled = true
+		mailer.wake = make(chan struct{}, 1)
+
+		mailer.Registered(7, "Anna", "anna@example.com", LocaleEN)
+		mailer.Registered(7, "Anna", "anna@example.org", LocaleEN)
+
+		Expect(store.added).To(HaveLen(2))
+		Expect(store.added[0].IdempotencyKey).To(Equal(TemplateRegistered + ":7"))
+		Expect(store.added[1].IdempotencyKey).To(Equal(store.added[0].IdempotencyKey))
+	})
+
+	Context("messages with account tokens", func() {
+		It("should keep only the token hash in the idempotency key", func() {
+			mailer.enabled = true
+			mailer.wake = make(chan struct{}, 1)
+
+			mailer.PasswordReset("Anna", "raw-token", time.Hour, "anna@example.com", LocaleEN)
+
+			Expect(store.added).To(HaveLen(1))
+			Expect(store.added[0].IdempotencyKey).To(HavePrefix(TemplatePasswordReset + ":"))
+			Expect(store.added[0].IdempotencyKey).NotTo(ContainSubstring("raw-token"))
+		})
+
+		It("should not store the verification link in the idempotency key", func() {
+			mailer.enabled = true
+			mailer.wake = make(chan struct{}, 1)
+
+			mailer.EmailVerification("Anna", "https://example.com/verify?token=raw-token", 24*time.Hour,
+				"anna@example.com", LocaleEN)
+
+			Expect(store.added).To(HaveLen(1))
+			Expect(store.added[0].IdempotencyKey).NotTo(ContainSubstring("raw-token"))
+			data, err := json.Marshal(store.added[0].Data)
+			Expect(err).NotTo(HaveOccurred())
+			verification := OutboxMessage{Template: TemplateEmailVerification, Data: data}
+			Expect(string(verification.Redacted().Data)).NotTo(ContainSubstring("raw-token"))
+		})
+
+		It("should redact the token from the stored data", func() {
+			data, err := json.Marshal(PasswordResetData{UserName: "Anna", Token: "raw-token", ValidMinutes: 60})
+			Expect(err).NotTo(HaveOccurred())
+			reset := OutboxMessage{ID: 2, Template: TemplatePasswordReset, Data: data}
+
+			Expect(string(reset.Redacted().Data)).NotTo(ContainSubstring("raw-token"))
+			Expect(msg.Redacted().Data).To(Equal(msg.Data))
+		})
+	})
+})
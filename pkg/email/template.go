@@ -0,0 +1,238 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"slices"
+	"sort"
+	texttemplate "text/template"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Языки писем
+const (
+	LocaleEN      = "en"
+	LocaleRU      = "ru"
+	DefaultLocale = LocaleEN
+)
+
+var Locales = []string{LocaleEN, LocaleRU}
+
+// Имена шаблонов писем
+const (
+	TemplateRegistered     = "registered"
+	TemplateOfferReceived  = "offer_received"
+	TemplateStatusUpdate   = "status_update"
+	TemplateOfferCancelled = "offer_cancelled"
+	TemplateShopInvitation = "shop_invitation"
+	TemplateGuestOffer     = "guest_offer"
+	TemplatePasswordReset  = "password_reset"
+	// TemplateEmailVerification ссылка подтверждения почты после регистрации
+	TemplateEmailVerification = "email_verification"
+	// TemplateEmailChanged уведомление на прежний адрес о смене почты
+	TemplateEmailChanged = "email_changed"
+)
+
+type RegisteredData struct {
+	UserName string
+}
+
+type OfferReceivedData struct {
+	OfferID     uint
+	ProductName string
+	VariantSKU  string
+	ShopName    string
+	Price       float64
+	Currency    string
+}
+
+type StatusUpdateData struct {
+	OfferID uint
+	// Status accepted, declined, cancelled или expired
+	Status      string
+	ProductName string
+	ShopName    string
+}
+
+type OfferCancelledData struct {
+	OfferID     uint
+	ProductName string
+	VariantSKU  string
+	ShopName    string
+	Price       float64
+	Currency    string
+}
+
+type ShopInvitationData struct {
+	ShopName string
+	Role     string
+	Token    string
+}
+
+type PasswordResetData struct {
+	UserName     string
+	Token        string
+	ValidMinutes int
+}
+
+type EmailVerificationData struct {
+	UserName   string
+	VerifyURL  string
+	ValidHours int
+}
+
+type EmailChangedData struct {
+	UserName string
+	NewEmail string
+}
+
+type GuestOfferData struct {
+	ProductID  uint
+	StoreID    uint
+	Price      float64
+	Currency   string
+	GuestName  string
+	GuestEmail string
+	GuestPhone string
+}
+
+type sample struct {
+	data any
+	// unsubscribe письма о событиях, от которых можно отписаться
+	unsubscribe bool
+}
+
+// samples данные для предпросмотра и golden-тестов. Каждый шаблон должен быть здесь
+var samples = map[string]sample{
+	TemplateRegistered: {data: RegisteredData{UserName: "Anna"}},
+	TemplateOfferReceived: {data: OfferReceivedData{
+		OfferID: 42, ProductName: "Strawberry jam", VariantSKU: "JAM-250", ShopName: "Berry Shop",
+		Price: 350, Currency: "RUB",
+	}, unsubscribe: true},
+	TemplateStatusUpdate: {data: StatusUpdateData{
+		OfferID: 42, Status: "accepted", ProductName: "Strawberry jam", ShopName: "Berry Shop",
+	}, unsubscribe: true},
+	TemplateOfferCancelled: {data: OfferCancelledData{
+		OfferID: 42, ProductName: "Strawberry jam", VariantSKU: "JAM-250", ShopName: "Berry Shop",
+		Price: 350, Currency: "RUB",
+	}, unsubscribe: true},
+	TemplateShopInvitation: {data: ShopInvitationData{
+		ShopName: "Berry Shop", Role: "manager", Token: "3f2b9c4e-6a1d-4c8e-9b7a-2d5e8f1a0c3b",
+	}},
+	TemplateGuestOffer: {data: GuestOfferData{
+		ProductID: 7, StoreID: 3, Price: 1250.5, Currency: "RUB",
+		GuestName: "Ivan", GuestEmail: "ivan@example.com", GuestPhone: "+79990000000",
+	}},
+	TemplatePasswordReset: {data: PasswordResetData{
+		UserName: "Anna", Token: "9c1e4b7a2f6d8e3c5a0b1d4f7e2c9a6b", ValidMinutes: 60,
+	}},
+	TemplateEmailVerification: {data: EmailVerificationData{
+		UserName: "Anna", VerifyURL: "https://example.com/api/v1/auth/email/verify?token=preview", ValidHours: 24,
+	}},
+	TemplateEmailChanged: {data: EmailChangedData{UserName: "Anna", NewEmail: "anna@example.com"}},
+}
+
+// Rendered готовое письмо: тема, текстовая и HTML версии
+type Rendered struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// view данные, которые получают шаблоны. Данные конкретного письма лежат в Data
+type view struct {
+	Locale         string
+	Subject        string
+	UnsubscribeURL string
+	Data           any
+}
+
+type localized struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// Templates реестр шаблонов писем. Шаблон письма - пара файлов templates/<язык>/<имя>.html и .txt,
+// тема задается блоком subject в текстовом файле, общая разметка - в templates/layout.*
+type Templates struct {
+	byName map[string]map[string]localized
+}
+
+// NewTemplates разбирает встроенные шаблоны и проверяет, что у каждого есть все языки
+func NewTemplates() (*Templates, error) {
+	t := &Templates{byName: make(map[string]map[string]localized, len(samples))}
+	for name := range samples {
+		t.byName[name] = make(map[string]localized, len(Locales))
+		for _, locale := range Locales {
+			common := "templates/" + locale + "/common.tmpl"
+			base := "templates/" + locale + "/" + name
+
+			html, err := htmltemplate.ParseFS(templateFS, "templates/layout.html", common, base+".html")
+			if err != nil {
+				return nil, fmt.Errorf("parse %s.html: %w", base, err)
+			}
+			text, err := texttemplate.ParseFS(templateFS, "templates/layout.txt", common, base+".txt")
+			if err != nil {
+				return nil, fmt.Errorf("parse %s.txt: %w", base, err)
+			}
+			if text.Lookup("subject") == nil {
+				return nil, fmt.Errorf("%s.txt: subject is not defined", base)
+			}
+			t.byName[name][locale] = localized{html: html, text: text}
+		}
+	}
+	return t, nil
+}
+
+// Names возвращает имена всех шаблонов
+func (t *Templates) Names() []string {
+	names := make([]string, 0, len(t.byName))
+	for name := range t.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render собирает письмо. Для неизвестного языка используется язык по умолчанию,
+// ссылка отписки добавляется в подвал, если передана
+func (t *Templates) Render(name, locale string, data any, unsubscribeURL string) (Rendered, error) {
+	byLocale, ok := t.byName[name]
+	if !ok {
+		return Rendered{}, fmt.Errorf("unknown email template %q", name)
+	}
+	if !slices.Contains(Locales, locale) {
+		locale = DefaultLocale
+	}
+	tmpl := byLocale[locale]
+
+	v := view{Locale: locale, UnsubscribeURL: unsubscribeURL, Data: data}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", v); err != nil {
+		return Rendered{}, fmt.Errorf("render %s subject: %w", name, err)
+	}
+	v.Subject = subject.String()
+	if err := tmpl.text.ExecuteTemplate(&text, "layout", v); err != nil {
+		return Rendered{}, fmt.Errorf("render %s text: %w", name, err)
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "layout", v); err != nil {
+		return Rendered{}, fmt.Errorf("render %s html: %w", name, err)
+	}
+
+	return Rendered{Subject: v.Subject, Text: text.String(), HTML: html.String()}, nil
+}
+
+// Preview собирает письмо на примере данных
+func (t *Templates) Preview(name, locale string) (Rendered, error) {
+	s := samples[name]
+	var unsubscribeURL string
+	if s.unsubscribe {
+		unsubscribeURL = "https://example.com/unsubscribe?token=preview"
+	}
+	return t.Render(name, locale, s.data, unsubscribeURL)
+}
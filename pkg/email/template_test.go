@@ -0,0 +1,90 @@
+package email_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// после намеренного изменения шаблонов: go test ./pkg/email -args -update
+var update = flag.Bool("update", false, "rewrite golden files")
+
+var _ = Describe("Templates", func() {
+	var templates *email.Templates
+
+	BeforeEach(func() {
+		var err error
+		templates, err = email.NewTemplates()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should match the golden files for every template and locale", func() {
+		for _, name := range templates.Names() {
+			for _, locale := range email.Locales {
+				rendered, err := templates.Preview(name, locale)
+				Expect(err).NotTo(HaveOccurred(), "%s/%s", name, locale)
+
+				base := filepath.Join("testdata", "golden", name+"."+locale)
+				assertGolden(base+".txt", "Subject: "+rendered.Subject+"\n\n"+rendered.Text)
+				assertGolden(base+".html", rendered.HTML)
+			}
+		}
+	})
+
+	It("should fall back to the default locale", func() {
+		data := email.RegisteredData{UserName: "Anna"}
+
+		unknown, err := templates.Render(email.TemplateRegistered, "de", data, "")
+		Expect(err).NotTo(HaveOccurred())
+		def, err := templates.Render(email.TemplateRegistered, email.DefaultLocale, data, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(unknown).To(Equal(def))
+	})
+
+	It("should escape user data in html", func() {
+		rendered, err := templates.Render(email.TemplateRegistered, email.LocaleEN,
+			email.RegisteredData{UserName: "<script>alert(1)</script>"}, "")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rendered.HTML).NotTo(ContainSubstring("<script>"))
+		Expect(rendered.Text).To(ContainSubstring("<script>"))
+	})
+
+	It("should add the unsubscribe link only when it is given", func() {
+		data := email.OfferReceivedData{OfferID: 1}
+
+		with, err := templates.Render(email.TemplateOfferReceived, email.LocaleEN, data, "https://example.com/u?t=1")
+		Expect(err).NotTo(HaveOccurred())
+		without, err := templates.Render(email.TemplateOfferReceived, email.LocaleEN, data, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(with.Text).To(ContainSubstring("https://example.com/u?t=1"))
+		Expect(with.HTML).To(ContainSubstring(`href="https://example.com/u?t=1"`))
+		Expect(without.Text).NotTo(ContainSubstring("Unsubscribe"))
+		Expect(without.HTML).NotTo(ContainSubstring("Unsubscribe"))
+	})
+
+	It("should reject an unknown template", func() {
+		_, err := templates.Render("unknown", email.LocaleEN, nil, "")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+func assertGolden(path, actual string) {
+	GinkgoHelper()
+
+	if *update {
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(actual), 0o600)).To(Succeed())
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	Expect(err).NotTo(HaveOccurred(), "golden file %s is missing, run with -update", path)
+	Expect(actual).To(Equal(string(expected)), "rendered email differs from %s", path)
+}
@@ -0,0 +1,170 @@
+package email
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"gopkg.in/gomail.v2"
+)
+
+// Транспорты доставки писем
+const (
+	TransportSMTP    = "smtp"
+	TransportMaildir = "maildir"
+	// TransportMemory хранит письма в памяти процесса, для разработки и тестов
+	TransportMemory = "memory"
+)
+
+// Envelope собранное письмо, готовое к доставке
+type Envelope struct {
+	From           string `json:"from"`
+	To             string `json:"to"`
+	Subject        string `json:"subject"`
+	Text           string `json:"text"`
+	HTML           string `json:"html"`
+	UnsubscribeURL string `json:"unsubscribe_url,omitempty"`
+}
+
+// message собирает MIME-сообщение с текстовой и HTML версиями
+func (e Envelope) message() *gomail.Message {
+	msg := gomail.NewMessage()
+	msg.SetHeader("From", e.From)
+	msg.SetHeader("To", e.To)
+	msg.SetHeader("Subject", e.Subject)
+	if e.UnsubscribeURL != "" {
+		// отписка в один клик из почтового клиента (RFC 8058)
+		msg.SetHeader("List-Unsubscribe", "<"+e.UnsubscribeURL+">")
+		msg.SetHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+	msg.SetBody("text/plain", e.Text)
+	msg.AddAlternative("text/html", e.HTML)
+	return msg
+}
+
+// Transport доставляет собранные письма
+type Transport interface {
+	Send(ctx context.Context, env Envelope) error
+}
+
+// NewTransport создает транспорт, выбранный в конфигурации
+func NewTransport(emailCfg *config.EmailConfig) (Transport, error) {
+	switch emailCfg.Transport {
+	case TransportSMTP, "":
+		return NewSMTPTransport(emailCfg), nil
+	case TransportMaildir:
+		return NewMaildirTransport(emailCfg.MaildirDir)
+	case TransportMemory:
+		return NewCaptureTransport(), nil
+	default:
+		return nil, fmt.Errorf("unknown email transport %q", emailCfg.Transport)
+	}
+}
+
+type SMTPTransport struct {
+	dialer *gomail.Dialer
+}
+
+func NewSMTPTransport(emailCfg *config.EmailConfig) *SMTPTransport {
+	return &SMTPTransport{
+		dialer: gomail.NewDialer(emailCfg.SMTPHost, emailCfg.SMTPPort, emailCfg.From, emailCfg.Password),
+	}
+}
+
+func (t *SMTPTransport) Send(_ context.Context, env Envelope) error {
+	return t.dialer.DialAndSend(env.message())
+}
+
+// MaildirTransport складывает письма файлами в каталог формата Maildir,
+// их можно открыть любым почтовым клиентом
+type MaildirTransport struct {
+	dir string
+}
+
+// NewMaildirTransport создает каталоги tmp, new и cur, если их еще нет
+func NewMaildirTransport(dir string) (*MaildirTransport, error) {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("create maildir: %w", err)
+		}
+	}
+	return &MaildirTransport{dir: dir}, nil
+}
+
+// Send пишет письмо в tmp и переносит в new, чтобы читатель не увидел недописанный файл
+func (t *MaildirTransport) Send(_ context.Context, env Envelope) error {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	host, _ := os.Hostname()
+	name := fmt.Sprintf("%d.%s.%s", time.Now().UnixNano(), hex.EncodeToString(suffix),
+		strings.ReplaceAll(host, "/", "_"))
+
+	tmp := filepath.Join(t.dir, "tmp", name)
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := env.message().WriteTo(f); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(t.dir, "new", name))
+}
+
+// CapturedMessage письмо, перехваченное CaptureTransport
+type CapturedMessage struct {
+	Envelope
+	SentAt time.Time `json:"sent_at"`
+}
+
+// CaptureTransport ничего не отправляет, а запоминает письма в памяти,
+// чтобы их можно было посмотреть через /dev/mailbox или проверить в тестах
+type CaptureTransport struct {
+	mu       sync.Mutex
+	messages []CapturedMessage
+}
+
+func NewCaptureTransport() *CaptureTransport {
+	return &CaptureTransport{}
+}
+
+func (t *CaptureTransport) Send(_ context.Context, env Envelope) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.messages = append(t.messages, CapturedMessage{Envelope: env, SentAt: time.Now()})
+	return nil
+}
+
+// Messages возвращает письма в порядке отправки. Если to не пустой, только письма этому адресату
+func (t *CaptureTransport) Messages(to string) []CapturedMessage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	messages := make([]CapturedMessage, 0, len(t.messages))
+	for _, msg := range t.messages {
+		if to == "" || strings.EqualFold(msg.To, to) {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+func (t *CaptureTransport) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.messages = nil
+}
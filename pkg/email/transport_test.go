@@ -0,0 +1,57 @@
+package email_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Transports", func() {
+	env := email.Envelope{
+		From: "shop@example.com", To: "buyer@example.com", Subject: "Offer 42",
+		Text: "plain body", HTML: "<p>html body</p>", UnsubscribeURL: "https://example.com/u?token=t",
+	}
+
+	It("should write a complete message into maildir new", func() {
+		dir := GinkgoT().TempDir()
+		transport, err := email.NewMaildirTransport(dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(transport.Send(context.Background(), env)).To(Succeed())
+
+		files, err := os.ReadDir(filepath.Join(dir, "new"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+		Expect(os.ReadDir(filepath.Join(dir, "tmp"))).To(BeEmpty())
+
+		raw, err := os.ReadFile(filepath.Join(dir, "new", files[0].Name()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(raw)).To(ContainSubstring("To: buyer@example.com"))
+		Expect(string(raw)).To(ContainSubstring("List-Unsubscribe: <https://example.com/u?token=t>"))
+		Expect(string(raw)).To(ContainSubstring("plain body"))
+	})
+
+	It("should capture messages and filter them by recipient", func() {
+		transport := email.NewCaptureTransport()
+		Expect(transport.Send(context.Background(), env)).To(Succeed())
+		other := env
+		other.To = "owner@example.com"
+		Expect(transport.Send(context.Background(), other)).To(Succeed())
+
+		Expect(transport.Messages("")).To(HaveLen(2))
+		Expect(transport.Messages("BUYER@example.com")).To(HaveLen(1))
+
+		transport.Clear()
+		Expect(transport.Messages("")).To(BeEmpty())
+	})
+
+	It("should reject an unknown transport", func() {
+		_, err := email.NewTransport(&config.EmailConfig{Transport: "pigeon"})
+		Expect(err).To(MatchError(ContainSubstring("pigeon")))
+	})
+})
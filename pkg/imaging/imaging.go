@@ -0,0 +1,125 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // регистрирует декодер GIF для image.Decode
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	// MaxPixels ограничивает размер декодируемого изображения,
+	// чтобы маленький файл не распаковался в гигабайты памяти
+	MaxPixels = 40_000_000
+
+	thumbnailJPEGQuality = 85
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrImageTooLarge     = errors.New("image dimensions are too large")
+)
+
+// Format описывает поддерживаемый формат изображения
+type Format struct {
+	ContentType string
+	Extension   string
+}
+
+var supportedFormats = map[string]Format{
+	"image/jpeg": {ContentType: "image/jpeg", Extension: "jpg"},
+	"image/png":  {ContentType: "image/png", Extension: "png"},
+	"image/gif":  {ContentType: "image/gif", Extension: "gif"},
+}
+
+// Sniff определяет формат изображения по содержимому, а не по заголовкам запроса,
+// и проверяет, что изображение корректно и не слишком велико
+func Sniff(data []byte) (Format, error) {
+	format, ok := supportedFormats[http.DetectContentType(data)]
+	if !ok {
+		return Format{}, ErrUnsupportedFormat
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Format{}, fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return Format{}, ErrImageTooLarge
+	}
+
+	return format, nil
+}
+
+// Thumbnail уменьшает изображение так, чтобы большая сторона была не больше maxSide.
+// Меньшие изображения только перекодируются. JPEG остается JPEG, остальное сохраняется в PNG.
+func Thumbnail(data []byte, format Format, maxSide int) ([]byte, Format, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, Format{}, fmt.Errorf("decode image: %w", err)
+	}
+
+	dst := resize(src, maxSide)
+
+	var buf bytes.Buffer
+	if format.ContentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailJPEGQuality})
+	} else {
+		format = supportedFormats["image/png"]
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return nil, Format{}, fmt.Errorf("encode thumbnail: %w", err)
+	}
+
+	return buf.Bytes(), format, nil
+}
+
+// resize масштабирует изображение усреднением пикселей исходной области
+func resize(src image.Image, maxSide int) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	dstW, dstH := srcW, srcH
+	if maxSide > 0 && (srcW > maxSide || srcH > maxSide) {
+		if srcW >= srcH {
+			dstW = maxSide
+			dstH = max(1, srcH*maxSide/srcW)
+		} else {
+			dstH = maxSide
+			dstW = max(1, srcW*maxSide/srcH)
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0 := bounds.Min.Y + y*srcH/dstH
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*srcH/dstH)
+		for x := 0; x < dstW; x++ {
+			x0 := bounds.Min.X + x*srcW/dstW
+			x1 := max(x0+1, bounds.Min.X+(x+1)*srcW/dstW)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBAModel.Convert(src.At(sx, sy)).(color.NRGBA)
+					r += uint64(c.R)
+					g += uint64(c.G)
+					b += uint64(c.B)
+					a += uint64(c.A)
+					n++
+				}
+			}
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: uint8(a / n),
+			})
+		}
+	}
+
+	return dst
+}
@@ -22,12 +22,17 @@ func StartServer(
 	router *gin.Engine,
 	mailer email.MailerService,
 	cfg *config.ServerConfig,
-	log *zap.Logger) error {
+	log *zap.Logger,
+	onShutdown ...func()) error {
 	srv := &http.Server{
 		Addr:              ":" + cfg.Port,
 		Handler:           router,
 		ReadHeaderTimeout: 3 * time.Second,
 	}
+	// Shutdown не отменяет контексты активных запросов, долгие соединения закрывают эти функции
+	for _, f := range onShutdown {
+		srv.RegisterOnShutdown(f)
+	}
 
 	switch cfg.GinMode {
 	case gin.DebugMode:
@@ -62,9 +67,10 @@ func StartServer(
 
 		mailer.Stop(ctx)
 
+		// ошибка остановки не возвращается, чтобы вызывающий успел завершить фоновые задачи
 		if err := srv.Shutdown(ctx); err != nil {
+			log.Warn("Could not stop server gracefully, closing connections", zap.Error(err))
 			_ = srv.Close()
-			return fmt.Errorf("could not stop server gracefully: %w", err)
 		}
 	}
 
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage хранит файлы на локальном диске. Используется для разработки и тестов,
+// раздавать файлы должен сам сервер (см. Dir).
+type LocalStorage struct {
+	dir       string
+	publicURL string
+}
+
+func NewLocalStorage(dir, publicURL string) (*LocalStorage, error) {
+	if dir == "" {
+		return nil, errors.New("local storage directory is not set")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create storage directory: %w", err)
+	}
+	return &LocalStorage{dir: dir, publicURL: publicURL}, nil
+}
+
+// Dir возвращает корневую директорию хранилища
+func (s *LocalStorage) Dir() string {
+	return s.dir
+}
+
+// PublicURL возвращает префикс, по которому сервер раздает файлы хранилища
+func (s *LocalStorage) PublicURL() string {
+	return s.publicURL
+}
+
+func (s *LocalStorage) Put(_ context.Context, key string, data []byte, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	// пишем во временный файл, чтобы не отдавать недописанные изображения
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("rename file: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrObjectNotFound
+		}
+		return fmt.Errorf("remove file: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return joinURL(s.publicURL, key)
+}
+
+// path не дает ключу выйти за пределы директории хранилища
+func (s *LocalStorage) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
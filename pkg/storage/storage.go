@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+)
+
+const (
+	DriverS3    = "s3"
+	DriverLocal = "local"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// Storage хранит загруженные файлы (изображения продуктов, фото отзывов и т.п.)
+// и отдает публичные ссылки на них
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// New создает хранилище согласно конфигурации
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.Storage.Driver {
+	case DriverS3:
+		return NewS3Storage(S3Options{
+			Endpoint:  cfg.URL,
+			Region:    cfg.SigningRegion,
+			Bucket:    cfg.BucketName,
+			AccessKey: cfg.AccessKey,
+			SecretKey: cfg.SecretKey,
+			PublicURL: cfg.Storage.PublicURL,
+		})
+	case DriverLocal, "":
+		return NewLocalStorage(cfg.Storage.LocalDir, cfg.Storage.PublicURL)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
+
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(key, "/")
+}